@@ -0,0 +1,10 @@
+package criteria
+
+import "github.com/rehandwi03/test-case-backend-majoo/util"
+
+type CategoryCriteria struct {
+	MerchantID string `json:"merchant_id"`
+	ParentID   string `json:"parent_id"`
+	Name       string `json:"name"`
+	Pagination util.Pagination
+}
@@ -0,0 +1,8 @@
+package criteria
+
+type MarginCriteria struct {
+	OutletID  string `json:"outlet_id"`
+	ProductID string `json:"product_id"`
+	From      string `json:"from"`
+	To        string `json:"to"`
+}
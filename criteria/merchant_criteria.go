@@ -5,5 +5,6 @@ import "github.com/rehandwi03/test-case-backend-majoo/util"
 type MerchantCriteria struct {
 	Name            string `json:"name"`
 	InstitutionName string `json:"institution_name"`
+	IncludeDeleted  bool   `json:"include_deleted"`
 	Pagination      util.Pagination
 }
@@ -0,0 +1,9 @@
+package criteria
+
+import "github.com/rehandwi03/test-case-backend-majoo/util"
+
+type NotificationCriteria struct {
+	OutletID   string `json:"outlet_id"`
+	Type       string `json:"type"`
+	Pagination util.Pagination
+}
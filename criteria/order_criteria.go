@@ -0,0 +1,10 @@
+package criteria
+
+import "github.com/rehandwi03/test-case-backend-majoo/util"
+
+type OrderCriteria struct {
+	OutletID   string `json:"outlet_id"`
+	Status     string `json:"status"`
+	TableID    string `json:"table_id"`
+	Pagination util.Pagination
+}
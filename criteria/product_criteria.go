@@ -3,8 +3,18 @@ package criteria
 import "github.com/rehandwi03/test-case-backend-majoo/util"
 
 type ProductCriteria struct {
-	Name       string `json:"name"`
-	Stock      string `json:"stock"`
-	Price      string `json:"price"`
-	Pagination util.Pagination
+	Name           string   `json:"name"`
+	Keyword        string   `json:"keyword"`
+	Stock          string   `json:"stock"`
+	MinStock       string   `json:"min_stock"`
+	MaxStock       string   `json:"max_stock"`
+	Price          string   `json:"price"`
+	MinPrice       string   `json:"min_price"`
+	MaxPrice       string   `json:"max_price"`
+	OutletIDs      []string `json:"outlet_ids"`
+	CategoryIDs    []string `json:"category_ids"`
+	Barcode        string   `json:"barcode"`
+	Ingredient     string   `json:"ingredient"`
+	IncludeDeleted bool     `json:"include_deleted"`
+	Pagination     util.Pagination
 }
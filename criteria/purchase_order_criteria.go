@@ -0,0 +1,14 @@
+package criteria
+
+import "github.com/rehandwi03/test-case-backend-majoo/util"
+
+// PurchaseOrderCriteria lists purchase orders, those of a supplier being its purchase history. From and To are dates
+// or RFC 3339 times.
+type PurchaseOrderCriteria struct {
+	SupplierID string `json:"supplier_id"`
+	OutletID   string `json:"outlet_id"`
+	Status     string `json:"status"`
+	From       string `json:"from"`
+	To         string `json:"to"`
+	Pagination util.Pagination
+}
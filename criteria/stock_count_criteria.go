@@ -0,0 +1,9 @@
+package criteria
+
+import "github.com/rehandwi03/test-case-backend-majoo/util"
+
+type StockCountCriteria struct {
+	OutletID   string `json:"outlet_id"`
+	Status     string `json:"status"`
+	Pagination util.Pagination
+}
@@ -0,0 +1,15 @@
+package criteria
+
+import "github.com/rehandwi03/test-case-backend-majoo/util"
+
+// StockMovementCriteria lists the ledger of an outlet or, when ProductID is set, of a product. From and To are dates
+// or RFC 3339 times.
+type StockMovementCriteria struct {
+	OutletID   string `json:"outlet_id"`
+	ProductID  string `json:"product_id"`
+	VariantID  string `json:"variant_id"`
+	Type       string `json:"type"`
+	From       string `json:"from"`
+	To         string `json:"to"`
+	Pagination util.Pagination
+}
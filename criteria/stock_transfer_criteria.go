@@ -0,0 +1,11 @@
+package criteria
+
+import "github.com/rehandwi03/test-case-backend-majoo/util"
+
+// StockTransferCriteria lists the transfers of the merchants of the caller, OutletID matches the transfers from or
+// to the outlet.
+type StockTransferCriteria struct {
+	OutletID   string `json:"outlet_id"`
+	Status     string `json:"status"`
+	Pagination util.Pagination
+}
@@ -0,0 +1,10 @@
+package criteria
+
+import "github.com/rehandwi03/test-case-backend-majoo/util"
+
+type SupplierCriteria struct {
+	MerchantID     string `json:"merchant_id"`
+	Name           string `json:"name"`
+	IncludeDeleted bool   `json:"include_deleted"`
+	Pagination     util.Pagination
+}
@@ -0,0 +1,176 @@
+package gateway
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/rehandwi03/test-case-backend-majoo/money"
+	"sync"
+)
+
+// MockProvider is a payment gateway kept in memory for development, which answers the same way every time:
+//
+//   - a charge with an amount ending in 13 minor units is declined and fails;
+//   - a card charge is authorized, or captured when asked to, right away;
+//   - any other charge is pending until a callback says otherwise, its action is a mock:// URL.
+//
+// Callbacks are JSON like {"charge_id": "…", "status": "captured"} signed by the hex HMAC-SHA256 of the body with the
+// secret. As there is no gateway behind it, the mock takes the callbacks it verifies as what happened to the charge.
+type MockProvider struct {
+	secret  []byte
+	mu      sync.Mutex
+	charges map[string]*Charge
+	// refunds are the references of the refunds made
+	refunds map[string]bool
+}
+
+func NewMockProvider(secret string) *MockProvider {
+	return &MockProvider{secret: []byte(secret), charges: map[string]*Charge{}, refunds: map[string]bool{}}
+}
+
+func (m *MockProvider) CreateCharge(ctx context.Context, request ChargeRequest) (Charge, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// the id comes from the reference, so asking again finds the same charge
+	sum := sha256.Sum256([]byte(request.Reference))
+	id := "mock_" + hex.EncodeToString(sum[:12])
+	if charge, ok := m.charges[id]; ok {
+		return *charge, nil
+	}
+
+	charge := &Charge{ID: id, Amount: request.Amount, Refunded: money.New(0, request.Amount.Currency)}
+	switch {
+	case request.Amount.Amount%100 == 13:
+		charge.Status = StatusFailed
+	case request.Method != "card":
+		charge.Status = StatusPending
+		charge.Action = "mock://pay/" + id
+	case request.Capture:
+		charge.Status = StatusCaptured
+	default:
+		charge.Status = StatusAuthorized
+	}
+
+	m.charges[id] = charge
+
+	return *charge, nil
+}
+
+func (m *MockProvider) Capture(ctx context.Context, chargeId string) (Charge, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	charge, ok := m.charges[chargeId]
+	if !ok {
+		return Charge{}, ErrChargeNotFound
+	}
+
+	switch charge.Status {
+	case StatusCaptured:
+	case StatusAuthorized:
+		charge.Status = StatusCaptured
+	default:
+		return *charge, ErrInvalidState
+	}
+
+	return *charge, nil
+}
+
+func (m *MockProvider) Cancel(ctx context.Context, chargeId string) (Charge, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	charge, ok := m.charges[chargeId]
+	if !ok {
+		return Charge{}, ErrChargeNotFound
+	}
+
+	switch charge.Status {
+	case StatusCancelled:
+	case StatusPending, StatusAuthorized:
+		charge.Status = StatusCancelled
+		charge.Action = ""
+	default:
+		return *charge, ErrInvalidState
+	}
+
+	return *charge, nil
+}
+
+func (m *MockProvider) Refund(ctx context.Context, chargeId string, reference string, amount money.Money) (
+	Charge, error,
+) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	charge, ok := m.charges[chargeId]
+	if !ok {
+		return Charge{}, ErrChargeNotFound
+	}
+
+	if m.refunds[reference] {
+		return *charge, nil
+	}
+
+	if charge.Status != StatusCaptured || amount.Currency != charge.Amount.Currency || amount.Amount <= 0 ||
+		charge.Refunded.Amount+amount.Amount > charge.Amount.Amount {
+		return *charge, ErrInvalidState
+	}
+
+	charge.Refunded.Amount += amount.Amount
+	m.refunds[reference] = true
+	if charge.Refunded.Amount == charge.Amount.Amount {
+		charge.Status = StatusRefunded
+	}
+
+	return *charge, nil
+}
+
+func (m *MockProvider) Status(ctx context.Context, chargeId string) (Charge, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	charge, ok := m.charges[chargeId]
+	if !ok {
+		return Charge{}, ErrChargeNotFound
+	}
+
+	return *charge, nil
+}
+
+func (m *MockProvider) VerifyCallback(payload []byte, signature string) (Callback, error) {
+	expected, err := hex.DecodeString(signature)
+	if err != nil || !hmac.Equal(expected, m.Sign(payload)) {
+		return Callback{}, ErrInvalidSignature
+	}
+
+	var body struct {
+		ChargeID string `json:"charge_id"`
+		Status   Status `json:"status"`
+	}
+	if err := json.Unmarshal(payload, &body); err != nil {
+		return Callback{}, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	charge, ok := m.charges[body.ChargeID]
+	if !ok {
+		return Callback{}, ErrChargeNotFound
+	}
+	charge.Status = body.Status
+
+	return Callback{ChargeID: body.ChargeID, Status: body.Status}, nil
+}
+
+// Sign is the signature of a callback payload, for sending the mock callbacks by hand.
+func (m *MockProvider) Sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, m.secret)
+	mac.Write(payload)
+
+	return mac.Sum(nil)
+}
@@ -0,0 +1,75 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"github.com/rehandwi03/test-case-backend-majoo/money"
+)
+
+// Status is where a charge stands at the gateway. A charge starts pending until the customer pays, like scanning a
+// QRIS code, or authorized when the payment was approved without being taken yet, like a card. Capturing takes the
+// authorized amount, refunding gives a captured one back and cancelling releases one that wasn't taken.
+type Status string
+
+const (
+	StatusPending    Status = "pending"
+	StatusAuthorized Status = "authorized"
+	StatusCaptured   Status = "captured"
+	StatusFailed     Status = "failed"
+	StatusRefunded   Status = "refunded"
+	StatusCancelled  Status = "cancelled"
+)
+
+var (
+	ErrChargeNotFound   = errors.New("charge not found")
+	ErrInvalidSignature = errors.New("callback signature is invalid")
+	// ErrInvalidState is a capture, refund or cancel the charge isn't in the state for, like capturing a failed
+	// charge.
+	ErrInvalidState = errors.New("the charge can't do this in its state")
+)
+
+// ChargeRequest asks for Amount to be paid by Method. Reference identifies the payment on our side, asking again with
+// the same reference answers the charge it created the first time. Without Capture the charge is only authorized.
+type ChargeRequest struct {
+	Reference string
+	Method    string
+	Amount    money.Money
+	Capture   bool
+}
+
+// Charge is a payment at the gateway. Action is what the customer pays with when the charge is pending, like the
+// string of a QRIS code or the URL of an e-wallet checkout.
+type Charge struct {
+	ID       string
+	Status   Status
+	Amount   money.Money
+	Refunded money.Money
+	Action   string
+}
+
+// Callback is the gateway telling the status of a charge changed.
+type Callback struct {
+	ChargeID string
+	Status   Status
+}
+
+// PaymentProvider is a payment gateway taking the payments of a sale which don't go through the till, like QRIS,
+// e-wallets or cards.
+type PaymentProvider interface {
+	CreateCharge(ctx context.Context, request ChargeRequest) (Charge, error)
+	// Capture takes an authorized charge.
+	Capture(ctx context.Context, chargeId string) (Charge, error)
+	// Cancel releases a pending or authorized charge, like the hold an authorization puts on a card, so it takes
+	// nothing. Cancelling a cancelled charge again answers it as it is.
+	Cancel(ctx context.Context, chargeId string) (Charge, error)
+	// Refund gives amount of a captured charge back, the charge is refunded once all of it is. Reference identifies
+	// the refund on our side, asking again with the same reference answers the charge without refunding it twice.
+	Refund(ctx context.Context, chargeId string, reference string, amount money.Money) (Charge, error)
+	Status(ctx context.Context, chargeId string) (Charge, error)
+	// VerifyCallback checks the callback was signed by the gateway before reading it, it fails with
+	// ErrInvalidSignature otherwise.
+	VerifyCallback(payload []byte, signature string) (Callback, error)
+}
+
+// Providers are the payment providers by the name payments choose them with.
+type Providers map[string]PaymentProvider
@@ -4,6 +4,7 @@ import (
 	"github.com/gofiber/fiber/v2"
 	custom_error "github.com/rehandwi03/test-case-backend-majoo/internal/error"
 	"github.com/rehandwi03/test-case-backend-majoo/internal/helper"
+	"github.com/rehandwi03/test-case-backend-majoo/internal/middleware"
 	request2 "github.com/rehandwi03/test-case-backend-majoo/request"
 	"github.com/rehandwi03/test-case-backend-majoo/service"
 	"log"
@@ -16,6 +17,9 @@ type authHandler struct {
 func NewAuthHandler(app fiber.Router, authService service.AuthService) {
 	handler := authHandler{authSvc: authService}
 	app.Post("/login", handler.Login)
+	app.Post("/token/refresh", handler.Refresh)
+	app.Post("/logout", middleware.JwtProtected(), handler.Logout)
+	app.Post("/logout/all", middleware.JwtProtected(), handler.LogoutAll)
 }
 
 func (a *authHandler) Login(c *fiber.Ctx) error {
@@ -73,3 +77,101 @@ func (a *authHandler) Login(c *fiber.Ctx) error {
 		)
 	}
 }
+
+func (a *authHandler) Refresh(c *fiber.Ctx) error {
+	request := new(request2.RefreshTokenRequest)
+
+	err := c.BodyParser(&request)
+	if err != nil {
+		log.Printf("error parsing request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  err.Error(),
+			},
+		)
+	}
+
+	errors := helper.ValidateRequest(*request)
+	if errors != nil {
+		log.Printf("error validate request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  errors,
+			},
+		)
+	}
+
+	res, err := a.authSvc.Refresh(c.Context(), request)
+	switch err.(type) {
+	case *custom_error.UnauthorizedError:
+		log.Printf("error unauthorized: %v", err)
+		return c.Status(fiber.StatusUnauthorized).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusUnauthorized",
+				Errors:  err,
+			},
+		)
+	case nil:
+		return c.Status(fiber.StatusOK).JSON(
+			helper.SuccessResponse{
+				Status: "success", Message: "success refresh token", Data: res,
+			},
+		)
+	default:
+		log.Printf("error internal server error: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusInternalServerError",
+				Errors:  err,
+			},
+		)
+	}
+}
+
+func (a *authHandler) Logout(c *fiber.Ctx) error {
+	err := a.authSvc.Logout(c.Context())
+	switch err.(type) {
+	case nil:
+		return c.Status(fiber.StatusOK).JSON(
+			helper.SuccessResponse{
+				Status: "success", Message: "success logout",
+			},
+		)
+	default:
+		log.Printf("error internal server error: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusInternalServerError",
+				Errors:  err,
+			},
+		)
+	}
+}
+
+func (a *authHandler) LogoutAll(c *fiber.Ctx) error {
+	err := a.authSvc.LogoutAll(c.Context())
+	switch err.(type) {
+	case nil:
+		return c.Status(fiber.StatusOK).JSON(
+			helper.SuccessResponse{
+				Status: "success", Message: "success logout from all sessions",
+			},
+		)
+	default:
+		log.Printf("error internal server error: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusInternalServerError",
+				Errors:  err,
+			},
+		)
+	}
+}
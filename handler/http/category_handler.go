@@ -0,0 +1,426 @@
+package http
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rehandwi03/test-case-backend-majoo/criteria"
+	custom_error "github.com/rehandwi03/test-case-backend-majoo/internal/error"
+	"github.com/rehandwi03/test-case-backend-majoo/internal/helper"
+	"github.com/rehandwi03/test-case-backend-majoo/internal/middleware"
+	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"github.com/rehandwi03/test-case-backend-majoo/query"
+	request2 "github.com/rehandwi03/test-case-backend-majoo/request"
+	"github.com/rehandwi03/test-case-backend-majoo/service"
+	"github.com/rehandwi03/test-case-backend-majoo/util"
+	"log"
+)
+
+type categoryHandler struct {
+	categorySvc service.CategoryService
+}
+
+func NewCategoryHandler(app fiber.Router, categoryService service.CategoryService) {
+	handler := categoryHandler{categorySvc: categoryService}
+
+	anyRole := middleware.RequireRole(model.RoleOwner, model.RoleManager, model.RoleCashier)
+	managers := middleware.RequireRole(model.RoleOwner, model.RoleManager)
+
+	app.Post("/categories", middleware.JwtProtected(), managers, handler.saveCategory)
+	app.Get("/categories/:id", middleware.JwtProtected(), anyRole, handler.getByID)
+	app.Put("/categories", middleware.JwtProtected(), managers, handler.updateCategory)
+	app.Delete("/categories/:id", middleware.JwtProtected(), managers, handler.deleteByID)
+	app.Get("/categories", middleware.JwtProtected(), anyRole, handler.fetch)
+	app.Get("/merchants/:id/categories", middleware.JwtProtected(), anyRole, handler.tree)
+}
+
+func (h *categoryHandler) fetch(c *fiber.Ctx) error {
+	pagination := util.GeneratePaginationFromRequest(c)
+
+	CategoryCriteria := criteria.CategoryCriteria{
+		Pagination: pagination,
+	}
+
+	CategoryCriteria.MerchantID = c.Query("merchant_id")
+	CategoryCriteria.ParentID = c.Query("parent_id")
+	CategoryCriteria.Name = c.Query("name")
+
+	res, err := h.categorySvc.Fetch(c.Context(), CategoryCriteria)
+	switch err.(type) {
+	case *custom_error.NotFoundError:
+		log.Printf("error not found: %v", err)
+		return c.Status(fiber.StatusNotFound).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusNotFound",
+				Errors:  err,
+			},
+		)
+	case *custom_error.BadRequest:
+		log.Printf("error bad request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
+	case nil:
+		return c.Status(fiber.StatusOK).JSON(
+			res,
+		)
+	default:
+		log.Printf("error internal server error: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusInternalServerError",
+				Errors:  err,
+			},
+		)
+	}
+}
+
+func (h *categoryHandler) deleteByID(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if id == "" {
+		log.Printf("error id is null")
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusNotFound",
+				Errors:  "id param is null",
+			},
+		)
+	}
+
+	params := query.Where(query.Eq("id", id))
+
+	err := h.categorySvc.DeleteCategory(c.Context(), params)
+	switch err.(type) {
+	case *custom_error.NotFoundError:
+		log.Printf("error not found: %v", err)
+		return c.Status(fiber.StatusNotFound).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusNotFound",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ConflictError:
+		log.Printf("error conflict: %v", err)
+		return c.Status(fiber.StatusConflict).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusConflict",
+				Errors:  err,
+			},
+		)
+	case nil:
+		return c.Status(fiber.StatusOK).JSON(
+			helper.SuccessResponse{
+				Status:  "success",
+				Message: "success delete data",
+			},
+		)
+	default:
+		log.Printf("error internal server error: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusInternalServerError",
+				Errors:  err,
+			},
+		)
+	}
+}
+
+func (h *categoryHandler) tree(c *fiber.Ctx) error {
+	merchantId, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		log.Printf("error parsing merchant id: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  "merchant id is invalid",
+			},
+		)
+	}
+
+	res, err := h.categorySvc.Tree(c.Context(), merchantId)
+	switch err.(type) {
+	case *custom_error.NotFoundError:
+		log.Printf("error not found: %v", err)
+		return c.Status(fiber.StatusNotFound).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusNotFound",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
+	case nil:
+		return c.Status(fiber.StatusOK).JSON(
+			helper.SuccessResponse{
+				Status:  "success",
+				Message: "success get data",
+				Data:    res,
+			},
+		)
+	default:
+		log.Printf("error internal server error: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusInternalServerError",
+				Errors:  err,
+			},
+		)
+	}
+}
+
+func (h *categoryHandler) getByID(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if id == "" {
+		log.Printf("error id is null")
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusNotFound",
+				Errors:  "id param is null",
+			},
+		)
+	}
+
+	params := query.Where(query.Eq("id", id))
+
+	res, err := h.categorySvc.GetByParam(c.Context(), params)
+	switch err.(type) {
+	case *custom_error.NotFoundError:
+		log.Printf("error not found: %v", err)
+		return c.Status(fiber.StatusNotFound).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusNotFound",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
+	case nil:
+		return c.Status(fiber.StatusOK).JSON(
+			helper.SuccessResponse{
+				Status:  "success",
+				Message: "success get data",
+				Data:    res,
+			},
+		)
+	default:
+		log.Printf("error internal server error: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusInternalServerError",
+				Errors:  err,
+			},
+		)
+	}
+}
+
+func (h *categoryHandler) updateCategory(c *fiber.Ctx) error {
+	request := new(request2.CategoryUpdateRequest)
+
+	err := c.BodyParser(&request)
+	if err != nil {
+		log.Printf("error parsing request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  err.Error(),
+			},
+		)
+	}
+
+	errors := helper.ValidateRequest(*request)
+	if errors != nil {
+		log.Printf("error validate request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  errors,
+			},
+		)
+	}
+
+	res, err := h.categorySvc.UpdateCategory(c.Context(), request)
+	switch err.(type) {
+	case *custom_error.NotFoundError:
+		log.Printf("error not found: %v", err)
+		return c.Status(fiber.StatusNotFound).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusNotFound",
+				Errors:  err,
+			},
+		)
+	case *custom_error.BadRequest:
+		log.Printf("error bad request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ConflictError:
+		log.Printf("error conflict: %v", err)
+		return c.Status(fiber.StatusConflict).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusConflict",
+				Errors:  err,
+			},
+		)
+	case nil:
+		return c.Status(fiber.StatusOK).JSON(
+			helper.SuccessResponse{
+				Status:  "success",
+				Message: "success update data",
+				Data: map[string]interface{}{
+					"category_id": res,
+				},
+			},
+		)
+	default:
+		log.Printf("error internal server error: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusInternalServerError",
+				Errors:  err,
+			},
+		)
+	}
+}
+
+func (h *categoryHandler) saveCategory(c *fiber.Ctx) error {
+	request := new(request2.CategoryAddRequest)
+
+	err := c.BodyParser(&request)
+	if err != nil {
+		log.Printf("error parsing request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  err.Error(),
+			},
+		)
+	}
+
+	errors := helper.ValidateRequest(*request)
+	if errors != nil {
+		log.Printf("error validate request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  errors,
+			},
+		)
+	}
+
+	res, err := h.categorySvc.SaveCategory(c.Context(), request)
+	switch err.(type) {
+	case *custom_error.BadRequest:
+		log.Printf("error bad request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ConflictError:
+		log.Printf("error conflict: %v", err)
+		return c.Status(fiber.StatusConflict).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusConflict",
+				Errors:  err,
+			},
+		)
+	case nil:
+		return c.Status(fiber.StatusCreated).JSON(
+			helper.SuccessResponse{
+				Status:  "success",
+				Message: "success add data",
+				Data: map[string]interface{}{
+					"category_id": res,
+				},
+			},
+		)
+	default:
+		log.Printf("error internal server error: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusInternalServerError",
+				Errors:  err,
+			},
+		)
+	}
+}
@@ -7,6 +7,8 @@ import (
 	custom_error "github.com/rehandwi03/test-case-backend-majoo/internal/error"
 	"github.com/rehandwi03/test-case-backend-majoo/internal/helper"
 	"github.com/rehandwi03/test-case-backend-majoo/internal/middleware"
+	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"github.com/rehandwi03/test-case-backend-majoo/query"
 	request2 "github.com/rehandwi03/test-case-backend-majoo/request"
 	"github.com/rehandwi03/test-case-backend-majoo/service"
 	"github.com/rehandwi03/test-case-backend-majoo/util"
@@ -20,11 +22,18 @@ type merchantHandler struct {
 func NewMerchantHandler(app fiber.Router, merchantService service.MerchantService) {
 	handler := merchantHandler{merchantSvc: merchantService}
 
+	anyRole := middleware.RequireRole(model.RoleOwner, model.RoleManager, model.RoleCashier)
+	ownerOnly := middleware.RequireRole(model.RoleOwner)
+
+	// every authenticated user may open a merchant and becomes its owner
 	app.Post("/merchants", middleware.JwtProtected(), handler.saveMerchant)
-	app.Get("/merchants/:id", middleware.JwtProtected(), handler.getByID)
-	app.Put("/merchants", middleware.JwtProtected(), handler.updateMerchant)
-	app.Delete("/merchants/:id", middleware.JwtProtected(), handler.deleteByID)
-	app.Get("/merchants", middleware.JwtProtected(), handler.fetch)
+	app.Get("/merchants/:id", middleware.JwtProtected(), anyRole, handler.getByID)
+	app.Put("/merchants", middleware.JwtProtected(), ownerOnly, handler.updateMerchant)
+	app.Delete("/merchants/:id", middleware.JwtProtected(), ownerOnly, handler.deleteByID)
+	app.Post("/merchants/:id/restore", middleware.JwtProtected(), ownerOnly, handler.restore)
+	app.Get("/merchants", middleware.JwtProtected(), anyRole, handler.fetch)
+	app.Post("/merchants/:id/users", middleware.JwtProtected(), ownerOnly, handler.saveMerchantUser)
+	app.Delete("/merchants/:id/users/:userId", middleware.JwtProtected(), ownerOnly, handler.deleteMerchantUser)
 }
 
 func (m *merchantHandler) fetch(c *fiber.Ctx) error {
@@ -34,11 +43,39 @@ func (m *merchantHandler) fetch(c *fiber.Ctx) error {
 		Pagination: pagination,
 	}
 
-	// MerchantCriteria.Email = c.Query("email")
-	// MerchantCriteria.PhoneNumber = c.Query("phoneNumber")
+	merchantCriteria.Name = c.Query("name")
+	merchantCriteria.InstitutionName = c.Query("institution_name")
+	merchantCriteria.IncludeDeleted = c.Query("include_deleted") == "true"
 
 	res, err := m.merchantSvc.Fetch(c.Context(), merchantCriteria)
 	switch err.(type) {
+	case *custom_error.NotFoundError:
+		log.Printf("error not found: %v", err)
+		return c.Status(fiber.StatusNotFound).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusNotFound",
+				Errors:  err,
+			},
+		)
+	case *custom_error.BadRequest:
+		log.Printf("error bad request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
 	case nil:
 		return c.Status(fiber.StatusOK).JSON(
 			res,
@@ -68,28 +105,70 @@ func (m *merchantHandler) deleteByID(c *fiber.Ctx) error {
 		)
 	}
 
-	userId, ok := c.Context().Value("user_id").(uuid.UUID)
-	if !ok {
-		return c.Status(fiber.StatusBadRequest).JSON(
+	params := query.Where(query.Eq("id", id))
+
+	err := m.merchantSvc.DeleteMerchant(c.Context(), params)
+	switch err.(type) {
+	case *custom_error.NotFoundError:
+		log.Printf("error not found: %v", err)
+		return c.Status(fiber.StatusNotFound).JSON(
 			helper.ErrorResponse{
 				Status:  "failed",
 				Message: "StatusNotFound",
-				Errors:  "user id not found",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ConflictError:
+		log.Printf("error conflict: %v", err)
+		return c.Status(fiber.StatusConflict).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusConflict",
+				Errors:  err,
+			},
+		)
+	case nil:
+		return c.Status(fiber.StatusOK).JSON(
+			helper.SuccessResponse{
+				Status:  "success",
+				Message: "success delete data",
+			},
+		)
+	default:
+		log.Printf("error internal server error: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusInternalServerError",
+				Errors:  err,
 			},
 		)
 	}
+}
 
-	params := map[string]interface{}{
-		"or": map[string]interface{}{},
-		"where": map[string]interface{}{
-			"default": map[string]interface{}{
-				"id = ?":      id,
-				"user_id = ?": userId,
+func (m *merchantHandler) restore(c *fiber.Ctx) error {
+	merchantId, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		log.Printf("error parsing merchant id: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  "merchant id is invalid",
 			},
-		},
+		)
 	}
 
-	err := m.merchantSvc.DeleteMerchant(c.Context(), params)
+	err = m.merchantSvc.RestoreMerchant(c.Context(), merchantId)
 	switch err.(type) {
 	case *custom_error.NotFoundError:
 		log.Printf("error not found: %v", err)
@@ -100,11 +179,29 @@ func (m *merchantHandler) deleteByID(c *fiber.Ctx) error {
 				Errors:  err,
 			},
 		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ConflictError:
+		log.Printf("error conflict: %v", err)
+		return c.Status(fiber.StatusConflict).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusConflict",
+				Errors:  err,
+			},
+		)
 	case nil:
 		return c.Status(fiber.StatusOK).JSON(
 			helper.SuccessResponse{
 				Status:  "success",
-				Message: "success delete data",
+				Message: "success restore data",
 			},
 		)
 	default:
@@ -132,26 +229,7 @@ func (m *merchantHandler) getByID(c *fiber.Ctx) error {
 		)
 	}
 
-	userId, ok := c.Context().Value("user_id").(uuid.UUID)
-	if !ok {
-		return c.Status(fiber.StatusBadRequest).JSON(
-			helper.ErrorResponse{
-				Status:  "failed",
-				Message: "StatusNotFound",
-				Errors:  "user id not found",
-			},
-		)
-	}
-
-	params := map[string]interface{}{
-		"or": map[string]interface{}{},
-		"where": map[string]interface{}{
-			"default": map[string]interface{}{
-				"id = ?":      id,
-				"user_id = ?": userId,
-			},
-		},
-	}
+	params := query.Where(query.Eq("id", id))
 
 	res, err := m.merchantSvc.GetByParam(c.Context(), params)
 	switch err.(type) {
@@ -164,6 +242,15 @@ func (m *merchantHandler) getByID(c *fiber.Ctx) error {
 				Errors:  err,
 			},
 		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
 	case nil:
 		return c.Status(fiber.StatusOK).JSON(
 			helper.SuccessResponse{
@@ -231,6 +318,24 @@ func (m *merchantHandler) updateMerchant(c *fiber.Ctx) error {
 				Errors:  err,
 			},
 		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ConflictError:
+		log.Printf("error conflict: %v", err)
+		return c.Status(fiber.StatusConflict).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusConflict",
+				Errors:  err,
+			},
+		)
 	case nil:
 		return c.Status(fiber.StatusOK).JSON(
 			helper.SuccessResponse{
@@ -291,6 +396,15 @@ func (m *merchantHandler) saveMerchant(c *fiber.Ctx) error {
 				Errors:  err,
 			},
 		)
+	case *custom_error.ConflictError:
+		log.Printf("error conflict: %v", err)
+		return c.Status(fiber.StatusConflict).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusConflict",
+				Errors:  err,
+			},
+		)
 	case nil:
 		return c.Status(fiber.StatusCreated).JSON(
 			helper.SuccessResponse{
@@ -312,3 +426,168 @@ func (m *merchantHandler) saveMerchant(c *fiber.Ctx) error {
 		)
 	}
 }
+
+func (m *merchantHandler) saveMerchantUser(c *fiber.Ctx) error {
+	merchantId, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		log.Printf("error parsing merchant id: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  "merchant id is invalid",
+			},
+		)
+	}
+
+	request := new(request2.MerchantUserAddRequest)
+
+	err = c.BodyParser(&request)
+	if err != nil {
+		log.Printf("error parsing request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  err.Error(),
+			},
+		)
+	}
+
+	errors := helper.ValidateRequest(*request)
+	if errors != nil {
+		log.Printf("error validate request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  errors,
+			},
+		)
+	}
+
+	request.MerchantID = merchantId
+
+	res, err := m.merchantSvc.SaveMerchantUser(c.Context(), request)
+	switch err.(type) {
+	case *custom_error.NotFoundError:
+		log.Printf("error not found: %v", err)
+		return c.Status(fiber.StatusNotFound).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusNotFound",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ConflictError:
+		log.Printf("error conflict: %v", err)
+		return c.Status(fiber.StatusConflict).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusConflict",
+				Errors:  err,
+			},
+		)
+	case nil:
+		return c.Status(fiber.StatusCreated).JSON(
+			helper.SuccessResponse{
+				Status:  "success",
+				Message: "success add data",
+				Data: map[string]interface{}{
+					"merchant_user_id": res,
+				},
+			},
+		)
+	default:
+		log.Printf("error internal server error: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusInternalServerError",
+				Errors:  err,
+			},
+		)
+	}
+}
+
+func (m *merchantHandler) deleteMerchantUser(c *fiber.Ctx) error {
+	merchantId, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		log.Printf("error parsing merchant id: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  "merchant id is invalid",
+			},
+		)
+	}
+
+	userId, err := uuid.Parse(c.Params("userId"))
+	if err != nil {
+		log.Printf("error parsing user id: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  "user id is invalid",
+			},
+		)
+	}
+
+	err = m.merchantSvc.DeleteMerchantUser(c.Context(), merchantId, userId)
+	switch err.(type) {
+	case *custom_error.NotFoundError:
+		log.Printf("error not found: %v", err)
+		return c.Status(fiber.StatusNotFound).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusNotFound",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ConflictError:
+		log.Printf("error conflict: %v", err)
+		return c.Status(fiber.StatusConflict).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusConflict",
+				Errors:  err,
+			},
+		)
+	case nil:
+		return c.Status(fiber.StatusOK).JSON(
+			helper.SuccessResponse{
+				Status:  "success",
+				Message: "success delete data",
+			},
+		)
+	default:
+		log.Printf("error internal server error: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusInternalServerError",
+				Errors:  err,
+			},
+		)
+	}
+}
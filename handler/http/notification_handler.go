@@ -0,0 +1,135 @@
+package http
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rehandwi03/test-case-backend-majoo/criteria"
+	custom_error "github.com/rehandwi03/test-case-backend-majoo/internal/error"
+	"github.com/rehandwi03/test-case-backend-majoo/internal/helper"
+	"github.com/rehandwi03/test-case-backend-majoo/internal/middleware"
+	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"github.com/rehandwi03/test-case-backend-majoo/service"
+	"github.com/rehandwi03/test-case-backend-majoo/util"
+	"log"
+)
+
+type notificationHandler struct {
+	notificationSvc service.NotificationService
+}
+
+func NewNotificationHandler(app fiber.Router, notificationService service.NotificationService) {
+	handler := notificationHandler{notificationSvc: notificationService}
+
+	anyRole := middleware.RequireRole(model.RoleOwner, model.RoleManager, model.RoleCashier)
+
+	app.Get("/outlets/:id/low-stock", middleware.JwtProtected(), anyRole, handler.lowStock)
+	app.Get("/outlets/:id/notifications", middleware.JwtProtected(), anyRole, handler.fetch)
+}
+
+func (n *notificationHandler) lowStock(c *fiber.Ctx) error {
+	outletId, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		log.Printf("error parsing outlet id: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  "outlet id is invalid",
+			},
+		)
+	}
+
+	res, err := n.notificationSvc.LowStock(c.Context(), outletId)
+	switch err.(type) {
+	case *custom_error.NotFoundError:
+		log.Printf("error not found: %v", err)
+		return c.Status(fiber.StatusNotFound).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusNotFound",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
+	case nil:
+		return c.Status(fiber.StatusOK).JSON(
+			helper.SuccessResponse{
+				Status:  "success",
+				Message: "success get data",
+				Data:    res,
+			},
+		)
+	default:
+		log.Printf("error internal server error: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusInternalServerError",
+				Errors:  err,
+			},
+		)
+	}
+}
+
+func (n *notificationHandler) fetch(c *fiber.Ctx) error {
+	pagination := util.GeneratePaginationFromRequest(c)
+
+	notificationCriteria := criteria.NotificationCriteria{
+		Pagination: pagination,
+	}
+
+	notificationCriteria.OutletID = c.Params("id")
+	notificationCriteria.Type = c.Query("type")
+
+	res, err := n.notificationSvc.Fetch(c.Context(), notificationCriteria)
+	switch err.(type) {
+	case *custom_error.NotFoundError:
+		log.Printf("error not found: %v", err)
+		return c.Status(fiber.StatusNotFound).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusNotFound",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
+	case *custom_error.BadRequest:
+		log.Printf("error bad request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  err,
+			},
+		)
+	case nil:
+		return c.Status(fiber.StatusOK).JSON(
+			res,
+		)
+	default:
+		log.Printf("error internal server error: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusInternalServerError",
+				Errors:  err,
+			},
+		)
+	}
+}
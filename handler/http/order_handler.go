@@ -0,0 +1,1021 @@
+package http
+
+import (
+	"context"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rehandwi03/test-case-backend-majoo/criteria"
+	custom_error "github.com/rehandwi03/test-case-backend-majoo/internal/error"
+	"github.com/rehandwi03/test-case-backend-majoo/internal/helper"
+	"github.com/rehandwi03/test-case-backend-majoo/internal/middleware"
+	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"github.com/rehandwi03/test-case-backend-majoo/query"
+	request2 "github.com/rehandwi03/test-case-backend-majoo/request"
+	"github.com/rehandwi03/test-case-backend-majoo/service"
+	"github.com/rehandwi03/test-case-backend-majoo/util"
+	"log"
+)
+
+type orderHandler struct {
+	orderSvc service.OrderService
+}
+
+func NewOrderHandler(app fiber.Router, orderService service.OrderService) {
+	handler := orderHandler{orderSvc: orderService}
+
+	anyRole := middleware.RequireRole(model.RoleOwner, model.RoleManager, model.RoleCashier)
+
+	app.Post("/outlets/:id/orders", middleware.JwtProtected(), anyRole, handler.open)
+	app.Get("/outlets/:id/orders", middleware.JwtProtected(), anyRole, handler.fetch)
+	app.Get("/outlets/:id/orders/:orderId", middleware.JwtProtected(), anyRole, handler.getByID)
+	app.Put("/outlets/:id/orders/:orderId", middleware.JwtProtected(), anyRole, handler.update)
+	app.Post("/outlets/:id/orders/:orderId/items", middleware.JwtProtected(), anyRole, handler.addItems)
+	app.Put("/outlets/:id/orders/:orderId/items/:itemId", middleware.JwtProtected(), anyRole, handler.updateItem)
+	app.Delete("/outlets/:id/orders/:orderId/items/:itemId", middleware.JwtProtected(), anyRole, handler.removeItem)
+	app.Post("/outlets/:id/orders/:orderId/park", middleware.JwtProtected(), anyRole, handler.park)
+	app.Post("/outlets/:id/orders/:orderId/resume", middleware.JwtProtected(), anyRole, handler.resume)
+	app.Post("/outlets/:id/orders/:orderId/cancel", middleware.JwtProtected(), anyRole, handler.cancel)
+	app.Post("/outlets/:id/orders/:orderId/transfer", middleware.JwtProtected(), anyRole, handler.transfer)
+	app.Post("/outlets/:id/orders/:orderId/merge", middleware.JwtProtected(), anyRole, handler.merge)
+	app.Post("/outlets/:id/orders/:orderId/split", middleware.JwtProtected(), anyRole, handler.split)
+}
+
+func (o *orderHandler) open(c *fiber.Ctx) error {
+	outletId, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		log.Printf("error parsing outlet id: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  "outlet id is invalid",
+			},
+		)
+	}
+
+	request := new(request2.OrderOpenRequest)
+
+	err = c.BodyParser(&request)
+	if err != nil {
+		log.Printf("error parsing request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  err.Error(),
+			},
+		)
+	}
+	request.OutletID = outletId
+
+	errors := helper.ValidateRequest(*request)
+	if errors != nil {
+		log.Printf("error validate request: %v", errors)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  errors,
+			},
+		)
+	}
+
+	res, err := o.orderSvc.Open(c.Context(), request)
+	switch err.(type) {
+	case *custom_error.NotFoundError:
+		log.Printf("error not found: %v", err)
+		return c.Status(fiber.StatusNotFound).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusNotFound",
+				Errors:  err,
+			},
+		)
+	case *custom_error.BadRequest:
+		log.Printf("error bad request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ConflictError:
+		log.Printf("error conflict: %v", err)
+		return c.Status(fiber.StatusConflict).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusConflict",
+				Errors:  err,
+			},
+		)
+	case nil:
+		return c.Status(fiber.StatusCreated).JSON(
+			helper.SuccessResponse{
+				Status:  "success",
+				Message: "success add data",
+				Data: map[string]interface{}{
+					"order_id": res,
+				},
+			},
+		)
+	default:
+		log.Printf("error internal server error: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusInternalServerError",
+				Errors:  err,
+			},
+		)
+	}
+}
+
+func (o *orderHandler) fetch(c *fiber.Ctx) error {
+	pagination := util.GeneratePaginationFromRequest(c)
+
+	orderCriteria := criteria.OrderCriteria{
+		Pagination: pagination,
+	}
+
+	orderCriteria.OutletID = c.Params("id")
+	orderCriteria.Status = c.Query("status")
+	orderCriteria.TableID = c.Query("table_id")
+
+	res, err := o.orderSvc.Fetch(c.Context(), orderCriteria)
+	switch err.(type) {
+	case *custom_error.NotFoundError:
+		log.Printf("error not found: %v", err)
+		return c.Status(fiber.StatusNotFound).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusNotFound",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
+	case *custom_error.BadRequest:
+		log.Printf("error bad request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  err,
+			},
+		)
+	case nil:
+		return c.Status(fiber.StatusOK).JSON(
+			res,
+		)
+	default:
+		log.Printf("error internal server error: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusInternalServerError",
+				Errors:  err,
+			},
+		)
+	}
+}
+
+func (o *orderHandler) getByID(c *fiber.Ctx) error {
+	id := c.Params("orderId")
+	if id == "" {
+		log.Printf("error id is null")
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusNotFound",
+				Errors:  "id param is null",
+			},
+		)
+	}
+
+	params := query.Where(query.Eq("id", id), query.Eq("outlet_id", c.Params("id")))
+
+	res, err := o.orderSvc.GetByParam(c.Context(), params)
+	switch err.(type) {
+	case *custom_error.NotFoundError:
+		log.Printf("error not found: %v", err)
+		return c.Status(fiber.StatusNotFound).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusNotFound",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
+	case nil:
+		return c.Status(fiber.StatusOK).JSON(
+			helper.SuccessResponse{
+				Status:  "success",
+				Message: "success get data",
+				Data:    res,
+			},
+		)
+	default:
+		log.Printf("error internal server error: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusInternalServerError",
+				Errors:  err,
+			},
+		)
+	}
+}
+
+func (o *orderHandler) update(c *fiber.Ctx) error {
+	outletId, orderId, message := orderIDs(c)
+	if message != "" {
+		log.Printf("error parsing ids: %v", message)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  message,
+			},
+		)
+	}
+
+	request := new(request2.OrderUpdateRequest)
+
+	err := c.BodyParser(&request)
+	if err != nil {
+		log.Printf("error parsing request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  err.Error(),
+			},
+		)
+	}
+	request.ID = orderId
+	request.OutletID = outletId
+
+	errors := helper.ValidateRequest(*request)
+	if errors != nil {
+		log.Printf("error validate request: %v", errors)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  errors,
+			},
+		)
+	}
+
+	err = o.orderSvc.Update(c.Context(), request)
+	switch err.(type) {
+	case *custom_error.NotFoundError:
+		log.Printf("error not found: %v", err)
+		return c.Status(fiber.StatusNotFound).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusNotFound",
+				Errors:  err,
+			},
+		)
+	case *custom_error.BadRequest:
+		log.Printf("error bad request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ConflictError:
+		log.Printf("error conflict: %v", err)
+		return c.Status(fiber.StatusConflict).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusConflict",
+				Errors:  err,
+			},
+		)
+	case nil:
+		return c.Status(fiber.StatusOK).JSON(
+			helper.SuccessResponse{
+				Status:  "success",
+				Message: "success update data",
+			},
+		)
+	default:
+		log.Printf("error internal server error: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusInternalServerError",
+				Errors:  err,
+			},
+		)
+	}
+}
+
+func (o *orderHandler) addItems(c *fiber.Ctx) error {
+	outletId, orderId, message := orderIDs(c)
+	if message != "" {
+		log.Printf("error parsing ids: %v", message)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  message,
+			},
+		)
+	}
+
+	request := new(request2.OrderAddItemsRequest)
+
+	err := c.BodyParser(&request)
+	if err != nil {
+		log.Printf("error parsing request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  err.Error(),
+			},
+		)
+	}
+	request.ID = orderId
+	request.OutletID = outletId
+
+	errors := helper.ValidateRequest(*request)
+	if errors != nil {
+		log.Printf("error validate request: %v", errors)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  errors,
+			},
+		)
+	}
+
+	err = o.orderSvc.AddItems(c.Context(), request)
+	switch err.(type) {
+	case *custom_error.NotFoundError:
+		log.Printf("error not found: %v", err)
+		return c.Status(fiber.StatusNotFound).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusNotFound",
+				Errors:  err,
+			},
+		)
+	case *custom_error.BadRequest:
+		log.Printf("error bad request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ConflictError:
+		log.Printf("error conflict: %v", err)
+		return c.Status(fiber.StatusConflict).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusConflict",
+				Errors:  err,
+			},
+		)
+	case nil:
+		return c.Status(fiber.StatusOK).JSON(
+			helper.SuccessResponse{
+				Status:  "success",
+				Message: "success add data",
+			},
+		)
+	default:
+		log.Printf("error internal server error: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusInternalServerError",
+				Errors:  err,
+			},
+		)
+	}
+}
+
+func (o *orderHandler) updateItem(c *fiber.Ctx) error {
+	outletId, orderId, message := orderIDs(c)
+	if message != "" {
+		log.Printf("error parsing ids: %v", message)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  message,
+			},
+		)
+	}
+
+	itemId, err := uuid.Parse(c.Params("itemId"))
+	if err != nil {
+		log.Printf("error parsing order item id: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  "order item id is invalid",
+			},
+		)
+	}
+
+	request := new(request2.OrderItemUpdateRequest)
+
+	err = c.BodyParser(&request)
+	if err != nil {
+		log.Printf("error parsing request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  err.Error(),
+			},
+		)
+	}
+	request.ID = itemId
+	request.OrderID = orderId
+	request.OutletID = outletId
+
+	errors := helper.ValidateRequest(*request)
+	if errors != nil {
+		log.Printf("error validate request: %v", errors)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  errors,
+			},
+		)
+	}
+
+	err = o.orderSvc.UpdateItem(c.Context(), request)
+	switch err.(type) {
+	case *custom_error.NotFoundError:
+		log.Printf("error not found: %v", err)
+		return c.Status(fiber.StatusNotFound).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusNotFound",
+				Errors:  err,
+			},
+		)
+	case *custom_error.BadRequest:
+		log.Printf("error bad request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ConflictError:
+		log.Printf("error conflict: %v", err)
+		return c.Status(fiber.StatusConflict).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusConflict",
+				Errors:  err,
+			},
+		)
+	case nil:
+		return c.Status(fiber.StatusOK).JSON(
+			helper.SuccessResponse{
+				Status:  "success",
+				Message: "success update data",
+			},
+		)
+	default:
+		log.Printf("error internal server error: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusInternalServerError",
+				Errors:  err,
+			},
+		)
+	}
+}
+
+func (o *orderHandler) removeItem(c *fiber.Ctx) error {
+	outletId, orderId, message := orderIDs(c)
+	if message != "" {
+		log.Printf("error parsing ids: %v", message)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  message,
+			},
+		)
+	}
+
+	itemId, err := uuid.Parse(c.Params("itemId"))
+	if err != nil {
+		log.Printf("error parsing order item id: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  "order item id is invalid",
+			},
+		)
+	}
+
+	err = o.orderSvc.RemoveItem(c.Context(), outletId, orderId, itemId)
+	switch err.(type) {
+	case *custom_error.NotFoundError:
+		log.Printf("error not found: %v", err)
+		return c.Status(fiber.StatusNotFound).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusNotFound",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ConflictError:
+		log.Printf("error conflict: %v", err)
+		return c.Status(fiber.StatusConflict).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusConflict",
+				Errors:  err,
+			},
+		)
+	case nil:
+		return c.Status(fiber.StatusOK).JSON(
+			helper.SuccessResponse{
+				Status:  "success",
+				Message: "success delete data",
+			},
+		)
+	default:
+		log.Printf("error internal server error: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusInternalServerError",
+				Errors:  err,
+			},
+		)
+	}
+}
+
+func (o *orderHandler) park(c *fiber.Ctx) error {
+	return o.act(c, o.orderSvc.Park, "success park data")
+}
+
+func (o *orderHandler) resume(c *fiber.Ctx) error {
+	return o.act(c, o.orderSvc.Resume, "success resume data")
+}
+
+func (o *orderHandler) cancel(c *fiber.Ctx) error {
+	return o.act(c, o.orderSvc.Cancel, "success cancel data")
+}
+
+func (o *orderHandler) transfer(c *fiber.Ctx) error {
+	outletId, orderId, message := orderIDs(c)
+	if message != "" {
+		log.Printf("error parsing ids: %v", message)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  message,
+			},
+		)
+	}
+
+	request := new(request2.OrderTransferRequest)
+
+	err := c.BodyParser(&request)
+	if err != nil {
+		log.Printf("error parsing request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  err.Error(),
+			},
+		)
+	}
+	request.ID = orderId
+	request.OutletID = outletId
+
+	errors := helper.ValidateRequest(*request)
+	if errors != nil {
+		log.Printf("error validate request: %v", errors)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  errors,
+			},
+		)
+	}
+
+	err = o.orderSvc.Transfer(c.Context(), request)
+	switch err.(type) {
+	case *custom_error.NotFoundError:
+		log.Printf("error not found: %v", err)
+		return c.Status(fiber.StatusNotFound).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusNotFound",
+				Errors:  err,
+			},
+		)
+	case *custom_error.BadRequest:
+		log.Printf("error bad request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ConflictError:
+		log.Printf("error conflict: %v", err)
+		return c.Status(fiber.StatusConflict).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusConflict",
+				Errors:  err,
+			},
+		)
+	case nil:
+		return c.Status(fiber.StatusOK).JSON(
+			helper.SuccessResponse{
+				Status:  "success",
+				Message: "success update data",
+			},
+		)
+	default:
+		log.Printf("error internal server error: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusInternalServerError",
+				Errors:  err,
+			},
+		)
+	}
+}
+
+func (o *orderHandler) merge(c *fiber.Ctx) error {
+	outletId, orderId, message := orderIDs(c)
+	if message != "" {
+		log.Printf("error parsing ids: %v", message)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  message,
+			},
+		)
+	}
+
+	request := new(request2.OrderMergeRequest)
+
+	err := c.BodyParser(&request)
+	if err != nil {
+		log.Printf("error parsing request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  err.Error(),
+			},
+		)
+	}
+	request.ID = orderId
+	request.OutletID = outletId
+
+	errors := helper.ValidateRequest(*request)
+	if errors != nil {
+		log.Printf("error validate request: %v", errors)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  errors,
+			},
+		)
+	}
+
+	err = o.orderSvc.Merge(c.Context(), request)
+	switch err.(type) {
+	case *custom_error.NotFoundError:
+		log.Printf("error not found: %v", err)
+		return c.Status(fiber.StatusNotFound).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusNotFound",
+				Errors:  err,
+			},
+		)
+	case *custom_error.BadRequest:
+		log.Printf("error bad request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ConflictError:
+		log.Printf("error conflict: %v", err)
+		return c.Status(fiber.StatusConflict).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusConflict",
+				Errors:  err,
+			},
+		)
+	case nil:
+		return c.Status(fiber.StatusOK).JSON(
+			helper.SuccessResponse{
+				Status:  "success",
+				Message: "success update data",
+			},
+		)
+	default:
+		log.Printf("error internal server error: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusInternalServerError",
+				Errors:  err,
+			},
+		)
+	}
+}
+
+func (o *orderHandler) split(c *fiber.Ctx) error {
+	outletId, orderId, message := orderIDs(c)
+	if message != "" {
+		log.Printf("error parsing ids: %v", message)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  message,
+			},
+		)
+	}
+
+	request := new(request2.OrderSplitRequest)
+
+	err := c.BodyParser(&request)
+	if err != nil {
+		log.Printf("error parsing request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  err.Error(),
+			},
+		)
+	}
+	request.ID = orderId
+	request.OutletID = outletId
+
+	errors := helper.ValidateRequest(*request)
+	if errors != nil {
+		log.Printf("error validate request: %v", errors)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  errors,
+			},
+		)
+	}
+
+	res, err := o.orderSvc.Split(c.Context(), request)
+	switch err.(type) {
+	case *custom_error.NotFoundError:
+		log.Printf("error not found: %v", err)
+		return c.Status(fiber.StatusNotFound).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusNotFound",
+				Errors:  err,
+			},
+		)
+	case *custom_error.BadRequest:
+		log.Printf("error bad request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ConflictError:
+		log.Printf("error conflict: %v", err)
+		return c.Status(fiber.StatusConflict).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusConflict",
+				Errors:  err,
+			},
+		)
+	case nil:
+		return c.Status(fiber.StatusCreated).JSON(
+			helper.SuccessResponse{
+				Status:  "success",
+				Message: "success add data",
+				Data: map[string]interface{}{
+					"order_id": res,
+				},
+			},
+		)
+	default:
+		log.Printf("error internal server error: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusInternalServerError",
+				Errors:  err,
+			},
+		)
+	}
+}
+
+// act moves the order of the request to another status with the given service call, parking, resuming or
+// cancelling it.
+func (o *orderHandler) act(
+	c *fiber.Ctx, action func(ctx context.Context, outletId uuid.UUID, orderId uuid.UUID) error, success string,
+) error {
+	outletId, orderId, message := orderIDs(c)
+	if message != "" {
+		log.Printf("error parsing ids: %v", message)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  message,
+			},
+		)
+	}
+
+	err := action(c.Context(), outletId, orderId)
+	switch err.(type) {
+	case *custom_error.NotFoundError:
+		log.Printf("error not found: %v", err)
+		return c.Status(fiber.StatusNotFound).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusNotFound",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ConflictError:
+		log.Printf("error conflict: %v", err)
+		return c.Status(fiber.StatusConflict).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusConflict",
+				Errors:  err,
+			},
+		)
+	case nil:
+		return c.Status(fiber.StatusOK).JSON(
+			helper.SuccessResponse{
+				Status:  "success",
+				Message: success,
+			},
+		)
+	default:
+		log.Printf("error internal server error: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusInternalServerError",
+				Errors:  err,
+			},
+		)
+	}
+}
+
+// orderIDs parses the outlet and order of the path, message tells which one is invalid.
+func orderIDs(c *fiber.Ctx) (outletId uuid.UUID, orderId uuid.UUID, message string) {
+	outletId, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return outletId, orderId, "outlet id is invalid"
+	}
+
+	orderId, err = uuid.Parse(c.Params("orderId"))
+	if err != nil {
+		return outletId, orderId, "order id is invalid"
+	}
+
+	return outletId, orderId, ""
+}
@@ -2,10 +2,13 @@ package http
 
 import (
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 	"github.com/rehandwi03/test-case-backend-majoo/criteria"
 	custom_error "github.com/rehandwi03/test-case-backend-majoo/internal/error"
 	"github.com/rehandwi03/test-case-backend-majoo/internal/helper"
 	"github.com/rehandwi03/test-case-backend-majoo/internal/middleware"
+	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"github.com/rehandwi03/test-case-backend-majoo/query"
 	request2 "github.com/rehandwi03/test-case-backend-majoo/request"
 	"github.com/rehandwi03/test-case-backend-majoo/service"
 	"github.com/rehandwi03/test-case-backend-majoo/util"
@@ -19,11 +22,15 @@ type outletHandler struct {
 func NewOutletHandler(app fiber.Router, outletService service.OutletService) {
 	handler := outletHandler{outletSvc: outletService}
 
-	app.Post("/outlets", middleware.JwtProtected(), handler.saveOutlet)
-	app.Get("/outlets/:id", middleware.JwtProtected(), handler.getByID)
-	app.Put("/outlets", middleware.JwtProtected(), handler.updateOutlet)
-	app.Delete("/outlets/:id", middleware.JwtProtected(), handler.deleteByID)
-	app.Get("/outlets", middleware.JwtProtected(), handler.fetch)
+	anyRole := middleware.RequireRole(model.RoleOwner, model.RoleManager, model.RoleCashier)
+	managers := middleware.RequireRole(model.RoleOwner, model.RoleManager)
+
+	app.Post("/outlets", middleware.JwtProtected(), managers, handler.saveOutlet)
+	app.Get("/outlets/:id", middleware.JwtProtected(), anyRole, handler.getByID)
+	app.Put("/outlets", middleware.JwtProtected(), managers, handler.updateOutlet)
+	app.Delete("/outlets/:id", middleware.JwtProtected(), managers, handler.deleteByID)
+	app.Post("/outlets/:id/restore", middleware.JwtProtected(), managers, handler.restore)
+	app.Get("/outlets", middleware.JwtProtected(), anyRole, handler.fetch)
 }
 
 func (o *outletHandler) fetch(c *fiber.Ctx) error {
@@ -35,9 +42,37 @@ func (o *outletHandler) fetch(c *fiber.Ctx) error {
 
 	OutletCriteria.Name = c.Query("name")
 	OutletCriteria.Location = c.Query("location")
+	OutletCriteria.IncludeDeleted = c.Query("include_deleted") == "true"
 
 	res, err := o.outletSvc.Fetch(c.Context(), OutletCriteria)
 	switch err.(type) {
+	case *custom_error.NotFoundError:
+		log.Printf("error not found: %v", err)
+		return c.Status(fiber.StatusNotFound).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusNotFound",
+				Errors:  err,
+			},
+		)
+	case *custom_error.BadRequest:
+		log.Printf("error bad request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
 	case nil:
 		return c.Status(fiber.StatusOK).JSON(
 			res,
@@ -67,14 +102,7 @@ func (o *outletHandler) deleteByID(c *fiber.Ctx) error {
 		)
 	}
 
-	params := map[string]interface{}{
-		"or": map[string]interface{}{},
-		"where": map[string]interface{}{
-			"default": map[string]interface{}{
-				"id = ?": id,
-			},
-		},
-	}
+	params := query.Where(query.Eq("id", id))
 
 	err := o.outletSvc.DeleteOutlet(c.Context(), params)
 	switch err.(type) {
@@ -87,6 +115,24 @@ func (o *outletHandler) deleteByID(c *fiber.Ctx) error {
 				Errors:  err,
 			},
 		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ConflictError:
+		log.Printf("error conflict: %v", err)
+		return c.Status(fiber.StatusConflict).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusConflict",
+				Errors:  err,
+			},
+		)
 	case nil:
 		return c.Status(fiber.StatusOK).JSON(
 			helper.SuccessResponse{
@@ -106,6 +152,67 @@ func (o *outletHandler) deleteByID(c *fiber.Ctx) error {
 	}
 }
 
+func (o *outletHandler) restore(c *fiber.Ctx) error {
+	outletId, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		log.Printf("error parsing outlet id: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  "outlet id is invalid",
+			},
+		)
+	}
+
+	err = o.outletSvc.RestoreOutlet(c.Context(), outletId)
+	switch err.(type) {
+	case *custom_error.NotFoundError:
+		log.Printf("error not found: %v", err)
+		return c.Status(fiber.StatusNotFound).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusNotFound",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ConflictError:
+		log.Printf("error conflict: %v", err)
+		return c.Status(fiber.StatusConflict).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusConflict",
+				Errors:  err,
+			},
+		)
+	case nil:
+		return c.Status(fiber.StatusOK).JSON(
+			helper.SuccessResponse{
+				Status:  "success",
+				Message: "success restore data",
+			},
+		)
+	default:
+		log.Printf("error internal server error: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusInternalServerError",
+				Errors:  err,
+			},
+		)
+	}
+}
+
 func (o *outletHandler) getByID(c *fiber.Ctx) error {
 	id := c.Params("id")
 	if id == "" {
@@ -119,14 +226,7 @@ func (o *outletHandler) getByID(c *fiber.Ctx) error {
 		)
 	}
 
-	params := map[string]interface{}{
-		"or": map[string]interface{}{},
-		"where": map[string]interface{}{
-			"default": map[string]interface{}{
-				"id = ?": id,
-			},
-		},
-	}
+	params := query.Where(query.Eq("id", id))
 
 	res, err := o.outletSvc.GetByParam(c.Context(), params)
 	switch err.(type) {
@@ -139,6 +239,15 @@ func (o *outletHandler) getByID(c *fiber.Ctx) error {
 				Errors:  err,
 			},
 		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
 	case nil:
 		return c.Status(fiber.StatusOK).JSON(
 			helper.SuccessResponse{
@@ -206,6 +315,24 @@ func (o *outletHandler) updateOutlet(c *fiber.Ctx) error {
 				Errors:  err,
 			},
 		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ConflictError:
+		log.Printf("error conflict: %v", err)
+		return c.Status(fiber.StatusConflict).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusConflict",
+				Errors:  err,
+			},
+		)
 	case nil:
 		return c.Status(fiber.StatusOK).JSON(
 			helper.SuccessResponse{
@@ -266,6 +393,24 @@ func (o *outletHandler) saveOutlet(c *fiber.Ctx) error {
 				Errors:  err,
 			},
 		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ConflictError:
+		log.Printf("error conflict: %v", err)
+		return c.Status(fiber.StatusConflict).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusConflict",
+				Errors:  err,
+			},
+		)
 	case nil:
 		return c.Status(fiber.StatusCreated).JSON(
 			helper.SuccessResponse{
@@ -0,0 +1,182 @@
+package http
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	custom_error "github.com/rehandwi03/test-case-backend-majoo/internal/error"
+	"github.com/rehandwi03/test-case-backend-majoo/internal/helper"
+	"github.com/rehandwi03/test-case-backend-majoo/internal/middleware"
+	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"github.com/rehandwi03/test-case-backend-majoo/service"
+	"log"
+)
+
+type paymentHandler struct {
+	paymentSvc service.PaymentService
+}
+
+func NewPaymentHandler(app fiber.Router, paymentService service.PaymentService) {
+	handler := paymentHandler{paymentSvc: paymentService}
+
+	anyRole := middleware.RequireRole(model.RoleOwner, model.RoleManager, model.RoleCashier)
+
+	// payment providers call back without a token, the callbacks are signed instead
+	app.Post("/payments/callbacks/:provider", handler.callback)
+	app.Post(
+		"/outlets/:id/transactions/:transactionId/payments/:paymentId/refresh", middleware.JwtProtected(), anyRole,
+		handler.refresh,
+	)
+}
+
+func (p *paymentHandler) callback(c *fiber.Ctx) error {
+	err := p.paymentSvc.Callback(c.Context(), c.Params("provider"), c.Body(), c.Get("X-Callback-Signature"))
+	switch err.(type) {
+	case *custom_error.NotFoundError:
+		log.Printf("error not found: %v", err)
+		return c.Status(fiber.StatusNotFound).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusNotFound",
+				Errors:  err,
+			},
+		)
+	case *custom_error.UnauthorizedError:
+		log.Printf("error unauthorized: %v", err)
+		return c.Status(fiber.StatusUnauthorized).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusUnauthorized",
+				Errors:  err,
+			},
+		)
+	case *custom_error.BadRequest:
+		log.Printf("error bad request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ConflictError:
+		log.Printf("error conflict: %v", err)
+		return c.Status(fiber.StatusConflict).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusConflict",
+				Errors:  err,
+			},
+		)
+	case nil:
+		return c.Status(fiber.StatusOK).JSON(
+			helper.SuccessResponse{
+				Status:  "success",
+				Message: "success update data",
+			},
+		)
+	default:
+		log.Printf("error internal server error: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusInternalServerError",
+				Errors:  err,
+			},
+		)
+	}
+}
+
+func (p *paymentHandler) refresh(c *fiber.Ctx) error {
+	outletId, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		log.Printf("error parsing outlet id: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  "outlet id is invalid",
+			},
+		)
+	}
+
+	transactionId, err := uuid.Parse(c.Params("transactionId"))
+	if err != nil {
+		log.Printf("error parsing transaction id: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  "transaction id is invalid",
+			},
+		)
+	}
+
+	paymentId, err := uuid.Parse(c.Params("paymentId"))
+	if err != nil {
+		log.Printf("error parsing payment id: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  "payment id is invalid",
+			},
+		)
+	}
+
+	res, err := p.paymentSvc.Refresh(c.Context(), outletId, transactionId, paymentId)
+	switch err.(type) {
+	case *custom_error.NotFoundError:
+		log.Printf("error not found: %v", err)
+		return c.Status(fiber.StatusNotFound).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusNotFound",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
+	case *custom_error.BadRequest:
+		log.Printf("error bad request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ConflictError:
+		log.Printf("error conflict: %v", err)
+		return c.Status(fiber.StatusConflict).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusConflict",
+				Errors:  err,
+			},
+		)
+	case nil:
+		return c.Status(fiber.StatusOK).JSON(
+			helper.SuccessResponse{
+				Status:  "success",
+				Message: "success update data",
+				Data:    res,
+			},
+		)
+	default:
+		log.Printf("error internal server error: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusInternalServerError",
+				Errors:  err,
+			},
+		)
+	}
+}
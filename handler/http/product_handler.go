@@ -3,15 +3,19 @@ package http
 import (
 	"fmt"
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 	"github.com/rehandwi03/test-case-backend-majoo/criteria"
 	custom_error "github.com/rehandwi03/test-case-backend-majoo/internal/error"
 	"github.com/rehandwi03/test-case-backend-majoo/internal/helper"
 	"github.com/rehandwi03/test-case-backend-majoo/internal/middleware"
+	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"github.com/rehandwi03/test-case-backend-majoo/query"
 	request2 "github.com/rehandwi03/test-case-backend-majoo/request"
 	"github.com/rehandwi03/test-case-backend-majoo/service"
 	"github.com/rehandwi03/test-case-backend-majoo/util"
 	"log"
 	"math/rand"
+	"os"
 	"strconv"
 	"strings"
 )
@@ -23,12 +27,28 @@ type productHandler struct {
 func NewProductHandler(app fiber.Router, productService service.ProductService) {
 	handler := productHandler{productSvc: productService}
 
-	app.Post("/products", middleware.JwtProtected(), handler.saveProduct)
-	app.Get("/products/:id", middleware.JwtProtected(), handler.getByID)
-	app.Put("/products", middleware.JwtProtected(), handler.updateProduct)
-	app.Delete("/products/:id", middleware.JwtProtected(), handler.deleteByID)
-	app.Get("/products", middleware.JwtProtected(), handler.fetch)
-	app.Post("/products/image", middleware.JwtProtected(), handler.uploadImage)
+	anyRole := middleware.RequireRole(model.RoleOwner, model.RoleManager, model.RoleCashier)
+	managers := middleware.RequireRole(model.RoleOwner, model.RoleManager)
+
+	app.Post("/products", middleware.JwtProtected(), managers, handler.saveProduct)
+	app.Get("/products/:id", middleware.JwtProtected(), anyRole, handler.getByID)
+	app.Put("/products", middleware.JwtProtected(), managers, handler.updateProduct)
+	app.Delete("/products/:id", middleware.JwtProtected(), managers, handler.deleteByID)
+	app.Post("/products/:id/restore", middleware.JwtProtected(), managers, handler.restore)
+	app.Put("/products/:id/categories", middleware.JwtProtected(), managers, handler.setCategories)
+	app.Put("/products/:id/recipe", middleware.JwtProtected(), managers, handler.setRecipe)
+	app.Post("/products/:id/modifier-groups", middleware.JwtProtected(), managers, handler.saveModifierGroup)
+	app.Put(
+		"/products/:id/modifier-groups/:groupId", middleware.JwtProtected(), managers, handler.updateModifierGroup,
+	)
+	app.Delete(
+		"/products/:id/modifier-groups/:groupId", middleware.JwtProtected(), managers, handler.deleteModifierGroup,
+	)
+	app.Post("/products/:id/bundle-slots", middleware.JwtProtected(), managers, handler.saveBundleSlot)
+	app.Put("/products/:id/bundle-slots/:slotId", middleware.JwtProtected(), managers, handler.updateBundleSlot)
+	app.Delete("/products/:id/bundle-slots/:slotId", middleware.JwtProtected(), managers, handler.deleteBundleSlot)
+	app.Get("/products", middleware.JwtProtected(), anyRole, handler.fetch)
+	app.Post("/products/image", middleware.JwtProtected(), managers, handler.uploadImage)
 
 }
 
@@ -46,7 +66,7 @@ func (p *productHandler) uploadImage(c *fiber.Ctx) error {
 
 	productId := form.Value["product_id"]
 
-	if len(productId) < 0 {
+	if len(productId) == 0 {
 		return c.Status(fiber.StatusBadRequest).JSON(
 			helper.ErrorResponse{
 				Message: "StatusBadRequest",
@@ -59,26 +79,68 @@ func (p *productHandler) uploadImage(c *fiber.Ctx) error {
 	files := form.File["image"]
 
 	for _, file := range files {
-		fmt.Println(file.Filename, file.Size, file.Header["Content-Type"][0])
-
-		header := file.Header["Content-Type"][0]
-
-		splitHeader := strings.Split(header, "/")
-		if splitHeader[0] != "image" {
-			return &custom_error.BadRequest{Message: "file format isn't image"}
-
+		// a part without a Content-Type has an empty one
+		if !strings.HasPrefix(file.Header.Get("Content-Type"), "image/") {
+			return c.Status(fiber.StatusBadRequest).JSON(
+				helper.ErrorResponse{
+					Message: "StatusBadRequest",
+					Status:  "failed",
+					Errors:  &custom_error.BadRequest{Message: "file format isn't image", Field: "image"},
+				},
+			)
 		}
 
 		fileName := strconv.Itoa(rand.Int()) + file.Filename
+		path := fmt.Sprintf("./internal/file/%s", fileName)
 
-		if err := c.SaveFile(
-			file, fmt.Sprintf("./internal/file/%s", fileName),
-		); err != nil {
-			return err
+		stored := false
+		err = p.productSvc.SaveProductIDImage(
+			c.Context(), productId[0], fileName, func() error {
+				if err := c.SaveFile(file, path); err != nil {
+					return err
+				}
+				stored = true
+
+				return nil
+			},
+		)
+		if err != nil && stored {
+			// the product can't reference the image, so don't keep it around
+			if err := os.Remove(path); err != nil {
+				log.Printf("error removing image: %v", err)
+			}
 		}
 
-		err = p.productSvc.SaveProductIDImage(c.Context(), productId[0], fileName)
-		if err != nil {
+		switch err.(type) {
+		case *custom_error.NotFoundError:
+			log.Printf("error not found: %v", err)
+			return c.Status(fiber.StatusNotFound).JSON(
+				helper.ErrorResponse{
+					Status:  "failed",
+					Message: "StatusNotFound",
+					Errors:  err,
+				},
+			)
+		case *custom_error.ForbiddenError:
+			log.Printf("error forbidden: %v", err)
+			return c.Status(fiber.StatusForbidden).JSON(
+				helper.ErrorResponse{
+					Status:  "failed",
+					Message: "StatusForbidden",
+					Errors:  err,
+				},
+			)
+		case *custom_error.BadRequest:
+			log.Printf("error bad request: %v", err)
+			return c.Status(fiber.StatusBadRequest).JSON(
+				helper.ErrorResponse{
+					Status:  "failed",
+					Message: "StatusBadRequest",
+					Errors:  err,
+				},
+			)
+		case nil:
+		default:
 			log.Println(err)
 			return c.Status(fiber.StatusInternalServerError).JSON(
 				helper.ErrorResponse{
@@ -109,9 +171,50 @@ func (p *productHandler) fetch(c *fiber.Ctx) error {
 	productCriteria.Name = c.Query("name")
 	productCriteria.Stock = c.Query("stock")
 	productCriteria.Price = c.Query("price")
+	productCriteria.Keyword = c.Query("keyword")
+	productCriteria.Barcode = c.Query("barcode")
+	productCriteria.Ingredient = c.Query("ingredient")
+	productCriteria.MinStock = c.Query("min_stock")
+	productCriteria.MaxStock = c.Query("max_stock")
+	productCriteria.MinPrice = c.Query("min_price")
+	productCriteria.MaxPrice = c.Query("max_price")
+	if outletIds := c.Query("outlet_id"); outletIds != "" {
+		productCriteria.OutletIDs = strings.Split(outletIds, ",")
+	}
+	if categoryIds := c.Query("category_id"); categoryIds != "" {
+		productCriteria.CategoryIDs = strings.Split(categoryIds, ",")
+	}
+	productCriteria.IncludeDeleted = c.Query("include_deleted") == "true"
 
 	res, err := p.productSvc.Fetch(c.Context(), productCriteria)
 	switch err.(type) {
+	case *custom_error.NotFoundError:
+		log.Printf("error not found: %v", err)
+		return c.Status(fiber.StatusNotFound).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusNotFound",
+				Errors:  err,
+			},
+		)
+	case *custom_error.BadRequest:
+		log.Printf("error bad request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
 	case nil:
 		return c.Status(fiber.StatusOK).JSON(
 			res,
@@ -141,14 +244,7 @@ func (p *productHandler) deleteByID(c *fiber.Ctx) error {
 		)
 	}
 
-	params := map[string]interface{}{
-		"or": map[string]interface{}{},
-		"where": map[string]interface{}{
-			"default": map[string]interface{}{
-				"id = ?": id,
-			},
-		},
-	}
+	params := query.Where(query.Eq("id", id))
 
 	err := p.productSvc.DeleteProduct(c.Context(), params)
 	switch err.(type) {
@@ -161,6 +257,24 @@ func (p *productHandler) deleteByID(c *fiber.Ctx) error {
 				Errors:  err,
 			},
 		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ConflictError:
+		log.Printf("error conflict: %v", err)
+		return c.Status(fiber.StatusConflict).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusConflict",
+				Errors:  err,
+			},
+		)
 	case nil:
 		return c.Status(fiber.StatusOK).JSON(
 			helper.SuccessResponse{
@@ -180,29 +294,108 @@ func (p *productHandler) deleteByID(c *fiber.Ctx) error {
 	}
 }
 
-func (p *productHandler) getByID(c *fiber.Ctx) error {
-	id := c.Params("id")
-	if id == "" {
-		log.Printf("error id is null")
+func (p *productHandler) restore(c *fiber.Ctx) error {
+	productId, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		log.Printf("error parsing product id: %v", err)
 		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  "product id is invalid",
+			},
+		)
+	}
+
+	err = p.productSvc.RestoreProduct(c.Context(), productId)
+	switch err.(type) {
+	case *custom_error.NotFoundError:
+		log.Printf("error not found: %v", err)
+		return c.Status(fiber.StatusNotFound).JSON(
 			helper.ErrorResponse{
 				Status:  "failed",
 				Message: "StatusNotFound",
-				Errors:  "id param is null",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ConflictError:
+		log.Printf("error conflict: %v", err)
+		return c.Status(fiber.StatusConflict).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusConflict",
+				Errors:  err,
+			},
+		)
+	case nil:
+		return c.Status(fiber.StatusOK).JSON(
+			helper.SuccessResponse{
+				Status:  "success",
+				Message: "success restore data",
+			},
+		)
+	default:
+		log.Printf("error internal server error: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusInternalServerError",
+				Errors:  err,
+			},
+		)
+	}
+}
+
+func (p *productHandler) setCategories(c *fiber.Ctx) error {
+	productId, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		log.Printf("error parsing product id: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  "product id is invalid",
 			},
 		)
 	}
 
-	params := map[string]interface{}{
-		"or": map[string]interface{}{},
-		"where": map[string]interface{}{
-			"default": map[string]interface{}{
-				"id = ?": id,
+	request := new(request2.ProductCategoriesRequest)
+
+	err = c.BodyParser(&request)
+	if err != nil {
+		log.Printf("error parsing request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  err.Error(),
 			},
-		},
+		)
 	}
+	request.ProductID = productId
 
-	res, err := p.productSvc.GetByParam(c.Context(), params)
+	errors := helper.ValidateRequest(*request)
+	if errors != nil {
+		log.Printf("error validate request: %v", errors)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  errors,
+			},
+		)
+	}
+
+	err = p.productSvc.SetProductCategories(c.Context(), request)
 	switch err.(type) {
 	case *custom_error.NotFoundError:
 		log.Printf("error not found: %v", err)
@@ -213,12 +406,29 @@ func (p *productHandler) getByID(c *fiber.Ctx) error {
 				Errors:  err,
 			},
 		)
+	case *custom_error.BadRequest:
+		log.Printf("error bad request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
 	case nil:
 		return c.Status(fiber.StatusOK).JSON(
 			helper.SuccessResponse{
 				Status:  "success",
-				Message: "success get data",
-				Data:    res,
+				Message: "success update data",
 			},
 		)
 	default:
@@ -233,10 +443,22 @@ func (p *productHandler) getByID(c *fiber.Ctx) error {
 	}
 }
 
-func (p *productHandler) updateProduct(c *fiber.Ctx) error {
-	request := new(request2.ProductUpdateRequest)
+func (p *productHandler) setRecipe(c *fiber.Ctx) error {
+	productId, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		log.Printf("error parsing product id: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  "product id is invalid",
+			},
+		)
+	}
 
-	err := c.BodyParser(&request)
+	request := new(request2.ProductRecipeRequest)
+
+	err = c.BodyParser(&request)
 	if err != nil {
 		log.Printf("error parsing request: %v", err)
 		return c.Status(fiber.StatusBadRequest).JSON(
@@ -247,10 +469,11 @@ func (p *productHandler) updateProduct(c *fiber.Ctx) error {
 			},
 		)
 	}
+	request.ProductID = productId
 
 	errors := helper.ValidateRequest(*request)
 	if errors != nil {
-		log.Printf("error validate request: %v", err)
+		log.Printf("error validate request: %v", errors)
 		return c.Status(fiber.StatusBadRequest).JSON(
 			helper.ErrorResponse{
 				Status:  "failed",
@@ -260,7 +483,7 @@ func (p *productHandler) updateProduct(c *fiber.Ctx) error {
 		)
 	}
 
-	res, err := p.productSvc.UpdateProduct(c.Context(), request)
+	err = p.productSvc.SetProductRecipe(c.Context(), request)
 	switch err.(type) {
 	case *custom_error.NotFoundError:
 		log.Printf("error not found: %v", err)
@@ -280,14 +503,20 @@ func (p *productHandler) updateProduct(c *fiber.Ctx) error {
 				Errors:  err,
 			},
 		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
 	case nil:
 		return c.Status(fiber.StatusOK).JSON(
 			helper.SuccessResponse{
 				Status:  "success",
 				Message: "success update data",
-				Data: map[string]interface{}{
-					"product_id": res,
-				},
 			},
 		)
 	default:
@@ -302,8 +531,63 @@ func (p *productHandler) updateProduct(c *fiber.Ctx) error {
 	}
 }
 
-func (p *productHandler) saveProduct(c *fiber.Ctx) error {
-	request := new(request2.ProductAddRequest)
+func (p *productHandler) getByID(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if id == "" {
+		log.Printf("error id is null")
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusNotFound",
+				Errors:  "id param is null",
+			},
+		)
+	}
+
+	params := query.Where(query.Eq("id", id))
+
+	res, err := p.productSvc.GetByParam(c.Context(), params)
+	switch err.(type) {
+	case *custom_error.NotFoundError:
+		log.Printf("error not found: %v", err)
+		return c.Status(fiber.StatusNotFound).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusNotFound",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
+	case nil:
+		return c.Status(fiber.StatusOK).JSON(
+			helper.SuccessResponse{
+				Status:  "success",
+				Message: "success get data",
+				Data:    res,
+			},
+		)
+	default:
+		log.Printf("error internal server error: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusInternalServerError",
+				Errors:  err,
+			},
+		)
+	}
+}
+
+func (p *productHandler) updateProduct(c *fiber.Ctx) error {
+	request := new(request2.ProductUpdateRequest)
 
 	err := c.BodyParser(&request)
 	if err != nil {
@@ -329,8 +613,17 @@ func (p *productHandler) saveProduct(c *fiber.Ctx) error {
 		)
 	}
 
-	res, err := p.productSvc.SaveProduct(c.Context(), request)
+	res, err := p.productSvc.UpdateProduct(c.Context(), request)
 	switch err.(type) {
+	case *custom_error.NotFoundError:
+		log.Printf("error not found: %v", err)
+		return c.Status(fiber.StatusNotFound).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusNotFound",
+				Errors:  err,
+			},
+		)
 	case *custom_error.BadRequest:
 		log.Printf("error bad request: %v", err)
 		return c.Status(fiber.StatusBadRequest).JSON(
@@ -340,11 +633,29 @@ func (p *productHandler) saveProduct(c *fiber.Ctx) error {
 				Errors:  err,
 			},
 		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ConflictError:
+		log.Printf("error conflict: %v", err)
+		return c.Status(fiber.StatusConflict).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusConflict",
+				Errors:  err,
+			},
+		)
 	case nil:
-		return c.Status(fiber.StatusCreated).JSON(
+		return c.Status(fiber.StatusOK).JSON(
 			helper.SuccessResponse{
 				Status:  "success",
-				Message: "success add data",
+				Message: "success update data",
 				Data: map[string]interface{}{
 					"product_id": res,
 				},
@@ -361,3 +672,680 @@ func (p *productHandler) saveProduct(c *fiber.Ctx) error {
 		)
 	}
 }
+
+func (p *productHandler) saveProduct(c *fiber.Ctx) error {
+	request := new(request2.ProductAddRequest)
+
+	err := c.BodyParser(&request)
+	if err != nil {
+		log.Printf("error parsing request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  err.Error(),
+			},
+		)
+	}
+
+	errors := helper.ValidateRequest(*request)
+	if errors != nil {
+		log.Printf("error validate request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  errors,
+			},
+		)
+	}
+
+	res, err := p.productSvc.SaveProduct(c.Context(), request)
+	switch err.(type) {
+	case *custom_error.BadRequest:
+		log.Printf("error bad request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  err,
+			},
+		)
+	case *custom_error.NotFoundError:
+		log.Printf("error not found: %v", err)
+		return c.Status(fiber.StatusNotFound).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusNotFound",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ConflictError:
+		log.Printf("error conflict: %v", err)
+		return c.Status(fiber.StatusConflict).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusConflict",
+				Errors:  err,
+			},
+		)
+	case nil:
+		return c.Status(fiber.StatusCreated).JSON(
+			helper.SuccessResponse{
+				Status:  "success",
+				Message: "success add data",
+				Data: map[string]interface{}{
+					"product_id": res,
+				},
+			},
+		)
+	default:
+		log.Printf("error internal server error: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusInternalServerError",
+				Errors:  err,
+			},
+		)
+	}
+}
+
+func (p *productHandler) saveModifierGroup(c *fiber.Ctx) error {
+	productId, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		log.Printf("error parsing product id: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  "product id is invalid",
+			},
+		)
+	}
+
+	request := new(request2.ModifierGroupAddRequest)
+
+	err = c.BodyParser(&request)
+	if err != nil {
+		log.Printf("error parsing request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  err.Error(),
+			},
+		)
+	}
+	request.ProductID = productId
+
+	errors := helper.ValidateRequest(*request)
+	if errors != nil {
+		log.Printf("error validate request: %v", errors)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  errors,
+			},
+		)
+	}
+
+	res, err := p.productSvc.SaveModifierGroup(c.Context(), request)
+	switch err.(type) {
+	case *custom_error.NotFoundError:
+		log.Printf("error not found: %v", err)
+		return c.Status(fiber.StatusNotFound).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusNotFound",
+				Errors:  err,
+			},
+		)
+	case *custom_error.BadRequest:
+		log.Printf("error bad request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ConflictError:
+		log.Printf("error conflict: %v", err)
+		return c.Status(fiber.StatusConflict).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusConflict",
+				Errors:  err,
+			},
+		)
+	case nil:
+		return c.Status(fiber.StatusCreated).JSON(
+			helper.SuccessResponse{
+				Status:  "success",
+				Message: "success add data",
+				Data: map[string]interface{}{
+					"modifier_group_id": res,
+				},
+			},
+		)
+	default:
+		log.Printf("error internal server error: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusInternalServerError",
+				Errors:  err,
+			},
+		)
+	}
+}
+
+func (p *productHandler) updateModifierGroup(c *fiber.Ctx) error {
+	productId, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		log.Printf("error parsing product id: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  "product id is invalid",
+			},
+		)
+	}
+
+	groupId, err := uuid.Parse(c.Params("groupId"))
+	if err != nil {
+		log.Printf("error parsing modifier group id: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  "modifier group id is invalid",
+			},
+		)
+	}
+
+	request := new(request2.ModifierGroupUpdateRequest)
+
+	err = c.BodyParser(&request)
+	if err != nil {
+		log.Printf("error parsing request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  err.Error(),
+			},
+		)
+	}
+	request.ID = groupId
+	request.ProductID = productId
+
+	errors := helper.ValidateRequest(*request)
+	if errors != nil {
+		log.Printf("error validate request: %v", errors)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  errors,
+			},
+		)
+	}
+
+	res, err := p.productSvc.UpdateModifierGroup(c.Context(), request)
+	switch err.(type) {
+	case *custom_error.NotFoundError:
+		log.Printf("error not found: %v", err)
+		return c.Status(fiber.StatusNotFound).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusNotFound",
+				Errors:  err,
+			},
+		)
+	case *custom_error.BadRequest:
+		log.Printf("error bad request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ConflictError:
+		log.Printf("error conflict: %v", err)
+		return c.Status(fiber.StatusConflict).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusConflict",
+				Errors:  err,
+			},
+		)
+	case nil:
+		return c.Status(fiber.StatusOK).JSON(
+			helper.SuccessResponse{
+				Status:  "success",
+				Message: "success update data",
+				Data: map[string]interface{}{
+					"modifier_group_id": res,
+				},
+			},
+		)
+	default:
+		log.Printf("error internal server error: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusInternalServerError",
+				Errors:  err,
+			},
+		)
+	}
+}
+
+func (p *productHandler) deleteModifierGroup(c *fiber.Ctx) error {
+	productId, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		log.Printf("error parsing product id: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  "product id is invalid",
+			},
+		)
+	}
+
+	groupId, err := uuid.Parse(c.Params("groupId"))
+	if err != nil {
+		log.Printf("error parsing modifier group id: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  "modifier group id is invalid",
+			},
+		)
+	}
+
+	err = p.productSvc.DeleteModifierGroup(c.Context(), productId, groupId)
+	switch err.(type) {
+	case *custom_error.NotFoundError:
+		log.Printf("error not found: %v", err)
+		return c.Status(fiber.StatusNotFound).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusNotFound",
+				Errors:  err,
+			},
+		)
+	case *custom_error.BadRequest:
+		log.Printf("error bad request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ConflictError:
+		log.Printf("error conflict: %v", err)
+		return c.Status(fiber.StatusConflict).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusConflict",
+				Errors:  err,
+			},
+		)
+	case nil:
+		return c.Status(fiber.StatusOK).JSON(
+			helper.SuccessResponse{
+				Status:  "success",
+				Message: "success delete data",
+			},
+		)
+	default:
+		log.Printf("error internal server error: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusInternalServerError",
+				Errors:  err,
+			},
+		)
+	}
+}
+
+func (p *productHandler) saveBundleSlot(c *fiber.Ctx) error {
+	productId, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		log.Printf("error parsing product id: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  "product id is invalid",
+			},
+		)
+	}
+
+	request := new(request2.BundleSlotAddRequest)
+
+	err = c.BodyParser(&request)
+	if err != nil {
+		log.Printf("error parsing request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  err.Error(),
+			},
+		)
+	}
+	request.ProductID = productId
+
+	errors := helper.ValidateRequest(*request)
+	if errors != nil {
+		log.Printf("error validate request: %v", errors)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  errors,
+			},
+		)
+	}
+
+	res, err := p.productSvc.SaveBundleSlot(c.Context(), request)
+	switch err.(type) {
+	case *custom_error.NotFoundError:
+		log.Printf("error not found: %v", err)
+		return c.Status(fiber.StatusNotFound).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusNotFound",
+				Errors:  err,
+			},
+		)
+	case *custom_error.BadRequest:
+		log.Printf("error bad request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ConflictError:
+		log.Printf("error conflict: %v", err)
+		return c.Status(fiber.StatusConflict).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusConflict",
+				Errors:  err,
+			},
+		)
+	case nil:
+		return c.Status(fiber.StatusCreated).JSON(
+			helper.SuccessResponse{
+				Status:  "success",
+				Message: "success add data",
+				Data: map[string]interface{}{
+					"bundle_slot_id": res,
+				},
+			},
+		)
+	default:
+		log.Printf("error internal server error: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusInternalServerError",
+				Errors:  err,
+			},
+		)
+	}
+}
+
+func (p *productHandler) updateBundleSlot(c *fiber.Ctx) error {
+	productId, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		log.Printf("error parsing product id: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  "product id is invalid",
+			},
+		)
+	}
+
+	slotId, err := uuid.Parse(c.Params("slotId"))
+	if err != nil {
+		log.Printf("error parsing bundle slot id: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  "bundle slot id is invalid",
+			},
+		)
+	}
+
+	request := new(request2.BundleSlotUpdateRequest)
+
+	err = c.BodyParser(&request)
+	if err != nil {
+		log.Printf("error parsing request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  err.Error(),
+			},
+		)
+	}
+	request.ID = slotId
+	request.ProductID = productId
+
+	errors := helper.ValidateRequest(*request)
+	if errors != nil {
+		log.Printf("error validate request: %v", errors)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  errors,
+			},
+		)
+	}
+
+	res, err := p.productSvc.UpdateBundleSlot(c.Context(), request)
+	switch err.(type) {
+	case *custom_error.NotFoundError:
+		log.Printf("error not found: %v", err)
+		return c.Status(fiber.StatusNotFound).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusNotFound",
+				Errors:  err,
+			},
+		)
+	case *custom_error.BadRequest:
+		log.Printf("error bad request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ConflictError:
+		log.Printf("error conflict: %v", err)
+		return c.Status(fiber.StatusConflict).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusConflict",
+				Errors:  err,
+			},
+		)
+	case nil:
+		return c.Status(fiber.StatusOK).JSON(
+			helper.SuccessResponse{
+				Status:  "success",
+				Message: "success update data",
+				Data: map[string]interface{}{
+					"bundle_slot_id": res,
+				},
+			},
+		)
+	default:
+		log.Printf("error internal server error: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusInternalServerError",
+				Errors:  err,
+			},
+		)
+	}
+}
+
+func (p *productHandler) deleteBundleSlot(c *fiber.Ctx) error {
+	productId, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		log.Printf("error parsing product id: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  "product id is invalid",
+			},
+		)
+	}
+
+	slotId, err := uuid.Parse(c.Params("slotId"))
+	if err != nil {
+		log.Printf("error parsing bundle slot id: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  "bundle slot id is invalid",
+			},
+		)
+	}
+
+	err = p.productSvc.DeleteBundleSlot(c.Context(), productId, slotId)
+	switch err.(type) {
+	case *custom_error.NotFoundError:
+		log.Printf("error not found: %v", err)
+		return c.Status(fiber.StatusNotFound).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusNotFound",
+				Errors:  err,
+			},
+		)
+	case *custom_error.BadRequest:
+		log.Printf("error bad request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ConflictError:
+		log.Printf("error conflict: %v", err)
+		return c.Status(fiber.StatusConflict).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusConflict",
+				Errors:  err,
+			},
+		)
+	case nil:
+		return c.Status(fiber.StatusOK).JSON(
+			helper.SuccessResponse{
+				Status:  "success",
+				Message: "success delete data",
+			},
+		)
+	default:
+		log.Printf("error internal server error: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusInternalServerError",
+				Errors:  err,
+			},
+		)
+	}
+}
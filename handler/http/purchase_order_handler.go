@@ -0,0 +1,434 @@
+package http
+
+import (
+	"context"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rehandwi03/test-case-backend-majoo/criteria"
+	custom_error "github.com/rehandwi03/test-case-backend-majoo/internal/error"
+	"github.com/rehandwi03/test-case-backend-majoo/internal/helper"
+	"github.com/rehandwi03/test-case-backend-majoo/internal/middleware"
+	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"github.com/rehandwi03/test-case-backend-majoo/query"
+	request2 "github.com/rehandwi03/test-case-backend-majoo/request"
+	"github.com/rehandwi03/test-case-backend-majoo/service"
+	"github.com/rehandwi03/test-case-backend-majoo/util"
+	"log"
+)
+
+type purchaseOrderHandler struct {
+	purchaseOrderSvc service.PurchaseOrderService
+}
+
+func NewPurchaseOrderHandler(app fiber.Router, purchaseOrderService service.PurchaseOrderService) {
+	handler := purchaseOrderHandler{purchaseOrderSvc: purchaseOrderService}
+
+	managers := middleware.RequireRole(model.RoleOwner, model.RoleManager)
+	anyRole := middleware.RequireRole(model.RoleOwner, model.RoleManager, model.RoleCashier)
+
+	app.Post("/purchase-orders", middleware.JwtProtected(), managers, handler.save)
+	app.Get("/purchase-orders", middleware.JwtProtected(), anyRole, handler.fetch)
+	app.Get("/purchase-orders/:id", middleware.JwtProtected(), anyRole, handler.getByID)
+	app.Post("/purchase-orders/:id/order", middleware.JwtProtected(), managers, handler.place)
+	app.Post("/purchase-orders/:id/receive", middleware.JwtProtected(), managers, handler.receive)
+	app.Post("/purchase-orders/:id/cancel", middleware.JwtProtected(), managers, handler.cancel)
+	app.Get("/suppliers/:id/purchase-orders", middleware.JwtProtected(), anyRole, handler.fetchBySupplier)
+}
+
+func (p *purchaseOrderHandler) save(c *fiber.Ctx) error {
+	request := new(request2.PurchaseOrderAddRequest)
+
+	err := c.BodyParser(&request)
+	if err != nil {
+		log.Printf("error parsing request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  err.Error(),
+			},
+		)
+	}
+
+	errors := helper.ValidateRequest(*request)
+	if errors != nil {
+		log.Printf("error validate request: %v", errors)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  errors,
+			},
+		)
+	}
+
+	res, err := p.purchaseOrderSvc.SavePurchaseOrder(c.Context(), request)
+	switch err.(type) {
+	case *custom_error.NotFoundError:
+		log.Printf("error not found: %v", err)
+		return c.Status(fiber.StatusNotFound).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusNotFound",
+				Errors:  err,
+			},
+		)
+	case *custom_error.BadRequest:
+		log.Printf("error bad request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ConflictError:
+		log.Printf("error conflict: %v", err)
+		return c.Status(fiber.StatusConflict).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusConflict",
+				Errors:  err,
+			},
+		)
+	case nil:
+		return c.Status(fiber.StatusCreated).JSON(
+			helper.SuccessResponse{
+				Status:  "success",
+				Message: "success add data",
+				Data: map[string]interface{}{
+					"purchase_order_id": res,
+				},
+			},
+		)
+	default:
+		log.Printf("error internal server error: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusInternalServerError",
+				Errors:  err,
+			},
+		)
+	}
+}
+
+func (p *purchaseOrderHandler) fetch(c *fiber.Ctx) error {
+	purchaseOrderCriteria := purchaseOrderCriteriaFromRequest(c)
+	purchaseOrderCriteria.SupplierID = c.Query("supplier_id")
+
+	return p.fetchByCriteria(c, purchaseOrderCriteria)
+}
+
+// fetchBySupplier lists the purchase history of a supplier.
+func (p *purchaseOrderHandler) fetchBySupplier(c *fiber.Ctx) error {
+	purchaseOrderCriteria := purchaseOrderCriteriaFromRequest(c)
+	purchaseOrderCriteria.SupplierID = c.Params("id")
+
+	return p.fetchByCriteria(c, purchaseOrderCriteria)
+}
+
+func purchaseOrderCriteriaFromRequest(c *fiber.Ctx) criteria.PurchaseOrderCriteria {
+	pagination := util.GeneratePaginationFromRequest(c)
+
+	purchaseOrderCriteria := criteria.PurchaseOrderCriteria{
+		Pagination: pagination,
+	}
+
+	purchaseOrderCriteria.OutletID = c.Query("outlet_id")
+	purchaseOrderCriteria.Status = c.Query("status")
+	purchaseOrderCriteria.From = c.Query("from")
+	purchaseOrderCriteria.To = c.Query("to")
+
+	return purchaseOrderCriteria
+}
+
+func (p *purchaseOrderHandler) fetchByCriteria(
+	c *fiber.Ctx, purchaseOrderCriteria criteria.PurchaseOrderCriteria,
+) error {
+	res, err := p.purchaseOrderSvc.Fetch(c.Context(), purchaseOrderCriteria)
+	switch err.(type) {
+	case *custom_error.NotFoundError:
+		log.Printf("error not found: %v", err)
+		return c.Status(fiber.StatusNotFound).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusNotFound",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
+	case *custom_error.BadRequest:
+		log.Printf("error bad request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  err,
+			},
+		)
+	case nil:
+		return c.Status(fiber.StatusOK).JSON(
+			res,
+		)
+	default:
+		log.Printf("error internal server error: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusInternalServerError",
+				Errors:  err,
+			},
+		)
+	}
+}
+
+func (p *purchaseOrderHandler) getByID(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if id == "" {
+		log.Printf("error id is null")
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusNotFound",
+				Errors:  "id param is null",
+			},
+		)
+	}
+
+	params := query.Where(query.Eq("id", id))
+
+	res, err := p.purchaseOrderSvc.GetByParam(c.Context(), params)
+	switch err.(type) {
+	case *custom_error.NotFoundError:
+		log.Printf("error not found: %v", err)
+		return c.Status(fiber.StatusNotFound).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusNotFound",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
+	case nil:
+		return c.Status(fiber.StatusOK).JSON(
+			helper.SuccessResponse{
+				Status:  "success",
+				Message: "success get data",
+				Data:    res,
+			},
+		)
+	default:
+		log.Printf("error internal server error: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusInternalServerError",
+				Errors:  err,
+			},
+		)
+	}
+}
+
+func (p *purchaseOrderHandler) receive(c *fiber.Ctx) error {
+	orderId, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		log.Printf("error parsing purchase order id: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  "purchase order id is invalid",
+			},
+		)
+	}
+
+	request := new(request2.PurchaseOrderReceiveRequest)
+
+	err = c.BodyParser(&request)
+	if err != nil {
+		log.Printf("error parsing request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  err.Error(),
+			},
+		)
+	}
+	request.ID = orderId
+
+	errors := helper.ValidateRequest(*request)
+	if errors != nil {
+		log.Printf("error validate request: %v", errors)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  errors,
+			},
+		)
+	}
+
+	err = p.purchaseOrderSvc.Receive(c.Context(), request)
+	switch err.(type) {
+	case *custom_error.NotFoundError:
+		log.Printf("error not found: %v", err)
+		return c.Status(fiber.StatusNotFound).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusNotFound",
+				Errors:  err,
+			},
+		)
+	case *custom_error.BadRequest:
+		log.Printf("error bad request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ConflictError:
+		log.Printf("error conflict: %v", err)
+		return c.Status(fiber.StatusConflict).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusConflict",
+				Errors:  err,
+			},
+		)
+	case nil:
+		return c.Status(fiber.StatusOK).JSON(
+			helper.SuccessResponse{
+				Status:  "success",
+				Message: "success receive data",
+			},
+		)
+	default:
+		log.Printf("error internal server error: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusInternalServerError",
+				Errors:  err,
+			},
+		)
+	}
+}
+
+func (p *purchaseOrderHandler) place(c *fiber.Ctx) error {
+	return p.act(c, p.purchaseOrderSvc.Place, "success order data")
+}
+
+func (p *purchaseOrderHandler) cancel(c *fiber.Ctx) error {
+	return p.act(c, p.purchaseOrderSvc.Cancel, "success cancel data")
+}
+
+// act moves the purchase order of the request on with the given service call, placing or cancelling it.
+func (p *purchaseOrderHandler) act(
+	c *fiber.Ctx, action func(ctx context.Context, orderId uuid.UUID) error, success string,
+) error {
+	orderId, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		log.Printf("error parsing purchase order id: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  "purchase order id is invalid",
+			},
+		)
+	}
+
+	err = action(c.Context(), orderId)
+	switch err.(type) {
+	case *custom_error.NotFoundError:
+		log.Printf("error not found: %v", err)
+		return c.Status(fiber.StatusNotFound).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusNotFound",
+				Errors:  err,
+			},
+		)
+	case *custom_error.BadRequest:
+		log.Printf("error bad request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ConflictError:
+		log.Printf("error conflict: %v", err)
+		return c.Status(fiber.StatusConflict).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusConflict",
+				Errors:  err,
+			},
+		)
+	case nil:
+		return c.Status(fiber.StatusOK).JSON(
+			helper.SuccessResponse{
+				Status:  "success",
+				Message: success,
+			},
+		)
+	default:
+		log.Printf("error internal server error: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusInternalServerError",
+				Errors:  err,
+			},
+		)
+	}
+}
@@ -0,0 +1,137 @@
+package http
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/rehandwi03/test-case-backend-majoo/criteria"
+	custom_error "github.com/rehandwi03/test-case-backend-majoo/internal/error"
+	"github.com/rehandwi03/test-case-backend-majoo/internal/helper"
+	"github.com/rehandwi03/test-case-backend-majoo/internal/middleware"
+	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"github.com/rehandwi03/test-case-backend-majoo/service"
+	"log"
+)
+
+type reportHandler struct {
+	reportSvc service.ReportService
+}
+
+func NewReportHandler(app fiber.Router, reportService service.ReportService) {
+	handler := reportHandler{reportSvc: reportService}
+
+	managers := middleware.RequireRole(model.RoleOwner, model.RoleManager)
+
+	app.Get("/outlets/:id/margins", middleware.JwtProtected(), managers, handler.outletMargins)
+	app.Get("/products/:id/margins", middleware.JwtProtected(), managers, handler.productMargins)
+}
+
+func (r *reportHandler) outletMargins(c *fiber.Ctx) error {
+	marginCriteria := criteria.MarginCriteria{
+		OutletID: c.Params("id"),
+		From:     c.Query("from"),
+		To:       c.Query("to"),
+	}
+
+	res, err := r.reportSvc.OutletMargins(c.Context(), marginCriteria)
+	switch err.(type) {
+	case *custom_error.NotFoundError:
+		log.Printf("error not found: %v", err)
+		return c.Status(fiber.StatusNotFound).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusNotFound",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
+	case *custom_error.BadRequest:
+		log.Printf("error bad request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  err,
+			},
+		)
+	case nil:
+		return c.Status(fiber.StatusOK).JSON(
+			helper.SuccessResponse{
+				Status:  "success",
+				Message: "success get data",
+				Data:    res,
+			},
+		)
+	default:
+		log.Printf("error internal server error: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusInternalServerError",
+				Errors:  err,
+			},
+		)
+	}
+}
+
+func (r *reportHandler) productMargins(c *fiber.Ctx) error {
+	marginCriteria := criteria.MarginCriteria{
+		ProductID: c.Params("id"),
+		From:      c.Query("from"),
+		To:        c.Query("to"),
+	}
+
+	res, err := r.reportSvc.ProductMargins(c.Context(), marginCriteria)
+	switch err.(type) {
+	case *custom_error.NotFoundError:
+		log.Printf("error not found: %v", err)
+		return c.Status(fiber.StatusNotFound).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusNotFound",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
+	case *custom_error.BadRequest:
+		log.Printf("error bad request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  err,
+			},
+		)
+	case nil:
+		return c.Status(fiber.StatusOK).JSON(
+			helper.SuccessResponse{
+				Status:  "success",
+				Message: "success get data",
+				Data:    res,
+			},
+		)
+	default:
+		log.Printf("error internal server error: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusInternalServerError",
+				Errors:  err,
+			},
+		)
+	}
+}
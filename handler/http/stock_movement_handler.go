@@ -0,0 +1,196 @@
+package http
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rehandwi03/test-case-backend-majoo/criteria"
+	custom_error "github.com/rehandwi03/test-case-backend-majoo/internal/error"
+	"github.com/rehandwi03/test-case-backend-majoo/internal/helper"
+	"github.com/rehandwi03/test-case-backend-majoo/internal/middleware"
+	"github.com/rehandwi03/test-case-backend-majoo/model"
+	request2 "github.com/rehandwi03/test-case-backend-majoo/request"
+	"github.com/rehandwi03/test-case-backend-majoo/service"
+	"github.com/rehandwi03/test-case-backend-majoo/util"
+	"log"
+)
+
+type stockMovementHandler struct {
+	stockMovementSvc service.StockMovementService
+}
+
+func NewStockMovementHandler(app fiber.Router, stockMovementService service.StockMovementService) {
+	handler := stockMovementHandler{stockMovementSvc: stockMovementService}
+
+	managers := middleware.RequireRole(model.RoleOwner, model.RoleManager)
+	anyRole := middleware.RequireRole(model.RoleOwner, model.RoleManager, model.RoleCashier)
+
+	app.Post("/products/:id/stock-movements", middleware.JwtProtected(), managers, handler.postAdjustment)
+	app.Get("/products/:id/stock-movements", middleware.JwtProtected(), anyRole, handler.fetchByProduct)
+	app.Get("/outlets/:id/stock-movements", middleware.JwtProtected(), anyRole, handler.fetchByOutlet)
+}
+
+func (s *stockMovementHandler) postAdjustment(c *fiber.Ctx) error {
+	productId, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		log.Printf("error parsing product id: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  "product id is invalid",
+			},
+		)
+	}
+
+	request := new(request2.StockAdjustmentRequest)
+
+	err = c.BodyParser(&request)
+	if err != nil {
+		log.Printf("error parsing request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  err.Error(),
+			},
+		)
+	}
+	request.ProductID = productId
+
+	errors := helper.ValidateRequest(*request)
+	if errors != nil {
+		log.Printf("error validate request: %v", errors)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  errors,
+			},
+		)
+	}
+
+	res, err := s.stockMovementSvc.PostAdjustment(c.Context(), request)
+	switch err.(type) {
+	case *custom_error.NotFoundError:
+		log.Printf("error not found: %v", err)
+		return c.Status(fiber.StatusNotFound).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusNotFound",
+				Errors:  err,
+			},
+		)
+	case *custom_error.BadRequest:
+		log.Printf("error bad request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
+	case nil:
+		return c.Status(fiber.StatusCreated).JSON(
+			helper.SuccessResponse{
+				Status:  "success",
+				Message: "success add data",
+				Data: map[string]interface{}{
+					"stock_movement_id": res,
+				},
+			},
+		)
+	default:
+		log.Printf("error internal server error: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusInternalServerError",
+				Errors:  err,
+			},
+		)
+	}
+}
+
+func (s *stockMovementHandler) fetchByProduct(c *fiber.Ctx) error {
+	stockMovementCriteria := stockMovementCriteriaFromRequest(c)
+	stockMovementCriteria.ProductID = c.Params("id")
+
+	return s.fetch(c, stockMovementCriteria)
+}
+
+func (s *stockMovementHandler) fetchByOutlet(c *fiber.Ctx) error {
+	stockMovementCriteria := stockMovementCriteriaFromRequest(c)
+	stockMovementCriteria.OutletID = c.Params("id")
+
+	return s.fetch(c, stockMovementCriteria)
+}
+
+func stockMovementCriteriaFromRequest(c *fiber.Ctx) criteria.StockMovementCriteria {
+	pagination := util.GeneratePaginationFromRequest(c)
+
+	stockMovementCriteria := criteria.StockMovementCriteria{
+		Pagination: pagination,
+	}
+
+	stockMovementCriteria.VariantID = c.Query("variant_id")
+	stockMovementCriteria.Type = c.Query("type")
+	stockMovementCriteria.From = c.Query("from")
+	stockMovementCriteria.To = c.Query("to")
+
+	return stockMovementCriteria
+}
+
+func (s *stockMovementHandler) fetch(c *fiber.Ctx, stockMovementCriteria criteria.StockMovementCriteria) error {
+	res, err := s.stockMovementSvc.Fetch(c.Context(), stockMovementCriteria)
+	switch err.(type) {
+	case *custom_error.NotFoundError:
+		log.Printf("error not found: %v", err)
+		return c.Status(fiber.StatusNotFound).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusNotFound",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
+	case *custom_error.BadRequest:
+		log.Printf("error bad request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  err,
+			},
+		)
+	case nil:
+		return c.Status(fiber.StatusOK).JSON(
+			res,
+		)
+	default:
+		log.Printf("error internal server error: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusInternalServerError",
+				Errors:  err,
+			},
+		)
+	}
+}
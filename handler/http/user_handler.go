@@ -6,6 +6,8 @@ import (
 	custom_error "github.com/rehandwi03/test-case-backend-majoo/internal/error"
 	"github.com/rehandwi03/test-case-backend-majoo/internal/helper"
 	"github.com/rehandwi03/test-case-backend-majoo/internal/middleware"
+	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"github.com/rehandwi03/test-case-backend-majoo/query"
 	request2 "github.com/rehandwi03/test-case-backend-majoo/request"
 	"github.com/rehandwi03/test-case-backend-majoo/service"
 	"github.com/rehandwi03/test-case-backend-majoo/util"
@@ -19,11 +21,15 @@ type userHandler struct {
 func NewUserHandler(app fiber.Router, userService service.UserService) {
 	handler := userHandler{userSvc: userService}
 
-	app.Post("/users", middleware.JwtProtected(), handler.saveUser)
+	adminOnly := middleware.RequireRole(model.RoleAdmin)
+	managers := middleware.RequireRole(model.RoleOwner, model.RoleManager)
+
+	// owners and managers register the accounts of their staff, everyone else may only read and update their own
+	app.Post("/users", middleware.JwtProtected(), managers, handler.saveUser)
 	app.Get("/users/:id", middleware.JwtProtected(), handler.getByID)
 	app.Put("/users", middleware.JwtProtected(), handler.updateUser)
-	app.Delete("/users/:id", middleware.JwtProtected(), handler.deleteByID)
-	app.Get("/users", middleware.JwtProtected(), handler.fetch)
+	app.Delete("/users/:id", middleware.JwtProtected(), adminOnly, handler.deleteByID)
+	app.Get("/users", middleware.JwtProtected(), adminOnly, handler.fetch)
 }
 
 func (u *userHandler) fetch(c *fiber.Ctx) error {
@@ -35,9 +41,28 @@ func (u *userHandler) fetch(c *fiber.Ctx) error {
 
 	userCriteria.Email = c.Query("email")
 	userCriteria.PhoneNumber = c.Query("phoneNumber")
+	userCriteria.IncludeDeleted = c.Query("include_deleted") == "true"
 
 	res, err := u.userSvc.Fetch(c.Context(), userCriteria)
 	switch err.(type) {
+	case *custom_error.BadRequest:
+		log.Printf("error bad request: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusBadRequest",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
 	case nil:
 		return c.Status(fiber.StatusOK).JSON(
 			res,
@@ -67,14 +92,7 @@ func (u *userHandler) deleteByID(c *fiber.Ctx) error {
 		)
 	}
 
-	params := map[string]interface{}{
-		"or": map[string]interface{}{},
-		"where": map[string]interface{}{
-			"default": map[string]interface{}{
-				"id = ?": id,
-			},
-		},
-	}
+	params := query.Where(query.Eq("id", id))
 
 	err := u.userSvc.DeleteUser(c.Context(), params)
 	switch err.(type) {
@@ -87,6 +105,15 @@ func (u *userHandler) deleteByID(c *fiber.Ctx) error {
 				Errors:  err,
 			},
 		)
+	case *custom_error.ConflictError:
+		log.Printf("error conflict: %v", err)
+		return c.Status(fiber.StatusConflict).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusConflict",
+				Errors:  err,
+			},
+		)
 	case nil:
 		return c.Status(fiber.StatusOK).JSON(
 			helper.SuccessResponse{
@@ -119,14 +146,7 @@ func (u *userHandler) getByID(c *fiber.Ctx) error {
 		)
 	}
 
-	params := map[string]interface{}{
-		"or": map[string]interface{}{},
-		"where": map[string]interface{}{
-			"default": map[string]interface{}{
-				"id = ?": id,
-			},
-		},
-	}
+	params := query.Where(query.Eq("id", id))
 
 	res, err := u.userSvc.GetByParam(c.Context(), params)
 	switch err.(type) {
@@ -139,6 +159,15 @@ func (u *userHandler) getByID(c *fiber.Ctx) error {
 				Errors:  err,
 			},
 		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
 	case nil:
 		return c.Status(fiber.StatusOK).JSON(
 			helper.SuccessResponse{
@@ -206,6 +235,24 @@ func (u *userHandler) updateUser(c *fiber.Ctx) error {
 				Errors:  err,
 			},
 		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ConflictError:
+		log.Printf("error conflict: %v", err)
+		return c.Status(fiber.StatusConflict).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusConflict",
+				Errors:  err,
+			},
+		)
 	case nil:
 		return c.Status(fiber.StatusOK).JSON(
 			helper.SuccessResponse{
@@ -266,6 +313,24 @@ func (u *userHandler) saveUser(c *fiber.Ctx) error {
 				Errors:  err,
 			},
 		)
+	case *custom_error.ForbiddenError:
+		log.Printf("error forbidden: %v", err)
+		return c.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  err,
+			},
+		)
+	case *custom_error.ConflictError:
+		log.Printf("error conflict: %v", err)
+		return c.Status(fiber.StatusConflict).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusConflict",
+				Errors:  err,
+			},
+		)
 	case nil:
 		return c.Status(fiber.StatusCreated).JSON(
 			helper.SuccessResponse{
@@ -18,8 +18,26 @@ func (f *ForbiddenError) Error() string {
 
 type BadRequest struct {
 	Message string `json:"message"`
+	Field   string `json:"field,omitempty"`
 }
 
 func (b *BadRequest) Error() string {
 	return b.Message
-}
\ No newline at end of file
+}
+
+type UnauthorizedError struct {
+	Message string `json:"message"`
+}
+
+func (u *UnauthorizedError) Error() string {
+	return u.Message
+}
+
+type ConflictError struct {
+	Message string `json:"message"`
+	Field   string `json:"field,omitempty"`
+}
+
+func (c *ConflictError) Error() string {
+	return c.Message
+}
@@ -0,0 +1,37 @@
+package helper
+
+import (
+	"context"
+	"github.com/rehandwi03/test-case-backend-majoo/model"
+)
+
+// IsAdmin reports whether the caller is a platform admin.
+func IsAdmin(ctx context.Context) bool {
+	role, _ := ctx.Value("role").(string)
+
+	return role == model.RoleAdmin
+}
+
+// HasRole reports whether the caller is a platform admin or holds one of the roles on the platform or in any
+// merchant.
+func HasRole(ctx context.Context, roles ...string) bool {
+	if IsAdmin(ctx) {
+		return true
+	}
+
+	role, _ := ctx.Value("role").(string)
+	merchantRoles, _ := ctx.Value("merchant_roles").(map[string]string)
+	for _, allowed := range roles {
+		if role != "" && role == allowed {
+			return true
+		}
+
+		for _, merchantRole := range merchantRoles {
+			if merchantRole == allowed {
+				return true
+			}
+		}
+	}
+
+	return false
+}
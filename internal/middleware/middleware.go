@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/gofiber/fiber/v2"
@@ -10,10 +11,41 @@ import (
 	"log"
 	"os"
 	"strings"
+	"time"
 )
 
 type TokenDetail struct {
-	UserID uuid.UUID `json:"user_id"`
+	TokenID       uuid.UUID         `json:"jti"`
+	SessionID     uuid.UUID         `json:"sid"`
+	UserID        uuid.UUID         `json:"user_id"`
+	Role          string            `json:"role"`
+	MerchantRoles map[string]string `json:"merchant_roles"`
+	ExpiresAt     time.Time         `json:"exp"`
+}
+
+// RevocationList tells whether an access token was revoked before it expired.
+type RevocationList interface {
+	IsRevoked(ctx context.Context, tokenId string) (bool, error)
+}
+
+var revocationList RevocationList
+
+// UseRevocationList makes JwtProtected reject the access tokens revoked in the given list.
+func UseRevocationList(list RevocationList) {
+	revocationList = list
+}
+
+// Memberships reads the role a user holds in every merchant they are a member of, by merchant id.
+type Memberships interface {
+	MerchantRoles(ctx context.Context, userId uuid.UUID) (map[string]string, error)
+}
+
+var memberships Memberships
+
+// UseMemberships makes RequireRole read the merchant roles of the caller from the given memberships instead of the
+// token, so a merchant opened, or a role given or taken away, after the token was issued counts right away.
+func UseMemberships(m Memberships) {
+	memberships = m
 }
 
 func exractToken(c *fiber.Ctx) (string, error) {
@@ -66,8 +98,38 @@ func ExtractTokenMetadata(c *fiber.Ctx) (*TokenDetail, error) {
 			return nil, err
 		}
 
+		tokenId, _ := claims["jti"].(string)
+		tokenIDUUID, err := uuid.Parse(tokenId)
+		if err != nil {
+			return nil, errors.New("token id not found")
+		}
+
+		sessionId, _ := claims["sid"].(string)
+		sessionIDUUID, err := uuid.Parse(sessionId)
+		if err != nil {
+			return nil, errors.New("session id not found")
+		}
+
+		exp, _ := claims["exp"].(float64)
+
+		role, _ := claims["role"].(string)
+
+		merchantRoles := map[string]string{}
+		if roles, ok := claims["merchant_roles"].(map[string]interface{}); ok {
+			for merchantId, merchantRole := range roles {
+				if merchantRole, ok := merchantRole.(string); ok {
+					merchantRoles[merchantId] = merchantRole
+				}
+			}
+		}
+
 		return &TokenDetail{
-			UserID: userIDUUID,
+			TokenID:       tokenIDUUID,
+			SessionID:     sessionIDUUID,
+			UserID:        userIDUUID,
+			Role:          role,
+			MerchantRoles: merchantRoles,
+			ExpiresAt:     time.Unix(int64(exp), 0),
 		}, nil
 	}
 	return nil, err
@@ -79,6 +141,17 @@ func checkAuthToken(c *fiber.Ctx) (*TokenDetail, error) {
 		return nil, err
 	}
 
+	if revocationList != nil {
+		revoked, err := revocationList.IsRevoked(c.Context(), extracTokenDetails.TokenID.String())
+		if err != nil {
+			return nil, err
+		}
+
+		if revoked {
+			return nil, errors.New("token has been revoked")
+		}
+	}
+
 	return extracTokenDetails, nil
 }
 
@@ -96,7 +169,48 @@ func JwtProtected() fiber.Handler {
 			)
 		}
 
+		ctx.Locals("token_id", tokenDetails.TokenID)
+		ctx.Locals("session_id", tokenDetails.SessionID)
+		ctx.Locals("token_expires_at", tokenDetails.ExpiresAt)
 		ctx.Locals("user_id", tokenDetails.UserID)
+		ctx.Locals("role", tokenDetails.Role)
+		ctx.Locals("merchant_roles", tokenDetails.MerchantRoles)
 		return ctx.Next()
 	}
 }
+
+// RequireRole only lets the request through when the caller is a platform admin or holds one of the given roles,
+// either as platform role or in at least one merchant. Whether the role is held in the merchant owning the
+// requested resource is checked by the services. It must be registered after JwtProtected.
+func RequireRole(roles ...string) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		if memberships != nil {
+			userId, _ := ctx.Locals("user_id").(uuid.UUID)
+			merchantRoles, err := memberships.MerchantRoles(ctx.Context(), userId)
+			if err != nil {
+				log.Printf("error reading merchant roles: %v", err)
+				return ctx.Status(fiber.StatusInternalServerError).JSON(
+					helper.ErrorResponse{
+						Status:  "failed",
+						Message: "StatusInternalServerError",
+						Errors:  err.Error(),
+					},
+				)
+			}
+
+			ctx.Locals("merchant_roles", merchantRoles)
+		}
+
+		if helper.HasRole(ctx.Context(), roles...) {
+			return ctx.Next()
+		}
+
+		return ctx.Status(fiber.StatusForbidden).JSON(
+			helper.ErrorResponse{
+				Status:  "failed",
+				Message: "StatusForbidden",
+				Errors:  "you don't have permission to access this resource",
+			},
+		)
+	}
+}
@@ -1,12 +1,15 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/joho/godotenv"
+	"github.com/rehandwi03/test-case-backend-majoo/gateway"
 	"github.com/rehandwi03/test-case-backend-majoo/handler/http"
-	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"github.com/rehandwi03/test-case-backend-majoo/internal/middleware"
+	"github.com/rehandwi03/test-case-backend-majoo/migration"
 	"github.com/rehandwi03/test-case-backend-majoo/repository"
 	"github.com/rehandwi03/test-case-backend-majoo/service"
 	"gorm.io/driver/postgres"
@@ -14,6 +17,8 @@ import (
 	gormLogger "gorm.io/gorm/logger"
 	"log"
 	"os"
+	"strconv"
+	"time"
 )
 
 func main() {
@@ -42,8 +47,29 @@ func main() {
 		log.Panicf("error when connecting to database: %v", err)
 	}
 
-	if err := db.AutoMigrate(&model.User{}, &model.Merchant{}, &model.Outlet{}, &model.Product{}); err != nil {
-		log.Printf("error migrating table: %v", err)
+	sqlDB, err := db.DB()
+	if err != nil {
+		log.Panicf("error when connecting to database: %v", err)
+	}
+
+	migrator, err := migration.NewMigrator(sqlDB)
+	if err != nil {
+		log.Panicf("error loading migrations: %v", err)
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := migrate(migrator, os.Args[2:]); err != nil {
+			log.Fatalf("error migrating: %v", err)
+		}
+		return
+	}
+
+	applied, err := migrator.Up(context.Background())
+	if err != nil {
+		log.Panicf("error migrating: %v", err)
+	}
+	for _, m := range applied {
+		log.Printf("applied migration %d_%s", m.Version, m.Name)
 	}
 
 	app := fiber.New()
@@ -60,22 +86,159 @@ func main() {
 
 	userRepo := repository.NewUserRepository(db)
 	merchantRepo := repository.NewMerchantRepository(db)
+	merchantUserRepo := repository.NewMerchantUserRepository(db)
 	outletRepo := repository.NewOutletRepository(db)
 	productRepo := repository.NewProductRepository(db)
+	categoryRepo := repository.NewCategoryRepository(db)
+	productOptionRepo := repository.NewProductOptionRepository(db)
+	productVariantRepo := repository.NewProductVariantRepository(db)
+	modifierGroupRepo := repository.NewModifierGroupRepository(db)
+	bundleSlotRepo := repository.NewBundleSlotRepository(db)
+	transactionRepo := repository.NewTransactionRepository(db)
+	transactionPaymentRepo := repository.NewTransactionPaymentRepository(db)
+	stockMovementRepo := repository.NewStockMovementRepository(db)
+	stockCountRepo := repository.NewStockCountRepository(db)
+	stockTransferRepo := repository.NewStockTransferRepository(db)
+	notificationRepo := repository.NewNotificationRepository(db)
+	supplierRepo := repository.NewSupplierRepository(db)
+	purchaseOrderRepo := repository.NewPurchaseOrderRepository(db)
+	areaRepo := repository.NewAreaRepository(db)
+	tableRepo := repository.NewTableRepository(db)
+	orderRepo := repository.NewOrderRepository(db)
+	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
+	revokedTokenRepo := repository.NewRevokedTokenRepository(db)
+	failedApprovalRepo := repository.NewFailedApprovalRepository(db)
+
+	middleware.UseRevocationList(revokedTokenRepo)
+	middleware.UseMemberships(merchantUserRepo)
+
+	scanInterval, err := time.ParseDuration(os.Getenv("LOW_STOCK_SCAN_INTERVAL"))
+	if err != nil || scanInterval <= 0 {
+		scanInterval = time.Minute
+	}
+	go service.NewLowStockScanner(notificationRepo, scanInterval).Run(context.Background())
+
+	// the mock payment provider answers the same way every time, for trying payment providers out without a gateway
+	providers := gateway.Providers{}
+	if secret := os.Getenv("MOCK_PAYMENT_SECRET"); secret != "" {
+		providers["mock"] = gateway.NewMockProvider(secret)
+	}
+
+	refundRetryInterval, err := time.ParseDuration(os.Getenv("REFUND_RETRY_INTERVAL"))
+	if err != nil || refundRetryInterval <= 0 {
+		refundRetryInterval = time.Minute
+	}
+	go service.NewRefundReconciler(transactionPaymentRepo, providers, refundRetryInterval).Run(context.Background())
+
+	purgeInterval, err := time.ParseDuration(os.Getenv("REVOKED_TOKEN_PURGE_INTERVAL"))
+	if err != nil || purgeInterval <= 0 {
+		purgeInterval = time.Hour
+	}
+	go service.NewRevokedTokenPurger(revokedTokenRepo, purgeInterval).Run(context.Background())
 
-	userSvc := service.NewUserService(userRepo)
-	merchantSvc := service.NewMerchantService(merchantRepo, userRepo)
-	outletSvc := service.NewOutletService(outletRepo, merchantRepo)
-	productSvc := service.NewProductService(productRepo, outletRepo)
-	authRepo := service.NewAuthService(userRepo)
+	accessGuard := service.NewAccessGuard(merchantRepo, merchantUserRepo, outletRepo, productRepo, categoryRepo)
+
+	userSvc := service.NewUserService(userRepo, refreshTokenRepo)
+	merchantSvc := service.NewMerchantService(merchantRepo, userRepo, merchantUserRepo, accessGuard)
+	outletSvc := service.NewOutletService(outletRepo, merchantRepo, accessGuard)
+	productSvc := service.NewProductService(
+		productRepo, outletRepo, categoryRepo, modifierGroupRepo, bundleSlotRepo, accessGuard,
+	)
+	categorySvc := service.NewCategoryService(categoryRepo, accessGuard)
+	productVariantSvc := service.NewProductVariantService(productOptionRepo, productVariantRepo, accessGuard)
+	transactionSvc := service.NewTransactionService(
+		transactionRepo, outletRepo, productRepo, orderRepo, transactionPaymentRepo, providers, accessGuard,
+	)
+	paymentSvc := service.NewPaymentService(transactionRepo, transactionPaymentRepo, providers, accessGuard)
+	refundSvc := service.NewRefundService(
+		transactionRepo, userRepo, merchantUserRepo, failedApprovalRepo, transactionPaymentRepo, providers, accessGuard,
+	)
+	stockMovementSvc := service.NewStockMovementService(stockMovementRepo, accessGuard)
+	stockCountSvc := service.NewStockCountService(stockCountRepo, productRepo, accessGuard)
+	stockTransferSvc := service.NewStockTransferService(stockTransferRepo, productRepo, accessGuard)
+	notificationSvc := service.NewNotificationService(notificationRepo, productRepo, accessGuard)
+	reportSvc := service.NewReportService(transactionRepo, accessGuard)
+	supplierSvc := service.NewSupplierService(supplierRepo, accessGuard)
+	purchaseOrderSvc := service.NewPurchaseOrderService(purchaseOrderRepo, supplierRepo, productRepo, accessGuard)
+	tableSvc := service.NewTableService(areaRepo, tableRepo, orderRepo, accessGuard)
+	orderSvc := service.NewOrderService(orderRepo, tableRepo, productRepo, accessGuard)
+	authRepo := service.NewAuthService(userRepo, merchantUserRepo, refreshTokenRepo, revokedTokenRepo)
 
 	http.NewUserHandler(apiGroup, userSvc)
 	http.NewMerchantHandler(apiGroup, merchantSvc)
 	http.NewOutletHandler(apiGroup, outletSvc)
 	http.NewProductHandler(apiGroup, productSvc)
+	http.NewCategoryHandler(apiGroup, categorySvc)
+	http.NewProductVariantHandler(apiGroup, productVariantSvc)
+	http.NewTransactionHandler(apiGroup, transactionSvc)
+	http.NewPaymentHandler(apiGroup, paymentSvc)
+	http.NewRefundHandler(apiGroup, refundSvc)
+	http.NewStockMovementHandler(apiGroup, stockMovementSvc)
+	http.NewStockCountHandler(apiGroup, stockCountSvc)
+	http.NewStockTransferHandler(apiGroup, stockTransferSvc)
+	http.NewNotificationHandler(apiGroup, notificationSvc)
+	http.NewSupplierHandler(apiGroup, supplierSvc)
+	http.NewPurchaseOrderHandler(apiGroup, purchaseOrderSvc)
+	http.NewReportHandler(apiGroup, reportSvc)
+	http.NewTableHandler(apiGroup, tableSvc)
+	http.NewOrderHandler(apiGroup, orderSvc)
 	http.NewAuthHandler(apiGroup, authRepo)
 
 	if err := app.Listen(":" + os.Getenv("APP_PORT")); err != nil {
 		log.Fatalf("can't start applicaton: %v", err)
 	}
 }
+
+// migrate runs the "migrate up|down [steps]|status" subcommand.
+func migrate(migrator *migration.Migrator, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: migrate up|down [steps]|status")
+	}
+
+	ctx := context.Background()
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, m := range applied {
+			fmt.Printf("applied %d_%s\n", m.Version, m.Name)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Println("no pending migrations")
+		}
+
+		return err
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			var err error
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return fmt.Errorf("steps must be a positive number")
+			}
+		}
+
+		rolledBack, err := migrator.Down(ctx, steps)
+		for _, m := range rolledBack {
+			fmt.Printf("rolled back %d_%s\n", m.Version, m.Name)
+		}
+
+		return err
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.AppliedAt.Valid {
+				appliedAt = "applied at " + status.AppliedAt.Time.Format(time.RFC3339)
+			}
+			fmt.Printf("%d_%s\t%s\n", status.Version, status.Name, appliedAt)
+		}
+
+		return nil
+	default:
+		return fmt.Errorf("unknown migrate command %q, use up, down or status", args[0])
+	}
+}
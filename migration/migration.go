@@ -0,0 +1,269 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// lockKey identifies the advisory lock held while migrating, any constant shared by every replica works.
+const lockKey = 7_380_412_301
+
+//go:embed sql/*.sql
+var files embed.FS
+
+// fileName is "<version>_<name>.<up|down>.sql", versions are applied in numeric order.
+var fileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+type Status struct {
+	Migration
+	AppliedAt sql.NullTime
+}
+
+// Migrator applies the migrations embedded in the binary and records them in the schema_migrations table. Every
+// operation holds a Postgres advisory lock, so replicas starting at the same time migrate one after the other.
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+func NewMigrator(db *sql.DB) (*Migrator, error) {
+	migrations, err := Load(files)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Load reads the migrations of fsys, every version needs both an up and a down file.
+func Load(fsys fs.FS) ([]Migration, error) {
+	paths, err := fs.Glob(fsys, "sql/*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, path := range paths {
+		name := path[len("sql/"):]
+		match := fileName.FindStringSubmatch(name)
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %q", name)
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version %q", name)
+		}
+
+		content, err := fs.ReadFile(fsys, path)
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has different names %q and %q", version, migration.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	var migrations []Migration
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s needs an up and a down file", migration.Version, migration.Name)
+		}
+
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(
+		migrations, func(i, j int) bool {
+			return migrations[i].Version < migrations[j].Version
+		},
+	)
+
+	return migrations, nil
+}
+
+// Up applies every pending migration in order and returns the applied ones.
+func (m *Migrator) Up(ctx context.Context) (applied []Migration, err error) {
+	err = m.locked(
+		ctx, func(conn *sql.Conn) error {
+			versions, err := appliedVersions(ctx, conn)
+			if err != nil {
+				return err
+			}
+
+			for _, migration := range m.migrations {
+				if _, ok := versions[migration.Version]; ok {
+					continue
+				}
+
+				err := run(
+					ctx, conn, migration.Up,
+					"INSERT INTO schema_migrations (version, name, applied_at) VALUES ($1, $2, $3)",
+					migration.Version, migration.Name, time.Now(),
+				)
+				if err != nil {
+					return fmt.Errorf("migration %d_%s up: %w", migration.Version, migration.Name, err)
+				}
+
+				applied = append(applied, migration)
+			}
+
+			return nil
+		},
+	)
+
+	return applied, err
+}
+
+// Down rolls back the last steps applied migrations, newest first, and returns the rolled back ones.
+func (m *Migrator) Down(ctx context.Context, steps int) (rolledBack []Migration, err error) {
+	err = m.locked(
+		ctx, func(conn *sql.Conn) error {
+			versions, err := appliedVersions(ctx, conn)
+			if err != nil {
+				return err
+			}
+
+			for i := len(m.migrations) - 1; i >= 0 && len(rolledBack) < steps; i-- {
+				migration := m.migrations[i]
+				if _, ok := versions[migration.Version]; !ok {
+					continue
+				}
+
+				err := run(
+					ctx, conn, migration.Down, "DELETE FROM schema_migrations WHERE version = $1", migration.Version,
+				)
+				if err != nil {
+					return fmt.Errorf("migration %d_%s down: %w", migration.Version, migration.Name, err)
+				}
+
+				rolledBack = append(rolledBack, migration)
+			}
+
+			return nil
+		},
+	)
+
+	return rolledBack, err
+}
+
+// Status lists every migration with the time it was applied, which isn't valid for pending ones.
+func (m *Migrator) Status(ctx context.Context) (statuses []Status, err error) {
+	err = m.locked(
+		ctx, func(conn *sql.Conn) error {
+			versions, err := appliedVersions(ctx, conn)
+			if err != nil {
+				return err
+			}
+
+			for _, migration := range m.migrations {
+				statuses = append(statuses, Status{Migration: migration, AppliedAt: versions[migration.Version]})
+			}
+
+			return nil
+		},
+	)
+
+	return statuses, err
+}
+
+// locked runs fn on a single connection holding the advisory lock, the lock belongs to the session so every
+// statement has to go through that connection.
+func (m *Migrator) locked(ctx context.Context, fn func(conn *sql.Conn) error) (err error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", lockKey); err != nil {
+		return err
+	}
+	defer func() {
+		// the context may be the reason fn failed, unlock regardless so the connection goes back to the pool clean
+		_, unlockErr := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", lockKey)
+		if err == nil {
+			err = unlockErr
+		}
+	}()
+
+	_, err = conn.ExecContext(
+		ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+			version    bigint PRIMARY KEY,
+			name       varchar(255) NOT NULL,
+			applied_at timestamptz NOT NULL
+		)`,
+	)
+	if err != nil {
+		return err
+	}
+
+	return fn(conn)
+}
+
+func appliedVersions(ctx context.Context, conn *sql.Conn) (map[int64]sql.NullTime, error) {
+	rows, err := conn.QueryContext(ctx, "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := map[int64]sql.NullTime{}
+	for rows.Next() {
+		var version int64
+		var appliedAt sql.NullTime
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+
+		versions[version] = appliedAt
+	}
+
+	return versions, rows.Err()
+}
+
+// run executes a migration script and its bookkeeping statement in one transaction, so a failing script leaves
+// neither the schema nor schema_migrations half updated.
+func run(ctx context.Context, conn *sql.Conn, script string, record string, args ...interface{}) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// without arguments the script is sent as a simple query, which may hold several statements
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS revoked_tokens;
+DROP TABLE IF EXISTS refresh_tokens;
+DROP TABLE IF EXISTS transaction_items;
+DROP TABLE IF EXISTS transactions;
+DROP TABLE IF EXISTS products;
+DROP TABLE IF EXISTS outlets;
+DROP TABLE IF EXISTS merchant_users;
+DROP TABLE IF EXISTS merchants;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,120 @@
+-- The tables as AutoMigrate created them, so databases created before migrations existed can adopt this one.
+
+CREATE TABLE IF NOT EXISTS users (
+    id           uuid PRIMARY KEY,
+    first_name   varchar(50),
+    last_name    varchar(50),
+    email        varchar(50),
+    password     varchar(255),
+    phone_number varchar(13),
+    role         varchar(20),
+    created_at   timestamptz,
+    modified_at  timestamptz,
+    deleted_at   timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
+
+CREATE TABLE IF NOT EXISTS merchants (
+    id               uuid PRIMARY KEY,
+    user_id          uuid,
+    name             varchar(255),
+    institution_name varchar(255),
+    phone_number     varchar(13),
+    created_at       timestamptz,
+    modified_at      timestamptz,
+    deleted_at       timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_merchants_deleted_at ON merchants (deleted_at);
+
+CREATE TABLE IF NOT EXISTS merchant_users (
+    id          uuid PRIMARY KEY,
+    merchant_id uuid,
+    user_id     uuid,
+    role        varchar(20),
+    created_at  timestamptz,
+    modified_at timestamptz,
+    deleted_at  timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_merchant_users_merchant_id ON merchant_users (merchant_id);
+CREATE INDEX IF NOT EXISTS idx_merchant_users_user_id ON merchant_users (user_id);
+CREATE INDEX IF NOT EXISTS idx_merchant_users_deleted_at ON merchant_users (deleted_at);
+
+CREATE TABLE IF NOT EXISTS outlets (
+    id           uuid PRIMARY KEY,
+    merchant_id  uuid,
+    name         varchar(255),
+    location     text,
+    phone_number varchar(13),
+    created_at   timestamptz,
+    modified_at  timestamptz,
+    deleted_at   timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_outlets_deleted_at ON outlets (deleted_at);
+
+CREATE TABLE IF NOT EXISTS products (
+    id          uuid PRIMARY KEY,
+    outlet_id   uuid,
+    name        varchar(255),
+    description varchar(255),
+    stock       bigint,
+    price       decimal,
+    image       varchar(255),
+    created_at  timestamptz,
+    modified_at timestamptz,
+    deleted_at  timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_products_deleted_at ON products (deleted_at);
+
+CREATE TABLE IF NOT EXISTS transactions (
+    id             uuid PRIMARY KEY,
+    outlet_id      uuid,
+    user_id        uuid,
+    total_quantity bigint,
+    total_amount   decimal,
+    created_at     timestamptz,
+    modified_at    timestamptz,
+    deleted_at     timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_transactions_outlet_id ON transactions (outlet_id);
+CREATE INDEX IF NOT EXISTS idx_transactions_deleted_at ON transactions (deleted_at);
+
+CREATE TABLE IF NOT EXISTS transaction_items (
+    id             uuid PRIMARY KEY,
+    transaction_id uuid,
+    product_id     uuid,
+    name           varchar(255),
+    price          decimal,
+    quantity       bigint,
+    subtotal       decimal,
+    created_at     timestamptz,
+    modified_at    timestamptz,
+    deleted_at     timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_transaction_items_transaction_id ON transaction_items (transaction_id);
+CREATE INDEX IF NOT EXISTS idx_transaction_items_deleted_at ON transaction_items (deleted_at);
+
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id              uuid PRIMARY KEY,
+    user_id         uuid,
+    family_id       uuid,
+    access_token_id uuid,
+    token_hash      varchar(64),
+    expires_at      timestamptz,
+    revoked_at      timestamptz,
+    created_at      timestamptz,
+    modified_at     timestamptz,
+    deleted_at      timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user_id ON refresh_tokens (user_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family_id ON refresh_tokens (family_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_deleted_at ON refresh_tokens (deleted_at);
+
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+    id          uuid PRIMARY KEY,
+    user_id     uuid,
+    expires_at  timestamptz,
+    created_at  timestamptz,
+    modified_at timestamptz,
+    deleted_at  timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_revoked_tokens_deleted_at ON revoked_tokens (deleted_at);
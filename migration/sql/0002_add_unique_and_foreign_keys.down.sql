@@ -0,0 +1,25 @@
+ALTER TABLE refresh_tokens DROP CONSTRAINT IF EXISTS fk_refresh_tokens_user_id;
+
+ALTER TABLE transaction_items
+    DROP CONSTRAINT IF EXISTS fk_transaction_items_product_id,
+    DROP CONSTRAINT IF EXISTS fk_transaction_items_transaction_id;
+
+ALTER TABLE transactions
+    DROP CONSTRAINT IF EXISTS fk_transactions_user_id,
+    DROP CONSTRAINT IF EXISTS fk_transactions_outlet_id;
+
+DROP INDEX IF EXISTS idx_products_outlet_id;
+ALTER TABLE products DROP CONSTRAINT IF EXISTS fk_products_outlet_id;
+
+DROP INDEX IF EXISTS idx_outlets_merchant_id;
+ALTER TABLE outlets DROP CONSTRAINT IF EXISTS fk_outlets_merchant_id;
+
+ALTER TABLE merchant_users
+    DROP CONSTRAINT IF EXISTS fk_merchant_users_user_id,
+    DROP CONSTRAINT IF EXISTS fk_merchant_users_merchant_id;
+
+DROP INDEX IF EXISTS idx_merchants_user_id;
+ALTER TABLE merchants DROP CONSTRAINT IF EXISTS fk_merchants_user_id;
+
+DROP INDEX IF EXISTS uq_merchant_users_merchant_id_user_id;
+DROP INDEX IF EXISTS uq_users_email;
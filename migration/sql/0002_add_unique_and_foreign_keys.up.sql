@@ -0,0 +1,31 @@
+-- Uniqueness only applies to live rows, a soft deleted user doesn't hold on to its email.
+CREATE UNIQUE INDEX uq_users_email ON users (email) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX uq_merchant_users_merchant_id_user_id ON merchant_users (merchant_id, user_id)
+    WHERE deleted_at IS NULL;
+
+ALTER TABLE merchants
+    ADD CONSTRAINT fk_merchants_user_id FOREIGN KEY (user_id) REFERENCES users (id);
+CREATE INDEX idx_merchants_user_id ON merchants (user_id);
+
+ALTER TABLE merchant_users
+    ADD CONSTRAINT fk_merchant_users_merchant_id FOREIGN KEY (merchant_id) REFERENCES merchants (id),
+    ADD CONSTRAINT fk_merchant_users_user_id FOREIGN KEY (user_id) REFERENCES users (id);
+
+ALTER TABLE outlets
+    ADD CONSTRAINT fk_outlets_merchant_id FOREIGN KEY (merchant_id) REFERENCES merchants (id);
+CREATE INDEX idx_outlets_merchant_id ON outlets (merchant_id);
+
+ALTER TABLE products
+    ADD CONSTRAINT fk_products_outlet_id FOREIGN KEY (outlet_id) REFERENCES outlets (id);
+CREATE INDEX idx_products_outlet_id ON products (outlet_id);
+
+ALTER TABLE transactions
+    ADD CONSTRAINT fk_transactions_outlet_id FOREIGN KEY (outlet_id) REFERENCES outlets (id),
+    ADD CONSTRAINT fk_transactions_user_id FOREIGN KEY (user_id) REFERENCES users (id);
+
+ALTER TABLE transaction_items
+    ADD CONSTRAINT fk_transaction_items_transaction_id FOREIGN KEY (transaction_id) REFERENCES transactions (id),
+    ADD CONSTRAINT fk_transaction_items_product_id FOREIGN KEY (product_id) REFERENCES products (id);
+
+ALTER TABLE refresh_tokens
+    ADD CONSTRAINT fk_refresh_tokens_user_id FOREIGN KEY (user_id) REFERENCES users (id);
@@ -0,0 +1,20 @@
+CREATE TABLE categories (
+    id          uuid PRIMARY KEY,
+    merchant_id uuid NOT NULL CONSTRAINT fk_categories_merchant_id REFERENCES merchants (id),
+    parent_id   uuid CONSTRAINT fk_categories_parent_id REFERENCES categories (id),
+    name        varchar(255),
+    sort_order  integer NOT NULL DEFAULT 0,
+    created_at  timestamptz,
+    modified_at timestamptz,
+    deleted_at  timestamptz
+);
+CREATE INDEX idx_categories_merchant_id ON categories (merchant_id);
+CREATE INDEX idx_categories_parent_id ON categories (parent_id);
+CREATE INDEX idx_categories_deleted_at ON categories (deleted_at);
+
+CREATE TABLE product_categories (
+    product_id  uuid NOT NULL CONSTRAINT fk_product_categories_product_id REFERENCES products (id),
+    category_id uuid NOT NULL CONSTRAINT fk_product_categories_category_id REFERENCES categories (id),
+    PRIMARY KEY (product_id, category_id)
+);
+CREATE INDEX idx_product_categories_category_id ON product_categories (category_id);
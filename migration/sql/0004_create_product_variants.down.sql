@@ -0,0 +1,8 @@
+ALTER TABLE transaction_items
+    DROP COLUMN IF EXISTS variant_name,
+    DROP COLUMN IF EXISTS variant_id;
+
+DROP TABLE IF EXISTS product_variant_values;
+DROP TABLE IF EXISTS product_variants;
+DROP TABLE IF EXISTS product_option_values;
+DROP TABLE IF EXISTS product_options;
@@ -0,0 +1,55 @@
+CREATE TABLE product_options (
+    id          uuid PRIMARY KEY,
+    product_id  uuid NOT NULL CONSTRAINT fk_product_options_product_id REFERENCES products (id),
+    name        varchar(255),
+    sort_order  integer NOT NULL DEFAULT 0,
+    created_at  timestamptz,
+    modified_at timestamptz,
+    deleted_at  timestamptz
+);
+CREATE INDEX idx_product_options_product_id ON product_options (product_id);
+CREATE INDEX idx_product_options_deleted_at ON product_options (deleted_at);
+CREATE UNIQUE INDEX uq_product_options_product_id_name ON product_options (product_id, name) WHERE deleted_at IS NULL;
+
+CREATE TABLE product_option_values (
+    id          uuid PRIMARY KEY,
+    option_id   uuid NOT NULL CONSTRAINT fk_product_option_values_option_id REFERENCES product_options (id),
+    value       varchar(255),
+    sort_order  integer NOT NULL DEFAULT 0,
+    created_at  timestamptz,
+    modified_at timestamptz,
+    deleted_at  timestamptz
+);
+CREATE INDEX idx_product_option_values_option_id ON product_option_values (option_id);
+CREATE INDEX idx_product_option_values_deleted_at ON product_option_values (deleted_at);
+CREATE UNIQUE INDEX uq_product_option_values_option_id_value ON product_option_values (option_id, value)
+    WHERE deleted_at IS NULL;
+
+CREATE TABLE product_variants (
+    id          uuid PRIMARY KEY,
+    product_id  uuid NOT NULL CONSTRAINT fk_product_variants_product_id REFERENCES products (id),
+    sku         varchar(64),
+    barcode     varchar(64),
+    price       decimal,
+    stock       bigint NOT NULL DEFAULT 0,
+    created_at  timestamptz,
+    modified_at timestamptz,
+    deleted_at  timestamptz
+);
+CREATE INDEX idx_product_variants_product_id ON product_variants (product_id);
+CREATE INDEX idx_product_variants_barcode ON product_variants (barcode);
+CREATE INDEX idx_product_variants_deleted_at ON product_variants (deleted_at);
+CREATE UNIQUE INDEX uq_product_variants_product_id_sku ON product_variants (product_id, sku)
+    WHERE deleted_at IS NULL AND sku <> '';
+
+CREATE TABLE product_variant_values (
+    variant_id      uuid NOT NULL CONSTRAINT fk_product_variant_values_variant_id REFERENCES product_variants (id),
+    option_value_id uuid NOT NULL
+        CONSTRAINT fk_product_variant_values_option_value_id REFERENCES product_option_values (id),
+    PRIMARY KEY (variant_id, option_value_id)
+);
+CREATE INDEX idx_product_variant_values_option_value_id ON product_variant_values (option_value_id);
+
+ALTER TABLE transaction_items
+    ADD COLUMN variant_id   uuid CONSTRAINT fk_transaction_items_variant_id REFERENCES product_variants (id),
+    ADD COLUMN variant_name varchar(255);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS transaction_item_modifiers;
+DROP TABLE IF EXISTS modifiers;
+DROP TABLE IF EXISTS modifier_groups;
@@ -0,0 +1,43 @@
+CREATE TABLE modifier_groups (
+    id          uuid PRIMARY KEY,
+    product_id  uuid NOT NULL CONSTRAINT fk_modifier_groups_product_id REFERENCES products (id),
+    name        varchar(255),
+    min_select  integer NOT NULL DEFAULT 0,
+    max_select  integer NOT NULL DEFAULT 1,
+    sort_order  integer NOT NULL DEFAULT 0,
+    created_at  timestamptz,
+    modified_at timestamptz,
+    deleted_at  timestamptz,
+    CONSTRAINT ck_modifier_groups_select CHECK (min_select >= 0 AND max_select >= min_select)
+);
+CREATE INDEX idx_modifier_groups_product_id ON modifier_groups (product_id);
+CREATE INDEX idx_modifier_groups_deleted_at ON modifier_groups (deleted_at);
+CREATE UNIQUE INDEX uq_modifier_groups_product_id_name ON modifier_groups (product_id, name) WHERE deleted_at IS NULL;
+
+CREATE TABLE modifiers (
+    id          uuid PRIMARY KEY,
+    group_id    uuid NOT NULL CONSTRAINT fk_modifiers_group_id REFERENCES modifier_groups (id),
+    name        varchar(255),
+    price_delta decimal NOT NULL DEFAULT 0,
+    sort_order  integer NOT NULL DEFAULT 0,
+    created_at  timestamptz,
+    modified_at timestamptz,
+    deleted_at  timestamptz
+);
+CREATE INDEX idx_modifiers_group_id ON modifiers (group_id);
+CREATE INDEX idx_modifiers_deleted_at ON modifiers (deleted_at);
+CREATE UNIQUE INDEX uq_modifiers_group_id_name ON modifiers (group_id, name) WHERE deleted_at IS NULL;
+
+CREATE TABLE transaction_item_modifiers (
+    id                  uuid PRIMARY KEY,
+    transaction_item_id uuid NOT NULL
+        CONSTRAINT fk_transaction_item_modifiers_transaction_item_id REFERENCES transaction_items (id),
+    modifier_id         uuid NOT NULL CONSTRAINT fk_transaction_item_modifiers_modifier_id REFERENCES modifiers (id),
+    name                varchar(255),
+    price_delta         decimal,
+    created_at          timestamptz,
+    modified_at         timestamptz,
+    deleted_at          timestamptz
+);
+CREATE INDEX idx_transaction_item_modifiers_transaction_item_id ON transaction_item_modifiers (transaction_item_id);
+CREATE INDEX idx_transaction_item_modifiers_deleted_at ON transaction_item_modifiers (deleted_at);
@@ -0,0 +1,40 @@
+-- amounts go back to rupiah in major units, the currency of the rows is dropped
+ALTER TABLE transaction_item_modifiers ADD COLUMN price_delta decimal;
+UPDATE transaction_item_modifiers SET price_delta = price_delta_amount / 100.0;
+ALTER TABLE transaction_item_modifiers
+    DROP COLUMN price_delta_amount,
+    DROP COLUMN price_delta_currency;
+
+ALTER TABLE transaction_items
+    ADD COLUMN price    decimal,
+    ADD COLUMN subtotal decimal;
+UPDATE transaction_items SET price = price_amount / 100.0, subtotal = subtotal_amount / 100.0;
+ALTER TABLE transaction_items
+    DROP COLUMN price_amount,
+    DROP COLUMN price_currency,
+    DROP COLUMN subtotal_amount,
+    DROP COLUMN subtotal_currency;
+
+ALTER TABLE transactions
+    ALTER COLUMN total_amount TYPE decimal USING total_amount / 100.0,
+    DROP COLUMN total_currency;
+
+ALTER TABLE modifiers ADD COLUMN price_delta decimal NOT NULL DEFAULT 0;
+UPDATE modifiers SET price_delta = price_delta_amount / 100.0;
+ALTER TABLE modifiers
+    DROP COLUMN price_delta_amount,
+    DROP COLUMN price_delta_currency;
+
+ALTER TABLE product_variants ADD COLUMN price decimal;
+UPDATE product_variants SET price = price_amount / 100.0;
+ALTER TABLE product_variants
+    DROP COLUMN price_amount,
+    DROP COLUMN price_currency;
+
+ALTER TABLE products ADD COLUMN price decimal;
+UPDATE products SET price = price_amount / 100.0;
+ALTER TABLE products
+    DROP COLUMN price_amount,
+    DROP COLUMN price_currency;
+
+ALTER TABLE merchants DROP COLUMN currency;
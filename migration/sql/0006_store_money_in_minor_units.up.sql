@@ -0,0 +1,53 @@
+-- every merchant existing so far sells in rupiah, whose ISO 4217 minor unit is the sen: 1 rupiah is 100 sen
+ALTER TABLE merchants ADD COLUMN currency char(3) NOT NULL DEFAULT 'IDR';
+
+ALTER TABLE products
+    ADD COLUMN price_amount   bigint NOT NULL DEFAULT 0,
+    ADD COLUMN price_currency char(3) NOT NULL DEFAULT 'IDR';
+UPDATE products SET price_amount = round(price * 100) WHERE price IS NOT NULL;
+ALTER TABLE products DROP COLUMN price;
+
+ALTER TABLE product_variants
+    ADD COLUMN price_amount   bigint,
+    ADD COLUMN price_currency char(3);
+UPDATE product_variants SET price_amount = round(price * 100), price_currency = 'IDR' WHERE price IS NOT NULL;
+ALTER TABLE product_variants DROP COLUMN price;
+
+ALTER TABLE modifiers
+    ADD COLUMN price_delta_amount   bigint NOT NULL DEFAULT 0,
+    ADD COLUMN price_delta_currency char(3) NOT NULL DEFAULT 'IDR';
+UPDATE modifiers SET price_delta_amount = round(price_delta * 100);
+ALTER TABLE modifiers DROP COLUMN price_delta;
+
+ALTER TABLE transactions
+    ALTER COLUMN total_amount TYPE bigint USING round(total_amount * 100),
+    ADD COLUMN total_currency char(3) NOT NULL DEFAULT 'IDR';
+
+ALTER TABLE transaction_items
+    ADD COLUMN price_amount      bigint,
+    ADD COLUMN price_currency    char(3) NOT NULL DEFAULT 'IDR',
+    ADD COLUMN subtotal_amount   bigint,
+    ADD COLUMN subtotal_currency char(3) NOT NULL DEFAULT 'IDR';
+UPDATE transaction_items SET price_amount = round(price * 100), subtotal_amount = round(subtotal * 100);
+ALTER TABLE transaction_items
+    DROP COLUMN price,
+    DROP COLUMN subtotal;
+
+ALTER TABLE transaction_item_modifiers
+    ADD COLUMN price_delta_amount   bigint,
+    ADD COLUMN price_delta_currency char(3) NOT NULL DEFAULT 'IDR';
+UPDATE transaction_item_modifiers SET price_delta_amount = round(price_delta * 100);
+ALTER TABLE transaction_item_modifiers DROP COLUMN price_delta;
+
+-- the currency of a row is the one of its merchant from now on, the defaults only served the existing rows
+ALTER TABLE products ALTER COLUMN price_currency DROP DEFAULT;
+ALTER TABLE modifiers ALTER COLUMN price_delta_currency DROP DEFAULT;
+ALTER TABLE transactions ALTER COLUMN total_currency DROP DEFAULT;
+ALTER TABLE transaction_items
+    ALTER COLUMN price_amount SET NOT NULL,
+    ALTER COLUMN price_currency DROP DEFAULT,
+    ALTER COLUMN subtotal_amount SET NOT NULL,
+    ALTER COLUMN subtotal_currency DROP DEFAULT;
+ALTER TABLE transaction_item_modifiers
+    ALTER COLUMN price_delta_amount SET NOT NULL,
+    ALTER COLUMN price_delta_currency DROP DEFAULT;
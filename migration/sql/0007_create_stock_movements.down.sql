@@ -0,0 +1,5 @@
+DROP TABLE stock_movements;
+
+ALTER TABLE products
+    ALTER COLUMN stock DROP NOT NULL,
+    ALTER COLUMN stock DROP DEFAULT;
@@ -0,0 +1,43 @@
+CREATE TABLE stock_movements (
+    id             uuid PRIMARY KEY,
+    outlet_id      uuid NOT NULL CONSTRAINT fk_stock_movements_outlet_id REFERENCES outlets (id),
+    product_id     uuid NOT NULL CONSTRAINT fk_stock_movements_product_id REFERENCES products (id),
+    variant_id     uuid CONSTRAINT fk_stock_movements_variant_id REFERENCES product_variants (id),
+    type           varchar(32) NOT NULL,
+    reason         varchar(32) NOT NULL DEFAULT '',
+    note           varchar(255) NOT NULL DEFAULT '',
+    quantity       bigint NOT NULL,
+    balance        bigint NOT NULL,
+    transaction_id uuid CONSTRAINT fk_stock_movements_transaction_id REFERENCES transactions (id),
+    user_id        uuid CONSTRAINT fk_stock_movements_user_id REFERENCES users (id),
+    created_at     timestamptz,
+    modified_at    timestamptz,
+    deleted_at     timestamptz
+);
+CREATE INDEX idx_stock_movements_outlet_id ON stock_movements (outlet_id);
+CREATE INDEX idx_stock_movements_product_id_created_at ON stock_movements (product_id, created_at);
+CREATE INDEX idx_stock_movements_variant_id ON stock_movements (variant_id);
+CREATE INDEX idx_stock_movements_deleted_at ON stock_movements (deleted_at);
+
+-- the stock of a product is the balance of its ledger from now on, which can't be unknown
+UPDATE products SET stock = 0 WHERE stock IS NULL;
+ALTER TABLE products
+    ALTER COLUMN stock SET DEFAULT 0,
+    ALTER COLUMN stock SET NOT NULL;
+
+-- the ledger opens with the stock products and variants have now, so it adds up to their stock from the start;
+-- gen_random_uuid isn't available before Postgres 13
+INSERT INTO stock_movements (id, outlet_id, product_id, type, reason, quantity, balance, created_at, modified_at)
+SELECT md5(random()::text || clock_timestamp()::text)::uuid, outlet_id, id, 'adjustment', 'opening_balance', stock,
+       stock, now(), now()
+FROM products
+WHERE stock <> 0;
+
+INSERT INTO stock_movements (
+    id, outlet_id, product_id, variant_id, type, reason, quantity, balance, created_at, modified_at
+)
+SELECT md5(random()::text || clock_timestamp()::text)::uuid, p.outlet_id, v.product_id, v.id, 'adjustment',
+       'opening_balance', v.stock, v.stock, now(), now()
+FROM product_variants v
+JOIN products p ON p.id = v.product_id
+WHERE v.stock <> 0;
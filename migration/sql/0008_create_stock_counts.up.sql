@@ -0,0 +1,39 @@
+CREATE TABLE stock_counts (
+    id          uuid PRIMARY KEY,
+    outlet_id   uuid NOT NULL CONSTRAINT fk_stock_counts_outlet_id REFERENCES outlets (id),
+    status      varchar(16) NOT NULL,
+    note        varchar(255) NOT NULL DEFAULT '',
+    created_by  uuid NOT NULL CONSTRAINT fk_stock_counts_created_by REFERENCES users (id),
+    approved_by uuid CONSTRAINT fk_stock_counts_approved_by REFERENCES users (id),
+    approved_at timestamptz,
+    created_at  timestamptz,
+    modified_at timestamptz,
+    deleted_at  timestamptz
+);
+CREATE INDEX idx_stock_counts_outlet_id ON stock_counts (outlet_id);
+CREATE INDEX idx_stock_counts_deleted_at ON stock_counts (deleted_at);
+-- an outlet is counted by one session at a time
+CREATE UNIQUE INDEX uq_stock_counts_outlet_id_open ON stock_counts (outlet_id)
+    WHERE status = 'open' AND deleted_at IS NULL;
+
+CREATE TABLE stock_count_lines (
+    id           uuid PRIMARY KEY,
+    count_id     uuid NOT NULL CONSTRAINT fk_stock_count_lines_count_id REFERENCES stock_counts (id),
+    product_id   uuid NOT NULL CONSTRAINT fk_stock_count_lines_product_id REFERENCES products (id),
+    variant_id   uuid CONSTRAINT fk_stock_count_lines_variant_id REFERENCES product_variants (id),
+    name         varchar(255),
+    variant_name varchar(255),
+    expected     bigint NOT NULL,
+    counted      bigint,
+    counted_by   uuid CONSTRAINT fk_stock_count_lines_counted_by REFERENCES users (id),
+    counted_at   timestamptz,
+    created_at   timestamptz,
+    modified_at  timestamptz,
+    deleted_at   timestamptz,
+    CONSTRAINT ck_stock_count_lines_counted CHECK (counted >= 0)
+);
+CREATE INDEX idx_stock_count_lines_count_id ON stock_count_lines (count_id);
+CREATE INDEX idx_stock_count_lines_deleted_at ON stock_count_lines (deleted_at);
+
+ALTER TABLE stock_movements
+    ADD COLUMN stock_count_id uuid CONSTRAINT fk_stock_movements_stock_count_id REFERENCES stock_counts (id);
@@ -0,0 +1,8 @@
+ALTER TABLE stock_movements DROP COLUMN stock_transfer_id;
+
+DROP TABLE stock_transfer_lines;
+DROP TABLE stock_transfers;
+
+DROP INDEX idx_product_variants_sku;
+DROP INDEX uq_products_outlet_id_sku;
+ALTER TABLE products DROP COLUMN sku;
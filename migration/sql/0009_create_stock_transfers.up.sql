@@ -0,0 +1,49 @@
+-- products are matched across the outlets of a merchant by their sku, like variants
+ALTER TABLE products ADD COLUMN sku varchar(64) NOT NULL DEFAULT '';
+CREATE UNIQUE INDEX uq_products_outlet_id_sku ON products (outlet_id, sku) WHERE deleted_at IS NULL AND sku <> '';
+CREATE INDEX idx_product_variants_sku ON product_variants (sku) WHERE deleted_at IS NULL AND sku <> '';
+
+CREATE TABLE stock_transfers (
+    id                    uuid PRIMARY KEY,
+    merchant_id           uuid NOT NULL CONSTRAINT fk_stock_transfers_merchant_id REFERENCES merchants (id),
+    source_outlet_id      uuid NOT NULL CONSTRAINT fk_stock_transfers_source_outlet_id REFERENCES outlets (id),
+    destination_outlet_id uuid NOT NULL CONSTRAINT fk_stock_transfers_destination_outlet_id REFERENCES outlets (id),
+    status                varchar(32) NOT NULL,
+    note                  varchar(255) NOT NULL DEFAULT '',
+    created_by            uuid NOT NULL CONSTRAINT fk_stock_transfers_created_by REFERENCES users (id),
+    sent_by               uuid CONSTRAINT fk_stock_transfers_sent_by REFERENCES users (id),
+    sent_at               timestamptz,
+    created_at            timestamptz,
+    modified_at           timestamptz,
+    deleted_at            timestamptz,
+    CONSTRAINT ck_stock_transfers_outlets CHECK (source_outlet_id <> destination_outlet_id)
+);
+CREATE INDEX idx_stock_transfers_merchant_id ON stock_transfers (merchant_id);
+CREATE INDEX idx_stock_transfers_source_outlet_id ON stock_transfers (source_outlet_id);
+CREATE INDEX idx_stock_transfers_destination_outlet_id ON stock_transfers (destination_outlet_id);
+CREATE INDEX idx_stock_transfers_deleted_at ON stock_transfers (deleted_at);
+
+CREATE TABLE stock_transfer_lines (
+    id                     uuid PRIMARY KEY,
+    transfer_id            uuid NOT NULL CONSTRAINT fk_stock_transfer_lines_transfer_id REFERENCES stock_transfers (id),
+    product_id             uuid NOT NULL CONSTRAINT fk_stock_transfer_lines_product_id REFERENCES products (id),
+    variant_id             uuid CONSTRAINT fk_stock_transfer_lines_variant_id REFERENCES product_variants (id),
+    destination_product_id uuid NOT NULL
+        CONSTRAINT fk_stock_transfer_lines_destination_product_id REFERENCES products (id),
+    destination_variant_id uuid
+        CONSTRAINT fk_stock_transfer_lines_destination_variant_id REFERENCES product_variants (id),
+    sku                    varchar(64) NOT NULL,
+    name                   varchar(255),
+    variant_name           varchar(255),
+    quantity               bigint NOT NULL,
+    received               bigint NOT NULL DEFAULT 0,
+    created_at             timestamptz,
+    modified_at            timestamptz,
+    deleted_at             timestamptz,
+    CONSTRAINT ck_stock_transfer_lines_received CHECK (quantity > 0 AND received >= 0 AND received <= quantity)
+);
+CREATE INDEX idx_stock_transfer_lines_transfer_id ON stock_transfer_lines (transfer_id);
+CREATE INDEX idx_stock_transfer_lines_deleted_at ON stock_transfer_lines (deleted_at);
+
+ALTER TABLE stock_movements
+    ADD COLUMN stock_transfer_id uuid CONSTRAINT fk_stock_movements_stock_transfer_id REFERENCES stock_transfers (id);
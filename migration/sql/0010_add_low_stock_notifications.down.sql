@@ -0,0 +1,5 @@
+DROP INDEX idx_stock_movements_created_at;
+DROP TABLE notifications;
+
+ALTER TABLE products DROP COLUMN reorder_quantity;
+ALTER TABLE products DROP COLUMN reorder_point;
@@ -0,0 +1,24 @@
+-- a product without a reorder point is never reported low on stock
+ALTER TABLE products ADD COLUMN reorder_point bigint;
+ALTER TABLE products ADD COLUMN reorder_quantity bigint NOT NULL DEFAULT 0;
+
+CREATE TABLE notifications (
+    id                uuid PRIMARY KEY,
+    outlet_id         uuid NOT NULL CONSTRAINT fk_notifications_outlet_id REFERENCES outlets (id),
+    type              varchar(32) NOT NULL,
+    product_id        uuid CONSTRAINT fk_notifications_product_id REFERENCES products (id),
+    variant_id        uuid CONSTRAINT fk_notifications_variant_id REFERENCES product_variants (id),
+    stock_movement_id uuid CONSTRAINT fk_notifications_stock_movement_id REFERENCES stock_movements (id),
+    message           varchar(255) NOT NULL,
+    stock             bigint NOT NULL DEFAULT 0,
+    reorder_point     bigint NOT NULL DEFAULT 0,
+    reorder_quantity  bigint NOT NULL DEFAULT 0,
+    created_at        timestamptz,
+    modified_at       timestamptz,
+    deleted_at        timestamptz
+);
+CREATE INDEX idx_notifications_outlet_id ON notifications (outlet_id);
+CREATE INDEX idx_notifications_deleted_at ON notifications (deleted_at);
+-- a movement raises a notification of a type once, however many times the scanner sees it
+CREATE UNIQUE INDEX uq_notifications_stock_movement_id_type ON notifications (stock_movement_id, type);
+CREATE INDEX idx_stock_movements_created_at ON stock_movements (created_at);
@@ -0,0 +1,15 @@
+ALTER TABLE stock_movements
+    DROP COLUMN unit_cost_currency,
+    DROP COLUMN unit_cost_amount,
+    DROP COLUMN purchase_order_id;
+
+ALTER TABLE product_variants
+    DROP COLUMN cost_price_currency,
+    DROP COLUMN cost_price_amount;
+ALTER TABLE products
+    DROP COLUMN cost_price_currency,
+    DROP COLUMN cost_price_amount;
+
+DROP TABLE purchase_order_lines;
+DROP TABLE purchase_orders;
+DROP TABLE suppliers;
@@ -0,0 +1,72 @@
+CREATE TABLE suppliers (
+    id           uuid PRIMARY KEY,
+    merchant_id  uuid NOT NULL CONSTRAINT fk_suppliers_merchant_id REFERENCES merchants (id),
+    name         varchar(255) NOT NULL,
+    contact_name varchar(255) NOT NULL DEFAULT '',
+    email        varchar(255) NOT NULL DEFAULT '',
+    phone_number varchar(20) NOT NULL DEFAULT '',
+    address      text NOT NULL DEFAULT '',
+    note         varchar(255) NOT NULL DEFAULT '',
+    created_at   timestamptz,
+    modified_at  timestamptz,
+    deleted_at   timestamptz
+);
+CREATE INDEX idx_suppliers_merchant_id ON suppliers (merchant_id);
+CREATE INDEX idx_suppliers_deleted_at ON suppliers (deleted_at);
+CREATE UNIQUE INDEX uq_suppliers_merchant_id_name ON suppliers (merchant_id, name) WHERE deleted_at IS NULL;
+
+CREATE TABLE purchase_orders (
+    id             uuid PRIMARY KEY,
+    merchant_id    uuid NOT NULL CONSTRAINT fk_purchase_orders_merchant_id REFERENCES merchants (id),
+    supplier_id    uuid NOT NULL CONSTRAINT fk_purchase_orders_supplier_id REFERENCES suppliers (id),
+    outlet_id      uuid NOT NULL CONSTRAINT fk_purchase_orders_outlet_id REFERENCES outlets (id),
+    status         varchar(32) NOT NULL,
+    note           varchar(255) NOT NULL DEFAULT '',
+    total_amount   bigint NOT NULL,
+    total_currency char(3) NOT NULL,
+    created_by     uuid NOT NULL CONSTRAINT fk_purchase_orders_created_by REFERENCES users (id),
+    ordered_by     uuid CONSTRAINT fk_purchase_orders_ordered_by REFERENCES users (id),
+    ordered_at     timestamptz,
+    created_at     timestamptz,
+    modified_at    timestamptz,
+    deleted_at     timestamptz
+);
+CREATE INDEX idx_purchase_orders_merchant_id ON purchase_orders (merchant_id);
+CREATE INDEX idx_purchase_orders_supplier_id ON purchase_orders (supplier_id);
+CREATE INDEX idx_purchase_orders_outlet_id ON purchase_orders (outlet_id);
+CREATE INDEX idx_purchase_orders_deleted_at ON purchase_orders (deleted_at);
+
+CREATE TABLE purchase_order_lines (
+    id                     uuid PRIMARY KEY,
+    order_id               uuid NOT NULL CONSTRAINT fk_purchase_order_lines_order_id REFERENCES purchase_orders (id),
+    product_id             uuid NOT NULL CONSTRAINT fk_purchase_order_lines_product_id REFERENCES products (id),
+    variant_id             uuid CONSTRAINT fk_purchase_order_lines_variant_id REFERENCES product_variants (id),
+    sku                    varchar(64) NOT NULL DEFAULT '',
+    name                   varchar(255),
+    variant_name           varchar(255),
+    quantity               bigint NOT NULL,
+    unit_cost_amount       bigint NOT NULL,
+    unit_cost_currency     char(3) NOT NULL,
+    received               bigint NOT NULL DEFAULT 0,
+    received_cost_amount   bigint NOT NULL DEFAULT 0,
+    received_cost_currency char(3) NOT NULL,
+    created_at             timestamptz,
+    modified_at            timestamptz,
+    deleted_at             timestamptz,
+    CONSTRAINT ck_purchase_order_lines_received CHECK (quantity > 0 AND received >= 0 AND received <= quantity)
+);
+CREATE INDEX idx_purchase_order_lines_order_id ON purchase_order_lines (order_id);
+CREATE INDEX idx_purchase_order_lines_deleted_at ON purchase_order_lines (deleted_at);
+
+-- the cost a unit was last bought at, empty until the first purchase is received
+ALTER TABLE products
+    ADD COLUMN cost_price_amount   bigint NOT NULL DEFAULT 0,
+    ADD COLUMN cost_price_currency varchar(3) NOT NULL DEFAULT '';
+ALTER TABLE product_variants
+    ADD COLUMN cost_price_amount   bigint NOT NULL DEFAULT 0,
+    ADD COLUMN cost_price_currency varchar(3) NOT NULL DEFAULT '';
+
+ALTER TABLE stock_movements
+    ADD COLUMN purchase_order_id  uuid CONSTRAINT fk_stock_movements_purchase_order_id REFERENCES purchase_orders (id),
+    ADD COLUMN unit_cost_amount   bigint NOT NULL DEFAULT 0,
+    ADD COLUMN unit_cost_currency varchar(3) NOT NULL DEFAULT '';
@@ -0,0 +1,23 @@
+DROP TABLE stock_cost_layers;
+
+ALTER TABLE stock_transfer_lines
+    DROP COLUMN unit_cost_currency,
+    DROP COLUMN unit_cost_amount;
+
+ALTER TABLE transaction_items
+    DROP COLUMN cost_currency,
+    DROP COLUMN cost_amount;
+
+ALTER TABLE stock_movements
+    DROP COLUMN cost_currency,
+    DROP COLUMN cost_amount;
+
+ALTER TABLE product_variants
+    DROP COLUMN average_cost_currency,
+    DROP COLUMN average_cost_amount;
+ALTER TABLE products
+    DROP COLUMN average_cost_currency,
+    DROP COLUMN average_cost_amount;
+
+ALTER TABLE merchants
+    DROP COLUMN costing_method;
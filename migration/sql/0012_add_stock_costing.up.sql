@@ -0,0 +1,69 @@
+-- how the stock taken out is costed, by the running weighted average or by the oldest lots first
+ALTER TABLE merchants
+    ADD COLUMN costing_method varchar(16) NOT NULL DEFAULT 'average';
+
+-- the weighted average cost of the stock on hand, what was last paid for it is the best guess for the stock already
+-- there
+ALTER TABLE products
+    ADD COLUMN average_cost_amount   bigint NOT NULL DEFAULT 0,
+    ADD COLUMN average_cost_currency varchar(3) NOT NULL DEFAULT '';
+ALTER TABLE product_variants
+    ADD COLUMN average_cost_amount   bigint NOT NULL DEFAULT 0,
+    ADD COLUMN average_cost_currency varchar(3) NOT NULL DEFAULT '';
+UPDATE products SET average_cost_amount = cost_price_amount, average_cost_currency = cost_price_currency;
+UPDATE product_variants SET average_cost_amount = cost_price_amount, average_cost_currency = cost_price_currency;
+
+ALTER TABLE stock_movements
+    ADD COLUMN cost_amount   bigint NOT NULL DEFAULT 0,
+    ADD COLUMN cost_currency varchar(3) NOT NULL DEFAULT '';
+
+ALTER TABLE transaction_items
+    ADD COLUMN cost_amount   bigint NOT NULL DEFAULT 0,
+    ADD COLUMN cost_currency varchar(3) NOT NULL DEFAULT '';
+
+ALTER TABLE stock_transfer_lines
+    ADD COLUMN unit_cost_amount   bigint NOT NULL DEFAULT 0,
+    ADD COLUMN unit_cost_currency varchar(3) NOT NULL DEFAULT '';
+
+CREATE TABLE stock_cost_layers (
+    id                 uuid PRIMARY KEY,
+    outlet_id          uuid NOT NULL CONSTRAINT fk_stock_cost_layers_outlet_id REFERENCES outlets (id),
+    product_id         uuid NOT NULL CONSTRAINT fk_stock_cost_layers_product_id REFERENCES products (id),
+    variant_id         uuid CONSTRAINT fk_stock_cost_layers_variant_id REFERENCES product_variants (id),
+    stock_movement_id  uuid CONSTRAINT fk_stock_cost_layers_stock_movement_id REFERENCES stock_movements (id),
+    unit_cost_amount   bigint NOT NULL,
+    unit_cost_currency varchar(3) NOT NULL,
+    quantity           bigint NOT NULL,
+    remaining          bigint NOT NULL,
+    created_at         timestamptz,
+    modified_at        timestamptz,
+    deleted_at         timestamptz,
+    CONSTRAINT ck_stock_cost_layers_remaining CHECK (remaining >= 0 AND remaining <= quantity)
+);
+CREATE INDEX idx_stock_cost_layers_outlet_id ON stock_cost_layers (outlet_id);
+CREATE INDEX idx_stock_cost_layers_product_id ON stock_cost_layers (product_id);
+CREATE INDEX idx_stock_cost_layers_remaining ON stock_cost_layers (product_id, variant_id, created_at)
+    WHERE remaining > 0;
+
+-- the stock on hand becomes the first lot of every product and variant, at the average cost it starts with
+INSERT INTO stock_cost_layers (
+    id, outlet_id, product_id, unit_cost_amount, unit_cost_currency, quantity, remaining, created_at, modified_at
+)
+SELECT md5(random()::text || clock_timestamp()::text)::uuid, p.outlet_id, p.id, p.average_cost_amount, m.currency,
+       p.stock, p.stock, now(), now()
+FROM products p
+         JOIN outlets o ON o.id = p.outlet_id
+         JOIN merchants m ON m.id = o.merchant_id
+WHERE p.stock > 0;
+
+INSERT INTO stock_cost_layers (
+    id, outlet_id, product_id, variant_id, unit_cost_amount, unit_cost_currency, quantity, remaining, created_at,
+    modified_at
+)
+SELECT md5(random()::text || clock_timestamp()::text)::uuid, p.outlet_id, v.product_id, v.id, v.average_cost_amount,
+       m.currency, v.stock, v.stock, now(), now()
+FROM product_variants v
+         JOIN products p ON p.id = v.product_id
+         JOIN outlets o ON o.id = p.outlet_id
+         JOIN merchants m ON m.id = o.merchant_id
+WHERE v.stock > 0;
@@ -0,0 +1,4 @@
+DROP TABLE recipe_lines;
+
+ALTER TABLE products
+    DROP COLUMN is_ingredient;
@@ -0,0 +1,11 @@
+ALTER TABLE products
+    ADD COLUMN is_ingredient boolean NOT NULL DEFAULT false;
+
+CREATE TABLE recipe_lines (
+    product_id    uuid NOT NULL CONSTRAINT fk_recipe_lines_product_id REFERENCES products (id),
+    ingredient_id uuid NOT NULL CONSTRAINT fk_recipe_lines_ingredient_id REFERENCES products (id),
+    quantity      bigint NOT NULL,
+    PRIMARY KEY (product_id, ingredient_id),
+    CONSTRAINT ck_recipe_lines_quantity CHECK (quantity > 0)
+);
+CREATE INDEX idx_recipe_lines_ingredient_id ON recipe_lines (ingredient_id);
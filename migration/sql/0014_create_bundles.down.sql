@@ -0,0 +1,6 @@
+DROP TABLE transaction_item_components;
+DROP TABLE bundle_options;
+DROP TABLE bundle_slots;
+
+ALTER TABLE products
+    DROP COLUMN is_bundle;
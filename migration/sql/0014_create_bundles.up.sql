@@ -0,0 +1,54 @@
+ALTER TABLE products
+    ADD COLUMN is_bundle boolean NOT NULL DEFAULT false;
+
+CREATE TABLE bundle_slots (
+    id          uuid PRIMARY KEY,
+    product_id  uuid NOT NULL CONSTRAINT fk_bundle_slots_product_id REFERENCES products (id),
+    name        varchar(255),
+    quantity    bigint NOT NULL,
+    sort_order  integer NOT NULL DEFAULT 0,
+    created_at  timestamptz,
+    modified_at timestamptz,
+    deleted_at  timestamptz,
+    CONSTRAINT ck_bundle_slots_quantity CHECK (quantity > 0)
+);
+CREATE INDEX idx_bundle_slots_product_id ON bundle_slots (product_id);
+CREATE INDEX idx_bundle_slots_deleted_at ON bundle_slots (deleted_at);
+CREATE UNIQUE INDEX uq_bundle_slots_product_id_name ON bundle_slots (product_id, name) WHERE deleted_at IS NULL;
+
+CREATE TABLE bundle_options (
+    id                   uuid PRIMARY KEY,
+    slot_id              uuid NOT NULL CONSTRAINT fk_bundle_options_slot_id REFERENCES bundle_slots (id),
+    product_id           uuid NOT NULL CONSTRAINT fk_bundle_options_product_id REFERENCES products (id),
+    variant_id           uuid CONSTRAINT fk_bundle_options_variant_id REFERENCES product_variants (id),
+    price_delta_amount   bigint NOT NULL DEFAULT 0,
+    price_delta_currency char(3) NOT NULL,
+    sort_order           integer NOT NULL DEFAULT 0,
+    created_at           timestamptz,
+    modified_at          timestamptz,
+    deleted_at           timestamptz
+);
+CREATE INDEX idx_bundle_options_slot_id ON bundle_options (slot_id);
+CREATE INDEX idx_bundle_options_deleted_at ON bundle_options (deleted_at);
+
+CREATE TABLE transaction_item_components (
+    id                   uuid PRIMARY KEY,
+    transaction_item_id  uuid NOT NULL
+        CONSTRAINT fk_transaction_item_components_transaction_item_id REFERENCES transaction_items (id),
+    option_id            uuid NOT NULL
+        CONSTRAINT fk_transaction_item_components_option_id REFERENCES bundle_options (id),
+    slot_name            varchar(255),
+    product_id           uuid NOT NULL
+        CONSTRAINT fk_transaction_item_components_product_id REFERENCES products (id),
+    variant_id           uuid CONSTRAINT fk_transaction_item_components_variant_id REFERENCES product_variants (id),
+    name                 varchar(255),
+    variant_name         varchar(255),
+    quantity             bigint NOT NULL,
+    price_delta_amount   bigint NOT NULL,
+    price_delta_currency char(3) NOT NULL,
+    created_at           timestamptz,
+    modified_at          timestamptz,
+    deleted_at           timestamptz
+);
+CREATE INDEX idx_transaction_item_components_transaction_item_id ON transaction_item_components (transaction_item_id);
+CREATE INDEX idx_transaction_item_components_deleted_at ON transaction_item_components (deleted_at);
@@ -0,0 +1,16 @@
+DROP TABLE transaction_payments;
+
+ALTER TABLE transactions
+    DROP COLUMN change_currency,
+    DROP COLUMN change_amount,
+    DROP COLUMN paid_currency,
+    DROP COLUMN paid_amount,
+    DROP COLUMN rounding_currency,
+    DROP COLUMN rounding_amount;
+
+DROP TABLE merchant_payment_methods;
+
+ALTER TABLE merchants
+    DROP CONSTRAINT ck_merchants_cash_rounding,
+    DROP COLUMN cash_rounding_mode,
+    DROP COLUMN cash_rounding;
@@ -0,0 +1,50 @@
+-- the amount left to pay in cash is rounded to cash_rounding minor units, zero leaves it as is
+ALTER TABLE merchants
+    ADD COLUMN cash_rounding      bigint NOT NULL DEFAULT 0,
+    ADD COLUMN cash_rounding_mode varchar(16) NOT NULL DEFAULT 'nearest',
+    ADD CONSTRAINT ck_merchants_cash_rounding CHECK (cash_rounding >= 0);
+
+CREATE TABLE merchant_payment_methods (
+    merchant_id uuid NOT NULL CONSTRAINT fk_merchant_payment_methods_merchant_id REFERENCES merchants (id),
+    method      varchar(32) NOT NULL,
+    PRIMARY KEY (merchant_id, method)
+);
+
+-- the merchants there are take every method, like they did before the methods could be chosen
+INSERT INTO merchant_payment_methods (merchant_id, method)
+SELECT merchants.id, methods.method
+FROM merchants
+         CROSS JOIN (VALUES ('cash'), ('card'), ('e_wallet'), ('bank_transfer')) AS methods (method);
+
+-- the sales made before tenders were recorded are taken to be paid exactly
+ALTER TABLE transactions
+    ADD COLUMN rounding_amount   bigint NOT NULL DEFAULT 0,
+    ADD COLUMN rounding_currency char(3) NOT NULL DEFAULT 'IDR',
+    ADD COLUMN paid_amount       bigint NOT NULL DEFAULT 0,
+    ADD COLUMN paid_currency     char(3) NOT NULL DEFAULT 'IDR',
+    ADD COLUMN change_amount     bigint NOT NULL DEFAULT 0,
+    ADD COLUMN change_currency   char(3) NOT NULL DEFAULT 'IDR';
+UPDATE transactions
+SET rounding_currency = total_currency,
+    paid_amount       = total_amount,
+    paid_currency     = total_currency,
+    change_currency   = total_currency;
+ALTER TABLE transactions
+    ALTER COLUMN rounding_currency DROP DEFAULT,
+    ALTER COLUMN paid_currency DROP DEFAULT,
+    ALTER COLUMN change_currency DROP DEFAULT;
+
+CREATE TABLE transaction_payments (
+    id              uuid PRIMARY KEY,
+    transaction_id  uuid NOT NULL CONSTRAINT fk_transaction_payments_transaction_id REFERENCES transactions (id),
+    method          varchar(32) NOT NULL,
+    amount_amount   bigint NOT NULL,
+    amount_currency char(3) NOT NULL,
+    reference       varchar(255),
+    created_at      timestamptz,
+    modified_at     timestamptz,
+    deleted_at      timestamptz,
+    CONSTRAINT ck_transaction_payments_amount CHECK (amount_amount > 0)
+);
+CREATE INDEX idx_transaction_payments_transaction_id ON transaction_payments (transaction_id);
+CREATE INDEX idx_transaction_payments_deleted_at ON transaction_payments (deleted_at);
@@ -0,0 +1,7 @@
+DROP INDEX uq_transaction_payments_provider_charge_id;
+
+ALTER TABLE transaction_payments
+    DROP COLUMN action,
+    DROP COLUMN charge_id,
+    DROP COLUMN provider,
+    DROP COLUMN status;
@@ -0,0 +1,10 @@
+-- payments made before payment providers were taken at the till, so they were captured right away
+ALTER TABLE transaction_payments
+    ADD COLUMN status    varchar(16) NOT NULL DEFAULT 'captured',
+    ADD COLUMN provider  varchar(32) NOT NULL DEFAULT '',
+    ADD COLUMN charge_id varchar(255) NOT NULL DEFAULT '',
+    ADD COLUMN action    varchar(1024) NOT NULL DEFAULT '';
+ALTER TABLE transaction_payments
+    ALTER COLUMN status DROP DEFAULT;
+CREATE UNIQUE INDEX uq_transaction_payments_provider_charge_id ON transaction_payments (provider, charge_id)
+    WHERE provider <> '';
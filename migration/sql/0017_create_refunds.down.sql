@@ -0,0 +1,21 @@
+ALTER TABLE stock_movements
+    DROP COLUMN refund_id,
+    DROP COLUMN transaction_item_id;
+
+DROP TABLE refund_payments;
+DROP TABLE refund_lines;
+DROP TABLE refunds;
+
+ALTER TABLE transaction_payments
+    DROP COLUMN refunded_currency,
+    DROP COLUMN refunded_amount;
+
+ALTER TABLE transaction_items
+    DROP CONSTRAINT ck_transaction_items_returned_quantity,
+    DROP COLUMN returned_cost_currency,
+    DROP COLUMN returned_cost_amount,
+    DROP COLUMN returned_quantity;
+
+DROP INDEX idx_transactions_status;
+ALTER TABLE transactions
+    DROP COLUMN status;
@@ -0,0 +1,90 @@
+-- every sale so far is completed, nothing of them was refunded
+ALTER TABLE transactions
+    ADD COLUMN status varchar(32) NOT NULL DEFAULT 'completed';
+ALTER TABLE transactions
+    ALTER COLUMN status DROP DEFAULT;
+CREATE INDEX idx_transactions_status ON transactions (status);
+
+ALTER TABLE transaction_items
+    ADD COLUMN returned_quantity      bigint NOT NULL DEFAULT 0,
+    ADD COLUMN returned_cost_amount   bigint NOT NULL DEFAULT 0,
+    ADD COLUMN returned_cost_currency varchar(3) NOT NULL DEFAULT '',
+    ADD CONSTRAINT ck_transaction_items_returned_quantity CHECK (returned_quantity BETWEEN 0 AND quantity);
+
+ALTER TABLE transaction_payments
+    ADD COLUMN refunded_amount   bigint NOT NULL DEFAULT 0,
+    ADD COLUMN refunded_currency varchar(3) NOT NULL DEFAULT '';
+UPDATE transaction_payments SET refunded_currency = amount_currency;
+
+CREATE TABLE refunds (
+    id              uuid PRIMARY KEY,
+    transaction_id  uuid NOT NULL CONSTRAINT fk_refunds_transaction_id REFERENCES transactions (id),
+    outlet_id       uuid NOT NULL CONSTRAINT fk_refunds_outlet_id REFERENCES outlets (id),
+    kind            varchar(16) NOT NULL,
+    reason          varchar(255),
+    amount_amount   bigint NOT NULL,
+    amount_currency char(3) NOT NULL,
+    user_id         uuid NOT NULL CONSTRAINT fk_refunds_user_id REFERENCES users (id),
+    approved_by     uuid NOT NULL CONSTRAINT fk_refunds_approved_by REFERENCES users (id),
+    created_at      timestamptz,
+    modified_at     timestamptz,
+    deleted_at      timestamptz
+);
+CREATE INDEX idx_refunds_transaction_id ON refunds (transaction_id);
+CREATE INDEX idx_refunds_outlet_id ON refunds (outlet_id);
+CREATE INDEX idx_refunds_deleted_at ON refunds (deleted_at);
+
+CREATE TABLE refund_lines (
+    id                  uuid PRIMARY KEY,
+    refund_id           uuid NOT NULL CONSTRAINT fk_refund_lines_refund_id REFERENCES refunds (id),
+    transaction_item_id uuid NOT NULL CONSTRAINT fk_refund_lines_transaction_item_id REFERENCES transaction_items (id),
+    quantity            bigint NOT NULL,
+    restock             boolean NOT NULL,
+    amount_amount       bigint NOT NULL,
+    amount_currency     char(3) NOT NULL,
+    cost_amount         bigint NOT NULL DEFAULT 0,
+    cost_currency       varchar(3) NOT NULL DEFAULT '',
+    created_at          timestamptz,
+    modified_at         timestamptz,
+    deleted_at          timestamptz,
+    CONSTRAINT ck_refund_lines_quantity CHECK (quantity > 0)
+);
+CREATE INDEX idx_refund_lines_refund_id ON refund_lines (refund_id);
+CREATE INDEX idx_refund_lines_deleted_at ON refund_lines (deleted_at);
+
+CREATE TABLE refund_payments (
+    id              uuid PRIMARY KEY,
+    refund_id       uuid NOT NULL CONSTRAINT fk_refund_payments_refund_id REFERENCES refunds (id),
+    payment_id      uuid NOT NULL CONSTRAINT fk_refund_payments_payment_id REFERENCES transaction_payments (id),
+    method          varchar(32) NOT NULL,
+    amount_amount   bigint NOT NULL,
+    amount_currency char(3) NOT NULL,
+    created_at      timestamptz,
+    modified_at     timestamptz,
+    deleted_at      timestamptz,
+    CONSTRAINT ck_refund_payments_amount CHECK (amount_amount > 0)
+);
+CREATE INDEX idx_refund_payments_refund_id ON refund_payments (refund_id);
+CREATE INDEX idx_refund_payments_deleted_at ON refund_payments (deleted_at);
+
+ALTER TABLE stock_movements
+    ADD COLUMN transaction_item_id uuid
+        CONSTRAINT fk_stock_movements_transaction_item_id REFERENCES transaction_items (id),
+    ADD COLUMN refund_id           uuid CONSTRAINT fk_stock_movements_refund_id REFERENCES refunds (id);
+CREATE INDEX idx_stock_movements_transaction_item_id ON stock_movements (transaction_item_id);
+
+-- a sale made so far is matched to the only item of its transaction selling its product, or variant, and quantity;
+-- the items of bundles, recipes and sales that can't be told apart can only be written off
+UPDATE stock_movements
+SET transaction_item_id = transaction_items.id
+FROM transaction_items
+WHERE stock_movements.type = 'sale'
+  AND transaction_items.transaction_id = stock_movements.transaction_id
+  AND transaction_items.product_id = stock_movements.product_id
+  AND transaction_items.variant_id IS NOT DISTINCT FROM stock_movements.variant_id
+  AND transaction_items.quantity = -stock_movements.quantity
+  AND (SELECT COUNT(*)
+       FROM transaction_items other
+       WHERE other.transaction_id = transaction_items.transaction_id
+         AND other.product_id = transaction_items.product_id
+         AND other.variant_id IS NOT DISTINCT FROM transaction_items.variant_id) = 1;
@@ -0,0 +1,9 @@
+ALTER TABLE transactions
+    DROP COLUMN order_id;
+
+DROP TABLE order_item_options;
+DROP TABLE order_item_modifiers;
+DROP TABLE order_items;
+DROP TABLE orders;
+DROP TABLE tables;
+DROP TABLE areas;
@@ -0,0 +1,94 @@
+CREATE TABLE areas (
+    id          uuid PRIMARY KEY,
+    outlet_id   uuid NOT NULL CONSTRAINT fk_areas_outlet_id REFERENCES outlets (id),
+    name        varchar(255) NOT NULL,
+    sort_order  integer NOT NULL DEFAULT 0,
+    created_at  timestamptz,
+    modified_at timestamptz,
+    deleted_at  timestamptz
+);
+CREATE INDEX idx_areas_outlet_id ON areas (outlet_id);
+CREATE INDEX idx_areas_deleted_at ON areas (deleted_at);
+CREATE UNIQUE INDEX uq_areas_outlet_id_name ON areas (outlet_id, name) WHERE deleted_at IS NULL;
+
+CREATE TABLE tables (
+    id          uuid PRIMARY KEY,
+    outlet_id   uuid NOT NULL CONSTRAINT fk_tables_outlet_id REFERENCES outlets (id),
+    area_id     uuid CONSTRAINT fk_tables_area_id REFERENCES areas (id),
+    name        varchar(255) NOT NULL,
+    seats       bigint NOT NULL DEFAULT 0,
+    sort_order  integer NOT NULL DEFAULT 0,
+    created_at  timestamptz,
+    modified_at timestamptz,
+    deleted_at  timestamptz,
+    CONSTRAINT ck_tables_seats CHECK (seats >= 0)
+);
+CREATE INDEX idx_tables_outlet_id ON tables (outlet_id);
+CREATE INDEX idx_tables_area_id ON tables (area_id);
+CREATE INDEX idx_tables_deleted_at ON tables (deleted_at);
+CREATE UNIQUE INDEX uq_tables_outlet_id_name ON tables (outlet_id, name) WHERE deleted_at IS NULL;
+
+CREATE TABLE orders (
+    id             uuid PRIMARY KEY,
+    outlet_id      uuid NOT NULL CONSTRAINT fk_orders_outlet_id REFERENCES outlets (id),
+    table_id       uuid CONSTRAINT fk_orders_table_id REFERENCES tables (id),
+    name           varchar(255),
+    guests         bigint NOT NULL DEFAULT 0,
+    status         varchar(16) NOT NULL,
+    note           varchar(255),
+    user_id        uuid NOT NULL CONSTRAINT fk_orders_user_id REFERENCES users (id),
+    transaction_id uuid CONSTRAINT fk_orders_transaction_id REFERENCES transactions (id),
+    merged_into_id uuid CONSTRAINT fk_orders_merged_into_id REFERENCES orders (id),
+    created_at     timestamptz,
+    modified_at    timestamptz,
+    deleted_at     timestamptz,
+    CONSTRAINT ck_orders_guests CHECK (guests >= 0)
+);
+CREATE INDEX idx_orders_outlet_id ON orders (outlet_id);
+CREATE INDEX idx_orders_table_id ON orders (table_id);
+CREATE INDEX idx_orders_status ON orders (status);
+CREATE INDEX idx_orders_deleted_at ON orders (deleted_at);
+
+CREATE TABLE order_items (
+    id             uuid PRIMARY KEY,
+    order_id       uuid NOT NULL CONSTRAINT fk_order_items_order_id REFERENCES orders (id),
+    product_id     uuid NOT NULL CONSTRAINT fk_order_items_product_id REFERENCES products (id),
+    variant_id     uuid CONSTRAINT fk_order_items_variant_id REFERENCES product_variants (id),
+    name           varchar(255),
+    variant_name   varchar(255),
+    price_amount   bigint NOT NULL,
+    price_currency char(3) NOT NULL,
+    quantity       bigint NOT NULL,
+    seat           bigint NOT NULL DEFAULT 0,
+    note           varchar(255),
+    created_at     timestamptz,
+    modified_at    timestamptz,
+    deleted_at     timestamptz,
+    CONSTRAINT ck_order_items_quantity CHECK (quantity > 0),
+    CONSTRAINT ck_order_items_seat CHECK (seat >= 0)
+);
+CREATE INDEX idx_order_items_order_id ON order_items (order_id);
+CREATE INDEX idx_order_items_deleted_at ON order_items (deleted_at);
+
+CREATE TABLE order_item_modifiers (
+    id            uuid PRIMARY KEY,
+    order_item_id uuid NOT NULL CONSTRAINT fk_order_item_modifiers_order_item_id REFERENCES order_items (id),
+    modifier_id   uuid NOT NULL CONSTRAINT fk_order_item_modifiers_modifier_id REFERENCES modifiers (id),
+    created_at    timestamptz,
+    modified_at   timestamptz,
+    deleted_at    timestamptz
+);
+CREATE INDEX idx_order_item_modifiers_order_item_id ON order_item_modifiers (order_item_id);
+
+CREATE TABLE order_item_options (
+    id            uuid PRIMARY KEY,
+    order_item_id uuid NOT NULL CONSTRAINT fk_order_item_options_order_item_id REFERENCES order_items (id),
+    option_id     uuid NOT NULL CONSTRAINT fk_order_item_options_option_id REFERENCES bundle_options (id),
+    created_at    timestamptz,
+    modified_at   timestamptz,
+    deleted_at    timestamptz
+);
+CREATE INDEX idx_order_item_options_order_item_id ON order_item_options (order_item_id);
+
+ALTER TABLE transactions
+    ADD COLUMN order_id uuid CONSTRAINT fk_transactions_order_id REFERENCES orders (id);
@@ -0,0 +1,3 @@
+DROP INDEX idx_refund_payments_status;
+ALTER TABLE refund_payments
+    DROP COLUMN status;
@@ -0,0 +1,6 @@
+-- every refund payment so far was given back inside the refund, by the till or its payment provider
+ALTER TABLE refund_payments
+    ADD COLUMN status varchar(16) NOT NULL DEFAULT 'completed';
+ALTER TABLE refund_payments
+    ALTER COLUMN status DROP DEFAULT;
+CREATE INDEX idx_refund_payments_status ON refund_payments (status);
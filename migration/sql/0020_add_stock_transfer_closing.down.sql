@@ -0,0 +1,4 @@
+ALTER TABLE stock_transfer_lines
+    DROP CONSTRAINT ck_stock_transfer_lines_closed,
+    DROP COLUMN lost,
+    DROP COLUMN returned;
@@ -0,0 +1,6 @@
+-- what a closed transfer took back into the source outlet, or wrote off as lost, of what was never received
+ALTER TABLE stock_transfer_lines
+    ADD COLUMN returned bigint NOT NULL DEFAULT 0,
+    ADD COLUMN lost     bigint NOT NULL DEFAULT 0,
+    ADD CONSTRAINT ck_stock_transfer_lines_closed CHECK (returned >= 0 AND lost >= 0 AND
+                                                         received + returned + lost <= quantity);
@@ -0,0 +1,2 @@
+ALTER TABLE refresh_tokens
+    DROP COLUMN access_token_expires_at;
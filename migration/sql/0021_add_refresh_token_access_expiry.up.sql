@@ -0,0 +1,6 @@
+-- when the access token issued with a refresh token expires, which is how long its jti stays revoked
+ALTER TABLE refresh_tokens
+    ADD COLUMN access_token_expires_at timestamptz;
+
+UPDATE refresh_tokens
+SET access_token_expires_at = created_at + interval '15 minutes';
@@ -0,0 +1,14 @@
+-- approvals of refunds and voids whose credentials were refused, a cashier or an outlet with too many of them
+-- recently can't ask for approvals for a while
+CREATE TABLE failed_approvals (
+    id          uuid PRIMARY KEY,
+    outlet_id   uuid NOT NULL CONSTRAINT fk_failed_approvals_outlet_id REFERENCES outlets (id),
+    user_id     uuid NOT NULL CONSTRAINT fk_failed_approvals_user_id REFERENCES users (id),
+    email       varchar(255),
+    created_at  timestamptz,
+    modified_at timestamptz,
+    deleted_at  timestamptz
+);
+CREATE INDEX idx_failed_approvals_outlet_id_created_at ON failed_approvals (outlet_id, created_at);
+CREATE INDEX idx_failed_approvals_user_id_created_at ON failed_approvals (user_id, created_at);
+CREATE INDEX idx_failed_approvals_deleted_at ON failed_approvals (deleted_at);
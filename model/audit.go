@@ -6,7 +6,7 @@ import (
 )
 
 type Audit struct {
-	CreatedAt sql.NullTime
+	CreatedAt  sql.NullTime
 	ModifiedAt sql.NullTime
-	DeletedAt gorm.DeletedAt
+	DeletedAt  gorm.DeletedAt
 }
@@ -0,0 +1,73 @@
+package model
+
+import (
+	"database/sql"
+	"github.com/google/uuid"
+	"github.com/rehandwi03/test-case-backend-majoo/money"
+	"gorm.io/gorm"
+	"time"
+)
+
+// BundleSlot is a part of a bundle, like the drink of a combo, of which Quantity units go into every bundle sold. A
+// slot with a single option is a fixed part of the bundle, otherwise one of its options is chosen when it is sold.
+type BundleSlot struct {
+	ID        uuid.UUID `gorm:"primaryKey;type:uuid"`
+	ProductID uuid.UUID `gorm:"type:uuid;index"`
+	Name      string    `gorm:"type:string;size:255"`
+	Quantity  int64
+	SortOrder int
+	Options   []BundleOption `gorm:"foreignKey:SlotID"`
+	Audit
+}
+
+// BundleOption is a product, or a variant of it, a slot can be filled with. PriceDelta is added to the price of the
+// bundle when it is chosen, like an upsized drink, and may be zero.
+type BundleOption struct {
+	ID         uuid.UUID   `gorm:"primaryKey;type:uuid"`
+	SlotID     uuid.UUID   `gorm:"type:uuid;index"`
+	ProductID  uuid.UUID   `gorm:"type:uuid"`
+	VariantID  *uuid.UUID  `gorm:"type:uuid"`
+	PriceDelta money.Money `gorm:"embedded;embeddedPrefix:price_delta_"`
+	SortOrder  int
+	Product    *Product        `gorm:"foreignKey:ProductID"`
+	Variant    *ProductVariant `gorm:"foreignKey:VariantID"`
+	Audit
+}
+
+func (b *BundleSlot) PrimaryKey() uuid.UUID {
+	return b.ID
+}
+
+func (b *BundleSlot) BeforeCreate(tx *gorm.DB) (err error) {
+	b.ID = uuid.New()
+
+	b.Audit.CreatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	b.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
+
+func (b *BundleSlot) BeforeUpdate(tx *gorm.DB) (err error) {
+	b.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
+
+func (b *BundleOption) PrimaryKey() uuid.UUID {
+	return b.ID
+}
+
+func (b *BundleOption) BeforeCreate(tx *gorm.DB) (err error) {
+	b.ID = uuid.New()
+
+	b.Audit.CreatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	b.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
+
+func (b *BundleOption) BeforeUpdate(tx *gorm.DB) (err error) {
+	b.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
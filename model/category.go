@@ -0,0 +1,43 @@
+package model
+
+import (
+	"database/sql"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+// Category groups the products of a merchant, a category without parent is at the top of the tree.
+type Category struct {
+	ID         uuid.UUID  `gorm:"primaryKey;type:uuid"`
+	MerchantID uuid.UUID  `gorm:"type:uuid;index"`
+	ParentID   *uuid.UUID `gorm:"type:uuid;index"`
+	Name       string     `gorm:"type:string;size:255"`
+	SortOrder  int
+	Audit
+}
+
+// ProductCategory assigns a product to a category, a product may be in many categories.
+type ProductCategory struct {
+	ProductID  uuid.UUID `gorm:"primaryKey;type:uuid"`
+	CategoryID uuid.UUID `gorm:"primaryKey;type:uuid"`
+}
+
+func (c *Category) PrimaryKey() uuid.UUID {
+	return c.ID
+}
+
+func (c *Category) BeforeCreate(tx *gorm.DB) (err error) {
+	c.ID = uuid.New()
+
+	c.Audit.CreatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	c.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
+
+func (c *Category) BeforeUpdate(tx *gorm.DB) (err error) {
+	c.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
@@ -7,15 +7,34 @@ import (
 	"time"
 )
 
+// Ways a merchant values the stock its sales take out, stored on Merchant.CostingMethod. With CostingAverage a unit
+// costs the weighted average of what the stock on hand was bought at, with CostingFIFO it costs what the oldest unit
+// still in stock was bought at.
+const (
+	CostingAverage = "average"
+	CostingFIFO    = "fifo"
+)
+
 type Merchant struct {
 	ID              uuid.UUID `gorm:"primaryKey;type:uuid"`
 	UserID          uuid.UUID `gorm:"type:uuid"`
 	Name            string    `gorm:"type:string;size:255"`
 	InstitutionName string    `gorm:"type:string;size:255"`
 	PhoneNumber     string    `gorm:"type:string;size:13"`
+	Currency        string    `gorm:"type:char(3)"`
+	CostingMethod   string    `gorm:"type:string;size:16"`
+	// CashRounding is the minor units the amount left to pay in cash is rounded to, like 10000 for Rp 100, zero
+	// leaves it as is.
+	CashRounding     int64
+	CashRoundingMode string                  `gorm:"type:string;size:16"`
+	PaymentMethods   []MerchantPaymentMethod `gorm:"foreignKey:MerchantID"`
 	Audit
 }
 
+func (m *Merchant) PrimaryKey() uuid.UUID {
+	return m.ID
+}
+
 func (m *Merchant) BeforeCreate(tx *gorm.DB) (err error) {
 	m.ID = uuid.New()
 
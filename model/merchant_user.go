@@ -0,0 +1,35 @@
+package model
+
+import (
+	"database/sql"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+type MerchantUser struct {
+	ID         uuid.UUID `gorm:"primaryKey;type:uuid"`
+	MerchantID uuid.UUID `gorm:"type:uuid;index"`
+	UserID     uuid.UUID `gorm:"type:uuid;index"`
+	Role       string    `gorm:"type:string;size:20"`
+	Audit
+}
+
+func (m *MerchantUser) PrimaryKey() uuid.UUID {
+	return m.ID
+}
+
+func (m *MerchantUser) BeforeCreate(tx *gorm.DB) (err error) {
+	m.ID = uuid.New()
+
+	m.Audit.CreatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	m.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
+
+func (m *MerchantUser) BeforeUpdate(tx *gorm.DB) (err error) {
+	m.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
@@ -0,0 +1,70 @@
+package model
+
+import (
+	"database/sql"
+	"github.com/google/uuid"
+	"github.com/rehandwi03/test-case-backend-majoo/money"
+	"gorm.io/gorm"
+	"time"
+)
+
+// ModifierGroup is a set of add-ons of a product, like extras or sugar level, of which at least MinSelect and at most
+// MaxSelect are chosen when the product is sold.
+type ModifierGroup struct {
+	ID        uuid.UUID `gorm:"primaryKey;type:uuid"`
+	ProductID uuid.UUID `gorm:"type:uuid;index"`
+	Name      string    `gorm:"type:string;size:255"`
+	MinSelect int
+	MaxSelect int
+	SortOrder int
+	Modifiers []Modifier `gorm:"foreignKey:GroupID"`
+	Audit
+}
+
+// Modifier is an add-on changing the price of the product it is chosen for by PriceDelta, which may be zero.
+type Modifier struct {
+	ID         uuid.UUID   `gorm:"primaryKey;type:uuid"`
+	GroupID    uuid.UUID   `gorm:"type:uuid;index"`
+	Name       string      `gorm:"type:string;size:255"`
+	PriceDelta money.Money `gorm:"embedded;embeddedPrefix:price_delta_"`
+	SortOrder  int
+	Audit
+}
+
+func (m *ModifierGroup) PrimaryKey() uuid.UUID {
+	return m.ID
+}
+
+func (m *ModifierGroup) BeforeCreate(tx *gorm.DB) (err error) {
+	m.ID = uuid.New()
+
+	m.Audit.CreatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	m.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
+
+func (m *ModifierGroup) BeforeUpdate(tx *gorm.DB) (err error) {
+	m.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
+
+func (m *Modifier) PrimaryKey() uuid.UUID {
+	return m.ID
+}
+
+func (m *Modifier) BeforeCreate(tx *gorm.DB) (err error) {
+	m.ID = uuid.New()
+
+	m.Audit.CreatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	m.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
+
+func (m *Modifier) BeforeUpdate(tx *gorm.DB) (err error) {
+	m.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
@@ -0,0 +1,49 @@
+package model
+
+import (
+	"database/sql"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+// Kinds of notification, stored on Notification.Type.
+const (
+	NotificationLowStock = "low_stock"
+)
+
+// Notification tells the staff of an outlet about something that needs their attention. A low stock notification is
+// raised by the stock movement that took a product, or one of its variants, down to its reorder point and records the
+// stock it left along with the reorder point and quantity of the product at the time.
+type Notification struct {
+	ID              uuid.UUID  `gorm:"primaryKey;type:uuid"`
+	OutletID        uuid.UUID  `gorm:"type:uuid;index"`
+	Type            string     `gorm:"type:string;size:32"`
+	ProductID       *uuid.UUID `gorm:"type:uuid"`
+	VariantID       *uuid.UUID `gorm:"type:uuid"`
+	StockMovementID *uuid.UUID `gorm:"type:uuid"`
+	Message         string     `gorm:"type:string;size:255"`
+	Stock           int64
+	ReorderPoint    int64
+	ReorderQuantity int64
+	Audit
+}
+
+func (n *Notification) PrimaryKey() uuid.UUID {
+	return n.ID
+}
+
+func (n *Notification) BeforeCreate(tx *gorm.DB) (err error) {
+	n.ID = uuid.New()
+
+	n.Audit.CreatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	n.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
+
+func (n *Notification) BeforeUpdate(tx *gorm.DB) (err error) {
+	n.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
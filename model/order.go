@@ -0,0 +1,140 @@
+package model
+
+import (
+	"database/sql"
+	"github.com/google/uuid"
+	"github.com/rehandwi03/test-case-backend-majoo/money"
+	"gorm.io/gorm"
+	"time"
+)
+
+// States of an order, stored on Order.Status. An open order takes items, a parked one is held as it is until it is
+// resumed. An order is closed by the sale it is checked out as, merged into another order or cancelled.
+const (
+	OrderOpen      = "open"
+	OrderParked    = "parked"
+	OrderClosed    = "closed"
+	OrderMerged    = "merged"
+	OrderCancelled = "cancelled"
+)
+
+// Order is an open bill of an outlet, at a table or not, items are added to until it is checked out as a sale.
+// Name tells orders without a table apart, like the name of a takeaway customer.
+type Order struct {
+	ID            uuid.UUID  `gorm:"primaryKey;type:uuid"`
+	OutletID      uuid.UUID  `gorm:"type:uuid;index"`
+	TableID       *uuid.UUID `gorm:"type:uuid;index"`
+	Name          string     `gorm:"type:string;size:255"`
+	Guests        int64
+	Status        string     `gorm:"type:string;size:16"`
+	Note          string     `gorm:"type:string;size:255"`
+	UserID        uuid.UUID  `gorm:"type:uuid"`
+	TransactionID *uuid.UUID `gorm:"type:uuid"`
+	MergedIntoID  *uuid.UUID `gorm:"type:uuid"`
+	Items         []OrderItem
+	Audit
+}
+
+// OrderItem is a product, or a variant of it, ordered for a seat of the table, seat 0 being shared by the table.
+// Name, VariantName and Price are what it was when it was ordered, the sale prices it as it is at checkout.
+type OrderItem struct {
+	ID          uuid.UUID  `gorm:"primaryKey;type:uuid"`
+	OrderID     uuid.UUID  `gorm:"type:uuid;index"`
+	ProductID   uuid.UUID  `gorm:"type:uuid"`
+	VariantID   *uuid.UUID `gorm:"type:uuid"`
+	Name        string     `gorm:"type:string;size:255"`
+	VariantName string     `gorm:"type:string;size:255"`
+	// Price is the unit price, including the price deltas of the modifiers and bundle options.
+	Price     money.Money `gorm:"embedded;embeddedPrefix:price_"`
+	Quantity  int64
+	Seat      int64
+	Note      string `gorm:"type:string;size:255"`
+	Modifiers []OrderItemModifier
+	Options   []OrderItemOption
+	Audit
+}
+
+// OrderItemModifier is a modifier chosen for an item of an order.
+type OrderItemModifier struct {
+	ID          uuid.UUID `gorm:"primaryKey;type:uuid"`
+	OrderItemID uuid.UUID `gorm:"type:uuid;index"`
+	ModifierID  uuid.UUID `gorm:"type:uuid"`
+	Audit
+}
+
+// OrderItemOption is a bundle option chosen for an item of an order.
+type OrderItemOption struct {
+	ID          uuid.UUID `gorm:"primaryKey;type:uuid"`
+	OrderItemID uuid.UUID `gorm:"type:uuid;index"`
+	OptionID    uuid.UUID `gorm:"type:uuid"`
+	Audit
+}
+
+// IsActive reports whether the order is still being served, open or parked.
+func (o *Order) IsActive() bool {
+	return o.Status == OrderOpen || o.Status == OrderParked
+}
+
+func (o *Order) PrimaryKey() uuid.UUID {
+	return o.ID
+}
+
+func (o *Order) BeforeCreate(tx *gorm.DB) (err error) {
+	o.ID = uuid.New()
+
+	o.Audit.CreatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	o.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
+
+func (o *Order) BeforeUpdate(tx *gorm.DB) (err error) {
+	o.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
+
+func (o *OrderItem) BeforeCreate(tx *gorm.DB) (err error) {
+	o.ID = uuid.New()
+
+	o.Audit.CreatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	o.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
+
+func (o *OrderItem) BeforeUpdate(tx *gorm.DB) (err error) {
+	o.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
+
+func (o *OrderItemModifier) BeforeCreate(tx *gorm.DB) (err error) {
+	o.ID = uuid.New()
+
+	o.Audit.CreatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	o.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
+
+func (o *OrderItemModifier) BeforeUpdate(tx *gorm.DB) (err error) {
+	o.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
+
+func (o *OrderItemOption) BeforeCreate(tx *gorm.DB) (err error) {
+	o.ID = uuid.New()
+
+	o.Audit.CreatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	o.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
+
+func (o *OrderItemOption) BeforeUpdate(tx *gorm.DB) (err error) {
+	o.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
@@ -16,6 +16,10 @@ type Outlet struct {
 	Audit
 }
 
+func (o *Outlet) PrimaryKey() uuid.UUID {
+	return o.ID
+}
+
 func (o *Outlet) BeforeCreate(tx *gorm.DB) (err error) {
 	o.ID = uuid.New()
 
@@ -0,0 +1,94 @@
+package model
+
+import (
+	"database/sql"
+	"github.com/google/uuid"
+	"github.com/rehandwi03/test-case-backend-majoo/money"
+	"gorm.io/gorm"
+	"time"
+)
+
+// Ways a sale is paid for, stored on MerchantPaymentMethod.Method and TransactionPayment.Method. Only cash is given
+// change, the others are charged the exact amount they pay.
+const (
+	PaymentCash         = "cash"
+	PaymentCard         = "card"
+	PaymentEWallet      = "e_wallet"
+	PaymentBankTransfer = "bank_transfer"
+	PaymentQRIS         = "qris"
+)
+
+// PaymentMethods are the payment methods a merchant can enable, all of them are enabled for a new merchant unless it
+// chooses.
+var PaymentMethods = []string{PaymentCash, PaymentCard, PaymentEWallet, PaymentBankTransfer, PaymentQRIS}
+
+// Statuses of a TransactionPayment, the ones of the charge at the gateway for a payment going through one. A payment
+// taken at the till is captured right away.
+const (
+	PaymentPending    = "pending"
+	PaymentAuthorized = "authorized"
+	PaymentCaptured   = "captured"
+	PaymentFailed     = "failed"
+	PaymentRefunded   = "refunded"
+)
+
+// Ways the amount left to pay in cash is rounded to Merchant.CashRounding, stored on Merchant.CashRoundingMode.
+const (
+	CashRoundNearest = "nearest"
+	CashRoundDown    = "down"
+	CashRoundUp      = "up"
+)
+
+// MerchantPaymentMethod is a payment method the outlets of a merchant take.
+type MerchantPaymentMethod struct {
+	MerchantID uuid.UUID `gorm:"primaryKey;type:uuid"`
+	Method     string    `gorm:"primaryKey;type:string;size:32"`
+}
+
+// TransactionPayment is a tender paying for a sale, as it was handed over. Cash may be more than what was left to
+// pay, the transaction records the change given back.
+type TransactionPayment struct {
+	ID            uuid.UUID   `gorm:"primaryKey;type:uuid"`
+	TransactionID uuid.UUID   `gorm:"type:uuid;index"`
+	Method        string      `gorm:"type:string;size:32"`
+	Amount        money.Money `gorm:"embedded;embeddedPrefix:amount_"`
+	// Reference identifies the payment outside the till, like the approval code of a card.
+	Reference string `gorm:"type:string;size:255"`
+	Status    string `gorm:"type:string;size:16"`
+	// Provider is the payment provider the payment went through, if any, where it is the charge ChargeID. Action is
+	// what the customer pays a pending charge with, like the string of a QRIS code.
+	Provider string `gorm:"type:string;size:32"`
+	ChargeID string `gorm:"type:string;size:255"`
+	Action   string `gorm:"type:string;size:1024"`
+	// Refunded is what refunds of the sale gave back of the payment, it is refunded once all it paid is.
+	Refunded money.Money `gorm:"embedded;embeddedPrefix:refunded_"`
+	Audit
+}
+
+// IsPaid reports whether the payment paid for the sale: it was taken, or approved to be, and not given up. A refunded
+// payment paid too, the refund gave it back.
+func (t *TransactionPayment) IsPaid() bool {
+	return t.Status == PaymentAuthorized || t.Status == PaymentCaptured || t.Status == PaymentRefunded
+}
+
+func (t *TransactionPayment) PrimaryKey() uuid.UUID {
+	return t.ID
+}
+
+// BeforeCreate keeps an id given before, like the one the charge of the payment was created with.
+func (t *TransactionPayment) BeforeCreate(tx *gorm.DB) (err error) {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+
+	t.Audit.CreatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	t.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
+
+func (t *TransactionPayment) BeforeUpdate(tx *gorm.DB) (err error) {
+	t.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
@@ -3,21 +3,44 @@ package model
 import (
 	"database/sql"
 	"github.com/google/uuid"
+	"github.com/rehandwi03/test-case-backend-majoo/money"
 	"gorm.io/gorm"
 	"time"
 )
 
+// Product is sold by an outlet. ReorderPoint is the stock at or below which the product, or any of its variants, is low
+// on stock, nil when it isn't watched, and ReorderQuantity how much to order then. CostPrice is what a unit was last
+// bought at, it is only written by receiving purchases, and AverageCost the weighted average cost of the stock on hand
+// kept by the ledger. An ingredient isn't sold on its own but goes into the Recipe of other products. A bundle is sold
+// at its own price as the products filling its BundleSlots.
 type Product struct {
-	ID          uuid.UUID `gorm:"primaryKey;type:uuid"`
-	OutletID    uuid.UUID `gorm:"type:uuid"`
-	Name        string    `gorm:"type:string;size:255"`
-	Description string    `gorm:"type:string;size:255"`
-	Stock       int64
-	Price       float64
-	Image       string `gorm:"type:string;size:255"`
+	ID              uuid.UUID `gorm:"primaryKey;type:uuid"`
+	OutletID        uuid.UUID `gorm:"type:uuid"`
+	Name            string    `gorm:"type:string;size:255"`
+	Description     string    `gorm:"type:string;size:255"`
+	SKU             string    `gorm:"column:sku;type:string;size:64"`
+	IsIngredient    bool
+	IsBundle        bool
+	Stock           int64
+	ReorderPoint    *int64
+	ReorderQuantity int64
+	Price           money.Money `gorm:"embedded;embeddedPrefix:price_"`
+	CostPrice       money.Money `gorm:"embedded;embeddedPrefix:cost_price_"`
+	AverageCost     money.Money `gorm:"embedded;embeddedPrefix:average_cost_"`
+	Image           string      `gorm:"type:string;size:255"`
+	Categories      []Category  `gorm:"many2many:product_categories"`
+	Options         []ProductOption
+	Variants        []ProductVariant
+	ModifierGroups  []ModifierGroup
+	Recipe          []RecipeLine
+	BundleSlots     []BundleSlot
 	Audit
 }
 
+func (p *Product) PrimaryKey() uuid.UUID {
+	return p.ID
+}
+
 func (p *Product) BeforeCreate(tx *gorm.DB) (err error) {
 	p.ID = uuid.New()
 
@@ -0,0 +1,86 @@
+package model
+
+import (
+	"database/sql"
+	"github.com/google/uuid"
+	"github.com/rehandwi03/test-case-backend-majoo/money"
+	"gorm.io/gorm"
+	"time"
+)
+
+// States of a purchase order, stored on PurchaseOrder.Status.
+const (
+	PurchaseOrderDraft             = "draft"
+	PurchaseOrderOrdered           = "ordered"
+	PurchaseOrderPartiallyReceived = "partially_received"
+	PurchaseOrderReceived          = "received"
+	PurchaseOrderCancelled         = "cancelled"
+)
+
+// PurchaseOrder orders goods of a supplier for an outlet. Total is what the lines are expected to cost, the stock of
+// the outlet only grows as the goods are received, possibly in several receipts.
+type PurchaseOrder struct {
+	ID         uuid.UUID   `gorm:"primaryKey;type:uuid"`
+	MerchantID uuid.UUID   `gorm:"type:uuid;index"`
+	SupplierID uuid.UUID   `gorm:"type:uuid;index"`
+	OutletID   uuid.UUID   `gorm:"type:uuid;index"`
+	Status     string      `gorm:"type:string;size:32"`
+	Note       string      `gorm:"type:string;size:255"`
+	Total      money.Money `gorm:"embedded;embeddedPrefix:total_"`
+	CreatedBy  uuid.UUID   `gorm:"type:uuid"`
+	OrderedBy  *uuid.UUID  `gorm:"type:uuid"`
+	OrderedAt  sql.NullTime
+	Lines      []PurchaseOrderLine `gorm:"foreignKey:OrderID"`
+	Audit
+}
+
+// PurchaseOrderLine is a product, or a variant of it, ordered at an expected unit cost. ReceivedCost adds up what the
+// received units actually cost.
+type PurchaseOrderLine struct {
+	ID           uuid.UUID  `gorm:"primaryKey;type:uuid"`
+	OrderID      uuid.UUID  `gorm:"type:uuid;index"`
+	ProductID    uuid.UUID  `gorm:"type:uuid"`
+	VariantID    *uuid.UUID `gorm:"type:uuid"`
+	SKU          string     `gorm:"column:sku;type:string;size:64"`
+	Name         string     `gorm:"type:string;size:255"`
+	VariantName  string     `gorm:"type:string;size:255"`
+	Quantity     int64
+	UnitCost     money.Money `gorm:"embedded;embeddedPrefix:unit_cost_"`
+	Received     int64
+	ReceivedCost money.Money `gorm:"embedded;embeddedPrefix:received_cost_"`
+	Audit
+}
+
+func (p *PurchaseOrder) PrimaryKey() uuid.UUID {
+	return p.ID
+}
+
+func (p *PurchaseOrder) BeforeCreate(tx *gorm.DB) (err error) {
+	p.ID = uuid.New()
+
+	p.Audit.CreatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	p.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
+
+func (p *PurchaseOrder) BeforeUpdate(tx *gorm.DB) (err error) {
+	p.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
+
+func (p *PurchaseOrderLine) BeforeCreate(tx *gorm.DB) (err error) {
+	p.ID = uuid.New()
+
+	p.Audit.CreatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	p.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
+
+func (p *PurchaseOrderLine) BeforeUpdate(tx *gorm.DB) (err error) {
+	p.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
@@ -0,0 +1,13 @@
+package model
+
+import "github.com/google/uuid"
+
+// RecipeLine is how much of an ingredient a unit of a product takes, in the unit the stock of the ingredient is kept
+// in, like grams of cheese in a burger. Selling a product with a recipe takes its ingredients out of the stock instead
+// of the product itself.
+type RecipeLine struct {
+	ProductID    uuid.UUID `gorm:"primaryKey;type:uuid"`
+	IngredientID uuid.UUID `gorm:"primaryKey;type:uuid"`
+	Quantity     int64
+	Ingredient   *Product `gorm:"foreignKey:IngredientID"`
+}
@@ -0,0 +1,59 @@
+package model
+
+import (
+	"database/sql"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+// RefreshToken is one link of a login session. Every refresh revokes the current token and issues the next one in
+// the same family, so a family is the whole session and reusing a revoked token revokes the session. The access token
+// issued with it is kept revoked until its own AccessTokenExpiresAt once the session ends.
+type RefreshToken struct {
+	ID                   uuid.UUID `gorm:"primaryKey;type:uuid"`
+	UserID               uuid.UUID `gorm:"type:uuid;index"`
+	FamilyID             uuid.UUID `gorm:"type:uuid;index"`
+	AccessTokenID        uuid.UUID `gorm:"type:uuid"`
+	AccessTokenExpiresAt time.Time
+	TokenHash            string `gorm:"type:string;size:64"`
+	ExpiresAt            time.Time
+	RevokedAt            sql.NullTime
+	Audit
+}
+
+func (r *RefreshToken) PrimaryKey() uuid.UUID {
+	return r.ID
+}
+
+func (r *RefreshToken) BeforeCreate(tx *gorm.DB) (err error) {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+
+	r.Audit.CreatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	r.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
+
+func (r *RefreshToken) BeforeUpdate(tx *gorm.DB) (err error) {
+	r.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
+
+// RevokedToken is the id (jti) of an access token that must be rejected before it expires.
+type RevokedToken struct {
+	ID        uuid.UUID `gorm:"primaryKey;type:uuid"`
+	UserID    uuid.UUID `gorm:"type:uuid"`
+	ExpiresAt time.Time
+	Audit
+}
+
+func (r *RevokedToken) BeforeCreate(tx *gorm.DB) (err error) {
+	r.Audit.CreatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	r.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
@@ -0,0 +1,149 @@
+package model
+
+import (
+	"database/sql"
+	"github.com/google/uuid"
+	"github.com/rehandwi03/test-case-backend-majoo/money"
+	"gorm.io/gorm"
+	"time"
+)
+
+// Statuses of a Transaction. A sale is pending at checkout while a payment of it is still pending at its provider,
+// completed once all of them are paid and unpaid when one of them fails. A completed sale is partially refunded once
+// some of its items are returned and refunded once all of them are. A voided sale was cancelled as a whole.
+const (
+	TransactionPending           = "pending"
+	TransactionUnpaid            = "unpaid"
+	TransactionCompleted         = "completed"
+	TransactionPartiallyRefunded = "partially_refunded"
+	TransactionRefunded          = "refunded"
+	TransactionVoided            = "voided"
+)
+
+// Kinds of Refund. A void gives back all of a sale nothing was refunded of yet, a refund some of its items.
+const (
+	RefundKindRefund = "refund"
+	RefundKindVoid   = "void"
+)
+
+// Refund gives back items of a sale and what they were paid, approved by an owner or manager of the merchant.
+// Amount is what its payments gave back.
+type Refund struct {
+	ID            uuid.UUID   `gorm:"primaryKey;type:uuid"`
+	TransactionID uuid.UUID   `gorm:"type:uuid;index"`
+	OutletID      uuid.UUID   `gorm:"type:uuid;index"`
+	Kind          string      `gorm:"type:string;size:16"`
+	Reason        string      `gorm:"type:string;size:255"`
+	Amount        money.Money `gorm:"embedded;embeddedPrefix:amount_"`
+	UserID        uuid.UUID   `gorm:"type:uuid"`
+	ApprovedBy    uuid.UUID   `gorm:"type:uuid"`
+	Lines         []RefundLine
+	Payments      []RefundPayment
+	Audit
+}
+
+// RefundLine returns Quantity units of an item of the sale, worth Amount at the price they were sold at. A restocked
+// line puts the units back into the stock they were sold out of, worth Cost, the units of a line written off are
+// thrown away.
+type RefundLine struct {
+	ID                uuid.UUID `gorm:"primaryKey;type:uuid"`
+	RefundID          uuid.UUID `gorm:"type:uuid;index"`
+	TransactionItemID uuid.UUID `gorm:"type:uuid"`
+	Quantity          int64
+	Restock           bool
+	Amount            money.Money `gorm:"embedded;embeddedPrefix:amount_"`
+	Cost              money.Money `gorm:"embedded;embeddedPrefix:cost_"`
+	Audit
+}
+
+// Statuses of a RefundPayment. What the till gives back is completed with the refund, what a payment provider does
+// is pending until the provider took the refund, or failed when it won't.
+const (
+	RefundPaymentPending   = "pending"
+	RefundPaymentCompleted = "completed"
+	RefundPaymentFailed    = "failed"
+)
+
+// RefundPayment gives Amount back by a payment of the sale, through the payment provider of the payment if it went
+// through one.
+type RefundPayment struct {
+	ID        uuid.UUID   `gorm:"primaryKey;type:uuid"`
+	RefundID  uuid.UUID   `gorm:"type:uuid;index"`
+	PaymentID uuid.UUID   `gorm:"type:uuid"`
+	Method    string      `gorm:"type:string;size:32"`
+	Amount    money.Money `gorm:"embedded;embeddedPrefix:amount_"`
+	Status    string      `gorm:"type:string;size:16;index"`
+	Audit
+}
+
+// FailedApproval is an approval of a refund or void asked for by UserID in the outlet with credentials that were
+// refused, Email is the one tried.
+type FailedApproval struct {
+	ID       uuid.UUID `gorm:"primaryKey;type:uuid"`
+	OutletID uuid.UUID `gorm:"type:uuid"`
+	UserID   uuid.UUID `gorm:"type:uuid"`
+	Email    string    `gorm:"type:string;size:255"`
+	Audit
+}
+
+func (r *Refund) PrimaryKey() uuid.UUID {
+	return r.ID
+}
+
+func (r *Refund) BeforeCreate(tx *gorm.DB) (err error) {
+	r.ID = uuid.New()
+
+	r.Audit.CreatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	r.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
+
+func (r *Refund) BeforeUpdate(tx *gorm.DB) (err error) {
+	r.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
+
+func (r *RefundLine) BeforeCreate(tx *gorm.DB) (err error) {
+	r.ID = uuid.New()
+
+	r.Audit.CreatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	r.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
+
+func (r *RefundLine) BeforeUpdate(tx *gorm.DB) (err error) {
+	r.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
+
+func (r *RefundPayment) BeforeCreate(tx *gorm.DB) (err error) {
+	r.ID = uuid.New()
+
+	r.Audit.CreatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	r.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
+
+func (r *RefundPayment) BeforeUpdate(tx *gorm.DB) (err error) {
+	r.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
+
+func (f *FailedApproval) PrimaryKey() uuid.UUID {
+	return f.ID
+}
+
+func (f *FailedApproval) BeforeCreate(tx *gorm.DB) (err error) {
+	f.ID = uuid.New()
+
+	f.Audit.CreatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	f.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
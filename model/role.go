@@ -0,0 +1,13 @@
+package model
+
+// Platform wide role, stored on User.Role.
+const (
+	RoleAdmin = "admin"
+)
+
+// Merchant scoped roles, stored on MerchantUser.Role.
+const (
+	RoleOwner   = "owner"
+	RoleManager = "manager"
+	RoleCashier = "cashier"
+)
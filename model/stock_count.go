@@ -0,0 +1,90 @@
+package model
+
+import (
+	"database/sql"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+// States of a stock count, stored on StockCount.Status.
+const (
+	StockCountOpen      = "open"
+	StockCountApproved  = "approved"
+	StockCountCancelled = "cancelled"
+)
+
+// StockCount is a physical inventory count of an outlet. It snapshots the stock every product and variant of the
+// outlet is expected to have when it starts, collects the counted quantities while open and, once approved, has
+// adjusted the stock by the variances.
+type StockCount struct {
+	ID         uuid.UUID  `gorm:"primaryKey;type:uuid"`
+	OutletID   uuid.UUID  `gorm:"type:uuid;index"`
+	Status     string     `gorm:"type:string;size:16"`
+	Note       string     `gorm:"type:string;size:255"`
+	CreatedBy  uuid.UUID  `gorm:"type:uuid"`
+	ApprovedBy *uuid.UUID `gorm:"type:uuid"`
+	ApprovedAt sql.NullTime
+	Lines      []StockCountLine `gorm:"foreignKey:CountID"`
+	Audit
+}
+
+// StockCountLine is a product, or a variant of it, of a stock count. Counted stays nil until it is counted.
+type StockCountLine struct {
+	ID          uuid.UUID  `gorm:"primaryKey;type:uuid"`
+	CountID     uuid.UUID  `gorm:"type:uuid;index"`
+	ProductID   uuid.UUID  `gorm:"type:uuid"`
+	VariantID   *uuid.UUID `gorm:"type:uuid"`
+	Name        string     `gorm:"type:string;size:255"`
+	VariantName string     `gorm:"type:string;size:255"`
+	Expected    int64
+	Counted     *int64
+	CountedBy   *uuid.UUID `gorm:"type:uuid"`
+	CountedAt   sql.NullTime
+	Audit
+}
+
+// Variance is how many more were counted than expected, nil while the line isn't counted.
+func (s *StockCountLine) Variance() *int64 {
+	if s.Counted == nil {
+		return nil
+	}
+
+	variance := *s.Counted - s.Expected
+
+	return &variance
+}
+
+func (s *StockCount) PrimaryKey() uuid.UUID {
+	return s.ID
+}
+
+func (s *StockCount) BeforeCreate(tx *gorm.DB) (err error) {
+	s.ID = uuid.New()
+
+	s.Audit.CreatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	s.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
+
+func (s *StockCount) BeforeUpdate(tx *gorm.DB) (err error) {
+	s.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
+
+func (s *StockCountLine) BeforeCreate(tx *gorm.DB) (err error) {
+	s.ID = uuid.New()
+
+	s.Audit.CreatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	s.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
+
+func (s *StockCountLine) BeforeUpdate(tx *gorm.DB) (err error) {
+	s.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
@@ -0,0 +1,108 @@
+package model
+
+import (
+	"database/sql"
+	"github.com/google/uuid"
+	"github.com/rehandwi03/test-case-backend-majoo/money"
+	"gorm.io/gorm"
+	"time"
+)
+
+// Kinds of stock movement, stored on StockMovement.Type.
+const (
+	MovementSale       = "sale"
+	MovementRestock    = "restock"
+	MovementAdjustment = "adjustment"
+	MovementWaste      = "waste"
+	MovementTransfer   = "transfer"
+	MovementReturn     = "return"
+)
+
+// Reasons of a stock movement posted by hand, stored on StockMovement.Reason. StockOpeningBalance is recorded for
+// the stock a product or variant is created with.
+const (
+	StockOpeningBalance  = "opening_balance"
+	StockPurchase        = "purchase"
+	StockCountCorrection = "count_correction"
+	StockDamaged         = "damaged"
+	StockExpired         = "expired"
+	StockLost            = "lost"
+	StockFound           = "found"
+	StockTheft           = "theft"
+	StockOther           = "other"
+)
+
+// StockMovement is an entry of the append-only inventory ledger of a product, or of one of its variants, in an
+// outlet. Quantity is the signed change and Balance the stock left after it, the Stock of the product or variant is
+// the balance of its last movement. Cost is the value of the units the movement brought in or took out, UnitCost
+// that of one of them: what a purchase was bought at, or what the stock taken out cost by the costing method of the
+// merchant. A sale, or the return of a refund, is of an item of a transaction, TransactionItemID.
+type StockMovement struct {
+	ID                uuid.UUID  `gorm:"primaryKey;type:uuid"`
+	OutletID          uuid.UUID  `gorm:"type:uuid;index"`
+	ProductID         uuid.UUID  `gorm:"type:uuid;index"`
+	VariantID         *uuid.UUID `gorm:"type:uuid;index"`
+	Type              string     `gorm:"type:string;size:32"`
+	Reason            string     `gorm:"type:string;size:32"`
+	Note              string     `gorm:"type:string;size:255"`
+	Quantity          int64
+	Balance           int64
+	TransactionID     *uuid.UUID  `gorm:"type:uuid"`
+	TransactionItemID *uuid.UUID  `gorm:"type:uuid"`
+	RefundID          *uuid.UUID  `gorm:"type:uuid"`
+	StockCountID      *uuid.UUID  `gorm:"type:uuid"`
+	TransferID        *uuid.UUID  `gorm:"column:stock_transfer_id;type:uuid"`
+	PurchaseOrderID   *uuid.UUID  `gorm:"type:uuid"`
+	UnitCost          money.Money `gorm:"embedded;embeddedPrefix:unit_cost_"`
+	Cost              money.Money `gorm:"embedded;embeddedPrefix:cost_"`
+	UserID            *uuid.UUID  `gorm:"type:uuid"`
+	Audit
+}
+
+// StockCostLayer is a lot of units brought into the stock of a product, or variant, at a unit cost. Units leave the
+// oldest lots first, so the remaining quantities of the layers add up to the stock and value it first in, first out.
+type StockCostLayer struct {
+	ID              uuid.UUID   `gorm:"primaryKey;type:uuid"`
+	OutletID        uuid.UUID   `gorm:"type:uuid;index"`
+	ProductID       uuid.UUID   `gorm:"type:uuid;index"`
+	VariantID       *uuid.UUID  `gorm:"type:uuid"`
+	StockMovementID *uuid.UUID  `gorm:"type:uuid"`
+	UnitCost        money.Money `gorm:"embedded;embeddedPrefix:unit_cost_"`
+	Quantity        int64
+	Remaining       int64
+	Audit
+}
+
+func (s *StockMovement) PrimaryKey() uuid.UUID {
+	return s.ID
+}
+
+func (s *StockMovement) BeforeCreate(tx *gorm.DB) (err error) {
+	s.ID = uuid.New()
+
+	s.Audit.CreatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	s.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
+
+func (s *StockMovement) BeforeUpdate(tx *gorm.DB) (err error) {
+	s.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
+
+func (s *StockCostLayer) BeforeCreate(tx *gorm.DB) (err error) {
+	s.ID = uuid.New()
+
+	s.Audit.CreatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	s.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
+
+func (s *StockCostLayer) BeforeUpdate(tx *gorm.DB) (err error) {
+	s.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
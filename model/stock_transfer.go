@@ -0,0 +1,92 @@
+package model
+
+import (
+	"database/sql"
+	"github.com/google/uuid"
+	"github.com/rehandwi03/test-case-backend-majoo/money"
+	"gorm.io/gorm"
+	"time"
+)
+
+// States of a stock transfer, stored on StockTransfer.Status. A transfer sent but never received in full is closed,
+// settling what wasn't received.
+const (
+	StockTransferDraft             = "draft"
+	StockTransferSent              = "sent"
+	StockTransferPartiallyReceived = "partially_received"
+	StockTransferReceived          = "received"
+	StockTransferClosed            = "closed"
+	StockTransferCancelled         = "cancelled"
+)
+
+// StockTransfer moves goods between two outlets of a merchant. Sending it takes the stock out of the source outlet,
+// the destination outlet only gets it as it is received, possibly in several receipts.
+type StockTransfer struct {
+	ID                  uuid.UUID  `gorm:"primaryKey;type:uuid"`
+	MerchantID          uuid.UUID  `gorm:"type:uuid;index"`
+	SourceOutletID      uuid.UUID  `gorm:"type:uuid"`
+	DestinationOutletID uuid.UUID  `gorm:"type:uuid"`
+	Status              string     `gorm:"type:string;size:32"`
+	Note                string     `gorm:"type:string;size:255"`
+	CreatedBy           uuid.UUID  `gorm:"type:uuid"`
+	SentBy              *uuid.UUID `gorm:"type:uuid"`
+	SentAt              sql.NullTime
+	Lines               []StockTransferLine `gorm:"foreignKey:TransferID"`
+	Audit
+}
+
+// StockTransferLine is a product, or a variant of it, of the source outlet and the one with the same SKU it becomes
+// in the destination outlet. UnitCost is what a unit cost the source outlet when it was sent, the destination receives
+// it at that cost. Closing the transfer settles what wasn't received, Returned to the source outlet or written off as
+// Lost.
+type StockTransferLine struct {
+	ID                   uuid.UUID  `gorm:"primaryKey;type:uuid"`
+	TransferID           uuid.UUID  `gorm:"type:uuid;index"`
+	ProductID            uuid.UUID  `gorm:"type:uuid"`
+	VariantID            *uuid.UUID `gorm:"type:uuid"`
+	DestinationProductID uuid.UUID  `gorm:"type:uuid"`
+	DestinationVariantID *uuid.UUID `gorm:"type:uuid"`
+	SKU                  string     `gorm:"column:sku;type:string;size:64"`
+	Name                 string     `gorm:"type:string;size:255"`
+	VariantName          string     `gorm:"type:string;size:255"`
+	Quantity             int64
+	Received             int64
+	Returned             int64
+	Lost                 int64
+	UnitCost             money.Money `gorm:"embedded;embeddedPrefix:unit_cost_"`
+	Audit
+}
+
+func (s *StockTransfer) PrimaryKey() uuid.UUID {
+	return s.ID
+}
+
+func (s *StockTransfer) BeforeCreate(tx *gorm.DB) (err error) {
+	s.ID = uuid.New()
+
+	s.Audit.CreatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	s.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
+
+func (s *StockTransfer) BeforeUpdate(tx *gorm.DB) (err error) {
+	s.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
+
+func (s *StockTransferLine) BeforeCreate(tx *gorm.DB) (err error) {
+	s.ID = uuid.New()
+
+	s.Audit.CreatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	s.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
+
+func (s *StockTransferLine) BeforeUpdate(tx *gorm.DB) (err error) {
+	s.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
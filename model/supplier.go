@@ -0,0 +1,40 @@
+package model
+
+import (
+	"database/sql"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+// Supplier is a business a merchant buys its goods from.
+type Supplier struct {
+	ID          uuid.UUID `gorm:"primaryKey;type:uuid"`
+	MerchantID  uuid.UUID `gorm:"type:uuid;index"`
+	Name        string    `gorm:"type:string;size:255"`
+	ContactName string    `gorm:"type:string;size:255"`
+	Email       string    `gorm:"type:string;size:255"`
+	PhoneNumber string    `gorm:"type:string;size:20"`
+	Address     string
+	Note        string `gorm:"type:string;size:255"`
+	Audit
+}
+
+func (s *Supplier) PrimaryKey() uuid.UUID {
+	return s.ID
+}
+
+func (s *Supplier) BeforeCreate(tx *gorm.DB) (err error) {
+	s.ID = uuid.New()
+
+	s.Audit.CreatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	s.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
+
+func (s *Supplier) BeforeUpdate(tx *gorm.DB) (err error) {
+	s.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
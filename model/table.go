@@ -0,0 +1,67 @@
+package model
+
+import (
+	"database/sql"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+// Area is a part of the floor of an outlet, like the terrace, its tables are listed together.
+type Area struct {
+	ID        uuid.UUID `gorm:"primaryKey;type:uuid"`
+	OutletID  uuid.UUID `gorm:"type:uuid;index"`
+	Name      string    `gorm:"type:string;size:255"`
+	SortOrder int
+	Tables    []Table
+	Audit
+}
+
+// Table is a table of an outlet guests are seated at, in an area or not. Seats is how many it sits.
+type Table struct {
+	ID        uuid.UUID  `gorm:"primaryKey;type:uuid"`
+	OutletID  uuid.UUID  `gorm:"type:uuid;index"`
+	AreaID    *uuid.UUID `gorm:"type:uuid;index"`
+	Name      string     `gorm:"type:string;size:255"`
+	Seats     int64
+	SortOrder int
+	Audit
+}
+
+func (a *Area) PrimaryKey() uuid.UUID {
+	return a.ID
+}
+
+func (a *Area) BeforeCreate(tx *gorm.DB) (err error) {
+	a.ID = uuid.New()
+
+	a.Audit.CreatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	a.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
+
+func (a *Area) BeforeUpdate(tx *gorm.DB) (err error) {
+	a.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
+
+func (t *Table) PrimaryKey() uuid.UUID {
+	return t.ID
+}
+
+func (t *Table) BeforeCreate(tx *gorm.DB) (err error) {
+	t.ID = uuid.New()
+
+	t.Audit.CreatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	t.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
+
+func (t *Table) BeforeUpdate(tx *gorm.DB) (err error) {
+	t.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
@@ -0,0 +1,172 @@
+package model
+
+import (
+	"database/sql"
+	"github.com/google/uuid"
+	"github.com/rehandwi03/test-case-backend-majoo/money"
+	"gorm.io/gorm"
+	"time"
+)
+
+type Transaction struct {
+	ID       uuid.UUID `gorm:"primaryKey;type:uuid"`
+	OutletID uuid.UUID `gorm:"type:uuid;index"`
+	UserID   uuid.UUID `gorm:"type:uuid"`
+	// OrderID is the order the sale was checked out from, if any.
+	OrderID       *uuid.UUID `gorm:"type:uuid"`
+	Status        string     `gorm:"type:string;size:32"`
+	TotalQuantity int64
+	Total         money.Money `gorm:"embedded;embeddedPrefix:total_"`
+	// Rounding is what the cash rounding of the merchant added to the total, negative when it was rounded down.
+	Rounding money.Money `gorm:"embedded;embeddedPrefix:rounding_"`
+	// Paid is what the tenders handed over add up to, less the ones still pending or failed at their provider,
+	// Change what was given back of it in cash.
+	Paid     money.Money `gorm:"embedded;embeddedPrefix:paid_"`
+	Change   money.Money `gorm:"embedded;embeddedPrefix:change_"`
+	Items    []TransactionItem
+	Payments []TransactionPayment
+	Refunds  []Refund
+	Audit
+}
+
+type TransactionItem struct {
+	ID            uuid.UUID  `gorm:"primaryKey;type:uuid"`
+	TransactionID uuid.UUID  `gorm:"type:uuid;index"`
+	ProductID     uuid.UUID  `gorm:"type:uuid"`
+	VariantID     *uuid.UUID `gorm:"type:uuid"`
+	Name          string     `gorm:"type:string;size:255"`
+	VariantName   string     `gorm:"type:string;size:255"`
+	// Price is the unit price, including the price deltas of the modifiers.
+	Price    money.Money `gorm:"embedded;embeddedPrefix:price_"`
+	Quantity int64
+	Subtotal money.Money `gorm:"embedded;embeddedPrefix:subtotal_"`
+	// Cost is what the sold units cost by the costing method of the merchant, recorded by their sale movement.
+	Cost      money.Money `gorm:"embedded;embeddedPrefix:cost_"`
+	Modifiers []TransactionItemModifier
+	// Components are the products a bundle was sold as.
+	Components []TransactionItemComponent
+	// ReturnedQuantity is how many of the units sold were refunded, ReturnedCost what the ones put back into the stock
+	// were worth.
+	ReturnedQuantity int64
+	ReturnedCost     money.Money `gorm:"embedded;embeddedPrefix:returned_cost_"`
+	Audit
+}
+
+// TransactionItemModifier records a modifier chosen for an item as it was priced at checkout.
+type TransactionItemModifier struct {
+	ID                uuid.UUID   `gorm:"primaryKey;type:uuid"`
+	TransactionItemID uuid.UUID   `gorm:"type:uuid;index"`
+	ModifierID        uuid.UUID   `gorm:"type:uuid"`
+	Name              string      `gorm:"type:string;size:255"`
+	PriceDelta        money.Money `gorm:"embedded;embeddedPrefix:price_delta_"`
+	Audit
+}
+
+// TransactionItemComponent records a product, or variant, filling a slot of a bundle item as it was chosen at
+// checkout. Quantity is what every bundle of the item took.
+type TransactionItemComponent struct {
+	ID                uuid.UUID  `gorm:"primaryKey;type:uuid"`
+	TransactionItemID uuid.UUID  `gorm:"type:uuid;index"`
+	OptionID          uuid.UUID  `gorm:"type:uuid"`
+	SlotName          string     `gorm:"type:string;size:255"`
+	ProductID         uuid.UUID  `gorm:"type:uuid"`
+	VariantID         *uuid.UUID `gorm:"type:uuid"`
+	Name              string     `gorm:"type:string;size:255"`
+	VariantName       string     `gorm:"type:string;size:255"`
+	Quantity          int64
+	PriceDelta        money.Money `gorm:"embedded;embeddedPrefix:price_delta_"`
+	Audit
+}
+
+func (t *Transaction) PrimaryKey() uuid.UUID {
+	return t.ID
+}
+
+// Refundable is what every payment of the transaction can still give back, in the order they were made: what a
+// captured payment paid less what it gave back already. The change came out of the cash paid last, so it isn't
+// given back.
+func (t *Transaction) Refundable() map[uuid.UUID]int64 {
+	refundable := map[uuid.UUID]int64{}
+	change := t.Change.Amount
+	for i := len(t.Payments) - 1; i >= 0; i-- {
+		payment := t.Payments[i]
+
+		paid := payment.Amount.Amount
+		if payment.Method == PaymentCash {
+			given := paid
+			if change < given {
+				given = change
+			}
+			paid -= given
+			change -= given
+		}
+
+		if payment.Status == PaymentCaptured || payment.Status == PaymentRefunded {
+			refundable[payment.ID] = paid - payment.Refunded.Amount
+		} else {
+			refundable[payment.ID] = 0
+		}
+	}
+
+	return refundable
+}
+
+func (t *Transaction) BeforeCreate(tx *gorm.DB) (err error) {
+	t.ID = uuid.New()
+
+	t.Audit.CreatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	t.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
+
+func (t *Transaction) BeforeUpdate(tx *gorm.DB) (err error) {
+	t.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
+
+func (t *TransactionItem) BeforeCreate(tx *gorm.DB) (err error) {
+	t.ID = uuid.New()
+
+	t.Audit.CreatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	t.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
+
+func (t *TransactionItem) BeforeUpdate(tx *gorm.DB) (err error) {
+	t.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
+
+func (t *TransactionItemModifier) BeforeCreate(tx *gorm.DB) (err error) {
+	t.ID = uuid.New()
+
+	t.Audit.CreatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	t.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
+
+func (t *TransactionItemModifier) BeforeUpdate(tx *gorm.DB) (err error) {
+	t.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
+
+func (t *TransactionItemComponent) BeforeCreate(tx *gorm.DB) (err error) {
+	t.ID = uuid.New()
+
+	t.Audit.CreatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	t.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
+
+func (t *TransactionItemComponent) BeforeUpdate(tx *gorm.DB) (err error) {
+	t.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
@@ -0,0 +1,85 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"github.com/rehandwi03/test-case-backend-majoo/money"
+	"testing"
+)
+
+func TestRefundable(t *testing.T) {
+	payment := func(method string, status string, amount int64, refunded int64) TransactionPayment {
+		return TransactionPayment{
+			ID: uuid.New(), Method: method, Status: status, Amount: money.New(amount, "IDR"),
+			Refunded: money.New(refunded, "IDR"),
+		}
+	}
+
+	tests := []struct {
+		name     string
+		change   int64
+		payments []TransactionPayment
+		want     []int64
+	}{
+		{
+			name:     "captured card",
+			payments: []TransactionPayment{payment(PaymentCard, PaymentCaptured, 50000, 0)},
+			want:     []int64{50000},
+		},
+		{
+			name:     "change out of the cash",
+			change:   20000,
+			payments: []TransactionPayment{payment(PaymentCash, PaymentCaptured, 100000, 0)},
+			want:     []int64{80000},
+		},
+		{
+			name:   "change out of the cash paid last",
+			change: 30000,
+			payments: []TransactionPayment{
+				payment(PaymentCash, PaymentCaptured, 20000, 0), payment(PaymentCash, PaymentCaptured, 20000, 0),
+			},
+			want: []int64{10000, 0},
+		},
+		{
+			name:   "change isn't taken out of other methods",
+			change: 5000,
+			payments: []TransactionPayment{
+				payment(PaymentCash, PaymentCaptured, 25000, 0), payment(PaymentCard, PaymentCaptured, 40000, 0),
+			},
+			want: []int64{20000, 40000},
+		},
+		{
+			name: "less what was given back",
+			payments: []TransactionPayment{
+				payment(PaymentCard, PaymentCaptured, 40000, 15000),
+				payment(PaymentQRIS, PaymentRefunded, 10000, 10000),
+			},
+			want: []int64{25000, 0},
+		},
+		{
+			name: "not captured",
+			payments: []TransactionPayment{
+				payment(PaymentCard, PaymentAuthorized, 40000, 0), payment(PaymentQRIS, PaymentPending, 10000, 0),
+				payment(PaymentQRIS, PaymentFailed, 10000, 0),
+			},
+			want: []int64{0, 0, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				transaction := Transaction{Change: money.New(tt.change, "IDR"), Payments: tt.payments}
+				refundable := transaction.Refundable()
+				if len(refundable) != len(tt.payments) {
+					t.Fatalf("%d payments refundable, want %d", len(refundable), len(tt.payments))
+				}
+
+				for i, payment := range tt.payments {
+					if refundable[payment.ID] != tt.want[i] {
+						t.Errorf("payment %d refundable %d, want %d", i, refundable[payment.ID], tt.want[i])
+					}
+				}
+			},
+		)
+	}
+}
@@ -15,6 +15,7 @@ type User struct {
 	Email       string    `gorm:"type:string;size:50"`
 	Password    string    `gorm:"type:string;size:255"`
 	PhoneNumber string    `gorm:"type:string;size:13"`
+	Role        string    `gorm:"type:string;size:20"`
 	Audit
 }
 
@@ -37,6 +38,10 @@ func (u *User) EncryptPassword() (err error) {
 	return
 }
 
+func (u *User) PrimaryKey() uuid.UUID {
+	return u.ID
+}
+
 func (u *User) BeforeCreate(tx *gorm.DB) (err error) {
 	u.ID = uuid.New()
 
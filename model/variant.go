@@ -0,0 +1,126 @@
+package model
+
+import (
+	"database/sql"
+	"github.com/google/uuid"
+	"github.com/rehandwi03/test-case-backend-majoo/money"
+	"gorm.io/gorm"
+	"strings"
+	"time"
+)
+
+// ProductOption is a group of choices of a product, like size or temperature.
+type ProductOption struct {
+	ID        uuid.UUID `gorm:"primaryKey;type:uuid"`
+	ProductID uuid.UUID `gorm:"type:uuid;index"`
+	Name      string    `gorm:"type:string;size:255"`
+	SortOrder int
+	Values    []ProductOptionValue `gorm:"foreignKey:OptionID"`
+	Audit
+}
+
+// ProductOptionValue is one choice of an option, like "L" or "iced".
+type ProductOptionValue struct {
+	ID        uuid.UUID `gorm:"primaryKey;type:uuid"`
+	OptionID  uuid.UUID `gorm:"type:uuid;index"`
+	Value     string    `gorm:"type:string;size:255"`
+	SortOrder int
+	Audit
+}
+
+// ProductVariant is a sellable combination of option values of a product with its own stock. Price overrides the
+// price of the product when set, CostPrice and AverageCost are kept like the ones of a product.
+type ProductVariant struct {
+	ID           uuid.UUID   `gorm:"primaryKey;type:uuid"`
+	ProductID    uuid.UUID   `gorm:"type:uuid;index"`
+	SKU          string      `gorm:"column:sku;type:string;size:64"`
+	Barcode      string      `gorm:"type:string;size:64;index"`
+	Price        money.Money `gorm:"embedded;embeddedPrefix:price_"`
+	CostPrice    money.Money `gorm:"embedded;embeddedPrefix:cost_price_"`
+	AverageCost  money.Money `gorm:"embedded;embeddedPrefix:average_cost_"`
+	Stock        int64
+	OptionValues []ProductOptionValue `gorm:"many2many:product_variant_values;joinForeignKey:VariantID;joinReferences:OptionValueID"`
+	Audit
+}
+
+// ProductVariantValue assigns an option value to a variant, a variant has at most one value of every option.
+type ProductVariantValue struct {
+	VariantID     uuid.UUID `gorm:"primaryKey;type:uuid"`
+	OptionValueID uuid.UUID `gorm:"primaryKey;type:uuid"`
+}
+
+// EffectivePrice is the price the variant is sold for.
+func (p *ProductVariant) EffectivePrice(product Product) money.Money {
+	if p.Price.IsSet() {
+		return p.Price
+	}
+
+	return product.Price
+}
+
+// Label names the variant after its option values, like "L, iced".
+func (p *ProductVariant) Label() string {
+	var values []string
+	for _, value := range p.OptionValues {
+		values = append(values, value.Value)
+	}
+
+	return strings.Join(values, ", ")
+}
+
+func (p *ProductOption) PrimaryKey() uuid.UUID {
+	return p.ID
+}
+
+func (p *ProductOption) BeforeCreate(tx *gorm.DB) (err error) {
+	p.ID = uuid.New()
+
+	p.Audit.CreatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	p.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
+
+func (p *ProductOption) BeforeUpdate(tx *gorm.DB) (err error) {
+	p.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
+
+func (p *ProductOptionValue) PrimaryKey() uuid.UUID {
+	return p.ID
+}
+
+func (p *ProductOptionValue) BeforeCreate(tx *gorm.DB) (err error) {
+	p.ID = uuid.New()
+
+	p.Audit.CreatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	p.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
+
+func (p *ProductOptionValue) BeforeUpdate(tx *gorm.DB) (err error) {
+	p.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
+
+func (p *ProductVariant) PrimaryKey() uuid.UUID {
+	return p.ID
+}
+
+func (p *ProductVariant) BeforeCreate(tx *gorm.DB) (err error) {
+	p.ID = uuid.New()
+
+	p.Audit.CreatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	p.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
+
+func (p *ProductVariant) BeforeUpdate(tx *gorm.DB) (err error) {
+	p.Audit.ModifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return err
+}
@@ -0,0 +1,97 @@
+package money
+
+import (
+	"errors"
+	"fmt"
+)
+
+// DefaultCurrency is the currency of merchants that don't choose one.
+const DefaultCurrency = "IDR"
+
+// exponents are the ISO 4217 minor unit exponents of the supported currencies, e.g. 2 for IDR where 1 rupiah is 100
+// sen and an amount of 1500000 is Rp 15,000.00.
+var exponents = map[string]int{
+	"AUD": 2,
+	"CNY": 2,
+	"EUR": 2,
+	"GBP": 2,
+	"IDR": 2,
+	"JPY": 0,
+	"KRW": 0,
+	"MYR": 2,
+	"PHP": 2,
+	"SGD": 2,
+	"THB": 2,
+	"USD": 2,
+	"VND": 0,
+}
+
+var (
+	ErrCurrencyMismatch    = errors.New("amounts of different currencies can't be combined")
+	ErrUnsupportedCurrency = errors.New("currency isn't supported")
+)
+
+// Money is an exact amount in the minor unit of an ISO 4217 currency. Models embed it with a prefix, so a Price is
+// stored in the price_amount and price_currency columns. The zero value has no currency and stands for "no amount",
+// like a variant without its own price.
+type Money struct {
+	Amount   int64  `json:"amount" gorm:"column:amount"`
+	Currency string `json:"currency" gorm:"column:currency;type:char(3)"`
+}
+
+// New returns an amount of minor units of the currency.
+func New(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// Supported reports whether amounts can be kept in the currency.
+func Supported(currency string) bool {
+	_, ok := exponents[currency]
+
+	return ok
+}
+
+// IsSet reports whether m holds an amount, the zero value doesn't.
+func (m Money) IsSet() bool {
+	return m.Currency != ""
+}
+
+// Add returns m + other, both have to be in the same currency. Adding to the zero value takes the currency of other.
+func (m Money) Add(other Money) (Money, error) {
+	if !m.IsSet() {
+		return other, nil
+	}
+
+	if m.Currency != other.Currency {
+		return Money{}, ErrCurrencyMismatch
+	}
+
+	return Money{Amount: m.Amount + other.Amount, Currency: m.Currency}, nil
+}
+
+// Mul returns m times quantity.
+func (m Money) Mul(quantity int64) Money {
+	return Money{Amount: m.Amount * quantity, Currency: m.Currency}
+}
+
+// String formats m in major units like "IDR 15000.00".
+func (m Money) String() string {
+	exponent, ok := exponents[m.Currency]
+	if !ok || exponent == 0 {
+		return fmt.Sprintf("%s %d", m.Currency, m.Amount)
+	}
+
+	sign := ""
+	amount := m.Amount
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	unit := int64(1)
+	for i := 0; i < exponent; i++ {
+		unit *= 10
+	}
+
+	return fmt.Sprintf("%s %s%d.%0*d", m.Currency, sign, amount/unit, exponent, amount%unit)
+}
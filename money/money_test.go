@@ -0,0 +1,85 @@
+package money
+
+import "testing"
+
+func TestAdd(t *testing.T) {
+	tests := []struct {
+		name    string
+		m       Money
+		other   Money
+		want    Money
+		wantErr error
+	}{
+		{name: "same currency", m: New(1500, "IDR"), other: New(250, "IDR"), want: New(1750, "IDR")},
+		{name: "to the zero value", m: Money{}, other: New(250, "USD"), want: New(250, "USD")},
+		{name: "negative", m: New(1500, "IDR"), other: New(-2000, "IDR"), want: New(-500, "IDR")},
+		{name: "another currency", m: New(1500, "IDR"), other: New(250, "USD"), wantErr: ErrCurrencyMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				got, err := tt.m.Add(tt.other)
+				if err != tt.wantErr {
+					t.Fatalf("%v.Add(%v) error = %v, want %v", tt.m, tt.other, err, tt.wantErr)
+				}
+
+				if got != tt.want {
+					t.Errorf("%v.Add(%v) = %v, want %v", tt.m, tt.other, got, tt.want)
+				}
+			},
+		)
+	}
+}
+
+func TestMul(t *testing.T) {
+	got := New(1250, "IDR").Mul(3)
+	if got != New(3750, "IDR") {
+		t.Errorf("Mul(3) = %v, want IDR 37.50", got)
+	}
+}
+
+func TestIsSet(t *testing.T) {
+	if (Money{}).IsSet() {
+		t.Error("the zero value is set")
+	}
+
+	if !New(0, "IDR").IsSet() {
+		t.Error("a zero amount of a currency isn't set")
+	}
+}
+
+func TestSupported(t *testing.T) {
+	for _, currency := range []string{"IDR", "USD", "JPY"} {
+		if !Supported(currency) {
+			t.Errorf("Supported(%q) = false", currency)
+		}
+	}
+
+	for _, currency := range []string{"", "idr", "XXX"} {
+		if Supported(currency) {
+			t.Errorf("Supported(%q) = true", currency)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		m    Money
+		want string
+	}{
+		{m: New(1500000, "IDR"), want: "IDR 15000.00"},
+		{m: New(1505, "USD"), want: "USD 15.05"},
+		{m: New(7, "USD"), want: "USD 0.07"},
+		{m: New(-1505, "USD"), want: "USD -15.05"},
+		{m: New(-7, "USD"), want: "USD -0.07"},
+		{m: New(1500, "JPY"), want: "JPY 1500"},
+		{m: New(1500, "XXX"), want: "XXX 1500"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.m.String(); got != tt.want {
+			t.Errorf("String() of %d %s = %q, want %q", tt.m.Amount, tt.m.Currency, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package query
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// fieldPattern guards the column names rendered into SQL, values are always passed as parameters.
+var fieldPattern = regexp.MustCompile(`^[a-z_][a-z0-9_]*(\.[a-z_][a-z0-9_]*)?$`)
+
+// Condition is a filter rendered into a parameterized SQL expression.
+type Condition interface {
+	Build() (string, []interface{}, error)
+}
+
+type comparison struct {
+	field    string
+	operator string
+	value    interface{}
+}
+
+func (c comparison) Build() (string, []interface{}, error) {
+	if !fieldPattern.MatchString(c.field) {
+		return "", nil, fmt.Errorf("invalid field %q", c.field)
+	}
+
+	return fmt.Sprintf("%s %s ?", c.field, c.operator), []interface{}{c.value}, nil
+}
+
+// Eq matches rows where field equals value.
+func Eq(field string, value interface{}) Condition {
+	return comparison{field: field, operator: "=", value: value}
+}
+
+// Ne matches rows where field doesn't equal value.
+func Ne(field string, value interface{}) Condition {
+	return comparison{field: field, operator: "<>", value: value}
+}
+
+// Gt matches rows where field is greater than value.
+func Gt(field string, value interface{}) Condition {
+	return comparison{field: field, operator: ">", value: value}
+}
+
+// Gte matches rows where field is greater than or equal to value.
+func Gte(field string, value interface{}) Condition {
+	return comparison{field: field, operator: ">=", value: value}
+}
+
+// Lt matches rows where field is less than value.
+func Lt(field string, value interface{}) Condition {
+	return comparison{field: field, operator: "<", value: value}
+}
+
+// Lte matches rows where field is less than or equal to value.
+func Lte(field string, value interface{}) Condition {
+	return comparison{field: field, operator: "<=", value: value}
+}
+
+// Contains matches rows where field contains value, ignoring case.
+func Contains(field string, value string) Condition {
+	return comparison{field: field, operator: "ILIKE", value: "%" + value + "%"}
+}
+
+type in struct {
+	field  string
+	values interface{}
+}
+
+func (i in) Build() (string, []interface{}, error) {
+	if !fieldPattern.MatchString(i.field) {
+		return "", nil, fmt.Errorf("invalid field %q", i.field)
+	}
+
+	return fmt.Sprintf("%s IN ?", i.field), []interface{}{i.values}, nil
+}
+
+// In matches rows where field is one of values, which must be a slice. An empty slice matches nothing.
+func In(field string, values interface{}) Condition {
+	return in{field: field, values: values}
+}
+
+// Between matches rows where field is within from and to, both inclusive. A nil bound leaves that side open.
+func Between(field string, from interface{}, to interface{}) Condition {
+	var conditions []Condition
+	if from != nil {
+		conditions = append(conditions, Gte(field, from))
+	}
+	if to != nil {
+		conditions = append(conditions, Lte(field, to))
+	}
+
+	return And(conditions...)
+}
+
+type null struct {
+	field string
+	not   bool
+}
+
+func (n null) Build() (string, []interface{}, error) {
+	if !fieldPattern.MatchString(n.field) {
+		return "", nil, fmt.Errorf("invalid field %q", n.field)
+	}
+
+	if n.not {
+		return n.field + " IS NOT NULL", nil, nil
+	}
+
+	return n.field + " IS NULL", nil, nil
+}
+
+// IsNull matches rows where field is null.
+func IsNull(field string) Condition {
+	return null{field: field}
+}
+
+// NotNull matches rows where field isn't null.
+func NotNull(field string) Condition {
+	return null{field: field, not: true}
+}
+
+type raw struct {
+	sql  string
+	args []interface{}
+}
+
+func (r raw) Build() (string, []interface{}, error) {
+	return r.sql, r.args, nil
+}
+
+// Raw is an escape hatch for expressions the builder can't describe, like sub queries. The sql must never contain
+// user input, pass it as args instead.
+func Raw(sql string, args ...interface{}) Condition {
+	return raw{sql: sql, args: args}
+}
+
+type group struct {
+	operator   string
+	conditions []Condition
+}
+
+func (g group) Build() (string, []interface{}, error) {
+	var parts []string
+	var args []interface{}
+	for _, condition := range g.conditions {
+		if condition == nil {
+			continue
+		}
+
+		sql, conditionArgs, err := condition.Build()
+		if err != nil {
+			return "", nil, err
+		}
+
+		if sql == "" {
+			continue
+		}
+
+		parts = append(parts, "("+sql+")")
+		args = append(args, conditionArgs...)
+	}
+
+	return strings.Join(parts, " "+g.operator+" "), args, nil
+}
+
+// And matches rows matching every condition, nil conditions are skipped.
+func And(conditions ...Condition) Condition {
+	return group{operator: "AND", conditions: conditions}
+}
+
+// Or matches rows matching at least one condition, nil conditions are skipped.
+func Or(conditions ...Condition) Condition {
+	return group{operator: "OR", conditions: conditions}
+}
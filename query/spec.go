@@ -0,0 +1,187 @@
+package query
+
+import (
+	"fmt"
+	"gorm.io/gorm"
+	"strings"
+)
+
+const (
+	DefaultLimit = 10
+	MaxLimit     = 100
+)
+
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+type Page struct {
+	Page  int
+	Limit int
+}
+
+// Spec describes which rows a repository reads: the filter, the order and the page. The zero value reads every row
+// that isn't soft deleted.
+type Spec struct {
+	conditions  []Condition
+	sorts       []Sort
+	page        *Page
+	withDeleted bool
+}
+
+// Where starts a spec matching every condition.
+func Where(conditions ...Condition) Spec {
+	return Spec{}.And(conditions...)
+}
+
+// And narrows the spec down to rows also matching every condition, nil conditions are skipped.
+func (s Spec) And(conditions ...Condition) Spec {
+	merged := make([]Condition, 0, len(s.conditions)+len(conditions))
+	merged = append(merged, s.conditions...)
+	for _, condition := range conditions {
+		if condition != nil {
+			merged = append(merged, condition)
+		}
+	}
+	s.conditions = merged
+
+	return s
+}
+
+// OrderBy appends sorts, the first one given has the highest priority.
+func (s Spec) OrderBy(sorts ...Sort) Spec {
+	merged := make([]Sort, 0, len(s.sorts)+len(sorts))
+	merged = append(merged, s.sorts...)
+	merged = append(merged, sorts...)
+	s.sorts = merged
+
+	return s
+}
+
+// Paginate limits the spec to a page, starting from 1. Out of range values fall back to the first page and the
+// default limit, and the limit is capped at MaxLimit.
+func (s Spec) Paginate(page int, limit int) Spec {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = DefaultLimit
+	}
+	if limit > MaxLimit {
+		limit = MaxLimit
+	}
+	s.page = &Page{Page: page, Limit: limit}
+
+	return s
+}
+
+// WithDeleted makes the spec read soft deleted rows too.
+func (s Spec) WithDeleted() Spec {
+	s.withDeleted = true
+
+	return s
+}
+
+// Filter returns the conditions of the spec as a single condition, nil when it has none.
+func (s Spec) Filter() Condition {
+	if len(s.conditions) == 0 {
+		return nil
+	}
+
+	return And(s.conditions...)
+}
+
+// ApplyFilter adds the conditions of the spec to the query, ignoring order and page so it can be used for counting.
+func (s Spec) ApplyFilter(db *gorm.DB) *gorm.DB {
+	if s.withDeleted {
+		db = db.Unscoped()
+	}
+
+	filter := s.Filter()
+	if filter == nil {
+		return db
+	}
+
+	sql, args, err := filter.Build()
+	if err != nil {
+		_ = db.AddError(err)
+		return db
+	}
+
+	if sql == "" {
+		return db
+	}
+
+	return db.Where(sql, args...)
+}
+
+// Apply adds the conditions, order and page of the spec to the query.
+func (s Spec) Apply(db *gorm.DB) *gorm.DB {
+	db = s.ApplyFilter(db)
+
+	for _, sort := range s.sorts {
+		if !fieldPattern.MatchString(sort.Field) {
+			_ = db.AddError(fmt.Errorf("invalid sort field %q", sort.Field))
+			return db
+		}
+
+		direction := "ASC"
+		if sort.Desc {
+			direction = "DESC"
+		}
+		db = db.Order(sort.Field + " " + direction)
+	}
+
+	if s.page != nil {
+		db = db.Limit(s.page.Limit).Offset((s.page.Page - 1) * s.page.Limit)
+	}
+
+	return db
+}
+
+// ParseSort parses a sort like "created_at desc" or "name asc, price desc". Only the allowed fields can be used, so
+// the sort can come straight from the request.
+func ParseSort(sort string, allowed ...string) ([]Sort, error) {
+	var sorts []Sort
+	for _, part := range strings.Split(sort, ",") {
+		fields := strings.Fields(part)
+		if len(fields) == 0 {
+			continue
+		}
+
+		if len(fields) > 2 {
+			return nil, fmt.Errorf("invalid sort %q", strings.TrimSpace(part))
+		}
+
+		field := strings.ToLower(fields[0])
+		if !contains(allowed, field) {
+			return nil, fmt.Errorf("can't sort by %q", fields[0])
+		}
+
+		desc := false
+		if len(fields) == 2 {
+			switch strings.ToLower(fields[1]) {
+			case "asc":
+			case "desc":
+				desc = true
+			default:
+				return nil, fmt.Errorf("invalid sort direction %q", fields[1])
+			}
+		}
+
+		sorts = append(sorts, Sort{Field: field, Desc: desc})
+	}
+
+	return sorts, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
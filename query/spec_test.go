@@ -0,0 +1,53 @@
+package query
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSort(t *testing.T) {
+	allowed := []string{"name", "price", "created_at"}
+
+	tests := []struct {
+		name    string
+		sort    string
+		want    []Sort
+		wantErr bool
+	}{
+		{name: "empty", sort: "", want: nil},
+		{name: "blank parts", sort: " , ,", want: nil},
+		{name: "ascending by default", sort: "name", want: []Sort{{Field: "name"}}},
+		{name: "descending", sort: "created_at desc", want: []Sort{{Field: "created_at", Desc: true}}},
+		{name: "any case", sort: "Price DESC", want: []Sort{{Field: "price", Desc: true}}},
+		{
+			name: "several fields", sort: "name asc, price desc",
+			want: []Sort{{Field: "name"}, {Field: "price", Desc: true}},
+		},
+		{name: "field not allowed", sort: "password", wantErr: true},
+		{name: "unknown direction", sort: "name up", wantErr: true},
+		{name: "too many words", sort: "name asc desc", wantErr: true},
+		{name: "injection", sort: "name; drop table users", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				got, err := ParseSort(tt.sort, allowed...)
+				if tt.wantErr {
+					if err == nil {
+						t.Fatalf("ParseSort(%q) = %v, want an error", tt.sort, got)
+					}
+
+					return
+				}
+				if err != nil {
+					t.Fatalf("ParseSort(%q): %v", tt.sort, err)
+				}
+
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("ParseSort(%q) = %v, want %v", tt.sort, got, tt.want)
+				}
+			},
+		)
+	}
+}
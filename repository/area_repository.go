@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"gorm.io/gorm"
+)
+
+type AreaRepository interface {
+	Repository[model.Area]
+}
+
+// NewAreaRepository reads areas with their tables.
+func NewAreaRepository(conn *gorm.DB) AreaRepository {
+	return NewRepository[model.Area](
+		conn, Hooks[model.Area]{
+			Query: func(db *gorm.DB) *gorm.DB {
+				return db.Preload("Tables", orderTables)
+			},
+		},
+	)
+}
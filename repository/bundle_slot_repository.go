@@ -0,0 +1,49 @@
+package repository
+
+import (
+	custom_error "github.com/rehandwi03/test-case-backend-majoo/internal/error"
+	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"gorm.io/gorm"
+)
+
+type BundleSlotRepository interface {
+	Repository[model.BundleSlot]
+}
+
+// NewBundleSlotRepository saves a slot of a bundle together with its options, the options left out are deleted.
+// Deleting a slot deletes its options.
+func NewBundleSlotRepository(conn *gorm.DB) BundleSlotRepository {
+	return NewRepository[model.BundleSlot](
+		conn, Hooks[model.BundleSlot]{
+			Query: func(db *gorm.DB) *gorm.DB {
+				return db.Preload("Options", orderBundleOptions)
+			},
+			AfterSave: func(tx *gorm.DB, slot *model.BundleSlot) error {
+				for i := range slot.Options {
+					slot.Options[i].SlotID = slot.ID
+				}
+
+				return saveChildren(
+					tx, "slot_id", slot.ID, slot.Options,
+					func(option *model.BundleOption) map[string]interface{} {
+						return map[string]interface{}{
+							"product_id":           option.ProductID,
+							"variant_id":           option.VariantID,
+							"price_delta_amount":   option.PriceDelta.Amount,
+							"price_delta_currency": option.PriceDelta.Currency,
+							"sort_order":           option.SortOrder,
+						}
+					},
+					&custom_error.BadRequest{Message: "bundle option not found", Field: "options"}, nil,
+				)
+			},
+			AfterDelete: func(tx *gorm.DB, slot *model.BundleSlot) error {
+				return cascadeDelete(tx, bundleSlotDescendants, slot.ID, slot.DeletedAt)
+			},
+		},
+	)
+}
+
+func orderBundleOptions(db *gorm.DB) *gorm.DB {
+	return db.Order("sort_order, created_at")
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	custom_error "github.com/rehandwi03/test-case-backend-majoo/internal/error"
+	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"gorm.io/gorm"
+	"strings"
+	"time"
+)
+
+// descendant is a table below a merchant, an outlet, a product, an option or a modifier group, where selects its rows
+// from the id of that parent.
+type descendant struct {
+	model interface{}
+	where string
+}
+
+const outletsOfMerchant = "outlet_id IN (SELECT id FROM outlets WHERE merchant_id = ?)"
+
+var (
+	merchantDescendants = append(
+		below(productDescendants, "SELECT id FROM products WHERE "+outletsOfMerchant),
+		descendant{model: &model.Product{}, where: outletsOfMerchant},
+		descendant{model: &model.Table{}, where: outletsOfMerchant},
+		descendant{model: &model.Area{}, where: outletsOfMerchant},
+		descendant{model: &model.Outlet{}, where: "merchant_id = ?"},
+		descendant{model: &model.Category{}, where: "merchant_id = ?"},
+		descendant{model: &model.Supplier{}, where: "merchant_id = ?"},
+	)
+	outletDescendants = append(
+		below(productDescendants, "SELECT id FROM products WHERE outlet_id = ?"),
+		descendant{model: &model.Product{}, where: "outlet_id = ?"},
+		descendant{model: &model.Table{}, where: "outlet_id = ?"},
+		descendant{model: &model.Area{}, where: "outlet_id = ?"},
+	)
+	productDescendants = []descendant{
+		{model: &model.ProductOptionValue{}, where: "option_id IN (SELECT id FROM product_options WHERE product_id = ?)"},
+		{model: &model.ProductOption{}, where: "product_id = ?"},
+		{model: &model.ProductVariant{}, where: "product_id = ?"},
+		{model: &model.Modifier{}, where: "group_id IN (SELECT id FROM modifier_groups WHERE product_id = ?)"},
+		{model: &model.ModifierGroup{}, where: "product_id = ?"},
+		{model: &model.BundleOption{}, where: "slot_id IN (SELECT id FROM bundle_slots WHERE product_id = ?)"},
+		{model: &model.BundleSlot{}, where: "product_id = ?"},
+	}
+	optionDescendants = []descendant{
+		{model: &model.ProductOptionValue{}, where: "option_id = ?"},
+	}
+	modifierGroupDescendants = []descendant{
+		{model: &model.Modifier{}, where: "group_id = ?"},
+	}
+	bundleSlotDescendants = []descendant{
+		{model: &model.BundleOption{}, where: "slot_id = ?"},
+	}
+)
+
+// below rewrites the descendants of a product to select them from the products the given subquery selects.
+func below(descendants []descendant, products string) []descendant {
+	var rewritten []descendant
+	for _, d := range descendants {
+		rewritten = append(
+			rewritten, descendant{model: d.model, where: strings.Replace(d.where, "= ?", "IN ("+products+")", 1)},
+		)
+	}
+
+	return rewritten
+}
+
+// cascadeDelete soft deletes the live descendants of a parent with the deleted_at of the parent, which is how
+// cascadeRestore tells them apart from rows deleted on their own before.
+func cascadeDelete(tx *gorm.DB, descendants []descendant, parentId uuid.UUID, deletedAt gorm.DeletedAt) error {
+	for _, d := range descendants {
+		err := tx.Model(d.model).Where(d.where, parentId).Where("deleted_at IS NULL").
+			Update("deleted_at", deletedAt).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// cascadeRestore restores the descendants deleted together with their parent.
+func cascadeRestore(tx *gorm.DB, descendants []descendant, parentId uuid.UUID, deletedAt gorm.DeletedAt) error {
+	for _, d := range descendants {
+		err := tx.Unscoped().Model(d.model).Where(d.where, parentId).Where("deleted_at = ?", deletedAt).
+			Updates(map[string]interface{}{"deleted_at": nil, "modified_at": time.Now()}).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// requireLive fails a restore whose parent is still deleted, the parent has to be restored first.
+func requireLive(tx *gorm.DB, parent interface{}, parentId uuid.UUID, field string, message string) error {
+	var count int64
+	err := tx.Model(parent).Where("id = ?", parentId).Count(&count).Error
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return &custom_error.ConflictError{Message: message, Field: field}
+	}
+
+	return nil
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"gorm.io/gorm"
+)
+
+type CategoryRepository interface {
+	Repository[model.Category]
+}
+
+func NewCategoryRepository(conn *gorm.DB) CategoryRepository {
+	return NewRepository[model.Category](conn, Hooks[model.Category]{})
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+// saveChildren writes the rows a parent owns, like the values of an option, in the transaction saving the parent.
+// Children with an id are updated with the columns update returns, unless they belong to another parent which fails
+// with notFound. The others are created and the children of the parent left out are soft deleted, after inUse, when
+// given, accepted the subquery selecting them.
+func saveChildren[T any, PT Entity[T]](
+	tx *gorm.DB, parentColumn string, parentId uuid.UUID, children []T, update func(child *T) map[string]interface{},
+	notFound error, inUse func(tx *gorm.DB, removed *gorm.DB) error,
+) error {
+	var kept []uuid.UUID
+	for i := range children {
+		child := PT(&children[i])
+		if child.PrimaryKey() == uuid.Nil {
+			if err := tx.Create(child).Error; err != nil {
+				return err
+			}
+		} else {
+			columns := update(&children[i])
+			columns["modified_at"] = time.Now()
+
+			result := tx.Model(child).Where(parentColumn+" = ?", parentId).Updates(columns)
+			if result.Error != nil {
+				return result.Error
+			}
+
+			if result.RowsAffected == 0 {
+				return notFound
+			}
+		}
+
+		kept = append(kept, child.PrimaryKey())
+	}
+
+	removed := func() *gorm.DB {
+		ids := tx.Model(PT(new(T))).Select("id").Where(parentColumn+" = ?", parentId)
+		if len(kept) > 0 {
+			ids = ids.Where("id NOT IN ?", kept)
+		}
+
+		return ids
+	}
+	if inUse != nil {
+		if err := inUse(tx, removed()); err != nil {
+			return err
+		}
+	}
+
+	return tx.Where("id IN (?)", removed()).Delete(PT(new(T))).Error
+}
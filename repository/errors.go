@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"errors"
+	"github.com/jackc/pgconn"
+	custom_error "github.com/rehandwi03/test-case-backend-majoo/internal/error"
+	"regexp"
+	"strings"
+)
+
+const (
+	uniqueViolation     = "23505"
+	foreignKeyViolation = "23503"
+)
+
+// keyDetail reads the columns out of a violation detail like "Key (email)=(john@example.com) already exists.".
+var keyDetail = regexp.MustCompile(`^Key \(([^)]+)\)=`)
+
+// constraintMessages words the violations of the constraints created by the migrations, the others get a message
+// built from the columns of the violation.
+var constraintMessages = map[string]string{
+	"uq_users_email":                                     "email already exist",
+	"uq_merchant_users_merchant_id_user_id":              "user is already a member of the merchant",
+	"fk_merchants_user_id":                               "user not found",
+	"fk_merchant_users_merchant_id":                      "merchant not found",
+	"fk_merchant_users_user_id":                          "user not found",
+	"fk_outlets_merchant_id":                             "merchant not found",
+	"fk_products_outlet_id":                              "outlet not found",
+	"fk_transactions_outlet_id":                          "outlet not found",
+	"fk_transactions_user_id":                            "user not found",
+	"fk_transaction_items_transaction_id":                "transaction not found",
+	"fk_transaction_items_product_id":                    "product not found",
+	"fk_refresh_tokens_user_id":                          "user not found",
+	"fk_categories_merchant_id":                          "merchant not found",
+	"fk_categories_parent_id":                            "parent category not found",
+	"fk_product_categories_product_id":                   "product not found",
+	"fk_product_categories_category_id":                  "category not found",
+	"uq_product_options_product_id_name":                 "the product already has an option with this name",
+	"uq_product_option_values_option_id_value":           "the option already has this value",
+	"uq_product_variants_product_id_sku":                 "the product already has a variant with this sku",
+	"fk_product_options_product_id":                      "product not found",
+	"fk_product_option_values_option_id":                 "option not found",
+	"fk_product_variants_product_id":                     "product not found",
+	"fk_product_variant_values_variant_id":               "variant not found",
+	"fk_product_variant_values_option_value_id":          "option value not found",
+	"uq_modifier_groups_product_id_name":                 "the product already has a modifier group with this name",
+	"uq_modifiers_group_id_name":                         "the modifier group already has a modifier with this name",
+	"fk_modifier_groups_product_id":                      "product not found",
+	"fk_modifiers_group_id":                              "modifier group not found",
+	"fk_transaction_item_modifiers_transaction_item_id":  "transaction item not found",
+	"fk_stock_movements_outlet_id":                       "outlet not found",
+	"fk_stock_movements_product_id":                      "product not found",
+	"fk_stock_movements_variant_id":                      "variant not found",
+	"fk_stock_movements_transaction_id":                  "transaction not found",
+	"fk_stock_movements_user_id":                         "user not found",
+	"fk_stock_movements_stock_count_id":                  "stock count not found",
+	"uq_stock_counts_outlet_id_open":                     "the outlet is already being counted",
+	"fk_stock_counts_outlet_id":                          "outlet not found",
+	"fk_stock_counts_created_by":                         "user not found",
+	"fk_stock_counts_approved_by":                        "user not found",
+	"fk_stock_count_lines_count_id":                      "stock count not found",
+	"fk_stock_count_lines_product_id":                    "product not found",
+	"fk_stock_count_lines_variant_id":                    "variant not found",
+	"fk_stock_count_lines_counted_by":                    "user not found",
+	"uq_products_outlet_id_sku":                          "the outlet already has a product with this sku",
+	"fk_stock_movements_stock_transfer_id":               "stock transfer not found",
+	"fk_stock_transfers_merchant_id":                     "merchant not found",
+	"fk_stock_transfers_source_outlet_id":                "source outlet not found",
+	"fk_stock_transfers_destination_outlet_id":           "destination outlet not found",
+	"fk_stock_transfers_created_by":                      "user not found",
+	"fk_stock_transfers_sent_by":                         "user not found",
+	"fk_stock_transfer_lines_transfer_id":                "stock transfer not found",
+	"fk_stock_transfer_lines_product_id":                 "product not found",
+	"fk_stock_transfer_lines_variant_id":                 "variant not found",
+	"fk_stock_transfer_lines_destination_product_id":     "destination product not found",
+	"fk_stock_transfer_lines_destination_variant_id":     "destination variant not found",
+	"fk_transaction_item_modifiers_modifier_id":          "modifier not found",
+	"fk_transaction_items_variant_id":                    "variant not found",
+	"fk_notifications_outlet_id":                         "outlet not found",
+	"fk_notifications_product_id":                        "product not found",
+	"fk_notifications_variant_id":                        "variant not found",
+	"fk_notifications_stock_movement_id":                 "stock movement not found",
+	"uq_suppliers_merchant_id_name":                      "the merchant already has a supplier with this name",
+	"fk_suppliers_merchant_id":                           "merchant not found",
+	"fk_purchase_orders_merchant_id":                     "merchant not found",
+	"fk_purchase_orders_supplier_id":                     "supplier not found",
+	"fk_purchase_orders_outlet_id":                       "outlet not found",
+	"fk_purchase_orders_created_by":                      "user not found",
+	"fk_purchase_orders_ordered_by":                      "user not found",
+	"fk_purchase_order_lines_order_id":                   "purchase order not found",
+	"fk_purchase_order_lines_product_id":                 "product not found",
+	"fk_purchase_order_lines_variant_id":                 "variant not found",
+	"fk_stock_movements_purchase_order_id":               "purchase order not found",
+	"fk_stock_cost_layers_outlet_id":                     "outlet not found",
+	"fk_stock_cost_layers_product_id":                    "product not found",
+	"fk_stock_cost_layers_variant_id":                    "variant not found",
+	"fk_stock_cost_layers_stock_movement_id":             "stock movement not found",
+	"fk_recipe_lines_product_id":                         "product not found",
+	"fk_recipe_lines_ingredient_id":                      "ingredient not found",
+	"uq_bundle_slots_product_id_name":                    "the bundle already has a slot with this name",
+	"fk_bundle_slots_product_id":                         "product not found",
+	"fk_bundle_options_slot_id":                          "bundle slot not found",
+	"fk_bundle_options_product_id":                       "product not found",
+	"fk_bundle_options_variant_id":                       "variant not found",
+	"fk_transaction_item_components_transaction_item_id": "transaction item not found",
+	"fk_transaction_item_components_option_id":           "bundle option not found",
+	"fk_transaction_item_components_product_id":          "product not found",
+	"fk_transaction_item_components_variant_id":          "variant not found",
+	"fk_merchant_payment_methods_merchant_id":            "merchant not found",
+	"fk_transaction_payments_transaction_id":             "transaction not found",
+	"uq_transaction_payments_provider_charge_id":         "the charge already pays for another payment",
+	"fk_refunds_transaction_id":                          "transaction not found",
+	"fk_refunds_outlet_id":                               "outlet not found",
+	"fk_refunds_user_id":                                 "user not found",
+	"fk_refunds_approved_by":                             "approver not found",
+	"fk_refund_lines_refund_id":                          "refund not found",
+	"fk_refund_lines_transaction_item_id":                "transaction item not found",
+	"fk_refund_payments_refund_id":                       "refund not found",
+	"fk_refund_payments_payment_id":                      "payment not found",
+	"fk_stock_movements_transaction_item_id":             "transaction item not found",
+	"fk_stock_movements_refund_id":                       "refund not found",
+	"uq_areas_outlet_id_name":                            "the outlet already has an area with this name",
+	"fk_areas_outlet_id":                                 "outlet not found",
+	"uq_tables_outlet_id_name":                           "the outlet already has a table with this name",
+	"fk_tables_outlet_id":                                "outlet not found",
+	"fk_tables_area_id":                                  "area not found",
+	"fk_orders_outlet_id":                                "outlet not found",
+	"fk_orders_table_id":                                 "table not found",
+	"fk_orders_user_id":                                  "user not found",
+	"fk_orders_transaction_id":                           "transaction not found",
+	"fk_orders_merged_into_id":                           "order not found",
+	"fk_order_items_order_id":                            "order not found",
+	"fk_order_items_product_id":                          "product not found",
+	"fk_order_items_variant_id":                          "variant not found",
+	"fk_order_item_modifiers_order_item_id":              "order item not found",
+	"fk_order_item_modifiers_modifier_id":                "modifier not found",
+	"fk_order_item_options_order_item_id":                "order item not found",
+	"fk_order_item_options_option_id":                    "bundle option not found",
+	"fk_transactions_order_id":                           "order not found",
+}
+
+// translateError turns constraint violations into errors the handlers answer with a client error: a duplicate or a
+// row still referenced is a conflict, a reference to a missing row is a bad request. Other errors are returned as is.
+func translateError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	var field string
+	if match := keyDetail.FindStringSubmatch(pgErr.Detail); match != nil {
+		field = match[1]
+	}
+
+	message, known := constraintMessages[pgErr.ConstraintName]
+
+	switch pgErr.Code {
+	case uniqueViolation:
+		if !known {
+			message = field + " already exist"
+		}
+
+		return &custom_error.ConflictError{Message: message, Field: field}
+	case foreignKeyViolation:
+		// the same code is raised when deleting a row other rows still reference
+		if strings.Contains(pgErr.Detail, "is still referenced") {
+			return &custom_error.ConflictError{Message: "it is still in use", Field: field}
+		}
+
+		if !known {
+			message = field + " not found"
+		}
+
+		return &custom_error.BadRequest{Message: message, Field: field}
+	default:
+		return err
+	}
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"gorm.io/gorm"
+)
+
+type FailedApprovalRepository interface {
+	Repository[model.FailedApproval]
+}
+
+func NewFailedApprovalRepository(conn *gorm.DB) FailedApprovalRepository {
+	return NewRepository[model.FailedApproval](conn, Hooks[model.FailedApproval]{})
+}
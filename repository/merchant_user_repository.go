@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"github.com/rehandwi03/test-case-backend-majoo/query"
+	"gorm.io/gorm"
+)
+
+type MerchantUserRepository interface {
+	Repository[model.MerchantUser]
+	// MerchantRoles returns the role the user holds in every merchant they are a member of, by merchant id.
+	MerchantRoles(ctx context.Context, userId uuid.UUID) (map[string]string, error)
+}
+
+type merchantUserRepository struct {
+	Repository[model.MerchantUser]
+}
+
+func NewMerchantUserRepository(conn *gorm.DB) MerchantUserRepository {
+	return &merchantUserRepository{
+		Repository: NewRepository[model.MerchantUser](conn, Hooks[model.MerchantUser]{}),
+	}
+}
+
+func (m merchantUserRepository) MerchantRoles(ctx context.Context, userId uuid.UUID) (map[string]string, error) {
+	memberships, err := m.List(ctx, query.Where(query.Eq("user_id", userId)))
+	if err != nil {
+		return nil, err
+	}
+
+	merchantRoles := map[string]string{}
+	for _, membership := range memberships {
+		merchantRoles[membership.MerchantID.String()] = membership.Role
+	}
+
+	return merchantRoles, nil
+}
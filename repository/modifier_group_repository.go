@@ -0,0 +1,48 @@
+package repository
+
+import (
+	custom_error "github.com/rehandwi03/test-case-backend-majoo/internal/error"
+	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"gorm.io/gorm"
+)
+
+type ModifierGroupRepository interface {
+	Repository[model.ModifierGroup]
+}
+
+// NewModifierGroupRepository saves a modifier group together with its modifiers, the modifiers left out are deleted.
+// Deleting a group deletes its modifiers.
+func NewModifierGroupRepository(conn *gorm.DB) ModifierGroupRepository {
+	return NewRepository[model.ModifierGroup](
+		conn, Hooks[model.ModifierGroup]{
+			Query: func(db *gorm.DB) *gorm.DB {
+				return db.Preload("Modifiers", orderModifiers)
+			},
+			AfterSave: func(tx *gorm.DB, group *model.ModifierGroup) error {
+				for i := range group.Modifiers {
+					group.Modifiers[i].GroupID = group.ID
+				}
+
+				return saveChildren(
+					tx, "group_id", group.ID, group.Modifiers,
+					func(modifier *model.Modifier) map[string]interface{} {
+						return map[string]interface{}{
+							"name":                 modifier.Name,
+							"price_delta_amount":   modifier.PriceDelta.Amount,
+							"price_delta_currency": modifier.PriceDelta.Currency,
+							"sort_order":           modifier.SortOrder,
+						}
+					},
+					&custom_error.BadRequest{Message: "modifier not found", Field: "modifiers"}, nil,
+				)
+			},
+			AfterDelete: func(tx *gorm.DB, group *model.ModifierGroup) error {
+				return cascadeDelete(tx, modifierGroupDescendants, group.ID, group.DeletedAt)
+			},
+		},
+	)
+}
+
+func orderModifiers(db *gorm.DB) *gorm.DB {
+	return db.Order("sort_order, name")
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type NotificationRepository interface {
+	Repository[model.Notification]
+	// LowStock builds a low stock notification for every movement created since the time that took the stock of a
+	// product, or variant, from above the reorder point of the product to at or below it, and that wasn't notified of
+	// yet.
+	LowStock(ctx context.Context, since time.Time) ([]model.Notification, error)
+	// Notify stores the notification unless its movement already raised one of the type, it returns whether it did.
+	Notify(ctx context.Context, notification *model.Notification) (bool, error)
+}
+
+type notificationRepository struct {
+	Repository[model.Notification]
+	conn *gorm.DB
+}
+
+func NewNotificationRepository(conn *gorm.DB) NotificationRepository {
+	return &notificationRepository{
+		Repository: NewRepository[model.Notification](conn, Hooks[model.Notification]{}),
+		conn:       conn,
+	}
+}
+
+// lowStockMovement is a movement that crossed the reorder point of its product.
+type lowStockMovement struct {
+	StockMovementID uuid.UUID
+	OutletID        uuid.UUID
+	ProductID       uuid.UUID
+	VariantID       *uuid.UUID
+	Name            string
+	SKU             string
+	Stock           int64
+	ReorderPoint    int64
+	ReorderQuantity int64
+}
+
+func (n notificationRepository) LowStock(ctx context.Context, since time.Time) ([]model.Notification, error) {
+	var movements []lowStockMovement
+	err := n.conn.WithContext(ctx).Table("stock_movements m").
+		Select(
+			"m.id AS stock_movement_id, m.outlet_id, m.product_id, m.variant_id, p.name, "+
+				"COALESCE(v.sku, p.sku) AS sku, m.balance AS stock, p.reorder_point, p.reorder_quantity",
+		).
+		Joins("JOIN products p ON p.id = m.product_id AND p.deleted_at IS NULL").
+		Joins("LEFT JOIN product_variants v ON v.id = m.variant_id").
+		Where("m.created_at >= ? AND m.quantity < 0 AND p.reorder_point IS NOT NULL", since).
+		Where("m.balance <= p.reorder_point AND m.balance - m.quantity > p.reorder_point").
+		Where(
+			"NOT EXISTS (SELECT 1 FROM notifications n WHERE n.stock_movement_id = m.id AND n.type = ?)",
+			model.NotificationLowStock,
+		).
+		Order("m.created_at").
+		Scan(&movements).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var notifications []model.Notification
+	for _, movement := range movements {
+		name := movement.Name
+		if movement.SKU != "" {
+			name += " (" + movement.SKU + ")"
+		}
+
+		productId, movementId := movement.ProductID, movement.StockMovementID
+		notifications = append(
+			notifications, model.Notification{
+				OutletID:        movement.OutletID,
+				Type:            model.NotificationLowStock,
+				ProductID:       &productId,
+				VariantID:       movement.VariantID,
+				StockMovementID: &movementId,
+				Message: fmt.Sprintf(
+					"%s is low on stock, %d left, reorder %d", name, movement.Stock, movement.ReorderQuantity,
+				),
+				Stock:           movement.Stock,
+				ReorderPoint:    movement.ReorderPoint,
+				ReorderQuantity: movement.ReorderQuantity,
+			},
+		)
+	}
+
+	return notifications, nil
+}
+
+func (n notificationRepository) Notify(ctx context.Context, notification *model.Notification) (bool, error) {
+	result := n.conn.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(notification)
+	if result.Error != nil {
+		return false, translateError(result.Error)
+	}
+
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,373 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"sort"
+	"time"
+)
+
+var (
+	// ErrOrderNotOpen is a change of an order that isn't open, or of a parked one that isn't resumed or closed.
+	ErrOrderNotOpen = errors.New("the order isn't open")
+	// ErrOrderChanged is a checkout of an order which was changed, or closed, after its items were read.
+	ErrOrderChanged = errors.New("the order was changed while it was checked out, check it out again")
+	ErrItemNotFound = errors.New("order item not found")
+	// ErrSplitTooMuch is a split moving more units of an item than it has, or every unit of the order.
+	ErrSplitTooMuch = errors.New("a split has to leave units of the item, and items on the order")
+)
+
+type OrderRepository interface {
+	Repository[model.Order]
+	// Open stores the order together with its items.
+	Open(ctx context.Context, order model.Order) (uuid.UUID, error)
+	// Update writes the name, guests and note of an open order.
+	Update(ctx context.Context, order model.Order) error
+	// Transfer moves an open order to the table, or away from its table when tableId is nil.
+	Transfer(ctx context.Context, orderId uuid.UUID, tableId *uuid.UUID) error
+	AddItems(ctx context.Context, orderId uuid.UUID, items []model.OrderItem) error
+	// UpdateItem writes the quantity, seat and note of an item of an open order.
+	UpdateItem(ctx context.Context, orderId uuid.UUID, item model.OrderItem) error
+	RemoveItem(ctx context.Context, orderId uuid.UUID, itemId uuid.UUID) error
+	// SetStatus moves the order to a status from one of the statuses given.
+	SetStatus(ctx context.Context, orderId uuid.UUID, to string, from ...string) error
+	// Merge moves the items of the other open orders into the order, the others are merged into it.
+	Merge(ctx context.Context, orderId uuid.UUID, otherIds []uuid.UUID) error
+	// Split moves units of items of the order, quantities by item id, to the new order split off, whose id is
+	// returned.
+	Split(ctx context.Context, orderId uuid.UUID, split model.Order, quantities map[uuid.UUID]int64) (uuid.UUID, error)
+}
+
+type orderRepository struct {
+	Repository[model.Order]
+	conn *gorm.DB
+}
+
+// NewOrderRepository reads orders with their items in the order they were added. Writes to an order lock it first
+// and fail with ErrOrderNotOpen unless it is open, every write bumps its modified_at so a checkout reading its items
+// can tell they changed.
+func NewOrderRepository(conn *gorm.DB) OrderRepository {
+	return &orderRepository{
+		Repository: NewRepository[model.Order](
+			conn, Hooks[model.Order]{
+				Query: func(db *gorm.DB) *gorm.DB {
+					return db.Preload("Items", orderByCreation).Preload("Items.Modifiers").Preload("Items.Options")
+				},
+			},
+		),
+		conn: conn,
+	}
+}
+
+func (o orderRepository) Open(ctx context.Context, order model.Order) (uuid.UUID, error) {
+	err := o.conn.WithContext(ctx).Create(&order).Error
+	if err != nil {
+		return uuid.Nil, translateError(err)
+	}
+
+	return order.ID, nil
+}
+
+func (o orderRepository) Update(ctx context.Context, order model.Order) error {
+	err := o.conn.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			_, err := lockOrder(tx, order.ID, model.OrderOpen)
+			if err != nil {
+				return err
+			}
+
+			return tx.Model(&model.Order{}).Where("id = ?", order.ID).Updates(
+				map[string]interface{}{
+					"name": order.Name, "guests": order.Guests, "note": order.Note, "modified_at": time.Now(),
+				},
+			).Error
+		},
+	)
+
+	return translateError(err)
+}
+
+func (o orderRepository) Transfer(ctx context.Context, orderId uuid.UUID, tableId *uuid.UUID) error {
+	err := o.conn.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			_, err := lockOrder(tx, orderId, model.OrderOpen)
+			if err != nil {
+				return err
+			}
+
+			return tx.Model(&model.Order{}).Where("id = ?", orderId).Updates(
+				map[string]interface{}{"table_id": tableId, "modified_at": time.Now()},
+			).Error
+		},
+	)
+
+	return translateError(err)
+}
+
+func (o orderRepository) AddItems(ctx context.Context, orderId uuid.UUID, items []model.OrderItem) error {
+	err := o.conn.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			_, err := lockOrder(tx, orderId, model.OrderOpen)
+			if err != nil {
+				return err
+			}
+
+			for i := range items {
+				items[i].OrderID = orderId
+			}
+
+			err = tx.Create(&items).Error
+			if err != nil {
+				return err
+			}
+
+			return touchOrder(tx, orderId)
+		},
+	)
+
+	return translateError(err)
+}
+
+func (o orderRepository) UpdateItem(ctx context.Context, orderId uuid.UUID, item model.OrderItem) error {
+	err := o.conn.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			_, err := lockOrder(tx, orderId, model.OrderOpen)
+			if err != nil {
+				return err
+			}
+
+			result := tx.Model(&model.OrderItem{}).Where("id = ? AND order_id = ?", item.ID, orderId).Updates(
+				map[string]interface{}{
+					"quantity": item.Quantity, "seat": item.Seat, "note": item.Note, "modified_at": time.Now(),
+				},
+			)
+			if result.Error != nil {
+				return result.Error
+			}
+
+			if result.RowsAffected == 0 {
+				return ErrItemNotFound
+			}
+
+			return touchOrder(tx, orderId)
+		},
+	)
+
+	return translateError(err)
+}
+
+func (o orderRepository) RemoveItem(ctx context.Context, orderId uuid.UUID, itemId uuid.UUID) error {
+	err := o.conn.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			_, err := lockOrder(tx, orderId, model.OrderOpen)
+			if err != nil {
+				return err
+			}
+
+			result := tx.Where("id = ? AND order_id = ?", itemId, orderId).Delete(&model.OrderItem{})
+			if result.Error != nil {
+				return result.Error
+			}
+
+			if result.RowsAffected == 0 {
+				return ErrItemNotFound
+			}
+
+			return touchOrder(tx, orderId)
+		},
+	)
+
+	return translateError(err)
+}
+
+func (o orderRepository) SetStatus(ctx context.Context, orderId uuid.UUID, to string, from ...string) error {
+	err := o.conn.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			_, err := lockOrder(tx, orderId, from...)
+			if err != nil {
+				return err
+			}
+
+			return tx.Model(&model.Order{}).Where("id = ?", orderId).Updates(
+				map[string]interface{}{"status": to, "modified_at": time.Now()},
+			).Error
+		},
+	)
+
+	return translateError(err)
+}
+
+// Merge locks the orders in id order, so merges of the same orders the other way around can't deadlock.
+func (o orderRepository) Merge(ctx context.Context, orderId uuid.UUID, otherIds []uuid.UUID) error {
+	err := o.conn.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			ids := append([]uuid.UUID{orderId}, otherIds...)
+			sort.Slice(
+				ids, func(i, j int) bool {
+					return ids[i].String() < ids[j].String()
+				},
+			)
+
+			var guests int64
+			for _, id := range ids {
+				order, err := lockOrder(tx, id, model.OrderOpen)
+				if err != nil {
+					return err
+				}
+
+				if id != orderId {
+					guests += order.Guests
+				}
+			}
+
+			err := tx.Model(&model.OrderItem{}).Where("order_id IN ?", otherIds).Updates(
+				map[string]interface{}{"order_id": orderId, "modified_at": time.Now()},
+			).Error
+			if err != nil {
+				return err
+			}
+
+			err = tx.Model(&model.Order{}).Where("id IN ?", otherIds).Updates(
+				map[string]interface{}{
+					"status": model.OrderMerged, "merged_into_id": orderId, "modified_at": time.Now(),
+				},
+			).Error
+			if err != nil {
+				return err
+			}
+
+			return tx.Model(&model.Order{}).Where("id = ?", orderId).Updates(
+				map[string]interface{}{"guests": gorm.Expr("guests + ?", guests), "modified_at": time.Now()},
+			).Error
+		},
+	)
+
+	return translateError(err)
+}
+
+// Split moves an item all of whose units are split off to the new order, and splits the units off an item into a
+// copy of it, with the same modifiers and options, otherwise.
+func (o orderRepository) Split(
+	ctx context.Context, orderId uuid.UUID, split model.Order, quantities map[uuid.UUID]int64,
+) (uuid.UUID, error) {
+	err := o.conn.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			_, err := lockOrder(tx, orderId, model.OrderOpen)
+			if err != nil {
+				return err
+			}
+
+			var items []model.OrderItem
+			err = tx.Preload("Modifiers").Preload("Options").Where("order_id = ?", orderId).Find(&items).Error
+			if err != nil {
+				return err
+			}
+
+			var left int64
+			for _, item := range items {
+				left += item.Quantity - quantities[item.ID]
+			}
+			if left <= 0 {
+				return ErrSplitTooMuch
+			}
+
+			split.Status = model.OrderOpen
+			err = tx.Omit(clause.Associations).Create(&split).Error
+			if err != nil {
+				return err
+			}
+
+			itemMap := map[uuid.UUID]model.OrderItem{}
+			for _, item := range items {
+				itemMap[item.ID] = item
+			}
+
+			for itemId, quantity := range quantities {
+				item, ok := itemMap[itemId]
+				if !ok {
+					return ErrItemNotFound
+				}
+
+				if quantity > item.Quantity {
+					return ErrSplitTooMuch
+				}
+
+				if quantity == item.Quantity {
+					err = tx.Model(&model.OrderItem{}).Where("id = ?", item.ID).Updates(
+						map[string]interface{}{"order_id": split.ID, "modified_at": time.Now()},
+					).Error
+					if err != nil {
+						return err
+					}
+
+					continue
+				}
+
+				err = tx.Model(&model.OrderItem{}).Where("id = ?", item.ID).Updates(
+					map[string]interface{}{"quantity": item.Quantity - quantity, "modified_at": time.Now()},
+				).Error
+				if err != nil {
+					return err
+				}
+
+				splitItem := model.OrderItem{
+					OrderID:     split.ID,
+					ProductID:   item.ProductID,
+					VariantID:   item.VariantID,
+					Name:        item.Name,
+					VariantName: item.VariantName,
+					Price:       item.Price,
+					Quantity:    quantity,
+					Seat:        item.Seat,
+					Note:        item.Note,
+				}
+				for _, modifier := range item.Modifiers {
+					splitItem.Modifiers = append(
+						splitItem.Modifiers, model.OrderItemModifier{ModifierID: modifier.ModifierID},
+					)
+				}
+				for _, option := range item.Options {
+					splitItem.Options = append(splitItem.Options, model.OrderItemOption{OptionID: option.OptionID})
+				}
+
+				err = tx.Create(&splitItem).Error
+				if err != nil {
+					return err
+				}
+			}
+
+			return touchOrder(tx, orderId)
+		},
+	)
+	if err != nil {
+		return uuid.Nil, translateError(err)
+	}
+
+	return split.ID, nil
+}
+
+// lockOrder locks the order for the rest of the transaction, so it can't be closed while it is written. The order
+// has to have one of the statuses.
+func lockOrder(tx *gorm.DB, orderId uuid.UUID, statuses ...string) (model.Order, error) {
+	var order model.Order
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", orderId).First(&order).Error
+	if err != nil {
+		return order, err
+	}
+
+	for _, status := range statuses {
+		if order.Status == status {
+			return order, nil
+		}
+	}
+
+	return order, ErrOrderNotOpen
+}
+
+func touchOrder(tx *gorm.DB, orderId uuid.UUID) error {
+	return tx.Model(&model.Order{}).Where("id = ?", orderId).Update("modified_at", time.Now()).Error
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	custom_error "github.com/rehandwi03/test-case-backend-majoo/internal/error"
+	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"gorm.io/gorm"
+)
+
+type ProductOptionRepository interface {
+	Repository[model.ProductOption]
+}
+
+// NewProductOptionRepository saves an option together with its values: values with an id are updated, the others are
+// created and the values left out are deleted. A value still used by a live variant can't be deleted, neither can the
+// option holding it.
+func NewProductOptionRepository(conn *gorm.DB) ProductOptionRepository {
+	return NewRepository[model.ProductOption](
+		conn, Hooks[model.ProductOption]{
+			Query: func(db *gorm.DB) *gorm.DB {
+				return db.Preload(
+					"Values", func(db *gorm.DB) *gorm.DB {
+						return db.Order("sort_order, value")
+					},
+				)
+			},
+			AfterSave: func(tx *gorm.DB, option *model.ProductOption) error {
+				for i := range option.Values {
+					option.Values[i].OptionID = option.ID
+				}
+
+				return saveChildren(
+					tx, "option_id", option.ID, option.Values,
+					func(value *model.ProductOptionValue) map[string]interface{} {
+						return map[string]interface{}{"value": value.Value, "sort_order": value.SortOrder}
+					},
+					&custom_error.BadRequest{Message: "option value not found", Field: "values"}, requireUnusedValues,
+				)
+			},
+			BeforeDelete: func(tx *gorm.DB, option *model.ProductOption) error {
+				return requireUnusedValues(
+					tx, tx.Model(&model.ProductOptionValue{}).Select("id").Where("option_id = ?", option.ID),
+				)
+			},
+			AfterDelete: func(tx *gorm.DB, option *model.ProductOption) error {
+				return cascadeDelete(tx, optionDescendants, option.ID, option.DeletedAt)
+			},
+		},
+	)
+}
+
+// requireUnusedValues fails when a live variant is made of one of the option values the subquery selects.
+func requireUnusedValues(tx *gorm.DB, values *gorm.DB) error {
+	var count int64
+	err := tx.Model(&model.ProductVariant{}).
+		Where("id IN (SELECT variant_id FROM product_variant_values WHERE option_value_id IN (?))", values).
+		Count(&count).Error
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return &custom_error.ConflictError{Message: "the option value is still used by a variant", Field: "values"}
+	}
+
+	return nil
+}
@@ -5,126 +5,135 @@ import (
 	"github.com/google/uuid"
 	"github.com/rehandwi03/test-case-backend-majoo/model"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ProductRepository interface {
-	Save(ctx context.Context, product model.Product) (uuid.UUID, error)
-	GetByParam(ctx context.Context, params map[string]interface{}) (model.Product, error)
-	GetByParams(ctx context.Context, params map[string]interface{}) ([]model.Product, error)
-	Delete(ctx context.Context, data *model.Product) error
-	Fetch(ctx context.Context, params map[string]interface{}) (res []model.Product, count int64, err error)
+	Repository[model.Product]
+	// SetCategories replaces the categories of the product.
+	SetCategories(ctx context.Context, productId uuid.UUID, categoryIds []uuid.UUID) error
+	// SetRecipe replaces the recipe of the product, no lines leave it without one.
+	SetRecipe(ctx context.Context, productId uuid.UUID, lines []model.RecipeLine) error
 }
 
 type productRepository struct {
+	Repository[model.Product]
 	conn *gorm.DB
 }
 
+// NewProductRepository reads products with their categories, options, variants, modifier groups, recipe and bundle
+// slots, and cascades deletes and restores of a product to its options, variants, modifier groups and bundle slots. A
+// product of a deleted outlet can't be restored on its own. Saving a product keeps the stock its ledger left, the stock
+// of a new one opens the ledger.
 func NewProductRepository(conn *gorm.DB) ProductRepository {
-	return &productRepository{conn: conn}
-}
-
-func (p productRepository) Fetch(ctx context.Context, params map[string]interface{}) (
-	res []model.Product, count int64, err error,
-) {
-	res, err = p.GetByParams(ctx, params)
-	if err != nil {
-		return res, count, err
-	}
-
-	done := make(chan bool, 1)
-	p.countRecords(ctx, model.Product{}, done, &count, params)
-
-	<-done
-
-	return res, count, nil
-}
-
-func (p productRepository) Save(ctx context.Context, product model.Product) (uuid.UUID, error) {
-	err := p.conn.WithContext(ctx).Save(&product).Error
-	if err != nil {
-		return uuid.Nil, err
+	return &productRepository{
+		Repository: NewRepository[model.Product](
+			conn, Hooks[model.Product]{
+				Query: func(db *gorm.DB) *gorm.DB {
+					db = preloadVariants(
+						db.Preload(
+							"Categories", func(db *gorm.DB) *gorm.DB {
+								return db.Order("sort_order, name")
+							},
+						).Preload(
+							"Options", func(db *gorm.DB) *gorm.DB {
+								return db.Order("sort_order, name")
+							},
+						).Preload(
+							"Options.Values", func(db *gorm.DB) *gorm.DB {
+								return db.Order("sort_order, value")
+							},
+						), "Variants.",
+					).Preload(
+						"Variants", func(db *gorm.DB) *gorm.DB {
+							return db.Order("created_at")
+						},
+					).Preload(
+						"ModifierGroups", func(db *gorm.DB) *gorm.DB {
+							return db.Order("sort_order, name")
+						},
+					).Preload("ModifierGroups.Modifiers", orderModifiers).Preload("Recipe").Preload("Recipe.Ingredient")
+
+					// the options of the bundle slots come with the products and variants filling them
+					return preloadVariants(
+						db.Preload(
+							"BundleSlots", func(db *gorm.DB) *gorm.DB {
+								return db.Order("sort_order, name")
+							},
+						).Preload("BundleSlots.Options", orderBundleOptions).Preload("BundleSlots.Options.Product"),
+						"BundleSlots.Options.Variant.",
+					).Preload("BundleSlots.Options.Variant")
+				},
+				BeforeSave: func(tx *gorm.DB, product *model.Product) error {
+					return keepStock(
+						tx, &model.Product{}, product.ID, &product.Stock, &product.CostPrice, &product.AverageCost,
+					)
+				},
+				AfterSave: func(tx *gorm.DB, product *model.Product) error {
+					return openStock(tx, product.ID, nil, product.Stock)
+				},
+				AfterDelete: func(tx *gorm.DB, product *model.Product) error {
+					return cascadeDelete(tx, productDescendants, product.ID, product.DeletedAt)
+				},
+				BeforeRestore: func(tx *gorm.DB, product *model.Product) error {
+					return requireLive(
+						tx, &model.Outlet{}, product.OutletID, "outlet_id", "the outlet of the product is deleted",
+					)
+				},
+				AfterRestore: func(tx *gorm.DB, product *model.Product) error {
+					return cascadeRestore(tx, productDescendants, product.ID, product.DeletedAt)
+				},
+			},
+		),
+		conn: conn,
 	}
-
-	return product.ID, nil
 }
 
-func (p productRepository) GetByParam(ctx context.Context, params map[string]interface{}) (
-	res model.Product, err error,
-) {
-	query := p.conn.WithContext(ctx)
-	if params["where"] != nil && params["where"].(map[string]interface{})["default"] != nil {
-		for field, value := range params["where"].(map[string]interface{})["default"].(map[string]interface{}) {
-			query = query.Where(field, value)
-		}
-	}
-
-	if params["where"] != nil && params["where"].(map[string]interface{})["or"] != nil {
-		for field, value := range params["where"].(map[string]interface{})["or"].(map[string]interface{}) {
-			query = query.Or(field, value)
-		}
-	}
-
-	err = query.First(&res).Error
-	if err != nil {
-		return res, err
-	}
-
-	return res, nil
+func (p productRepository) SetCategories(ctx context.Context, productId uuid.UUID, categoryIds []uuid.UUID) error {
+	err := p.conn.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			err := tx.Where("product_id = ?", productId).Delete(&model.ProductCategory{}).Error
+			if err != nil {
+				return err
+			}
+
+			if len(categoryIds) == 0 {
+				return nil
+			}
+
+			var productCategories []model.ProductCategory
+			for _, categoryId := range categoryIds {
+				productCategories = append(
+					productCategories, model.ProductCategory{ProductID: productId, CategoryID: categoryId},
+				)
+			}
+
+			return tx.Create(&productCategories).Error
+		},
+	)
+
+	return translateError(err)
 }
 
-func (p productRepository) GetByParams(ctx context.Context, params map[string]interface{}) (
-	res []model.Product, err error,
-) {
-	query := p.conn.WithContext(ctx)
-	if params["where"] != nil && params["where"].(map[string]interface{})["default"] != nil {
-		for field, value := range params["where"].(map[string]interface{})["default"].(map[string]interface{}) {
-			query = query.Where(field, value)
-		}
-	}
-
-	if params["where"] != nil && params["where"].(map[string]interface{})["or"] != nil {
-		for field, value := range params["where"].(map[string]interface{})["or"].(map[string]interface{}) {
-			query = query.Or(field, value)
-		}
-	}
-
-	if params["where"] != nil && params["where"].(map[string]interface{})["pagination"] != nil {
-		page := params["where"].(map[string]interface{})["pagination"].(map[string]interface{})["page"].(int)
-		limit := params["where"].(map[string]interface{})["pagination"].(map[string]interface{})["limit"].(int)
-		sort := params["where"].(map[string]interface{})["pagination"].(map[string]interface{})["sort"].(string)
-
-		offset := (page - 1) * limit
-		query = query.Limit(limit).Offset(offset).Order(sort)
-	}
-
-	err = query.Find(&res).Error
-	if err != nil {
-		return res, err
-	}
+func (p productRepository) SetRecipe(ctx context.Context, productId uuid.UUID, lines []model.RecipeLine) error {
+	err := p.conn.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			err := tx.Where("product_id = ?", productId).Delete(&model.RecipeLine{}).Error
+			if err != nil {
+				return err
+			}
 
-	return res, nil
-}
+			if len(lines) == 0 {
+				return nil
+			}
 
-func (p productRepository) Delete(ctx context.Context, data *model.Product) error {
-	err := p.conn.WithContext(ctx).Delete(data).Error
-	if err != nil {
-		return err
-	}
+			for i := range lines {
+				lines[i].ProductID = productId
+			}
 
-	return nil
-}
-
-func (p productRepository) countRecords(
-	ctx context.Context, countDataSource interface{}, done chan bool,
-	count *int64, params map[string]interface{},
-) {
-	query := p.conn.WithContext(ctx)
-	if params["where"] != nil && params["where"].(map[string]interface{})["default"] != nil {
-		for whereKey, whereValue := range params["where"].(map[string]interface{})["default"].(map[string]interface{}) {
-			query = query.Where(whereKey, whereValue)
-		}
-	}
+			return tx.Omit(clause.Associations).Create(&lines).Error
+		},
+	)
 
-	query.Model(countDataSource).Count(count)
-	done <- true
+	return translateError(err)
 }
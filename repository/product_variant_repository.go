@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"gorm.io/gorm"
+)
+
+type ProductVariantRepository interface {
+	Repository[model.ProductVariant]
+}
+
+// NewProductVariantRepository saves a variant together with the option values it is made of. Like products, saving a
+// variant keeps the stock its ledger left.
+func NewProductVariantRepository(conn *gorm.DB) ProductVariantRepository {
+	return NewRepository[model.ProductVariant](
+		conn, Hooks[model.ProductVariant]{
+			Query: func(db *gorm.DB) *gorm.DB {
+				return preloadVariants(db, "")
+			},
+			BeforeSave: func(tx *gorm.DB, variant *model.ProductVariant) error {
+				return keepStock(
+					tx, &model.ProductVariant{}, variant.ID, &variant.Stock, &variant.CostPrice, &variant.AverageCost,
+				)
+			},
+			AfterSave: func(tx *gorm.DB, variant *model.ProductVariant) error {
+				err := openStock(tx, variant.ProductID, &variant.ID, variant.Stock)
+				if err != nil {
+					return err
+				}
+
+				err = tx.Where("variant_id = ?", variant.ID).Delete(&model.ProductVariantValue{}).Error
+				if err != nil {
+					return err
+				}
+
+				if len(variant.OptionValues) == 0 {
+					return nil
+				}
+
+				var variantValues []model.ProductVariantValue
+				for _, value := range variant.OptionValues {
+					variantValues = append(
+						variantValues, model.ProductVariantValue{VariantID: variant.ID, OptionValueID: value.ID},
+					)
+				}
+
+				return tx.Create(&variantValues).Error
+			},
+		},
+	)
+}
+
+// preloadVariants loads the option values of the variants at path, in the order of their options.
+func preloadVariants(db *gorm.DB, path string) *gorm.DB {
+	return db.Preload(
+		path+"OptionValues", func(db *gorm.DB) *gorm.DB {
+			return db.Order("(SELECT sort_order FROM product_options WHERE id = option_id), sort_order")
+		},
+	)
+}
@@ -0,0 +1,236 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"github.com/rehandwi03/test-case-backend-majoo/money"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"sort"
+	"time"
+)
+
+var (
+	ErrPurchaseOrderStatus = errors.New("the purchase order can't be changed in its status")
+	ErrOverOrdered         = errors.New("more would be received than was ordered")
+)
+
+// GoodsReceipt is a quantity of a line of a purchase order received at a unit cost.
+type GoodsReceipt struct {
+	LineID   uuid.UUID
+	Quantity int64
+	UnitCost money.Money
+}
+
+type PurchaseOrderRepository interface {
+	Repository[model.PurchaseOrder]
+	// Create stores the draft together with its lines.
+	Create(ctx context.Context, order model.PurchaseOrder) (uuid.UUID, error)
+	// Place sends a draft to the supplier.
+	Place(ctx context.Context, orderId uuid.UUID, userId uuid.UUID) error
+	// Receive restocks the outlet of the order with the receipts and makes the cost of a receipt the cost price of its
+	// product or variant. The order is received once every line is, ErrOverOrdered is returned for a line receiving
+	// more than was ordered.
+	Receive(ctx context.Context, orderId uuid.UUID, userId uuid.UUID, receipts []GoodsReceipt) error
+	Cancel(ctx context.Context, orderId uuid.UUID) error
+}
+
+type purchaseOrderRepository struct {
+	Repository[model.PurchaseOrder]
+	conn *gorm.DB
+}
+
+// NewPurchaseOrderRepository reads orders with their lines. Writes to an order lock it first and fail with
+// ErrPurchaseOrderStatus when it isn't in a status allowing them.
+func NewPurchaseOrderRepository(conn *gorm.DB) PurchaseOrderRepository {
+	return &purchaseOrderRepository{
+		Repository: NewRepository[model.PurchaseOrder](
+			conn, Hooks[model.PurchaseOrder]{
+				Query: func(db *gorm.DB) *gorm.DB {
+					return db.Preload(
+						"Lines", func(db *gorm.DB) *gorm.DB {
+							return db.Order("name, variant_name")
+						},
+					)
+				},
+			},
+		),
+		conn: conn,
+	}
+}
+
+func (p purchaseOrderRepository) Create(ctx context.Context, order model.PurchaseOrder) (uuid.UUID, error) {
+	err := p.conn.WithContext(ctx).Create(&order).Error
+	if err != nil {
+		return uuid.Nil, translateError(err)
+	}
+
+	return order.ID, nil
+}
+
+func (p purchaseOrderRepository) Place(ctx context.Context, orderId uuid.UUID, userId uuid.UUID) error {
+	err := p.conn.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			_, err := lockPurchaseOrder(tx, orderId, model.PurchaseOrderDraft)
+			if err != nil {
+				return err
+			}
+
+			return tx.Model(&model.PurchaseOrder{}).Where("id = ?", orderId).Updates(
+				map[string]interface{}{
+					"status": model.PurchaseOrderOrdered, "ordered_by": userId,
+					"ordered_at": sql.NullTime{Time: time.Now(), Valid: true}, "modified_at": time.Now(),
+				},
+			).Error
+		},
+	)
+
+	return translateError(err)
+}
+
+func (p purchaseOrderRepository) Receive(
+	ctx context.Context, orderId uuid.UUID, userId uuid.UUID, receipts []GoodsReceipt,
+) error {
+	err := p.conn.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			order, err := lockPurchaseOrder(
+				tx, orderId, model.PurchaseOrderOrdered, model.PurchaseOrderPartiallyReceived,
+			)
+			if err != nil {
+				return err
+			}
+
+			lines := map[uuid.UUID]model.PurchaseOrderLine{}
+			for _, line := range order.Lines {
+				lines[line.ID] = line
+			}
+
+			sort.Slice(
+				receipts, func(i, j int) bool {
+					first, second := lines[receipts[i].LineID], lines[receipts[j].LineID]
+					return stockKey(first.ProductID, first.VariantID) < stockKey(second.ProductID, second.VariantID)
+				},
+			)
+			for _, receipt := range receipts {
+				line, ok := lines[receipt.LineID]
+				if !ok {
+					return gorm.ErrRecordNotFound
+				}
+
+				result := tx.Model(&model.PurchaseOrderLine{}).
+					Where("id = ? AND received + ? <= quantity", line.ID, receipt.Quantity).
+					Updates(
+						map[string]interface{}{
+							"received": gorm.Expr("received + ?", receipt.Quantity),
+							"received_cost_amount": gorm.Expr(
+								"received_cost_amount + ?", receipt.UnitCost.Mul(receipt.Quantity).Amount,
+							),
+							"modified_at": time.Now(),
+						},
+					)
+				if result.Error != nil {
+					return result.Error
+				}
+
+				if result.RowsAffected == 0 {
+					return ErrOverOrdered
+				}
+
+				err = postMovement(
+					tx, &model.StockMovement{
+						OutletID:        order.OutletID,
+						ProductID:       line.ProductID,
+						VariantID:       line.VariantID,
+						Type:            model.MovementRestock,
+						Reason:          model.StockPurchase,
+						Quantity:        receipt.Quantity,
+						PurchaseOrderID: &order.ID,
+						UnitCost:        receipt.UnitCost,
+						UserID:          &userId,
+					},
+				)
+				if err != nil {
+					return err
+				}
+
+				err = costPrice(tx, line.ProductID, line.VariantID).Updates(
+					map[string]interface{}{
+						"cost_price_amount":   receipt.UnitCost.Amount,
+						"cost_price_currency": receipt.UnitCost.Currency,
+					},
+				).Error
+				if err != nil {
+					return err
+				}
+			}
+
+			var outstanding int64
+			err = tx.Model(&model.PurchaseOrderLine{}).Where("order_id = ? AND received < quantity", orderId).
+				Count(&outstanding).Error
+			if err != nil {
+				return err
+			}
+
+			status := model.PurchaseOrderReceived
+			if outstanding > 0 {
+				status = model.PurchaseOrderPartiallyReceived
+			}
+
+			return tx.Model(&model.PurchaseOrder{}).Where("id = ?", orderId).
+				Updates(map[string]interface{}{"status": status, "modified_at": time.Now()}).Error
+		},
+	)
+
+	return translateError(err)
+}
+
+func (p purchaseOrderRepository) Cancel(ctx context.Context, orderId uuid.UUID) error {
+	err := p.conn.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			_, err := lockPurchaseOrder(tx, orderId, model.PurchaseOrderDraft, model.PurchaseOrderOrdered)
+			if err != nil {
+				return err
+			}
+
+			return tx.Model(&model.PurchaseOrder{}).Where("id = ?", orderId).
+				Updates(map[string]interface{}{"status": model.PurchaseOrderCancelled, "modified_at": time.Now()}).Error
+		},
+	)
+
+	return translateError(err)
+}
+
+// lockPurchaseOrder locks the order for the rest of the transaction and reads it with its lines, it has to be in one
+// of the statuses.
+func lockPurchaseOrder(tx *gorm.DB, orderId uuid.UUID, statuses ...string) (model.PurchaseOrder, error) {
+	var order model.PurchaseOrder
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", orderId).First(&order).Error
+	if err != nil {
+		return order, err
+	}
+
+	err = tx.Where("order_id = ?", orderId).Find(&order.Lines).Error
+	if err != nil {
+		return order, err
+	}
+
+	for _, status := range statuses {
+		if order.Status == status {
+			return order, nil
+		}
+	}
+
+	return order, ErrPurchaseOrderStatus
+}
+
+// costPrice selects the product, or the variant, whose cost price a purchase sets.
+func costPrice(tx *gorm.DB, productId uuid.UUID, variantId *uuid.UUID) *gorm.DB {
+	if variantId != nil {
+		return tx.Model(&model.ProductVariant{}).Where("id = ?", *variantId)
+	}
+
+	return tx.Model(&model.Product{}).Where("id = ?", productId)
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"github.com/rehandwi03/test-case-backend-majoo/query"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+var ErrTokenRevoked = errors.New("token has been revoked")
+
+type RefreshTokenRepository interface {
+	Repository[model.RefreshToken]
+	Rotate(ctx context.Context, current model.RefreshToken, next model.RefreshToken) error
+	Revoke(ctx context.Context, spec query.Spec) error
+}
+
+type refreshTokenRepository struct {
+	Repository[model.RefreshToken]
+	conn *gorm.DB
+}
+
+func NewRefreshTokenRepository(conn *gorm.DB) RefreshTokenRepository {
+	return &refreshTokenRepository{
+		Repository: NewRepository[model.RefreshToken](conn, Hooks[model.RefreshToken]{}),
+		conn:       conn,
+	}
+}
+
+// Rotate revokes the current refresh token together with its access token and stores the next one. The current
+// token is only revoked when nobody else did it first, so the same token can't be exchanged twice.
+func (r refreshTokenRepository) Rotate(
+	ctx context.Context, current model.RefreshToken, next model.RefreshToken,
+) error {
+	return r.conn.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			now := time.Now()
+			result := tx.Model(&model.RefreshToken{}).
+				Where("id = ? AND revoked_at IS NULL", current.ID).
+				Updates(map[string]interface{}{"revoked_at": now, "modified_at": now})
+			if result.Error != nil {
+				return result.Error
+			}
+
+			if result.RowsAffected == 0 {
+				return ErrTokenRevoked
+			}
+
+			err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(
+				&model.RevokedToken{
+					ID:        current.AccessTokenID,
+					UserID:    current.UserID,
+					ExpiresAt: current.AccessTokenExpiresAt,
+				},
+			).Error
+			if err != nil {
+				return err
+			}
+
+			return tx.Create(&next).Error
+		},
+	)
+}
+
+// Revoke revokes every active refresh token matching the spec and the access tokens issued with them.
+func (r refreshTokenRepository) Revoke(ctx context.Context, spec query.Spec) error {
+	return r.conn.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			var tokens []model.RefreshToken
+			err := spec.And(query.IsNull("revoked_at")).Apply(tx).
+				Clauses(clause.Locking{Strength: "UPDATE"}).Find(&tokens).Error
+			if err != nil {
+				return err
+			}
+
+			if len(tokens) == 0 {
+				return nil
+			}
+
+			var ids []uuid.UUID
+			var revokedTokens []model.RevokedToken
+			for _, token := range tokens {
+				ids = append(ids, token.ID)
+				revokedTokens = append(
+					revokedTokens, model.RevokedToken{
+						ID:        token.AccessTokenID,
+						UserID:    token.UserID,
+						ExpiresAt: token.AccessTokenExpiresAt,
+					},
+				)
+			}
+
+			err = tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&revokedTokens).Error
+			if err != nil {
+				return err
+			}
+
+			now := time.Now()
+			return tx.Model(&model.RefreshToken{}).
+				Where("id IN ?", ids).
+				Updates(map[string]interface{}{"revoked_at": now, "modified_at": now}).Error
+		},
+	)
+}
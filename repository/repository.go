@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/rehandwi03/test-case-backend-majoo/query"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// Entity is a model stored by a Repository, identified by an uuid primary key in the id column.
+type Entity[T any] interface {
+	*T
+	PrimaryKey() uuid.UUID
+}
+
+// Hooks customize a Repository. Save, delete and restore hooks run in the database transaction of the write, so
+// returning an error rolls it back.
+type Hooks[T any] struct {
+	// Query is applied to every Get and List, e.g. to preload associations.
+	Query        func(db *gorm.DB) *gorm.DB
+	BeforeSave   func(tx *gorm.DB, entity *T) error
+	AfterSave    func(tx *gorm.DB, entity *T) error
+	BeforeDelete func(tx *gorm.DB, entity *T) error
+	// AfterDelete sees the deleted_at the entity was soft deleted with.
+	AfterDelete   func(tx *gorm.DB, entity *T) error
+	BeforeRestore func(tx *gorm.DB, entity *T) error
+	// AfterRestore still sees the deleted_at the entity had, so rows deleted along with it can be found.
+	AfterRestore func(tx *gorm.DB, entity *T) error
+}
+
+// Repository is the CRUD every model gets. Reads skip soft deleted rows, Restore brings them back. Save writes the
+// columns of the entity only, associations are written by the hooks or methods of the repository owning them. Writes
+// violating a constraint fail with a custom_error.ConflictError or custom_error.BadRequest naming the field.
+type Repository[T any] interface {
+	Save(ctx context.Context, entity T) (uuid.UUID, error)
+	Get(ctx context.Context, spec query.Spec) (T, error)
+	List(ctx context.Context, spec query.Spec) ([]T, error)
+	Count(ctx context.Context, spec query.Spec) (int64, error)
+	// Fetch lists a page of the spec together with the count of every row matching it.
+	Fetch(ctx context.Context, spec query.Spec) (res []T, count int64, err error)
+	Delete(ctx context.Context, entity *T) error
+	Restore(ctx context.Context, id uuid.UUID) error
+}
+
+type repository[T any, PT Entity[T]] struct {
+	conn  *gorm.DB
+	hooks Hooks[T]
+}
+
+func NewRepository[T any, PT Entity[T]](conn *gorm.DB, hooks Hooks[T]) Repository[T] {
+	return &repository[T, PT]{conn: conn, hooks: hooks}
+}
+
+func (r repository[T, PT]) Save(ctx context.Context, entity T) (uuid.UUID, error) {
+	err := r.conn.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			if r.hooks.BeforeSave != nil {
+				if err := r.hooks.BeforeSave(tx, &entity); err != nil {
+					return err
+				}
+			}
+
+			if err := tx.Omit(clause.Associations).Save(&entity).Error; err != nil {
+				return err
+			}
+
+			if r.hooks.AfterSave != nil {
+				return r.hooks.AfterSave(tx, &entity)
+			}
+
+			return nil
+		},
+	)
+	if err != nil {
+		return uuid.Nil, translateError(err)
+	}
+
+	return PT(&entity).PrimaryKey(), nil
+}
+
+func (r repository[T, PT]) Get(ctx context.Context, spec query.Spec) (res T, err error) {
+	err = spec.Apply(r.read(ctx)).First(&res).Error
+	if err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (r repository[T, PT]) List(ctx context.Context, spec query.Spec) (res []T, err error) {
+	err = spec.Apply(r.read(ctx)).Find(&res).Error
+	if err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (r repository[T, PT]) Count(ctx context.Context, spec query.Spec) (count int64, err error) {
+	err = spec.ApplyFilter(r.conn.WithContext(ctx)).Model(PT(new(T))).Count(&count).Error
+	if err != nil {
+		return count, err
+	}
+
+	return count, nil
+}
+
+func (r repository[T, PT]) Fetch(ctx context.Context, spec query.Spec) (res []T, count int64, err error) {
+	res, err = r.List(ctx, spec)
+	if err != nil {
+		return res, count, err
+	}
+
+	count, err = r.Count(ctx, spec)
+	if err != nil {
+		return res, count, err
+	}
+
+	return res, count, nil
+}
+
+func (r repository[T, PT]) Delete(ctx context.Context, entity *T) error {
+	err := r.conn.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			if r.hooks.BeforeDelete != nil {
+				if err := r.hooks.BeforeDelete(tx, entity); err != nil {
+					return err
+				}
+			}
+
+			if err := tx.Delete(entity).Error; err != nil {
+				return err
+			}
+
+			if r.hooks.AfterDelete != nil {
+				return r.hooks.AfterDelete(tx, entity)
+			}
+
+			return nil
+		},
+	)
+
+	return translateError(err)
+}
+
+// Restore undoes the soft delete of the row with the given id, gorm.ErrRecordNotFound is returned when no deleted
+// row has it.
+func (r repository[T, PT]) Restore(ctx context.Context, id uuid.UUID) error {
+	err := r.conn.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			var entity T
+			err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("id = ? AND deleted_at IS NOT NULL", id).First(&entity).Error
+			if err != nil {
+				return err
+			}
+
+			if r.hooks.BeforeRestore != nil {
+				if err := r.hooks.BeforeRestore(tx, &entity); err != nil {
+					return err
+				}
+			}
+
+			err = tx.Unscoped().Model(PT(new(T))).Where("id = ?", id).
+				Updates(map[string]interface{}{"deleted_at": nil, "modified_at": time.Now()}).Error
+			if err != nil {
+				return err
+			}
+
+			if r.hooks.AfterRestore != nil {
+				return r.hooks.AfterRestore(tx, &entity)
+			}
+
+			return nil
+		},
+	)
+
+	return translateError(err)
+}
+
+func (r repository[T, PT]) read(ctx context.Context) *gorm.DB {
+	db := r.conn.WithContext(ctx)
+	if r.hooks.Query != nil {
+		db = r.hooks.Query(db)
+	}
+
+	return db
+}
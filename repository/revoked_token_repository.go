@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type RevokedTokenRepository interface {
+	Save(ctx context.Context, token model.RevokedToken) error
+	IsRevoked(ctx context.Context, tokenId string) (bool, error)
+	DeleteExpired(ctx context.Context) error
+}
+
+type revokedTokenRepository struct {
+	conn *gorm.DB
+}
+
+func NewRevokedTokenRepository(conn *gorm.DB) RevokedTokenRepository {
+	return &revokedTokenRepository{conn: conn}
+}
+
+func (r revokedTokenRepository) Save(ctx context.Context, token model.RevokedToken) error {
+	return r.conn.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&token).Error
+}
+
+func (r revokedTokenRepository) IsRevoked(ctx context.Context, tokenId string) (bool, error) {
+	var count int64
+	err := r.conn.WithContext(ctx).Model(&model.RevokedToken{}).Where("id = ?", tokenId).Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// DeleteExpired forgets revoked tokens that already expired, they are rejected by their exp claim anyway.
+func (r revokedTokenRepository) DeleteExpired(ctx context.Context) error {
+	return r.conn.WithContext(ctx).Unscoped().Where("expires_at < ?", time.Now()).Delete(&model.RevokedToken{}).Error
+}
@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"sort"
+	"time"
+)
+
+var ErrStockCountClosed = errors.New("the stock count is closed")
+
+type StockCountRepository interface {
+	Repository[model.StockCount]
+	// Start stores the count together with the lines it snapshots.
+	Start(ctx context.Context, count model.StockCount) (uuid.UUID, error)
+	// Record sets the counted quantities of lines of an open count, a line counted again keeps the latest count.
+	Record(ctx context.Context, countId uuid.UUID, lines []model.StockCountLine) error
+	// Approve posts an adjustment for the variance of every counted line and closes the count. A line's variance is
+	// taken against the stock it had when it was counted, the snapshot plus what moved from the start of the count
+	// until then. ErrInsufficientStock is returned when an adjustment would take the stock below zero.
+	Approve(ctx context.Context, countId uuid.UUID, userId uuid.UUID) error
+	Cancel(ctx context.Context, countId uuid.UUID) error
+}
+
+type stockCountRepository struct {
+	Repository[model.StockCount]
+	conn *gorm.DB
+}
+
+// NewStockCountRepository reads counts with their lines. Writes to a count lock it first and fail with
+// ErrStockCountClosed once it is approved or cancelled.
+func NewStockCountRepository(conn *gorm.DB) StockCountRepository {
+	return &stockCountRepository{
+		Repository: NewRepository[model.StockCount](
+			conn, Hooks[model.StockCount]{
+				Query: func(db *gorm.DB) *gorm.DB {
+					return db.Preload(
+						"Lines", func(db *gorm.DB) *gorm.DB {
+							return db.Order("name, variant_name")
+						},
+					)
+				},
+			},
+		),
+		conn: conn,
+	}
+}
+
+func (s stockCountRepository) Start(ctx context.Context, count model.StockCount) (uuid.UUID, error) {
+	err := s.conn.WithContext(ctx).Create(&count).Error
+	if err != nil {
+		return uuid.Nil, translateError(err)
+	}
+
+	return count.ID, nil
+}
+
+func (s stockCountRepository) Record(ctx context.Context, countId uuid.UUID, lines []model.StockCountLine) error {
+	err := s.conn.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			_, err := lockOpenCount(tx, countId)
+			if err != nil {
+				return err
+			}
+
+			for _, line := range lines {
+				err = tx.Model(&model.StockCountLine{}).Where("id = ? AND count_id = ?", line.ID, countId).Updates(
+					map[string]interface{}{
+						"counted": line.Counted, "counted_by": line.CountedBy, "counted_at": line.CountedAt,
+						"modified_at": time.Now(),
+					},
+				).Error
+				if err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+	)
+
+	return translateError(err)
+}
+
+func (s stockCountRepository) Approve(ctx context.Context, countId uuid.UUID, userId uuid.UUID) error {
+	err := s.conn.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			count, err := lockOpenCount(tx, countId)
+			if err != nil {
+				return err
+			}
+
+			var lines []model.StockCountLine
+			err = tx.Where("count_id = ? AND counted IS NOT NULL", countId).Find(&lines).Error
+			if err != nil {
+				return err
+			}
+
+			// in id order like checkouts, so an approval and a checkout can't deadlock
+			sort.Slice(
+				lines, func(i, j int) bool {
+					return stockKey(lines[i].ProductID, lines[i].VariantID) <
+						stockKey(lines[j].ProductID, lines[j].VariantID)
+				},
+			)
+			for _, line := range lines {
+				moved, err := movedBetween(tx, count.OutletID, line.ProductID, line.VariantID, count.CreatedAt.Time,
+					line.CountedAt.Time)
+				if err != nil {
+					return err
+				}
+
+				// sales and other movements since the snapshot were counted with the rest of the stock
+				variance := *line.Counted - (line.Expected + moved)
+				if variance == 0 {
+					continue
+				}
+
+				err = postMovement(
+					tx, &model.StockMovement{
+						OutletID:     count.OutletID,
+						ProductID:    line.ProductID,
+						VariantID:    line.VariantID,
+						Type:         model.MovementAdjustment,
+						Reason:       model.StockCountCorrection,
+						Quantity:     variance,
+						StockCountID: &count.ID,
+						UserID:       &userId,
+					},
+				)
+				if err != nil {
+					return err
+				}
+			}
+
+			return tx.Model(&model.StockCount{}).Where("id = ?", countId).Updates(
+				map[string]interface{}{
+					"status": model.StockCountApproved, "approved_by": userId,
+					"approved_at": sql.NullTime{Time: time.Now(), Valid: true}, "modified_at": time.Now(),
+				},
+			).Error
+		},
+	)
+
+	return translateError(err)
+}
+
+func (s stockCountRepository) Cancel(ctx context.Context, countId uuid.UUID) error {
+	err := s.conn.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			_, err := lockOpenCount(tx, countId)
+			if err != nil {
+				return err
+			}
+
+			return tx.Model(&model.StockCount{}).Where("id = ?", countId).Updates(
+				map[string]interface{}{"status": model.StockCountCancelled, "modified_at": time.Now()},
+			).Error
+		},
+	)
+
+	return translateError(err)
+}
+
+// lockOpenCount locks the count for the rest of the transaction, so it can't be closed while it is written.
+func lockOpenCount(tx *gorm.DB, countId uuid.UUID) (model.StockCount, error) {
+	var count model.StockCount
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", countId).First(&count).Error
+	if err != nil {
+		return count, err
+	}
+
+	if count.Status != model.StockCountOpen {
+		return count, ErrStockCountClosed
+	}
+
+	return count, nil
+}
+
+// movedBetween sums the movements of a product, or of a variant of it, in the outlet after from and up to to.
+func movedBetween(
+	tx *gorm.DB, outletId uuid.UUID, productId uuid.UUID, variantId *uuid.UUID, from time.Time, to time.Time,
+) (int64, error) {
+	db := tx.Model(&model.StockMovement{}).
+		Where("outlet_id = ? AND product_id = ? AND created_at > ? AND created_at <= ?", outletId, productId, from, to)
+	if variantId != nil {
+		db = db.Where("variant_id = ?", *variantId)
+	} else {
+		db = db.Where("variant_id IS NULL")
+	}
+
+	var moved int64
+	err := db.Select("COALESCE(SUM(quantity), 0)").Scan(&moved).Error
+
+	return moved, err
+}
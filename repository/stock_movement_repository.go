@@ -0,0 +1,294 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"github.com/google/uuid"
+	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"github.com/rehandwi03/test-case-backend-majoo/money"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type StockMovementRepository interface {
+	Repository[model.StockMovement]
+	// Post applies the movement to the stock of its product or variant and appends it to the ledger with the balance
+	// it leaves, ErrInsufficientStock is returned when it would take the stock below zero.
+	Post(ctx context.Context, movement model.StockMovement) (uuid.UUID, error)
+}
+
+type stockMovementRepository struct {
+	Repository[model.StockMovement]
+	conn *gorm.DB
+}
+
+// NewStockMovementRepository keeps the ledger append-only: movements are only ever added by Post, Checkout and the
+// opening balance of new products and variants, the stock cached on them is never written otherwise.
+func NewStockMovementRepository(conn *gorm.DB) StockMovementRepository {
+	return &stockMovementRepository{
+		Repository: NewRepository[model.StockMovement](conn, Hooks[model.StockMovement]{}),
+		conn:       conn,
+	}
+}
+
+func (s stockMovementRepository) Post(ctx context.Context, movement model.StockMovement) (uuid.UUID, error) {
+	err := s.conn.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			return postMovement(tx, &movement)
+		},
+	)
+	if err != nil {
+		return uuid.Nil, translateError(err)
+	}
+
+	return movement.ID, nil
+}
+
+// postMovement changes the stock of the product, or of the variant, by the quantity of the movement unless that takes
+// it below zero, then records the movement with the stock left and what it was worth. The update locks the row until
+// the transaction ends, so the balance and the average cost read back are the ones this movement left.
+func postMovement(tx *gorm.DB, movement *model.StockMovement) error {
+	return post(tx, movement, true)
+}
+
+// postWrittenOff posts a movement of units brought in and thrown away again straight away, like the ones a refund
+// writes off, at the unit cost it carries. The lots and the average cost are left alone, the units never join the
+// stock they are valued with.
+func postWrittenOff(tx *gorm.DB, movement *model.StockMovement) error {
+	return post(tx, movement, false)
+}
+
+// post changes the stock by the movement and records it, valuing it by the stock when valued is set.
+func post(tx *gorm.DB, movement *model.StockMovement, valued bool) error {
+	stocked := func() *gorm.DB {
+		if movement.VariantID != nil {
+			return tx.Model(&model.ProductVariant{}).
+				Where("id = ? AND product_id = ?", *movement.VariantID, movement.ProductID)
+		}
+
+		return tx.Model(&model.Product{}).Where("id = ? AND outlet_id = ?", movement.ProductID, movement.OutletID)
+	}
+
+	result := stocked().Where("stock + ? >= 0", movement.Quantity).
+		Update("stock", gorm.Expr("stock + ?", movement.Quantity))
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrInsufficientStock
+	}
+
+	var averageCost int64
+	err := stocked().Select("stock, average_cost_amount").Row().Scan(&movement.Balance, &averageCost)
+	if err != nil {
+		return err
+	}
+
+	var costingMethod, currency string
+	err = tx.Table("outlets").Joins("JOIN merchants ON merchants.id = outlets.merchant_id").
+		Where("outlets.id = ?", movement.OutletID).Select("merchants.costing_method, merchants.currency").Row().
+		Scan(&costingMethod, &currency)
+	if err != nil {
+		return err
+	}
+
+	if !valued {
+		quantity := movement.Quantity
+		if quantity < 0 {
+			quantity = -quantity
+		}
+		movement.UnitCost = money.New(movement.UnitCost.Amount, currency)
+		movement.Cost = movement.UnitCost.Mul(quantity)
+
+		return tx.Create(movement).Error
+	}
+
+	switch {
+	case movement.Quantity > 0:
+		// stock brought in without a cost of its own, like a count finding more than expected, is worth the average
+		unitCost := averageCost
+		if movement.UnitCost.IsSet() {
+			unitCost = movement.UnitCost.Amount
+		}
+		movement.UnitCost = money.New(unitCost, currency)
+		movement.Cost = movement.UnitCost.Mul(movement.Quantity)
+
+		before := movement.Balance - movement.Quantity
+		if before > 0 {
+			averageCost = divideRounded(before*averageCost+movement.Quantity*unitCost, movement.Balance)
+		} else {
+			averageCost = unitCost
+		}
+
+		err = stocked().Updates(map[string]interface{}{
+			"average_cost_amount": averageCost, "average_cost_currency": currency,
+		}).Error
+		if err != nil {
+			return err
+		}
+	case movement.Quantity < 0:
+		// the lots are used up whatever the costing method, so the stock is valued both ways and the merchant can
+		// switch between them
+		fifoCost, err := consumeCostLayers(tx, movement, -movement.Quantity, averageCost)
+		if err != nil {
+			return err
+		}
+
+		cost := averageCost * -movement.Quantity
+		if costingMethod == model.CostingFIFO {
+			cost = fifoCost
+		}
+		movement.Cost = money.New(cost, currency)
+		movement.UnitCost = money.New(divideRounded(cost, -movement.Quantity), currency)
+	}
+
+	err = tx.Create(movement).Error
+	if err != nil {
+		return err
+	}
+
+	return addCostLayer(tx, movement)
+}
+
+// consumeCostLayers takes quantity units out of the oldest lots of the product or variant and returns what they cost.
+// Units no lot holds anymore, like those of stock taken below what was ever brought in, cost fallback each.
+func consumeCostLayers(tx *gorm.DB, movement *model.StockMovement, quantity int64, fallback int64) (int64, error) {
+	lots := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("product_id = ? AND remaining > 0", movement.ProductID)
+	if movement.VariantID != nil {
+		lots = lots.Where("variant_id = ?", *movement.VariantID)
+	} else {
+		lots = lots.Where("variant_id IS NULL")
+	}
+
+	var layers []model.StockCostLayer
+	err := lots.Order("created_at, id").Find(&layers).Error
+	if err != nil {
+		return 0, err
+	}
+
+	taken, cost := drawLayers(layers, quantity, fallback)
+	for i, layer := range layers {
+		if taken[i] == 0 {
+			continue
+		}
+
+		err = tx.Model(&model.StockCostLayer{}).Where("id = ?", layer.ID).
+			Update("remaining", layer.Remaining-taken[i]).Error
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return cost, nil
+}
+
+// drawLayers takes quantity units out of the lots in the order given, returning how many come out of each lot and
+// what all of them cost. Units the lots don't hold cost fallback each.
+func drawLayers(layers []model.StockCostLayer, quantity int64, fallback int64) ([]int64, int64) {
+	taken := make([]int64, len(layers))
+	var cost int64
+	for i, layer := range layers {
+		if quantity == 0 {
+			break
+		}
+
+		taken[i] = layer.Remaining
+		if taken[i] > quantity {
+			taken[i] = quantity
+		}
+
+		cost += taken[i] * layer.UnitCost.Amount
+		quantity -= taken[i]
+	}
+
+	return taken, cost + quantity*fallback
+}
+
+// addCostLayer opens a lot with the units a movement brought in, at the unit cost it was posted with.
+func addCostLayer(tx *gorm.DB, movement *model.StockMovement) error {
+	if movement.Quantity <= 0 {
+		return nil
+	}
+
+	return tx.Create(
+		&model.StockCostLayer{
+			OutletID:        movement.OutletID,
+			ProductID:       movement.ProductID,
+			VariantID:       movement.VariantID,
+			StockMovementID: &movement.ID,
+			UnitCost:        movement.UnitCost,
+			Quantity:        movement.Quantity,
+			Remaining:       movement.Quantity,
+		},
+	).Error
+}
+
+// divideRounded divides non-negative amounts, rounding half up.
+func divideRounded(amount int64, divisor int64) int64 {
+	return (amount + divisor/2) / divisor
+}
+
+// keepStock reads the stock, the cost price and the average cost a product or variant has into them before it is
+// saved, so saving it never overwrites what the ledger and the purchases keep. The row stays locked until the save is
+// done.
+func keepStock(
+	tx *gorm.DB, entity interface{}, id uuid.UUID, stock *int64, costPrice *money.Money, averageCost *money.Money,
+) error {
+	if id == uuid.Nil {
+		return nil
+	}
+
+	err := tx.Model(entity).Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).
+		Select("stock, cost_price_amount, cost_price_currency, average_cost_amount, average_cost_currency").Row().
+		Scan(stock, &costPrice.Amount, &costPrice.Currency, &averageCost.Amount, &averageCost.Currency)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+
+	return err
+}
+
+// openStock records the stock a product or variant is created with as the opening balance of its ledger.
+func openStock(tx *gorm.DB, productId uuid.UUID, variantId *uuid.UUID, stock int64) error {
+	if stock == 0 {
+		return nil
+	}
+
+	ledger := tx.Model(&model.StockMovement{}).Where("product_id = ?", productId)
+	if variantId != nil {
+		ledger = ledger.Where("variant_id = ?", *variantId)
+	} else {
+		ledger = ledger.Where("variant_id IS NULL")
+	}
+
+	var recorded int64
+	err := ledger.Count(&recorded).Error
+	if err != nil || recorded > 0 {
+		return err
+	}
+
+	var outletId uuid.UUID
+	err = tx.Model(&model.Product{}).Where("id = ?", productId).Select("outlet_id").Row().Scan(&outletId)
+	if err != nil {
+		return err
+	}
+
+	// nothing was paid for the opening stock as far as the ledger knows, it is the first lot at no cost until bought
+	movement := model.StockMovement{
+		OutletID:  outletId,
+		ProductID: productId,
+		VariantID: variantId,
+		Type:      model.MovementAdjustment,
+		Reason:    model.StockOpeningBalance,
+		Quantity:  stock,
+		Balance:   stock,
+	}
+	err = tx.Create(&movement).Error
+	if err != nil {
+		return err
+	}
+
+	return addCostLayer(tx, &movement)
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"github.com/rehandwi03/test-case-backend-majoo/money"
+	"reflect"
+	"testing"
+)
+
+func TestDrawLayers(t *testing.T) {
+	layer := func(remaining int64, unitCost int64) model.StockCostLayer {
+		return model.StockCostLayer{Remaining: remaining, UnitCost: money.New(unitCost, "IDR")}
+	}
+
+	tests := []struct {
+		name      string
+		layers    []model.StockCostLayer
+		quantity  int64
+		fallback  int64
+		wantTaken []int64
+		wantCost  int64
+	}{
+		{
+			name: "out of the oldest lot", layers: []model.StockCostLayer{layer(10, 500), layer(10, 700)}, quantity: 4,
+			wantTaken: []int64{4, 0}, wantCost: 2000,
+		},
+		{
+			name: "across lots", layers: []model.StockCostLayer{layer(3, 500), layer(10, 700)}, quantity: 5,
+			wantTaken: []int64{3, 2}, wantCost: 2900,
+		},
+		{
+			name: "beyond the lots at the fallback", layers: []model.StockCostLayer{layer(2, 500)}, quantity: 5,
+			fallback: 600, wantTaken: []int64{2}, wantCost: 2800,
+		},
+		{name: "without lots", quantity: 3, fallback: 600, wantTaken: []int64{}, wantCost: 1800},
+		{
+			name: "nothing taken", layers: []model.StockCostLayer{layer(2, 500)}, quantity: 0,
+			wantTaken: []int64{0}, wantCost: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				taken, cost := drawLayers(tt.layers, tt.quantity, tt.fallback)
+				if !reflect.DeepEqual(taken, tt.wantTaken) {
+					t.Errorf("taken = %v, want %v", taken, tt.wantTaken)
+				}
+				if cost != tt.wantCost {
+					t.Errorf("cost = %d, want %d", cost, tt.wantCost)
+				}
+			},
+		)
+	}
+}
+
+func TestDivideRounded(t *testing.T) {
+	tests := []struct {
+		amount  int64
+		divisor int64
+		want    int64
+	}{
+		{amount: 10, divisor: 5, want: 2},
+		{amount: 0, divisor: 3, want: 0},
+		{amount: 10, divisor: 3, want: 3},
+		{amount: 11, divisor: 3, want: 4},
+		{amount: 5, divisor: 2, want: 3},
+		{amount: 7, divisor: 4, want: 2},
+	}
+
+	for _, tt := range tests {
+		if got := divideRounded(tt.amount, tt.divisor); got != tt.want {
+			t.Errorf("divideRounded(%d, %d) = %d, want %d", tt.amount, tt.divisor, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,310 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"github.com/rehandwi03/test-case-backend-majoo/money"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"sort"
+	"time"
+)
+
+var (
+	ErrStockTransferStatus = errors.New("the stock transfer can't be changed in its status")
+	ErrOverReceived        = errors.New("more would be received than was sent")
+)
+
+type StockTransferRepository interface {
+	Repository[model.StockTransfer]
+	// Create stores the draft together with its lines.
+	Create(ctx context.Context, transfer model.StockTransfer) (uuid.UUID, error)
+	// Send takes the stock of every line out of the source outlet, ErrInsufficientStock is returned when it doesn't
+	// have enough.
+	Send(ctx context.Context, transferId uuid.UUID, userId uuid.UUID) error
+	// Receive adds the received quantities, by line id, to the stock of the destination outlet. The transfer is
+	// received once every line is, ErrOverReceived is returned for a line receiving more than was sent.
+	Receive(ctx context.Context, transferId uuid.UUID, userId uuid.UUID, received map[uuid.UUID]int64) error
+	// Close settles what is still outstanding on the lines of a sent transfer, putting it back into the stock of the
+	// source outlet, or writing it off as lost when returnToSource isn't set.
+	Close(ctx context.Context, transferId uuid.UUID, userId uuid.UUID, returnToSource bool) error
+	Cancel(ctx context.Context, transferId uuid.UUID) error
+}
+
+type stockTransferRepository struct {
+	Repository[model.StockTransfer]
+	conn *gorm.DB
+}
+
+// NewStockTransferRepository reads transfers with their lines. Writes to a transfer lock it first and fail with
+// ErrStockTransferStatus when it isn't in a status allowing them.
+func NewStockTransferRepository(conn *gorm.DB) StockTransferRepository {
+	return &stockTransferRepository{
+		Repository: NewRepository[model.StockTransfer](
+			conn, Hooks[model.StockTransfer]{
+				Query: func(db *gorm.DB) *gorm.DB {
+					return db.Preload(
+						"Lines", func(db *gorm.DB) *gorm.DB {
+							return db.Order("name, variant_name")
+						},
+					)
+				},
+			},
+		),
+		conn: conn,
+	}
+}
+
+func (s stockTransferRepository) Create(ctx context.Context, transfer model.StockTransfer) (uuid.UUID, error) {
+	err := s.conn.WithContext(ctx).Create(&transfer).Error
+	if err != nil {
+		return uuid.Nil, translateError(err)
+	}
+
+	return transfer.ID, nil
+}
+
+func (s stockTransferRepository) Send(ctx context.Context, transferId uuid.UUID, userId uuid.UUID) error {
+	err := s.conn.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			transfer, err := lockTransfer(tx, transferId, model.StockTransferDraft)
+			if err != nil {
+				return err
+			}
+
+			lines := transfer.Lines
+			sort.Slice(
+				lines, func(i, j int) bool {
+					return stockKey(lines[i].ProductID, lines[i].VariantID) <
+						stockKey(lines[j].ProductID, lines[j].VariantID)
+				},
+			)
+			for _, line := range lines {
+				movement := model.StockMovement{
+					OutletID:   transfer.SourceOutletID,
+					ProductID:  line.ProductID,
+					VariantID:  line.VariantID,
+					Type:       model.MovementTransfer,
+					Quantity:   -line.Quantity,
+					TransferID: &transfer.ID,
+					UserID:     &userId,
+				}
+				err = postMovement(tx, &movement)
+				if err != nil {
+					return err
+				}
+
+				// the stock moves at what it cost the source outlet, the destination receives it at that cost
+				err = tx.Model(&model.StockTransferLine{}).Where("id = ?", line.ID).Updates(
+					map[string]interface{}{
+						"unit_cost_amount": movement.UnitCost.Amount, "unit_cost_currency": movement.UnitCost.Currency,
+					},
+				).Error
+				if err != nil {
+					return err
+				}
+			}
+
+			return tx.Model(&model.StockTransfer{}).Where("id = ?", transferId).Updates(
+				map[string]interface{}{
+					"status": model.StockTransferSent, "sent_by": userId,
+					"sent_at": sql.NullTime{Time: time.Now(), Valid: true}, "modified_at": time.Now(),
+				},
+			).Error
+		},
+	)
+
+	return translateError(err)
+}
+
+func (s stockTransferRepository) Receive(
+	ctx context.Context, transferId uuid.UUID, userId uuid.UUID, received map[uuid.UUID]int64,
+) error {
+	err := s.conn.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			transfer, err := lockTransfer(
+				tx, transferId, model.StockTransferSent, model.StockTransferPartiallyReceived,
+			)
+			if err != nil {
+				return err
+			}
+
+			lines := transfer.Lines
+			sort.Slice(
+				lines, func(i, j int) bool {
+					return stockKey(lines[i].DestinationProductID, lines[i].DestinationVariantID) <
+						stockKey(lines[j].DestinationProductID, lines[j].DestinationVariantID)
+				},
+			)
+			for _, line := range lines {
+				quantity := received[line.ID]
+				if quantity == 0 {
+					continue
+				}
+
+				result := tx.Model(&model.StockTransferLine{}).
+					Where("id = ? AND received + ? <= quantity", line.ID, quantity).
+					Updates(
+						map[string]interface{}{
+							"received": gorm.Expr("received + ?", quantity), "modified_at": time.Now(),
+						},
+					)
+				if result.Error != nil {
+					return result.Error
+				}
+
+				if result.RowsAffected == 0 {
+					return ErrOverReceived
+				}
+
+				err = postMovement(
+					tx, &model.StockMovement{
+						OutletID:   transfer.DestinationOutletID,
+						ProductID:  line.DestinationProductID,
+						VariantID:  line.DestinationVariantID,
+						Type:       model.MovementTransfer,
+						Quantity:   quantity,
+						UnitCost:   line.UnitCost,
+						TransferID: &transfer.ID,
+						UserID:     &userId,
+					},
+				)
+				if err != nil {
+					return err
+				}
+			}
+
+			var outstanding int64
+			err = tx.Model(&model.StockTransferLine{}).Where("transfer_id = ? AND received < quantity", transferId).
+				Count(&outstanding).Error
+			if err != nil {
+				return err
+			}
+
+			status := model.StockTransferReceived
+			if outstanding > 0 {
+				status = model.StockTransferPartiallyReceived
+			}
+
+			return tx.Model(&model.StockTransfer{}).Where("id = ?", transferId).
+				Updates(map[string]interface{}{"status": status, "modified_at": time.Now()}).Error
+		},
+	)
+
+	return translateError(err)
+}
+
+// Close posts a transfer movement bringing what is outstanding back into the source outlet, at what it cost when it
+// was sent. Written off, it is thrown away again as waste, the way refunds write units off, so the loss shows in the
+// ledger of the outlet it was sent from.
+func (s stockTransferRepository) Close(
+	ctx context.Context, transferId uuid.UUID, userId uuid.UUID, returnToSource bool,
+) error {
+	err := s.conn.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			transfer, err := lockTransfer(
+				tx, transferId, model.StockTransferSent, model.StockTransferPartiallyReceived,
+			)
+			if err != nil {
+				return err
+			}
+
+			lines := transfer.Lines
+			sort.Slice(
+				lines, func(i, j int) bool {
+					return stockKey(lines[i].ProductID, lines[i].VariantID) <
+						stockKey(lines[j].ProductID, lines[j].VariantID)
+				},
+			)
+			for _, line := range lines {
+				outstanding := line.Quantity - line.Received - line.Returned - line.Lost
+				if outstanding == 0 {
+					continue
+				}
+
+				returned := model.StockMovement{
+					OutletID:   transfer.SourceOutletID,
+					ProductID:  line.ProductID,
+					VariantID:  line.VariantID,
+					Type:       model.MovementTransfer,
+					Quantity:   outstanding,
+					UnitCost:   line.UnitCost,
+					TransferID: &transfer.ID,
+					UserID:     &userId,
+				}
+				err = postMovement(tx, &returned)
+				if err != nil {
+					return err
+				}
+
+				column := "returned"
+				if !returnToSource {
+					column = "lost"
+					lost := returned
+					lost.Type = model.MovementWaste
+					lost.Reason = model.StockLost
+					lost.Note = "lost in transfer"
+					lost.Quantity = -outstanding
+					lost.UnitCost = money.Money{}
+					err = postMovement(tx, &lost)
+					if err != nil {
+						return err
+					}
+				}
+
+				err = tx.Model(&model.StockTransferLine{}).Where("id = ?", line.ID).
+					Updates(map[string]interface{}{column: outstanding, "modified_at": time.Now()}).Error
+				if err != nil {
+					return err
+				}
+			}
+
+			return tx.Model(&model.StockTransfer{}).Where("id = ?", transferId).
+				Updates(map[string]interface{}{"status": model.StockTransferClosed, "modified_at": time.Now()}).Error
+		},
+	)
+
+	return translateError(err)
+}
+
+func (s stockTransferRepository) Cancel(ctx context.Context, transferId uuid.UUID) error {
+	err := s.conn.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			_, err := lockTransfer(tx, transferId, model.StockTransferDraft)
+			if err != nil {
+				return err
+			}
+
+			return tx.Model(&model.StockTransfer{}).Where("id = ?", transferId).
+				Updates(map[string]interface{}{"status": model.StockTransferCancelled, "modified_at": time.Now()}).Error
+		},
+	)
+
+	return translateError(err)
+}
+
+// lockTransfer locks the transfer for the rest of the transaction and reads it with its lines, it has to be in one of
+// the statuses.
+func lockTransfer(tx *gorm.DB, transferId uuid.UUID, statuses ...string) (model.StockTransfer, error) {
+	var transfer model.StockTransfer
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", transferId).First(&transfer).Error
+	if err != nil {
+		return transfer, err
+	}
+
+	err = tx.Where("transfer_id = ?", transferId).Find(&transfer.Lines).Error
+	if err != nil {
+		return transfer, err
+	}
+
+	for _, status := range statuses {
+		if transfer.Status == status {
+			return transfer, nil
+		}
+	}
+
+	return transfer, ErrStockTransferStatus
+}
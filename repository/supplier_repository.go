@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"gorm.io/gorm"
+)
+
+type SupplierRepository interface {
+	Repository[model.Supplier]
+}
+
+// NewSupplierRepository keeps a supplier of a deleted merchant from being restored on its own.
+func NewSupplierRepository(conn *gorm.DB) SupplierRepository {
+	return NewRepository[model.Supplier](
+		conn, Hooks[model.Supplier]{
+			BeforeRestore: func(tx *gorm.DB, supplier *model.Supplier) error {
+				return requireLive(
+					tx, &model.Merchant{}, supplier.MerchantID, "merchant_id", "the merchant of the supplier is deleted",
+				)
+			},
+		},
+	)
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"gorm.io/gorm"
+)
+
+type TableRepository interface {
+	Repository[model.Table]
+}
+
+func NewTableRepository(conn *gorm.DB) TableRepository {
+	return NewRepository[model.Table](conn, Hooks[model.Table]{})
+}
+
+func orderTables(db *gorm.DB) *gorm.DB {
+	return db.Order("sort_order, name")
+}
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type TransactionPaymentRepository interface {
+	Repository[model.TransactionPayment]
+	// SetStatus moves the payment from one status to another, it returns false when the payment isn't in the status
+	// anymore, like when a concurrent callback moved it first. The sale of the payment follows it: what it paid is
+	// added to, or taken off, what the sale was paid, and a sale that isn't refunded or voided is pending while one
+	// of its payments is, unpaid once one fails and completed otherwise.
+	SetStatus(ctx context.Context, id uuid.UUID, from string, to string) (bool, error)
+	// PendingRefunds returns the refund payments created before the time still pending at their payment provider,
+	// the oldest first.
+	PendingRefunds(ctx context.Context, before time.Time) ([]model.RefundPayment, error)
+	// SettleRefund moves a pending refund payment to the status given, it returns false when the refund payment
+	// isn't pending anymore.
+	SettleRefund(ctx context.Context, id uuid.UUID, status string) (bool, error)
+}
+
+type transactionPaymentRepository struct {
+	Repository[model.TransactionPayment]
+	conn *gorm.DB
+}
+
+func NewTransactionPaymentRepository(conn *gorm.DB) TransactionPaymentRepository {
+	return &transactionPaymentRepository{
+		Repository: NewRepository[model.TransactionPayment](conn, Hooks[model.TransactionPayment]{}),
+		conn:       conn,
+	}
+}
+
+// SetStatus locks the sale before the payment, in the order refunds lock them, so the two can't deadlock.
+func (t transactionPaymentRepository) SetStatus(ctx context.Context, id uuid.UUID, from string, to string) (
+	bool, error,
+) {
+	var moved bool
+	err := t.conn.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			var payment model.TransactionPayment
+			err := tx.Where("id = ?", id).First(&payment).Error
+			if err != nil {
+				return err
+			}
+
+			var transaction model.Transaction
+			err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", payment.TransactionID).
+				First(&transaction).Error
+			if err != nil {
+				return err
+			}
+
+			result := tx.Model(&model.TransactionPayment{}).
+				Where("id = ? AND status = ?", id, from).
+				Updates(map[string]interface{}{"status": to, "modified_at": time.Now()})
+			if result.Error != nil {
+				return result.Error
+			}
+
+			moved = result.RowsAffected > 0
+			if !moved {
+				return nil
+			}
+
+			paidBefore := payment.IsPaid()
+			payment.Status = to
+
+			return settleTransaction(tx, transaction, payment, paidBefore)
+		},
+	)
+	if err != nil {
+		return false, err
+	}
+
+	return moved, nil
+}
+
+func (t transactionPaymentRepository) PendingRefunds(ctx context.Context, before time.Time) (
+	[]model.RefundPayment, error,
+) {
+	var refundPayments []model.RefundPayment
+	err := t.conn.WithContext(ctx).Where("status = ? AND created_at < ?", model.RefundPaymentPending, before).
+		Order("created_at").Find(&refundPayments).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return refundPayments, nil
+}
+
+func (t transactionPaymentRepository) SettleRefund(ctx context.Context, id uuid.UUID, status string) (bool, error) {
+	result := t.conn.WithContext(ctx).Model(&model.RefundPayment{}).
+		Where("id = ? AND status = ?", id, model.RefundPaymentPending).
+		Updates(map[string]interface{}{"status": status, "modified_at": time.Now()})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// settleTransaction brings the sale up with the payment that moved, which paid for it before or not.
+func settleTransaction(
+	tx *gorm.DB, transaction model.Transaction, payment model.TransactionPayment, paidBefore bool,
+) error {
+	columns := map[string]interface{}{}
+	switch {
+	case !paidBefore && payment.IsPaid():
+		columns["paid_amount"] = gorm.Expr("paid_amount + ?", payment.Amount.Amount)
+	case paidBefore && !payment.IsPaid():
+		columns["paid_amount"] = gorm.Expr("paid_amount - ?", payment.Amount.Amount)
+	}
+
+	switch transaction.Status {
+	case model.TransactionPending, model.TransactionCompleted, model.TransactionUnpaid:
+		var payments []model.TransactionPayment
+		err := tx.Where("transaction_id = ?", transaction.ID).Find(&payments).Error
+		if err != nil {
+			return err
+		}
+
+		status := model.TransactionCompleted
+		for _, other := range payments {
+			switch other.Status {
+			case model.PaymentFailed:
+				status = model.TransactionUnpaid
+			case model.PaymentPending:
+				if status == model.TransactionCompleted {
+					status = model.TransactionPending
+				}
+			}
+		}
+		columns["status"] = status
+	}
+
+	if len(columns) == 0 {
+		return nil
+	}
+	columns["modified_at"] = time.Now()
+
+	return tx.Model(&model.Transaction{}).Where("id = ?", transaction.ID).Updates(columns).Error
+}
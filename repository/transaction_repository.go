@@ -0,0 +1,513 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"github.com/rehandwi03/test-case-backend-majoo/money"
+	"github.com/rehandwi03/test-case-backend-majoo/query"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"sort"
+	"time"
+)
+
+var (
+	ErrInsufficientStock = errors.New("insufficient stock")
+	ErrTransactionVoided = errors.New("the transaction is voided")
+	// ErrAlreadyRefunded is a void of a transaction items of which were refunded already.
+	ErrAlreadyRefunded = errors.New("items of the transaction were refunded already, refund the rest instead")
+	ErrNothingToReturn = errors.New("more units are returned than are left of the item")
+	// ErrTransactionUnpaid is a refund of items of a sale which is pending or unpaid, only a void can undo it.
+	ErrTransactionUnpaid = errors.New("the transaction isn't paid for, wait for its payments or void it")
+	// ErrRefundExceedsPayment is a refund giving back more by a payment than it can, see Transaction.Refundable.
+	ErrRefundExceedsPayment = errors.New("a payment gives back more than it paid")
+	// ErrSaleUntraced is a restock of an item whose sale movements aren't known, like the ones of a bundle sold
+	// before sales were traced to their items.
+	ErrSaleUntraced = errors.New("the stock the item was sold out of isn't known, write it off instead")
+)
+
+type TransactionRepository interface {
+	Repository[model.Transaction]
+	// Checkout stores the sale, closing the order it was checked out from when there is one, as it was read.
+	Checkout(ctx context.Context, transaction model.Transaction, order *model.Order) (uuid.UUID, error)
+	// Refund stores the refund of the transaction, returning the units of its lines and giving back what its payments
+	// do, in a single database transaction. What goes back through payment providers is only recorded, by its
+	// pending refund payments, for the providers to be asked once the refund is stored.
+	Refund(ctx context.Context, refund model.Refund) (uuid.UUID, error)
+	// Margins sums what the items sold brought in and cost by product, or by variant when byVariant is set, over the
+	// items and transactions matching the spec. Its fields are qualified by the transaction_items and transactions
+	// tables.
+	Margins(ctx context.Context, spec query.Spec, byVariant bool) ([]Margin, error)
+}
+
+// Margin is what the sold units of a product, or of a variant of it, brought in and cost, in the minor unit of the
+// currency of the merchant. Refunded units are left out, except for what the ones written off cost. Name and
+// VariantName are the ones of the latest sale.
+type Margin struct {
+	ProductID   uuid.UUID
+	VariantID   *uuid.UUID
+	Name        string
+	VariantName string
+	Quantity    int64
+	Revenue     int64
+	Cost        int64
+}
+
+type transactionRepository struct {
+	Repository[model.Transaction]
+	conn *gorm.DB
+}
+
+func NewTransactionRepository(conn *gorm.DB) TransactionRepository {
+	return &transactionRepository{
+		Repository: NewRepository[model.Transaction](
+			conn, Hooks[model.Transaction]{
+				Query: func(db *gorm.DB) *gorm.DB {
+					return db.Preload("Items").Preload("Items.Modifiers").Preload("Items.Components").
+						Preload("Payments", orderByCreation).Preload("Refunds", orderByCreation).
+						Preload("Refunds.Lines").Preload("Refunds.Payments")
+				},
+			},
+		),
+		conn: conn,
+	}
+}
+
+// Checkout stores the transaction with its items and posts a sale movement for every sold product, or sold variant,
+// in a single database transaction. A bundle is sold out of the stock of its components, and a product with a recipe
+// out of the stock of its ingredients, with a sale movement for every one of them. A sale is only posted when enough
+// stock is left, so concurrent checkouts of the same product can't oversell it. Rows are updated in id order to keep
+// concurrent checkouts from deadlocking. Every item records the cost of goods its sale movements took out.
+func (t transactionRepository) Checkout(
+	ctx context.Context, transaction model.Transaction, order *model.Order,
+) (uuid.UUID, error) {
+	err := t.conn.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			err := tx.Create(&transaction).Error
+			if err != nil {
+				return err
+			}
+
+			if order != nil {
+				err = closeOrder(tx, *order, transaction.ID)
+				if err != nil {
+					return err
+				}
+			}
+
+			var productIds []uuid.UUID
+			for _, item := range transaction.Items {
+				productIds = append(productIds, item.ProductID)
+				for _, component := range item.Components {
+					productIds = append(productIds, component.ProductID)
+				}
+			}
+
+			var recipes []model.RecipeLine
+			err = tx.Where("product_id IN ?", productIds).Find(&recipes).Error
+			if err != nil {
+				return err
+			}
+
+			recipeOf := map[uuid.UUID][]model.RecipeLine{}
+			for _, line := range recipes {
+				recipeOf[line.ProductID] = append(recipeOf[line.ProductID], line)
+			}
+
+			var sales []sale
+			for i, item := range transaction.Items {
+				movement := model.StockMovement{
+					OutletID:          transaction.OutletID,
+					ProductID:         item.ProductID,
+					VariantID:         item.VariantID,
+					Type:              model.MovementSale,
+					Quantity:          -item.Quantity,
+					TransactionID:     &transaction.ID,
+					TransactionItemID: &transaction.Items[i].ID,
+					UserID:            &transaction.UserID,
+				}
+
+				if len(item.Components) == 0 {
+					sales = append(sales, recipeSales(i, movement, recipeOf[item.ProductID])...)
+					continue
+				}
+
+				for _, component := range item.Components {
+					componentMovement := movement
+					componentMovement.ProductID = component.ProductID
+					componentMovement.VariantID = component.VariantID
+					componentMovement.Quantity = -component.Quantity * item.Quantity
+					sales = append(sales, recipeSales(i, componentMovement, recipeOf[component.ProductID])...)
+				}
+			}
+
+			sort.Slice(
+				sales, func(i, j int) bool {
+					return stockKey(sales[i].movement.ProductID, sales[i].movement.VariantID) <
+						stockKey(sales[j].movement.ProductID, sales[j].movement.VariantID)
+				},
+			)
+
+			costs := make([]money.Money, len(transaction.Items))
+			for _, sale := range sales {
+				err = postMovement(tx, &sale.movement)
+				if err != nil {
+					return err
+				}
+
+				costs[sale.item], err = costs[sale.item].Add(sale.movement.Cost)
+				if err != nil {
+					return err
+				}
+			}
+
+			for i, item := range transaction.Items {
+				err = tx.Model(&model.TransactionItem{}).Where("id = ?", item.ID).Updates(
+					map[string]interface{}{"cost_amount": costs[i].Amount, "cost_currency": costs[i].Currency},
+				).Error
+				if err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+	)
+	if err != nil {
+		return uuid.Nil, translateError(err)
+	}
+
+	return transaction.ID, nil
+}
+
+// closeOrder closes the order a transaction was checked out from, unless it was changed, or closed, since it was read.
+func closeOrder(tx *gorm.DB, order model.Order, transactionId uuid.UUID) error {
+	result := tx.Model(&model.Order{}).Where("id = ? AND modified_at = ?", order.ID, order.ModifiedAt).
+		Where("status IN ?", []string{model.OrderOpen, model.OrderParked}).Updates(
+		map[string]interface{}{
+			"status": model.OrderClosed, "transaction_id": transactionId, "modified_at": time.Now(),
+		},
+	)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrOrderChanged
+	}
+
+	return nil
+}
+
+// sale is a sale movement posted for an item of a transaction, out of the stock of the item, of a component of it or
+// of an ingredient of either. A written off movement is of units a refund returns and throws away again, which never
+// join the stock they'd be valued with.
+type sale struct {
+	item       int
+	movement   model.StockMovement
+	writtenOff bool
+}
+
+// recipeSales posts the movement of the item as is, or spreads it over the ingredients of the recipe when there is one.
+func recipeSales(item int, movement model.StockMovement, recipe []model.RecipeLine) []sale {
+	if len(recipe) == 0 {
+		return []sale{{item: item, movement: movement}}
+	}
+
+	var sales []sale
+	for _, line := range recipe {
+		ingredient := movement
+		ingredient.ProductID = line.IngredientID
+		ingredient.VariantID = nil
+		ingredient.Quantity = line.Quantity * movement.Quantity
+		sales = append(sales, sale{item: item, movement: ingredient})
+	}
+
+	return sales
+}
+
+// Refund locks the transaction, so refunds of it are made one after the other, and checks the refund against it
+// again. The units of a restocked line are returned to the stocks their sale movements took them out of, at what
+// they cost then, the ones of a line written off are returned and thrown away again as waste. The transaction is
+// refunded once all its units are, or voided by a void.
+func (t transactionRepository) Refund(ctx context.Context, refund model.Refund) (uuid.UUID, error) {
+	err := t.conn.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			var transaction model.Transaction
+			err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", refund.TransactionID).
+				First(&transaction).Error
+			if err != nil {
+				return err
+			}
+
+			switch {
+			case transaction.Status == model.TransactionVoided:
+				return ErrTransactionVoided
+			case refund.Kind == model.RefundKindVoid && transaction.Status != model.TransactionCompleted &&
+				transaction.Status != model.TransactionUnpaid:
+				return ErrAlreadyRefunded
+			case refund.Kind != model.RefundKindVoid && (transaction.Status == model.TransactionPending ||
+				transaction.Status == model.TransactionUnpaid):
+				return ErrTransactionUnpaid
+			}
+
+			err = tx.Where("transaction_id = ?", transaction.ID).Order("created_at").Find(&transaction.Payments).Error
+			if err != nil {
+				return err
+			}
+
+			refundable := transaction.Refundable()
+			for _, payment := range refund.Payments {
+				if payment.Amount.Amount > refundable[payment.PaymentID] {
+					return ErrRefundExceedsPayment
+				}
+
+				refundable[payment.PaymentID] -= payment.Amount.Amount
+			}
+
+			for _, line := range refund.Lines {
+				result := tx.Model(&model.TransactionItem{}).
+					Where("id = ? AND transaction_id = ?", line.TransactionItemID, transaction.ID).
+					Where("returned_quantity + ? <= quantity", line.Quantity).
+					Update("returned_quantity", gorm.Expr("returned_quantity + ?", line.Quantity))
+				if result.Error != nil {
+					return result.Error
+				}
+
+				if result.RowsAffected == 0 {
+					return ErrNothingToReturn
+				}
+			}
+
+			err = tx.Create(&refund).Error
+			if err != nil {
+				return err
+			}
+
+			err = t.returnStock(tx, transaction, &refund)
+			if err != nil {
+				return err
+			}
+
+			for _, payment := range refund.Payments {
+				columns := map[string]interface{}{
+					"refunded_amount":   gorm.Expr("refunded_amount + ?", payment.Amount.Amount),
+					"refunded_currency": payment.Amount.Currency,
+				}
+				if refundable[payment.PaymentID] == 0 {
+					columns["status"] = model.PaymentRefunded
+				}
+
+				err = tx.Model(&model.TransactionPayment{}).Where("id = ?", payment.PaymentID).Updates(columns).Error
+				if err != nil {
+					return err
+				}
+			}
+
+			status := model.TransactionVoided
+			if refund.Kind != model.RefundKindVoid {
+				var left int64
+				err = tx.Model(&model.TransactionItem{}).
+					Where("transaction_id = ? AND returned_quantity < quantity", transaction.ID).Count(&left).Error
+				if err != nil {
+					return err
+				}
+
+				status = model.TransactionRefunded
+				if left > 0 {
+					status = model.TransactionPartiallyRefunded
+				}
+			}
+
+			return tx.Model(&model.Transaction{}).Where("id = ?", transaction.ID).Update("status", status).Error
+		},
+	)
+	if err != nil {
+		return uuid.Nil, translateError(err)
+	}
+
+	return refund.ID, nil
+}
+
+// returnStock posts the return movements of the lines of the refund, and records what the restocked ones were worth
+// on them and on their items.
+func (t transactionRepository) returnStock(tx *gorm.DB, transaction model.Transaction, refund *model.Refund) error {
+	var itemIds []uuid.UUID
+	for _, line := range refund.Lines {
+		itemIds = append(itemIds, line.TransactionItemID)
+	}
+
+	var items []model.TransactionItem
+	err := tx.Where("id IN ?", itemIds).Find(&items).Error
+	if err != nil {
+		return err
+	}
+
+	soldQuantity := map[uuid.UUID]int64{}
+	for _, item := range items {
+		soldQuantity[item.ID] = item.Quantity
+	}
+
+	var sold []model.StockMovement
+	err = tx.Where("transaction_item_id IN ? AND type = ?", itemIds, model.MovementSale).Order("created_at").
+		Find(&sold).Error
+	if err != nil {
+		return err
+	}
+
+	soldBy := map[uuid.UUID][]model.StockMovement{}
+	for _, movement := range sold {
+		soldBy[*movement.TransactionItemID] = append(soldBy[*movement.TransactionItemID], movement)
+	}
+
+	returns, err := refundMovements(transaction, refund, soldBy, soldQuantity)
+	if err != nil {
+		return err
+	}
+
+	costs := make([]money.Money, len(refund.Lines))
+	for _, returned := range returns {
+		if returned.writtenOff {
+			err = postWrittenOff(tx, &returned.movement)
+		} else {
+			err = postMovement(tx, &returned.movement)
+		}
+		if err != nil {
+			return err
+		}
+
+		if returned.movement.Type == model.MovementReturn && refund.Lines[returned.item].Restock {
+			costs[returned.item], err = costs[returned.item].Add(returned.movement.Cost)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for i, line := range refund.Lines {
+		if !costs[i].IsSet() {
+			continue
+		}
+
+		refund.Lines[i].Cost = costs[i]
+		err = tx.Model(&model.RefundLine{}).Where("id = ?", line.ID).Updates(
+			map[string]interface{}{"cost_amount": costs[i].Amount, "cost_currency": costs[i].Currency},
+		).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Model(&model.TransactionItem{}).Where("id = ?", line.TransactionItemID).Updates(
+			map[string]interface{}{
+				"returned_cost_amount":   gorm.Expr("returned_cost_amount + ?", costs[i].Amount),
+				"returned_cost_currency": costs[i].Currency,
+			},
+		).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// refundMovements are the return movements of the lines of the refund, one for every sale movement of their items,
+// ordered by the stock they go into. The units of a line written off are returned and thrown away again at what they
+// were sold at, leaving the lots and the average cost of the stock as they are: a waste valued by the stock would
+// use up the oldest lot instead of the units just returned.
+func refundMovements(
+	transaction model.Transaction, refund *model.Refund, soldBy map[uuid.UUID][]model.StockMovement,
+	soldQuantity map[uuid.UUID]int64,
+) ([]sale, error) {
+	var returns []sale
+	for i, line := range refund.Lines {
+		movements := soldBy[line.TransactionItemID]
+		if len(movements) == 0 && line.Restock {
+			return nil, ErrSaleUntraced
+		}
+
+		for _, movement := range movements {
+			// every unit of the item took the same out of the stock of the movement
+			quantity := -movement.Quantity / soldQuantity[line.TransactionItemID] * line.Quantity
+
+			returned := model.StockMovement{
+				OutletID:          movement.OutletID,
+				ProductID:         movement.ProductID,
+				VariantID:         movement.VariantID,
+				Type:              model.MovementReturn,
+				Quantity:          quantity,
+				TransactionID:     &transaction.ID,
+				TransactionItemID: &refund.Lines[i].TransactionItemID,
+				RefundID:          &refund.ID,
+				UnitCost:          movement.UnitCost,
+				UserID:            &refund.UserID,
+			}
+			if line.Restock {
+				returns = append(returns, sale{item: i, movement: returned})
+				continue
+			}
+
+			wasted := returned
+			wasted.Type = model.MovementWaste
+			wasted.Reason = model.StockDamaged
+			wasted.Note = "written off by a refund"
+			wasted.Quantity = -quantity
+			returns = append(
+				returns, sale{item: i, movement: returned, writtenOff: true},
+				sale{item: i, movement: wasted, writtenOff: true},
+			)
+		}
+	}
+
+	// a unit written off is returned before it is thrown away
+	sort.SliceStable(
+		returns, func(i, j int) bool {
+			return stockKey(returns[i].movement.ProductID, returns[i].movement.VariantID) <
+				stockKey(returns[j].movement.ProductID, returns[j].movement.VariantID)
+		},
+	)
+
+	return returns, nil
+}
+
+func (t transactionRepository) Margins(ctx context.Context, spec query.Spec, byVariant bool) ([]Margin, error) {
+	columns := "transaction_items.product_id, " +
+		"(ARRAY_AGG(transaction_items.name ORDER BY transactions.created_at DESC))[1] AS name, " +
+		"SUM(transaction_items.quantity - transaction_items.returned_quantity) AS quantity, " +
+		"SUM(transaction_items.subtotal_amount - " +
+		"transaction_items.price_amount * transaction_items.returned_quantity) AS revenue, " +
+		"SUM(transaction_items.cost_amount - transaction_items.returned_cost_amount) AS cost"
+	groups := "transaction_items.product_id"
+	if byVariant {
+		columns += ", transaction_items.variant_id, " +
+			"(ARRAY_AGG(transaction_items.variant_name ORDER BY transactions.created_at DESC))[1] AS variant_name"
+		groups += ", transaction_items.variant_id"
+	}
+
+	db := t.conn.WithContext(ctx).Table("transaction_items").
+		Joins("JOIN transactions ON transactions.id = transaction_items.transaction_id").
+		Where("transaction_items.deleted_at IS NULL AND transactions.deleted_at IS NULL")
+
+	var margins []Margin
+	err := spec.ApplyFilter(db).Select(columns).Group(groups).Order("revenue DESC").Scan(&margins).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return margins, nil
+}
+
+func orderByCreation(db *gorm.DB) *gorm.DB {
+	return db.Order("created_at")
+}
+
+// stockKey is the row keeping the stock of a product, or of its variant.
+func stockKey(productId uuid.UUID, variantId *uuid.UUID) string {
+	if variantId != nil {
+		return "variant " + variantId.String()
+	}
+
+	return "product " + productId.String()
+}
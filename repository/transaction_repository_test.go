@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"github.com/rehandwi03/test-case-backend-majoo/money"
+	"testing"
+)
+
+// TestRefundMovementsWriteOffUnderFIFO sells units out of a lot at 500 that is used up since, the oldest lot left
+// being at 700, and refunds one unit written off and one restocked.
+func TestRefundMovementsWriteOffUnderFIFO(t *testing.T) {
+	productId := uuid.New()
+	writtenOffItem := uuid.New()
+	restockedItem := uuid.New()
+	soldAt := money.New(500, "IDR")
+	sold := func(itemId uuid.UUID) model.StockMovement {
+		return model.StockMovement{
+			ProductID: productId, Type: model.MovementSale, Quantity: -2, UnitCost: soldAt, TransactionItemID: &itemId,
+		}
+	}
+
+	refund := model.Refund{
+		Lines: []model.RefundLine{
+			{TransactionItemID: writtenOffItem, Quantity: 1},
+			{TransactionItemID: restockedItem, Quantity: 1, Restock: true},
+		},
+	}
+	soldBy := map[uuid.UUID][]model.StockMovement{
+		writtenOffItem: {sold(writtenOffItem)}, restockedItem: {sold(restockedItem)},
+	}
+	returns, err := refundMovements(
+		model.Transaction{ID: uuid.New()}, &refund, soldBy, map[uuid.UUID]int64{writtenOffItem: 2, restockedItem: 2},
+	)
+	if err != nil {
+		t.Fatalf("refundMovements: %v", err)
+	}
+
+	if len(returns) != 3 {
+		t.Fatalf("%d movements, want a return and a waste written off and a return restocked", len(returns))
+	}
+
+	// the lots are posted the way post values the movements: written off ones leave them alone
+	lots := []model.StockCostLayer{{Remaining: 4, UnitCost: money.New(700, "IDR")}}
+	var wasteCost int64
+	for _, returned := range returns {
+		movement := returned.movement
+		if movement.UnitCost != soldAt {
+			t.Errorf("%s movement at %v, want it at what the unit was sold at", movement.Type, movement.UnitCost)
+		}
+
+		switch {
+		case returned.writtenOff:
+			if refund.Lines[returned.item].Restock {
+				t.Errorf("restocked %s movement is written off", movement.Type)
+			}
+			if movement.Type == model.MovementWaste {
+				wasteCost += -movement.Quantity * movement.UnitCost.Amount
+			}
+		case movement.Quantity > 0:
+			lots = append(lots, model.StockCostLayer{Remaining: movement.Quantity, UnitCost: movement.UnitCost})
+		default:
+			t.Errorf("%s movement of %d takes from the lots", movement.Type, movement.Quantity)
+		}
+	}
+
+	if wasteCost != 500 {
+		t.Errorf("the written off unit cost %d, want 500", wasteCost)
+	}
+
+	// the oldest lot is still whole, the restocked unit is a lot of its own at what it was sold at
+	taken, cost := drawLayers(lots, 5, 0)
+	if taken[0] != 4 || taken[1] != 1 || cost != 4*700+500 {
+		t.Errorf("the lots left give %v worth %d, want 4 at 700 and 1 at 500", taken, cost)
+	}
+}
@@ -0,0 +1,17 @@
+package request
+
+import "github.com/google/uuid"
+
+type CategoryAddRequest struct {
+	MerchantID uuid.UUID  `json:"merchant_id" validate:"required"`
+	ParentID   *uuid.UUID `json:"parent_id"`
+	Name       string     `json:"name" validate:"required,max=255"`
+	SortOrder  int        `json:"sort_order" validate:"min=0"`
+}
+
+type CategoryUpdateRequest struct {
+	ID        uuid.UUID  `json:"id" validate:"required"`
+	ParentID  *uuid.UUID `json:"parent_id"`
+	Name      string     `json:"name" validate:"required,max=255"`
+	SortOrder int        `json:"sort_order" validate:"min=0"`
+}
@@ -6,6 +6,9 @@ type MerchantAddRequest struct {
 	Name            string `json:"name" validate:"required,max=255"`
 	InstitutionName string `json:"institution_name" validate:"required,max=255"`
 	PhoneNumber     string `json:"phone_number" validate:"required,max=13"`
+	Currency        string `json:"currency"`
+	CostingMethod   string `json:"costing_method" validate:"omitempty,oneof=average fifo"`
+	PaymentSettings
 }
 
 type MerchantUpdateRequest struct {
@@ -13,4 +16,21 @@ type MerchantUpdateRequest struct {
 	Name            string    `json:"name" validate:"required,max=255"`
 	InstitutionName string    `json:"institution_name" validate:"required,max=255"`
 	PhoneNumber     string    `json:"phone_number" validate:"required,max=13"`
+	Currency        string    `json:"currency"`
+	CostingMethod   string    `json:"costing_method" validate:"omitempty,oneof=average fifo"`
+	PaymentSettings
+}
+
+// PaymentSettings are the payment methods the outlets of a merchant take and how the cash they take is rounded, the
+// settings left out keep the ones the merchant has, or the defaults of a new one.
+type PaymentSettings struct {
+	PaymentMethods   []string `json:"payment_methods" validate:"omitempty,dive,oneof=cash card e_wallet bank_transfer qris"`
+	CashRounding     *int64   `json:"cash_rounding" validate:"omitempty,min=0"`
+	CashRoundingMode string   `json:"cash_rounding_mode" validate:"omitempty,oneof=nearest down up"`
+}
+
+type MerchantUserAddRequest struct {
+	MerchantID uuid.UUID `json:"-"`
+	UserID     uuid.UUID `json:"user_id" validate:"required"`
+	Role       string    `json:"role" validate:"required,oneof=owner manager cashier"`
 }
@@ -0,0 +1,75 @@
+package request
+
+import "github.com/google/uuid"
+
+// OrderOpenRequest opens a bill, at a table of the outlet or not, with the items ordered so far.
+type OrderOpenRequest struct {
+	OutletID uuid.UUID          `json:"-"`
+	TableID  *uuid.UUID         `json:"table_id"`
+	Name     string             `json:"name" validate:"max=255"`
+	Guests   int64              `json:"guests" validate:"min=0"`
+	Note     string             `json:"note" validate:"max=255"`
+	Items    []OrderItemRequest `json:"items" validate:"dive"`
+}
+
+type OrderUpdateRequest struct {
+	ID       uuid.UUID `json:"-"`
+	OutletID uuid.UUID `json:"-"`
+	Name     string    `json:"name" validate:"max=255"`
+	Guests   int64     `json:"guests" validate:"min=0"`
+	Note     string    `json:"note" validate:"max=255"`
+}
+
+type OrderAddItemsRequest struct {
+	ID       uuid.UUID          `json:"-"`
+	OutletID uuid.UUID          `json:"-"`
+	Items    []OrderItemRequest `json:"items" validate:"required,min=1,dive"`
+}
+
+// OrderItemRequest orders a product like TransactionItemRequest sells it, for a seat of the table, seat 0 being
+// shared by the table.
+type OrderItemRequest struct {
+	TransactionItemRequest
+	Seat int64  `json:"seat" validate:"min=0"`
+	Note string `json:"note" validate:"max=255"`
+}
+
+type OrderItemUpdateRequest struct {
+	ID       uuid.UUID `json:"-"`
+	OrderID  uuid.UUID `json:"-"`
+	OutletID uuid.UUID `json:"-"`
+	Quantity int64     `json:"quantity" validate:"required,min=1"`
+	Seat     int64     `json:"seat" validate:"min=0"`
+	Note     string    `json:"note" validate:"max=255"`
+}
+
+// OrderTransferRequest moves an order to another table of the outlet, or away from its table without one.
+type OrderTransferRequest struct {
+	ID       uuid.UUID  `json:"-"`
+	OutletID uuid.UUID  `json:"-"`
+	TableID  *uuid.UUID `json:"table_id"`
+}
+
+// OrderMergeRequest merges other open orders of the outlet into the order.
+type OrderMergeRequest struct {
+	ID       uuid.UUID   `json:"-"`
+	OutletID uuid.UUID   `json:"-"`
+	OrderIDs []uuid.UUID `json:"order_ids" validate:"required,min=1"`
+}
+
+// OrderSplitRequest splits the items, or units of them, and the items of the seats off the order into a new order,
+// at the table given or at the table of the order.
+type OrderSplitRequest struct {
+	ID       uuid.UUID               `json:"-"`
+	OutletID uuid.UUID               `json:"-"`
+	Items    []OrderSplitItemRequest `json:"items" validate:"dive"`
+	Seats    []int64                 `json:"seats" validate:"dive,min=1"`
+	TableID  *uuid.UUID              `json:"table_id"`
+	Name     string                  `json:"name" validate:"max=255"`
+	Guests   int64                   `json:"guests" validate:"min=0"`
+}
+
+type OrderSplitItemRequest struct {
+	OrderItemID uuid.UUID `json:"order_item_id" validate:"required"`
+	Quantity    int64     `json:"quantity" validate:"required,min=1"`
+}
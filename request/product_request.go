@@ -2,21 +2,144 @@ package request
 
 import (
 	"github.com/google/uuid"
+	"github.com/rehandwi03/test-case-backend-majoo/money"
 )
 
 type ProductAddRequest struct {
-	OutletID    uuid.UUID `json:"outlet_id" validate:"required"`
-	Name        string    `json:"name" validate:"required"`
-	Description string    `json:"description" validate:"required"`
-	Stock       int64     `json:"stock" validate:"required"`
-	Price       float64   `json:"price" validate:"required"`
+	OutletID        uuid.UUID   `json:"outlet_id" validate:"required"`
+	Name            string      `json:"name" validate:"required"`
+	Description     string      `json:"description" validate:"required"`
+	SKU             string      `json:"sku" validate:"max=64"`
+	IsIngredient    bool        `json:"is_ingredient"`
+	IsBundle        bool        `json:"is_bundle"`
+	Stock           int64       `json:"stock" validate:"min=0"`
+	ReorderPoint    *int64      `json:"reorder_point" validate:"omitempty,min=0"`
+	ReorderQuantity int64       `json:"reorder_quantity" validate:"min=0"`
+	Price           money.Money `json:"price"`
 }
 
+// ProductUpdateRequest leaves the stock alone, it is only changed by the movements of the stock ledger. OutletID has
+// to be the outlet of the product, which it can't move out of.
 type ProductUpdateRequest struct {
-	ID          uuid.UUID `json:"id" validate:"required"`
-	OutletID    uuid.UUID `json:"outlet_id" validate:"required"`
-	Name        string    `json:"name" validate:"required"`
-	Description string    `json:"description" validate:"required"`
-	Stock       int64     `json:"stock" validate:"required"`
-	Price       float64   `json:"price" validate:"required"`
+	ID              uuid.UUID   `json:"id" validate:"required"`
+	OutletID        uuid.UUID   `json:"outlet_id" validate:"required"`
+	Name            string      `json:"name" validate:"required"`
+	Description     string      `json:"description" validate:"required"`
+	SKU             string      `json:"sku" validate:"max=64"`
+	IsIngredient    bool        `json:"is_ingredient"`
+	IsBundle        bool        `json:"is_bundle"`
+	ReorderPoint    *int64      `json:"reorder_point" validate:"omitempty,min=0"`
+	ReorderQuantity int64       `json:"reorder_quantity" validate:"min=0"`
+	Price           money.Money `json:"price"`
+}
+
+type ProductCategoriesRequest struct {
+	ProductID   uuid.UUID   `json:"-"`
+	CategoryIDs []uuid.UUID `json:"category_ids" validate:"required"`
+}
+
+// ProductRecipeRequest replaces the recipe of a product, no lines leave it without one.
+type ProductRecipeRequest struct {
+	ProductID uuid.UUID           `json:"-"`
+	Lines     []RecipeLineRequest `json:"lines" validate:"required,dive"`
+}
+
+// RecipeLineRequest is how much of an ingredient a unit of the product takes, in the unit its stock is kept in.
+type RecipeLineRequest struct {
+	IngredientID uuid.UUID `json:"ingredient_id" validate:"required"`
+	Quantity     int64     `json:"quantity" validate:"required,min=1"`
+}
+
+type BundleSlotAddRequest struct {
+	ProductID uuid.UUID             `json:"-"`
+	Name      string                `json:"name" validate:"required,max=255"`
+	Quantity  int64                 `json:"quantity" validate:"required,min=1"`
+	SortOrder int                   `json:"sort_order" validate:"min=0"`
+	Options   []BundleOptionRequest `json:"options" validate:"required,min=1,dive"`
+}
+
+type BundleSlotUpdateRequest struct {
+	ID        uuid.UUID             `json:"-"`
+	ProductID uuid.UUID             `json:"-"`
+	Name      string                `json:"name" validate:"required,max=255"`
+	Quantity  int64                 `json:"quantity" validate:"required,min=1"`
+	SortOrder int                   `json:"sort_order" validate:"min=0"`
+	Options   []BundleOptionRequest `json:"options" validate:"required,min=1,dive"`
+}
+
+// BundleOptionRequest updates the option with the id, an option without id is added to the slot. A product with
+// variants fills a slot with one of them.
+type BundleOptionRequest struct {
+	ID         uuid.UUID   `json:"id"`
+	ProductID  uuid.UUID   `json:"product_id" validate:"required"`
+	VariantID  *uuid.UUID  `json:"variant_id"`
+	PriceDelta money.Money `json:"price_delta"`
+	SortOrder  int         `json:"sort_order" validate:"min=0"`
+}
+
+type ProductOptionAddRequest struct {
+	ProductID uuid.UUID                   `json:"-"`
+	Name      string                      `json:"name" validate:"required,max=255"`
+	SortOrder int                         `json:"sort_order" validate:"min=0"`
+	Values    []ProductOptionValueRequest `json:"values" validate:"required,min=1,dive"`
+}
+
+type ProductOptionUpdateRequest struct {
+	ID        uuid.UUID                   `json:"-"`
+	ProductID uuid.UUID                   `json:"-"`
+	Name      string                      `json:"name" validate:"required,max=255"`
+	SortOrder int                         `json:"sort_order" validate:"min=0"`
+	Values    []ProductOptionValueRequest `json:"values" validate:"required,min=1,dive"`
+}
+
+// ProductOptionValueRequest updates the value with the id, a value without id is added to the option.
+type ProductOptionValueRequest struct {
+	ID        uuid.UUID `json:"id"`
+	Value     string    `json:"value" validate:"required,max=255"`
+	SortOrder int       `json:"sort_order" validate:"min=0"`
+}
+
+type ProductVariantAddRequest struct {
+	ProductID      uuid.UUID    `json:"-"`
+	SKU            string       `json:"sku" validate:"max=64"`
+	Barcode        string       `json:"barcode" validate:"max=64"`
+	Price          *money.Money `json:"price"`
+	Stock          int64        `json:"stock" validate:"min=0"`
+	OptionValueIDs []uuid.UUID  `json:"option_value_ids" validate:"required,min=1"`
+}
+
+type ProductVariantUpdateRequest struct {
+	ID             uuid.UUID    `json:"-"`
+	ProductID      uuid.UUID    `json:"-"`
+	SKU            string       `json:"sku" validate:"max=64"`
+	Barcode        string       `json:"barcode" validate:"max=64"`
+	Price          *money.Money `json:"price"`
+	OptionValueIDs []uuid.UUID  `json:"option_value_ids" validate:"required,min=1"`
+}
+
+type ModifierGroupAddRequest struct {
+	ProductID uuid.UUID         `json:"-"`
+	Name      string            `json:"name" validate:"required,max=255"`
+	MinSelect int               `json:"min_select" validate:"min=0"`
+	MaxSelect int               `json:"max_select" validate:"required,min=1,gtefield=MinSelect"`
+	SortOrder int               `json:"sort_order" validate:"min=0"`
+	Modifiers []ModifierRequest `json:"modifiers" validate:"required,min=1,dive"`
+}
+
+type ModifierGroupUpdateRequest struct {
+	ID        uuid.UUID         `json:"-"`
+	ProductID uuid.UUID         `json:"-"`
+	Name      string            `json:"name" validate:"required,max=255"`
+	MinSelect int               `json:"min_select" validate:"min=0"`
+	MaxSelect int               `json:"max_select" validate:"required,min=1,gtefield=MinSelect"`
+	SortOrder int               `json:"sort_order" validate:"min=0"`
+	Modifiers []ModifierRequest `json:"modifiers" validate:"required,min=1,dive"`
+}
+
+// ModifierRequest updates the modifier with the id, a modifier without id is added to the group.
+type ModifierRequest struct {
+	ID         uuid.UUID   `json:"id"`
+	Name       string      `json:"name" validate:"required,max=255"`
+	PriceDelta money.Money `json:"price_delta"`
+	SortOrder  int         `json:"sort_order" validate:"min=0"`
 }
@@ -0,0 +1,35 @@
+package request
+
+import (
+	"github.com/google/uuid"
+	"github.com/rehandwi03/test-case-backend-majoo/money"
+)
+
+type PurchaseOrderAddRequest struct {
+	SupplierID uuid.UUID                  `json:"supplier_id" validate:"required"`
+	OutletID   uuid.UUID                  `json:"outlet_id" validate:"required"`
+	Note       string                     `json:"note" validate:"max=255"`
+	Lines      []PurchaseOrderLineRequest `json:"lines" validate:"required,min=1,dive"`
+}
+
+// PurchaseOrderLineRequest orders a product of the outlet, or a variant of it, at the unit cost it is expected to be
+// bought at.
+type PurchaseOrderLineRequest struct {
+	ProductID uuid.UUID   `json:"product_id" validate:"required"`
+	VariantID *uuid.UUID  `json:"variant_id"`
+	Quantity  int64       `json:"quantity" validate:"required,min=1"`
+	UnitCost  money.Money `json:"unit_cost"`
+}
+
+type PurchaseOrderReceiveRequest struct {
+	ID    uuid.UUID                     `json:"-"`
+	Lines []PurchaseOrderReceiptRequest `json:"lines" validate:"required,min=1,dive"`
+}
+
+// PurchaseOrderReceiptRequest receives a quantity of a line, at the unit cost of the line unless the goods were
+// invoiced at another one.
+type PurchaseOrderReceiptRequest struct {
+	LineID   uuid.UUID    `json:"line_id" validate:"required"`
+	Quantity int64        `json:"quantity" validate:"required,min=1"`
+	UnitCost *money.Money `json:"unit_cost"`
+}
@@ -0,0 +1,44 @@
+package request
+
+import (
+	"github.com/google/uuid"
+	"github.com/rehandwi03/test-case-backend-majoo/money"
+)
+
+// RefundRequest returns items of a sale. Without payments what the items are worth is given back by the payments of
+// the sale, the last one made first. A cashier needs the approval of an owner or manager of the merchant.
+type RefundRequest struct {
+	OutletID      uuid.UUID              `json:"-"`
+	TransactionID uuid.UUID              `json:"-"`
+	Reason        string                 `json:"reason" validate:"required,max=255"`
+	Items         []RefundItemRequest    `json:"items" validate:"required,min=1,dive"`
+	Payments      []RefundPaymentRequest `json:"payments" validate:"dive"`
+	Approval      *ApprovalRequest       `json:"approval"`
+}
+
+// RefundItemRequest returns units of an item of the sale, back into the stock they were sold out of unless they are
+// written off.
+type RefundItemRequest struct {
+	TransactionItemID uuid.UUID `json:"transaction_item_id" validate:"required"`
+	Quantity          int64     `json:"quantity" validate:"required,min=1"`
+	WriteOff          bool      `json:"write_off"`
+}
+
+type RefundPaymentRequest struct {
+	PaymentID uuid.UUID   `json:"payment_id" validate:"required"`
+	Amount    money.Money `json:"amount"`
+}
+
+// VoidRequest cancels a sale as a whole, nothing of it may have been refunded yet.
+type VoidRequest struct {
+	OutletID      uuid.UUID        `json:"-"`
+	TransactionID uuid.UUID        `json:"-"`
+	Reason        string           `json:"reason" validate:"required,max=255"`
+	Approval      *ApprovalRequest `json:"approval"`
+}
+
+// ApprovalRequest are the credentials of the owner or manager approving what a cashier does.
+type ApprovalRequest struct {
+	Email    string `json:"email" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
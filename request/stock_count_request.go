@@ -0,0 +1,21 @@
+package request
+
+import "github.com/google/uuid"
+
+type StockCountStartRequest struct {
+	OutletID uuid.UUID `json:"-"`
+	Note     string    `json:"note" validate:"max=255"`
+}
+
+// StockCountRecordRequest submits a batch of counted quantities, a product counted again keeps the latest count.
+type StockCountRecordRequest struct {
+	ID       uuid.UUID               `json:"-"`
+	OutletID uuid.UUID               `json:"-"`
+	Lines    []StockCountLineRequest `json:"lines" validate:"required,min=1,dive"`
+}
+
+type StockCountLineRequest struct {
+	ProductID uuid.UUID  `json:"product_id" validate:"required"`
+	VariantID *uuid.UUID `json:"variant_id"`
+	Counted   int64      `json:"counted" validate:"min=0"`
+}
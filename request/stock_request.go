@@ -0,0 +1,14 @@
+package request
+
+import "github.com/google/uuid"
+
+// StockAdjustmentRequest posts a stock movement by hand. A restock or waste takes the positive quantity received or
+// thrown away, an adjustment takes the signed change.
+type StockAdjustmentRequest struct {
+	ProductID uuid.UUID  `json:"-"`
+	VariantID *uuid.UUID `json:"variant_id"`
+	Type      string     `json:"type" validate:"required,oneof=restock adjustment waste"`
+	Quantity  int64      `json:"quantity" validate:"required"`
+	Reason    string     `json:"reason" validate:"required,oneof=purchase count_correction damaged expired lost found theft other"`
+	Note      string     `json:"note" validate:"max=255"`
+}
@@ -0,0 +1,35 @@
+package request
+
+import "github.com/google/uuid"
+
+type StockTransferAddRequest struct {
+	SourceOutletID      uuid.UUID                  `json:"source_outlet_id" validate:"required"`
+	DestinationOutletID uuid.UUID                  `json:"destination_outlet_id" validate:"required"`
+	Note                string                     `json:"note" validate:"max=255"`
+	Lines               []StockTransferLineRequest `json:"lines" validate:"required,min=1,dive"`
+}
+
+// StockTransferLineRequest is a product of the source outlet, or a variant of it, to transfer.
+type StockTransferLineRequest struct {
+	ProductID uuid.UUID  `json:"product_id" validate:"required"`
+	VariantID *uuid.UUID `json:"variant_id"`
+	Quantity  int64      `json:"quantity" validate:"required,min=1"`
+}
+
+// StockTransferReceiveRequest receives part or all of what is still outstanding on the lines of the transfer.
+type StockTransferReceiveRequest struct {
+	ID    uuid.UUID                     `json:"-"`
+	Lines []StockTransferReceiptRequest `json:"lines" validate:"required,min=1,dive"`
+}
+
+type StockTransferReceiptRequest struct {
+	LineID   uuid.UUID `json:"line_id" validate:"required"`
+	Quantity int64     `json:"quantity" validate:"required,min=1"`
+}
+
+// StockTransferCloseRequest settles what is still outstanding on a transfer, Return puts it back into the stock of the
+// source outlet, otherwise it is written off as lost.
+type StockTransferCloseRequest struct {
+	ID     uuid.UUID `json:"-"`
+	Return bool      `json:"return"`
+}
@@ -0,0 +1,23 @@
+package request
+
+import "github.com/google/uuid"
+
+type SupplierAddRequest struct {
+	MerchantID  uuid.UUID `json:"merchant_id" validate:"required"`
+	Name        string    `json:"name" validate:"required,max=255"`
+	ContactName string    `json:"contact_name" validate:"max=255"`
+	Email       string    `json:"email" validate:"omitempty,email,max=255"`
+	PhoneNumber string    `json:"phone_number" validate:"max=20"`
+	Address     string    `json:"address"`
+	Note        string    `json:"note" validate:"max=255"`
+}
+
+type SupplierUpdateRequest struct {
+	ID          uuid.UUID `json:"-"`
+	Name        string    `json:"name" validate:"required,max=255"`
+	ContactName string    `json:"contact_name" validate:"max=255"`
+	Email       string    `json:"email" validate:"omitempty,email,max=255"`
+	PhoneNumber string    `json:"phone_number" validate:"max=20"`
+	Address     string    `json:"address"`
+	Note        string    `json:"note" validate:"max=255"`
+}
@@ -0,0 +1,34 @@
+package request
+
+import "github.com/google/uuid"
+
+type AreaAddRequest struct {
+	OutletID  uuid.UUID `json:"-"`
+	Name      string    `json:"name" validate:"required,max=255"`
+	SortOrder int       `json:"sort_order" validate:"min=0"`
+}
+
+type AreaUpdateRequest struct {
+	ID        uuid.UUID `json:"-"`
+	OutletID  uuid.UUID `json:"-"`
+	Name      string    `json:"name" validate:"required,max=255"`
+	SortOrder int       `json:"sort_order" validate:"min=0"`
+}
+
+// TableAddRequest adds a table to the outlet, in one of its areas or not.
+type TableAddRequest struct {
+	OutletID  uuid.UUID  `json:"-"`
+	AreaID    *uuid.UUID `json:"area_id"`
+	Name      string     `json:"name" validate:"required,max=255"`
+	Seats     int64      `json:"seats" validate:"min=0"`
+	SortOrder int        `json:"sort_order" validate:"min=0"`
+}
+
+type TableUpdateRequest struct {
+	ID        uuid.UUID  `json:"-"`
+	OutletID  uuid.UUID  `json:"-"`
+	AreaID    *uuid.UUID `json:"area_id"`
+	Name      string     `json:"name" validate:"required,max=255"`
+	Seats     int64      `json:"seats" validate:"min=0"`
+	SortOrder int        `json:"sort_order" validate:"min=0"`
+}
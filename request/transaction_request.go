@@ -0,0 +1,36 @@
+package request
+
+import (
+	"github.com/google/uuid"
+	"github.com/rehandwi03/test-case-backend-majoo/money"
+)
+
+// TransactionCheckoutRequest is paid for by its payments, which have to cover the total. Only cash may pay more, the
+// rest is given back as change, and a checkout with a total of zero needs no payments. A checkout of an order sells
+// the items of the order instead of its own.
+type TransactionCheckoutRequest struct {
+	OutletID uuid.UUID                   `json:"-"`
+	OrderID  *uuid.UUID                  `json:"order_id"`
+	Items    []TransactionItemRequest    `json:"items" validate:"required_without=OrderID,dive"`
+	Payments []TransactionPaymentRequest `json:"payments" validate:"dive"`
+}
+
+// TransactionItemRequest sells a product, a product with variants needs the variant too. The modifiers have to follow
+// the selection rules of the modifier groups of the product. A bundle needs an option chosen for every slot of it
+// with more than one.
+type TransactionItemRequest struct {
+	ProductID   uuid.UUID   `json:"product_id" validate:"required"`
+	VariantID   *uuid.UUID  `json:"variant_id"`
+	ModifierIDs []uuid.UUID `json:"modifier_ids"`
+	OptionIDs   []uuid.UUID `json:"option_ids"`
+	Quantity    int64       `json:"quantity" validate:"required,min=1"`
+}
+
+// TransactionPaymentRequest is a tender of a checkout, a payment other than cash goes through the payment provider
+// it names, if any.
+type TransactionPaymentRequest struct {
+	Method    string      `json:"method" validate:"required,oneof=cash card e_wallet bank_transfer qris"`
+	Amount    money.Money `json:"amount"`
+	Reference string      `json:"reference" validate:"max=255"`
+	Provider  string      `json:"provider" validate:"max=32"`
+}
@@ -10,6 +10,7 @@ type UserAddRequest struct {
 	Email       string `json:"email" validate:"required,email,min=3,max=50"`
 	Password    string `json:"password" validate:"required,min=8,max=50"`
 	PhoneNumber string `json:"phone_number" validate:"required,min=3,max=13"`
+	Role        string `json:"role" validate:"omitempty,oneof=admin"`
 }
 
 type UserUpdateRequest struct {
@@ -19,4 +20,5 @@ type UserUpdateRequest struct {
 	Email       string    `json:"email" validate:"required,email,min=3,max=50"`
 	Password    string    `json:"password" validate:"required,min=8,max=50"`
 	PhoneNumber string    `json:"phone_number" validate:"required,min=3,max=13"`
+	Role        string    `json:"role" validate:"omitempty,oneof=admin"`
 }
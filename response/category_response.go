@@ -0,0 +1,22 @@
+package response
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type CategoryResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	MerchantID uuid.UUID  `json:"merchant_id"`
+	ParentID   *uuid.UUID `json:"parent_id"`
+	Name       string     `json:"name"`
+	SortOrder  int        `json:"sort_order"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type CategoryTreeResponse struct {
+	ID        uuid.UUID              `json:"id"`
+	Name      string                 `json:"name"`
+	SortOrder int                    `json:"sort_order"`
+	Children  []CategoryTreeResponse `json:"children"`
+}
@@ -6,10 +6,16 @@ import (
 )
 
 type MerchantResponse struct {
-	ID              uuid.UUID `json:"id"`
-	UserID          uuid.UUID `json:"user_id"`
-	Name            string    `json:"name"`
-	InstitutionName string    `json:"institution_name"`
-	PhoneNumber     string    `json:"phone_number"`
-	CreatedAt       time.Time `json:"created_at"`
+	ID               uuid.UUID  `json:"id"`
+	UserID           uuid.UUID  `json:"user_id"`
+	Name             string     `json:"name"`
+	InstitutionName  string     `json:"institution_name"`
+	PhoneNumber      string     `json:"phone_number"`
+	Currency         string     `json:"currency"`
+	CostingMethod    string     `json:"costing_method"`
+	PaymentMethods   []string   `json:"payment_methods"`
+	CashRounding     int64      `json:"cash_rounding"`
+	CashRoundingMode string     `json:"cash_rounding_mode"`
+	CreatedAt        time.Time  `json:"created_at"`
+	DeletedAt        *time.Time `json:"deleted_at,omitempty"`
 }
@@ -0,0 +1,32 @@
+package response
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type NotificationResponse struct {
+	ID              uuid.UUID  `json:"id"`
+	OutletID        uuid.UUID  `json:"outlet_id"`
+	Type            string     `json:"type"`
+	ProductID       *uuid.UUID `json:"product_id,omitempty"`
+	VariantID       *uuid.UUID `json:"variant_id,omitempty"`
+	StockMovementID *uuid.UUID `json:"stock_movement_id,omitempty"`
+	Message         string     `json:"message"`
+	Stock           int64      `json:"stock"`
+	ReorderPoint    int64      `json:"reorder_point"`
+	ReorderQuantity int64      `json:"reorder_quantity"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// LowStockResponse is a product, or a variant of it, whose stock is at or below the reorder point of the product.
+type LowStockResponse struct {
+	ProductID       uuid.UUID  `json:"product_id"`
+	VariantID       *uuid.UUID `json:"variant_id,omitempty"`
+	Name            string     `json:"name"`
+	VariantName     string     `json:"variant_name,omitempty"`
+	SKU             string     `json:"sku"`
+	Stock           int64      `json:"stock"`
+	ReorderPoint    int64      `json:"reorder_point"`
+	ReorderQuantity int64      `json:"reorder_quantity"`
+}
@@ -0,0 +1,41 @@
+package response
+
+import (
+	"github.com/google/uuid"
+	"github.com/rehandwi03/test-case-backend-majoo/money"
+	"time"
+)
+
+// OrderResponse is a bill, Total adds up what its items were priced at when they were ordered.
+type OrderResponse struct {
+	ID            uuid.UUID           `json:"id"`
+	OutletID      uuid.UUID           `json:"outlet_id"`
+	TableID       *uuid.UUID          `json:"table_id"`
+	Name          string              `json:"name,omitempty"`
+	Guests        int64               `json:"guests"`
+	Status        string              `json:"status"`
+	Note          string              `json:"note,omitempty"`
+	UserID        uuid.UUID           `json:"user_id"`
+	TransactionID *uuid.UUID          `json:"transaction_id,omitempty"`
+	MergedIntoID  *uuid.UUID          `json:"merged_into_id,omitempty"`
+	TotalQuantity int64               `json:"total_quantity"`
+	Total         *money.Money        `json:"total"`
+	Items         []OrderItemResponse `json:"items"`
+	CreatedAt     time.Time           `json:"created_at"`
+	ModifiedAt    time.Time           `json:"modified_at"`
+}
+
+type OrderItemResponse struct {
+	ID          uuid.UUID   `json:"id"`
+	ProductID   uuid.UUID   `json:"product_id"`
+	VariantID   *uuid.UUID  `json:"variant_id,omitempty"`
+	Name        string      `json:"name"`
+	VariantName string      `json:"variant_name,omitempty"`
+	Price       money.Money `json:"price"`
+	Quantity    int64       `json:"quantity"`
+	Subtotal    money.Money `json:"subtotal"`
+	Seat        int64       `json:"seat"`
+	Note        string      `json:"note,omitempty"`
+	ModifierIDs []uuid.UUID `json:"modifier_ids"`
+	OptionIDs   []uuid.UUID `json:"option_ids,omitempty"`
+}
@@ -6,10 +6,11 @@ import (
 )
 
 type OutletResponse struct {
-	ID          uuid.UUID `json:"id"`
-	MerchantID  uuid.UUID `json:"merchant_id"`
-	Name        string    `json:"name"`
-	Location    string    `json:"location"`
-	PhoneNumber string    `json:"phone_number"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID          uuid.UUID  `json:"id"`
+	MerchantID  uuid.UUID  `json:"merchant_id"`
+	Name        string     `json:"name"`
+	Location    string     `json:"location"`
+	PhoneNumber string     `json:"phone_number"`
+	CreatedAt   time.Time  `json:"created_at"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
 }
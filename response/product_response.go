@@ -2,15 +2,100 @@ package response
 
 import (
 	"github.com/google/uuid"
+	"github.com/rehandwi03/test-case-backend-majoo/money"
 	"time"
 )
 
 type ProductResponse struct {
-	ID          uuid.UUID `json:"id"`
-	OutletID    uuid.UUID `json:"outlet_id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	Stock       int64     `json:"stock"`
-	Price       float64   `json:"price"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID              uuid.UUID                `json:"id"`
+	OutletID        uuid.UUID                `json:"outlet_id"`
+	Name            string                   `json:"name"`
+	Description     string                   `json:"description"`
+	SKU             string                   `json:"sku"`
+	IsIngredient    bool                     `json:"is_ingredient"`
+	IsBundle        bool                     `json:"is_bundle"`
+	Stock           int64                    `json:"stock"`
+	ReorderPoint    *int64                   `json:"reorder_point"`
+	ReorderQuantity int64                    `json:"reorder_quantity"`
+	Price           money.Money              `json:"price"`
+	CostPrice       *money.Money             `json:"cost_price"`
+	AverageCost     *money.Money             `json:"average_cost"`
+	Categories      []CategoryResponse       `json:"categories"`
+	Options         []ProductOptionResponse  `json:"options"`
+	Variants        []ProductVariantResponse `json:"variants"`
+	ModifierGroups  []ModifierGroupResponse  `json:"modifier_groups"`
+	Recipe          []RecipeLineResponse     `json:"recipe"`
+	BundleSlots     []BundleSlotResponse     `json:"bundle_slots"`
+	CreatedAt       time.Time                `json:"created_at"`
+	DeletedAt       *time.Time               `json:"deleted_at,omitempty"`
+}
+
+// RecipeLineResponse is an ingredient of a product, Name is empty once the ingredient is deleted.
+type RecipeLineResponse struct {
+	IngredientID uuid.UUID `json:"ingredient_id"`
+	Name         string    `json:"name"`
+	SKU          string    `json:"sku"`
+	Quantity     int64     `json:"quantity"`
+}
+
+type BundleSlotResponse struct {
+	ID        uuid.UUID              `json:"id"`
+	Name      string                 `json:"name"`
+	Quantity  int64                  `json:"quantity"`
+	SortOrder int                    `json:"sort_order"`
+	Options   []BundleOptionResponse `json:"options"`
+}
+
+// BundleOptionResponse is a product, or variant, filling a slot, Name is empty once the product is deleted.
+type BundleOptionResponse struct {
+	ID          uuid.UUID   `json:"id"`
+	ProductID   uuid.UUID   `json:"product_id"`
+	VariantID   *uuid.UUID  `json:"variant_id,omitempty"`
+	Name        string      `json:"name"`
+	VariantName string      `json:"variant_name,omitempty"`
+	PriceDelta  money.Money `json:"price_delta"`
+	SortOrder   int         `json:"sort_order"`
+}
+
+type ProductOptionResponse struct {
+	ID        uuid.UUID                    `json:"id"`
+	Name      string                       `json:"name"`
+	SortOrder int                          `json:"sort_order"`
+	Values    []ProductOptionValueResponse `json:"values"`
+}
+
+type ProductOptionValueResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Value     string    `json:"value"`
+	SortOrder int       `json:"sort_order"`
+}
+
+// ProductVariantResponse has the price the variant is sold for, which is the price of the product unless the variant
+// overrides it. CostPrice is null until a purchase of the variant is received, like the one of a product.
+type ProductVariantResponse struct {
+	ID           uuid.UUID                    `json:"id"`
+	Name         string                       `json:"name"`
+	SKU          string                       `json:"sku"`
+	Barcode      string                       `json:"barcode"`
+	Price        money.Money                  `json:"price"`
+	CostPrice    *money.Money                 `json:"cost_price"`
+	AverageCost  *money.Money                 `json:"average_cost"`
+	Stock        int64                        `json:"stock"`
+	OptionValues []ProductOptionValueResponse `json:"option_values"`
+}
+
+type ModifierGroupResponse struct {
+	ID        uuid.UUID          `json:"id"`
+	Name      string             `json:"name"`
+	MinSelect int                `json:"min_select"`
+	MaxSelect int                `json:"max_select"`
+	SortOrder int                `json:"sort_order"`
+	Modifiers []ModifierResponse `json:"modifiers"`
+}
+
+type ModifierResponse struct {
+	ID         uuid.UUID   `json:"id"`
+	Name       string      `json:"name"`
+	PriceDelta money.Money `json:"price_delta"`
+	SortOrder  int         `json:"sort_order"`
 }
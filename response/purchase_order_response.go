@@ -0,0 +1,38 @@
+package response
+
+import (
+	"github.com/google/uuid"
+	"github.com/rehandwi03/test-case-backend-majoo/money"
+	"time"
+)
+
+// PurchaseOrderResponse has the total the order is expected to cost and what was received of it so far.
+type PurchaseOrderResponse struct {
+	ID            uuid.UUID                   `json:"id"`
+	MerchantID    uuid.UUID                   `json:"merchant_id"`
+	SupplierID    uuid.UUID                   `json:"supplier_id"`
+	OutletID      uuid.UUID                   `json:"outlet_id"`
+	Status        string                      `json:"status"`
+	Note          string                      `json:"note"`
+	Total         money.Money                 `json:"total"`
+	ReceivedTotal money.Money                 `json:"received_total"`
+	CreatedBy     uuid.UUID                   `json:"created_by"`
+	OrderedBy     *uuid.UUID                  `json:"ordered_by,omitempty"`
+	OrderedAt     *time.Time                  `json:"ordered_at,omitempty"`
+	Lines         []PurchaseOrderLineResponse `json:"lines"`
+	CreatedAt     time.Time                   `json:"created_at"`
+}
+
+type PurchaseOrderLineResponse struct {
+	ID           uuid.UUID   `json:"id"`
+	ProductID    uuid.UUID   `json:"product_id"`
+	VariantID    *uuid.UUID  `json:"variant_id,omitempty"`
+	SKU          string      `json:"sku"`
+	Name         string      `json:"name"`
+	VariantName  string      `json:"variant_name,omitempty"`
+	Quantity     int64       `json:"quantity"`
+	UnitCost     money.Money `json:"unit_cost"`
+	Received     int64       `json:"received"`
+	ReceivedCost money.Money `json:"received_cost"`
+	Outstanding  int64       `json:"outstanding"`
+}
@@ -0,0 +1,31 @@
+package response
+
+import (
+	"github.com/google/uuid"
+	"github.com/rehandwi03/test-case-backend-majoo/money"
+)
+
+// MarginReportResponse is the gross margin of the sales of an outlet, or of a product, with a line for every product,
+// or variant, sold. Margin is the gross profit as a percentage of the revenue, nil when nothing was sold.
+type MarginReportResponse struct {
+	OutletID    uuid.UUID        `json:"outlet_id"`
+	ProductID   *uuid.UUID       `json:"product_id,omitempty"`
+	Quantity    int64            `json:"quantity"`
+	Revenue     money.Money      `json:"revenue"`
+	Cost        money.Money      `json:"cost"`
+	GrossProfit money.Money      `json:"gross_profit"`
+	Margin      *float64         `json:"margin"`
+	Lines       []MarginResponse `json:"lines"`
+}
+
+type MarginResponse struct {
+	ProductID   uuid.UUID   `json:"product_id"`
+	VariantID   *uuid.UUID  `json:"variant_id,omitempty"`
+	Name        string      `json:"name"`
+	VariantName string      `json:"variant_name,omitempty"`
+	Quantity    int64       `json:"quantity"`
+	Revenue     money.Money `json:"revenue"`
+	Cost        money.Money `json:"cost"`
+	GrossProfit money.Money `json:"gross_profit"`
+	Margin      *float64    `json:"margin"`
+}
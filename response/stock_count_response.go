@@ -0,0 +1,36 @@
+package response
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// StockCountResponse sums up the lines of the count, which are only listed in its detail.
+type StockCountResponse struct {
+	ID                uuid.UUID                `json:"id"`
+	OutletID          uuid.UUID                `json:"outlet_id"`
+	Status            string                   `json:"status"`
+	Note              string                   `json:"note"`
+	CreatedBy         uuid.UUID                `json:"created_by"`
+	ApprovedBy        *uuid.UUID               `json:"approved_by,omitempty"`
+	ApprovedAt        *time.Time               `json:"approved_at,omitempty"`
+	TotalLines        int                      `json:"total_lines"`
+	CountedLines      int                      `json:"counted_lines"`
+	LinesWithVariance int                      `json:"lines_with_variance"`
+	Lines             []StockCountLineResponse `json:"lines,omitempty"`
+	CreatedAt         time.Time                `json:"created_at"`
+}
+
+// StockCountLineResponse has the counted quantity and the variance once the line is counted.
+type StockCountLineResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	ProductID   uuid.UUID  `json:"product_id"`
+	VariantID   *uuid.UUID `json:"variant_id,omitempty"`
+	Name        string     `json:"name"`
+	VariantName string     `json:"variant_name,omitempty"`
+	Expected    int64      `json:"expected"`
+	Counted     *int64     `json:"counted"`
+	Variance    *int64     `json:"variance"`
+	CountedBy   *uuid.UUID `json:"counted_by,omitempty"`
+	CountedAt   *time.Time `json:"counted_at,omitempty"`
+}
@@ -0,0 +1,27 @@
+package response
+
+import (
+	"github.com/google/uuid"
+	"github.com/rehandwi03/test-case-backend-majoo/money"
+	"time"
+)
+
+type StockMovementResponse struct {
+	ID                uuid.UUID    `json:"id"`
+	OutletID          uuid.UUID    `json:"outlet_id"`
+	ProductID         uuid.UUID    `json:"product_id"`
+	VariantID         *uuid.UUID   `json:"variant_id,omitempty"`
+	Type              string       `json:"type"`
+	Reason            string       `json:"reason,omitempty"`
+	Note              string       `json:"note,omitempty"`
+	Quantity          int64        `json:"quantity"`
+	Balance           int64        `json:"balance"`
+	TransactionID     *uuid.UUID   `json:"transaction_id,omitempty"`
+	TransactionItemID *uuid.UUID   `json:"transaction_item_id,omitempty"`
+	RefundID          *uuid.UUID   `json:"refund_id,omitempty"`
+	PurchaseOrderID   *uuid.UUID   `json:"purchase_order_id,omitempty"`
+	UnitCost          *money.Money `json:"unit_cost,omitempty"`
+	Cost              *money.Money `json:"cost,omitempty"`
+	UserID            *uuid.UUID   `json:"user_id,omitempty"`
+	CreatedAt         time.Time    `json:"created_at"`
+}
@@ -0,0 +1,40 @@
+package response
+
+import (
+	"github.com/google/uuid"
+	"github.com/rehandwi03/test-case-backend-majoo/money"
+	"time"
+)
+
+type StockTransferResponse struct {
+	ID                  uuid.UUID                   `json:"id"`
+	MerchantID          uuid.UUID                   `json:"merchant_id"`
+	SourceOutletID      uuid.UUID                   `json:"source_outlet_id"`
+	DestinationOutletID uuid.UUID                   `json:"destination_outlet_id"`
+	Status              string                      `json:"status"`
+	Note                string                      `json:"note"`
+	CreatedBy           uuid.UUID                   `json:"created_by"`
+	SentBy              *uuid.UUID                  `json:"sent_by,omitempty"`
+	SentAt              *time.Time                  `json:"sent_at,omitempty"`
+	Lines               []StockTransferLineResponse `json:"lines"`
+	CreatedAt           time.Time                   `json:"created_at"`
+}
+
+// StockTransferLineResponse has the product of the source outlet and the one with the same SKU it is received as in
+// the destination outlet.
+type StockTransferLineResponse struct {
+	ID                   uuid.UUID    `json:"id"`
+	ProductID            uuid.UUID    `json:"product_id"`
+	VariantID            *uuid.UUID   `json:"variant_id,omitempty"`
+	DestinationProductID uuid.UUID    `json:"destination_product_id"`
+	DestinationVariantID *uuid.UUID   `json:"destination_variant_id,omitempty"`
+	SKU                  string       `json:"sku"`
+	Name                 string       `json:"name"`
+	VariantName          string       `json:"variant_name,omitempty"`
+	Quantity             int64        `json:"quantity"`
+	Received             int64        `json:"received"`
+	Returned             int64        `json:"returned"`
+	Lost                 int64        `json:"lost"`
+	Outstanding          int64        `json:"outstanding"`
+	UnitCost             *money.Money `json:"unit_cost,omitempty"`
+}
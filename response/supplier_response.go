@@ -0,0 +1,19 @@
+package response
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type SupplierResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	MerchantID  uuid.UUID  `json:"merchant_id"`
+	Name        string     `json:"name"`
+	ContactName string     `json:"contact_name"`
+	Email       string     `json:"email"`
+	PhoneNumber string     `json:"phone_number"`
+	Address     string     `json:"address"`
+	Note        string     `json:"note"`
+	CreatedAt   time.Time  `json:"created_at"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+}
@@ -0,0 +1,34 @@
+package response
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type AreaResponse struct {
+	ID        uuid.UUID       `json:"id"`
+	OutletID  uuid.UUID       `json:"outlet_id"`
+	Name      string          `json:"name"`
+	SortOrder int             `json:"sort_order"`
+	Tables    []TableResponse `json:"tables"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// TableResponse is a table with the orders being served at it, open or parked, a table without is free.
+type TableResponse struct {
+	ID        uuid.UUID            `json:"id"`
+	OutletID  uuid.UUID            `json:"outlet_id"`
+	AreaID    *uuid.UUID           `json:"area_id"`
+	Name      string               `json:"name"`
+	Seats     int64                `json:"seats"`
+	SortOrder int                  `json:"sort_order"`
+	Orders    []TableOrderResponse `json:"orders"`
+	CreatedAt time.Time            `json:"created_at"`
+}
+
+type TableOrderResponse struct {
+	ID     uuid.UUID `json:"id"`
+	Name   string    `json:"name,omitempty"`
+	Guests int64     `json:"guests"`
+	Status string    `json:"status"`
+}
@@ -0,0 +1,113 @@
+package response
+
+import (
+	"github.com/google/uuid"
+	"github.com/rehandwi03/test-case-backend-majoo/money"
+	"time"
+)
+
+type TransactionResponse struct {
+	ID            uuid.UUID                    `json:"id"`
+	OutletID      uuid.UUID                    `json:"outlet_id"`
+	UserID        uuid.UUID                    `json:"user_id"`
+	OrderID       *uuid.UUID                   `json:"order_id,omitempty"`
+	Status        string                       `json:"status"`
+	TotalQuantity int64                        `json:"total_quantity"`
+	Total         money.Money                  `json:"total"`
+	Rounding      money.Money                  `json:"rounding"`
+	Paid          money.Money                  `json:"paid"`
+	Change        money.Money                  `json:"change"`
+	Items         []TransactionItemResponse    `json:"items"`
+	Payments      []TransactionPaymentResponse `json:"payments"`
+	Refunds       []RefundResponse             `json:"refunds"`
+	CreatedAt     time.Time                    `json:"created_at"`
+}
+
+// CheckoutResponse tells the cashier what was due after the cash rounding, the change to give back and how the
+// payments stand, a pending one has the action the customer pays it with. The sale is pending until it is paid.
+type CheckoutResponse struct {
+	TransactionID uuid.UUID                    `json:"transaction_id"`
+	Status        string                       `json:"status"`
+	Total         money.Money                  `json:"total"`
+	Rounding      money.Money                  `json:"rounding"`
+	Paid          money.Money                  `json:"paid"`
+	Change        money.Money                  `json:"change"`
+	Payments      []TransactionPaymentResponse `json:"payments"`
+}
+
+type TransactionItemResponse struct {
+	ID          uuid.UUID    `json:"id"`
+	ProductID   uuid.UUID    `json:"product_id"`
+	VariantID   *uuid.UUID   `json:"variant_id,omitempty"`
+	Name        string       `json:"name"`
+	VariantName string       `json:"variant_name,omitempty"`
+	Price       money.Money  `json:"price"`
+	Quantity    int64        `json:"quantity"`
+	Subtotal    money.Money  `json:"subtotal"`
+	Cost        *money.Money `json:"cost"`
+	// ReturnedQuantity is how many of the units were refunded.
+	ReturnedQuantity int64                              `json:"returned_quantity"`
+	Modifiers        []TransactionItemModifierResponse  `json:"modifiers"`
+	Components       []TransactionItemComponentResponse `json:"components,omitempty"`
+}
+
+type TransactionItemComponentResponse struct {
+	OptionID    uuid.UUID   `json:"option_id"`
+	SlotName    string      `json:"slot_name"`
+	ProductID   uuid.UUID   `json:"product_id"`
+	VariantID   *uuid.UUID  `json:"variant_id,omitempty"`
+	Name        string      `json:"name"`
+	VariantName string      `json:"variant_name,omitempty"`
+	Quantity    int64       `json:"quantity"`
+	PriceDelta  money.Money `json:"price_delta"`
+}
+
+type TransactionItemModifierResponse struct {
+	ModifierID uuid.UUID   `json:"modifier_id"`
+	Name       string      `json:"name"`
+	PriceDelta money.Money `json:"price_delta"`
+}
+
+type TransactionPaymentResponse struct {
+	ID        uuid.UUID   `json:"id"`
+	Method    string      `json:"method"`
+	Amount    money.Money `json:"amount"`
+	Reference string      `json:"reference,omitempty"`
+	Status    string      `json:"status"`
+	Provider  string      `json:"provider,omitempty"`
+	ChargeID  string      `json:"charge_id,omitempty"`
+	Action    string      `json:"action,omitempty"`
+	Refunded  money.Money `json:"refunded"`
+}
+
+// RefundResponse is a refund, or void, of a sale. Amount is what its payments gave back.
+type RefundResponse struct {
+	ID            uuid.UUID               `json:"id"`
+	TransactionID uuid.UUID               `json:"transaction_id"`
+	Kind          string                  `json:"kind"`
+	Reason        string                  `json:"reason"`
+	Amount        money.Money             `json:"amount"`
+	UserID        uuid.UUID               `json:"user_id"`
+	ApprovedBy    uuid.UUID               `json:"approved_by"`
+	Lines         []RefundLineResponse    `json:"lines"`
+	Payments      []RefundPaymentResponse `json:"payments"`
+	CreatedAt     time.Time               `json:"created_at"`
+}
+
+// RefundLineResponse returns units of an item of the sale, Cost is what the restocked ones were worth.
+type RefundLineResponse struct {
+	TransactionItemID uuid.UUID    `json:"transaction_item_id"`
+	Quantity          int64        `json:"quantity"`
+	Restock           bool         `json:"restock"`
+	Amount            money.Money  `json:"amount"`
+	Cost              *money.Money `json:"cost"`
+}
+
+// RefundPaymentResponse is given back by a payment, a refund through a payment provider is pending until the provider
+// took it, or failed when it won't.
+type RefundPaymentResponse struct {
+	PaymentID uuid.UUID   `json:"payment_id"`
+	Method    string      `json:"method"`
+	Amount    money.Money `json:"amount"`
+	Status    string      `json:"status"`
+}
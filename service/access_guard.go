@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"github.com/google/uuid"
+	custom_error "github.com/rehandwi03/test-case-backend-majoo/internal/error"
+	"github.com/rehandwi03/test-case-backend-majoo/internal/helper"
+	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"github.com/rehandwi03/test-case-backend-majoo/query"
+	"github.com/rehandwi03/test-case-backend-majoo/repository"
+	"gorm.io/gorm"
+)
+
+// AccessGuard resolves the merchant a resource belongs to (user → merchant → outlet → product, merchant → category)
+// and checks that the caller holds one of the given roles in it. Memberships are read from the database rather than
+// the token claims, so removing a user from a merchant takes effect immediately. Platform admins pass every check.
+type AccessGuard interface {
+	Merchant(ctx context.Context, merchantId uuid.UUID, roles ...string) (model.Merchant, error)
+	Outlet(ctx context.Context, outletId uuid.UUID, roles ...string) (model.Outlet, error)
+	Product(ctx context.Context, productId uuid.UUID, roles ...string) (model.Product, error)
+	Category(ctx context.Context, categoryId uuid.UUID, roles ...string) (model.Category, error)
+	// MerchantIDs returns the merchants the caller is a member of, all is true for platform admins whose
+	// queries must not be scoped.
+	MerchantIDs(ctx context.Context) (merchantIds []uuid.UUID, all bool, err error)
+	// IncludeDeleted returns a guard resolving soft deleted merchants, outlets and products too, for restoring them.
+	IncludeDeleted() AccessGuard
+}
+
+type accessGuard struct {
+	merchantRepo     repository.MerchantRepository
+	merchantUserRepo repository.MerchantUserRepository
+	outletRepo       repository.OutletRepository
+	productRepo      repository.ProductRepository
+	categoryRepo     repository.CategoryRepository
+	includeDeleted   bool
+}
+
+func NewAccessGuard(
+	merchantRepository repository.MerchantRepository, merchantUserRepository repository.MerchantUserRepository,
+	outletRepository repository.OutletRepository, productRepository repository.ProductRepository,
+	categoryRepository repository.CategoryRepository,
+) AccessGuard {
+	return &accessGuard{
+		merchantRepo: merchantRepository, merchantUserRepo: merchantUserRepository, outletRepo: outletRepository,
+		productRepo: productRepository, categoryRepo: categoryRepository,
+	}
+}
+
+func (a *accessGuard) Merchant(ctx context.Context, merchantId uuid.UUID, roles ...string) (model.Merchant, error) {
+	merchant, err := a.merchantRepo.Get(ctx, a.byID(merchantId))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return merchant, &custom_error.NotFoundError{Message: "merchant not found"}
+		}
+
+		return merchant, err
+	}
+
+	if helper.IsAdmin(ctx) {
+		return merchant, nil
+	}
+
+	userId, ok := ctx.Value("user_id").(uuid.UUID)
+	if !ok {
+		return merchant, &custom_error.NotFoundError{Message: "user id not found"}
+	}
+
+	membershipParams := query.Where(query.Eq("merchant_id", merchantId), query.Eq("user_id", userId))
+
+	membership, err := a.merchantUserRepo.Get(ctx, membershipParams)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return merchant, &custom_error.ForbiddenError{Message: "you aren't a member of this merchant"}
+		}
+
+		return merchant, err
+	}
+
+	for _, role := range roles {
+		if membership.Role == role {
+			return merchant, nil
+		}
+	}
+
+	return merchant, &custom_error.ForbiddenError{Message: "your role in this merchant isn't allowed to do this"}
+}
+
+func (a *accessGuard) Outlet(ctx context.Context, outletId uuid.UUID, roles ...string) (model.Outlet, error) {
+	outlet, err := a.outletRepo.Get(ctx, a.byID(outletId))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return outlet, &custom_error.NotFoundError{Message: "outlet not found"}
+		}
+
+		return outlet, err
+	}
+
+	_, err = a.Merchant(ctx, outlet.MerchantID, roles...)
+	if err != nil {
+		return outlet, err
+	}
+
+	return outlet, nil
+}
+
+func (a *accessGuard) Product(ctx context.Context, productId uuid.UUID, roles ...string) (model.Product, error) {
+	product, err := a.productRepo.Get(ctx, a.byID(productId))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return product, &custom_error.NotFoundError{Message: "product not found"}
+		}
+
+		return product, err
+	}
+
+	_, err = a.Outlet(ctx, product.OutletID, roles...)
+	if err != nil {
+		return product, err
+	}
+
+	return product, nil
+}
+
+func (a *accessGuard) Category(ctx context.Context, categoryId uuid.UUID, roles ...string) (model.Category, error) {
+	category, err := a.categoryRepo.Get(ctx, a.byID(categoryId))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return category, &custom_error.NotFoundError{Message: "category not found"}
+		}
+
+		return category, err
+	}
+
+	_, err = a.Merchant(ctx, category.MerchantID, roles...)
+	if err != nil {
+		return category, err
+	}
+
+	return category, nil
+}
+
+func (a *accessGuard) MerchantIDs(ctx context.Context) ([]uuid.UUID, bool, error) {
+	if helper.IsAdmin(ctx) {
+		return nil, true, nil
+	}
+
+	userId, ok := ctx.Value("user_id").(uuid.UUID)
+	if !ok {
+		return nil, false, &custom_error.NotFoundError{Message: "user id not found"}
+	}
+
+	params := query.Where(query.Eq("user_id", userId))
+
+	memberships, err := a.merchantUserRepo.List(ctx, params)
+	if err != nil {
+		return nil, false, err
+	}
+
+	merchantIds := []uuid.UUID{}
+	for _, membership := range memberships {
+		merchantIds = append(merchantIds, membership.MerchantID)
+	}
+
+	return merchantIds, false, nil
+}
+
+func (a *accessGuard) IncludeDeleted() AccessGuard {
+	guard := *a
+	guard.includeDeleted = true
+
+	return &guard
+}
+
+// byID selects a merchant, outlet or product. Memberships don't go through it, they aren't deleted along with their
+// merchant so a deleted one is a removed member.
+func (a *accessGuard) byID(id uuid.UUID) query.Spec {
+	spec := query.Where(query.Eq("id", id))
+	if a.includeDeleted {
+		spec = spec.WithDeleted()
+	}
+
+	return spec
+}
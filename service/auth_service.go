@@ -2,78 +2,278 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
 	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
 	custom_error "github.com/rehandwi03/test-case-backend-majoo/internal/error"
+	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"github.com/rehandwi03/test-case-backend-majoo/query"
 	"github.com/rehandwi03/test-case-backend-majoo/repository"
 	"github.com/rehandwi03/test-case-backend-majoo/request"
 	"gorm.io/gorm"
 	"log"
 	"os"
+	"strings"
 	"time"
 )
 
+const (
+	accessTokenTTL  = time.Minute * 15
+	refreshTokenTTL = time.Hour * 24 * 7
+)
+
 type AuthService interface {
 	Login(ctx context.Context, request *request.LoginRequest) (map[string]interface{}, error)
+	Refresh(ctx context.Context, request *request.RefreshTokenRequest) (map[string]interface{}, error)
+	Logout(ctx context.Context) error
+	LogoutAll(ctx context.Context) error
 }
 
 type authService struct {
-	userRepo repository.UserRepository
+	userRepo         repository.UserRepository
+	merchantUserRepo repository.MerchantUserRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+	revokedTokenRepo repository.RevokedTokenRepository
 }
 
-func NewAuthService(userRepository repository.UserRepository) AuthService {
-	return &authService{userRepo: userRepository}
+func NewAuthService(
+	userRepository repository.UserRepository, merchantUserRepository repository.MerchantUserRepository,
+	refreshTokenRepository repository.RefreshTokenRepository, revokedTokenRepository repository.RevokedTokenRepository,
+) AuthService {
+	return &authService{
+		userRepo: userRepository, merchantUserRepo: merchantUserRepository, refreshTokenRepo: refreshTokenRepository,
+		revokedTokenRepo: revokedTokenRepository,
+	}
 }
 
 func (a *authService) Login(ctx context.Context, request *request.LoginRequest) (map[string]interface{}, error) {
-	params := map[string]interface{}{
-		"where": map[string]interface{}{
-			"default": map[string]interface{}{
-				"email = ?": request.Email,
-			},
-		},
-	}
+	params := query.Where(query.Eq("email", request.Email))
 
-	checkUser, err := a.userRepo.GetByParam(ctx, params)
+	checkUser, err := a.userRepo.Get(ctx, params)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, &custom_error.BadRequest{"email or password is incorrect"}
+			return nil, &custom_error.BadRequest{Message: "email or password is incorrect"}
 		}
 
 		return nil, err
 	}
 
 	ok, err := checkUser.ComparePassword(request.Password)
+	if err != nil || !ok {
+		return nil, &custom_error.BadRequest{Message: "email or password is incorrect"}
+	}
+
+	// every login starts a new session, which is the family of all refresh tokens rotated from this one
+	resToken, refreshToken, err := a.issueTokens(ctx, checkUser, uuid.New())
 	if err != nil {
 		log.Println(err)
 		return nil, err
 	}
 
-	if !ok {
-		return nil, &custom_error.BadRequest{Message: "email or password is incorrect"}
+	_, err = a.refreshTokenRepo.Save(ctx, refreshToken)
+	if err != nil {
+		return nil, err
 	}
 
-	token, err := a.GenerateToken(ctx, checkUser.ID.String())
+	return resToken, nil
+}
+
+func (a *authService) Refresh(ctx context.Context, request *request.RefreshTokenRequest) (
+	map[string]interface{}, error,
+) {
+	// a refresh token is "<id>.<secret>", only the hash of the secret is stored
+	parts := strings.Split(request.RefreshToken, ".")
+	if len(parts) != 2 {
+		return nil, &custom_error.UnauthorizedError{Message: "refresh token is invalid"}
+	}
+
+	tokenId, err := uuid.Parse(parts[0])
 	if err != nil {
-		log.Println(err)
+		return nil, &custom_error.UnauthorizedError{Message: "refresh token is invalid"}
+	}
+
+	params := query.Where(query.Eq("id", tokenId))
+
+	current, err := a.refreshTokenRepo.Get(ctx, params)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, &custom_error.UnauthorizedError{Message: "refresh token is invalid"}
+		}
+
 		return nil, err
 	}
 
-	resToken := map[string]interface{}{
-		"access_token": token,
+	if subtle.ConstantTimeCompare([]byte(hashToken(parts[1])), []byte(current.TokenHash)) != 1 {
+		return nil, &custom_error.UnauthorizedError{Message: "refresh token is invalid"}
+	}
+
+	// a revoked token coming back means it leaked, so end the whole session it belongs to
+	if current.RevokedAt.Valid {
+		if err := a.revokeFamily(ctx, current.FamilyID); err != nil {
+			return nil, err
+		}
+
+		return nil, &custom_error.UnauthorizedError{Message: "refresh token has been revoked"}
+	}
+
+	if current.ExpiresAt.Before(time.Now()) {
+		return nil, &custom_error.UnauthorizedError{Message: "refresh token has expired"}
+	}
+
+	userParams := query.Where(query.Eq("id", current.UserID))
+
+	user, err := a.userRepo.Get(ctx, userParams)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, &custom_error.UnauthorizedError{Message: "user not found"}
+		}
+
+		return nil, err
+	}
+
+	resToken, next, err := a.issueTokens(ctx, user, current.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = a.refreshTokenRepo.Rotate(ctx, current, next)
+	if err != nil {
+		if err == repository.ErrTokenRevoked {
+			if err := a.revokeFamily(ctx, current.FamilyID); err != nil {
+				return nil, err
+			}
+
+			return nil, &custom_error.UnauthorizedError{Message: "refresh token has been revoked"}
+		}
+
+		return nil, err
 	}
 
 	return resToken, nil
 }
 
+func (a *authService) Logout(ctx context.Context) error {
+	sessionId, ok := ctx.Value("session_id").(uuid.UUID)
+	if !ok {
+		return &custom_error.NotFoundError{Message: "session id not found"}
+	}
+
+	err := a.revokeFamily(ctx, sessionId)
+	if err != nil {
+		return err
+	}
+
+	return a.revokeCurrentToken(ctx)
+}
+
+func (a *authService) LogoutAll(ctx context.Context) error {
+	userId, ok := ctx.Value("user_id").(uuid.UUID)
+	if !ok {
+		return &custom_error.NotFoundError{Message: "user id not found"}
+	}
+
+	err := RevokeUserSessions(ctx, a.refreshTokenRepo, userId)
+	if err != nil {
+		return err
+	}
+
+	return a.revokeCurrentToken(ctx)
+}
+
+// RevokeUserSessions ends every session of the user, including the access tokens already handed out.
+func RevokeUserSessions(
+	ctx context.Context, refreshTokenRepo repository.RefreshTokenRepository, userId uuid.UUID,
+) error {
+	params := query.Where(query.Eq("user_id", userId))
+
+	return refreshTokenRepo.Revoke(ctx, params)
+}
+
+func (a *authService) revokeFamily(ctx context.Context, familyId uuid.UUID) error {
+	params := query.Where(query.Eq("family_id", familyId))
+
+	return a.refreshTokenRepo.Revoke(ctx, params)
+}
+
+// revokeCurrentToken revokes the access token of the request, which may be older than the last refresh token of
+// its session.
+func (a *authService) revokeCurrentToken(ctx context.Context) error {
+	tokenId, ok := ctx.Value("token_id").(uuid.UUID)
+	if !ok {
+		return &custom_error.NotFoundError{Message: "token id not found"}
+	}
+
+	userId, _ := ctx.Value("user_id").(uuid.UUID)
+	expiresAt, _ := ctx.Value("token_expires_at").(time.Time)
+
+	return a.revokedTokenRepo.Save(
+		ctx, model.RevokedToken{
+			ID:        tokenId,
+			UserID:    userId,
+			ExpiresAt: expiresAt,
+		},
+	)
+}
+
+// issueTokens creates an access token and the refresh token paired with it in the given session. The refresh token
+// isn't stored yet, the caller decides whether it starts or continues a session.
+func (a *authService) issueTokens(ctx context.Context, user model.User, sessionId uuid.UUID) (
+	map[string]interface{}, model.RefreshToken, error,
+) {
+	accessTokenId := uuid.New()
+	accessTokenExpiresAt := time.Unix(time.Now().Add(accessTokenTTL).Unix(), 0)
+	accessToken, err := a.GenerateToken(ctx, user, accessTokenId, sessionId, accessTokenExpiresAt)
+	if err != nil {
+		return nil, model.RefreshToken{}, err
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, model.RefreshToken{}, err
+	}
+	encodedSecret := base64.RawURLEncoding.EncodeToString(secret)
+
+	refreshToken := model.RefreshToken{
+		ID:                   uuid.New(),
+		UserID:               user.ID,
+		FamilyID:             sessionId,
+		AccessTokenID:        accessTokenId,
+		AccessTokenExpiresAt: accessTokenExpiresAt,
+		TokenHash:            hashToken(encodedSecret),
+		ExpiresAt:            time.Now().Add(refreshTokenTTL),
+	}
+
+	resToken := map[string]interface{}{
+		"access_token":  accessToken,
+		"refresh_token": refreshToken.ID.String() + "." + encodedSecret,
+		"expires_in":    int64(accessTokenTTL.Seconds()),
+	}
+
+	return resToken, refreshToken, nil
+}
+
 func (a *authService) GenerateToken(
-	ctx context.Context, userId string,
+	ctx context.Context, user model.User, tokenId uuid.UUID, sessionId uuid.UUID, expiresAt time.Time,
 ) (token string, err error) {
-	exp := time.Now().Add(time.Hour * 15).Unix()
+	exp := expiresAt.Unix()
+
+	merchantRoles, err := a.merchantUserRepo.MerchantRoles(ctx, user.ID)
+	if err != nil {
+		return token, err
+	}
 
 	// create access token
 	acessTokenClaims := jwt.MapClaims{}
 	acessTokenClaims["authorized"] = true
-	acessTokenClaims["user_id"] = userId
+	acessTokenClaims["jti"] = tokenId.String()
+	acessTokenClaims["sid"] = sessionId.String()
+	acessTokenClaims["user_id"] = user.ID.String()
+	acessTokenClaims["role"] = user.Role
+	acessTokenClaims["merchant_roles"] = merchantRoles
 	acessTokenClaims["exp"] = exp
 
 	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, acessTokenClaims)
@@ -85,3 +285,9 @@ func (a *authService) GenerateToken(
 
 	return token, err
 }
+
+func hashToken(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+
+	return hex.EncodeToString(sum[:])
+}
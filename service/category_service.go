@@ -0,0 +1,299 @@
+package service
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/rehandwi03/test-case-backend-majoo/criteria"
+	custom_error "github.com/rehandwi03/test-case-backend-majoo/internal/error"
+	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"github.com/rehandwi03/test-case-backend-majoo/query"
+	"github.com/rehandwi03/test-case-backend-majoo/repository"
+	"github.com/rehandwi03/test-case-backend-majoo/request"
+	"github.com/rehandwi03/test-case-backend-majoo/response"
+	"github.com/rehandwi03/test-case-backend-majoo/util"
+	"gorm.io/gorm"
+)
+
+type CategoryService interface {
+	SaveCategory(ctx context.Context, request *request.CategoryAddRequest) (uuid.UUID, error)
+	UpdateCategory(ctx context.Context, request *request.CategoryUpdateRequest) (uuid.UUID, error)
+	DeleteCategory(ctx context.Context, spec query.Spec) error
+	GetByParam(ctx context.Context, spec query.Spec) (*response.CategoryResponse, error)
+	Fetch(ctx context.Context, criteria criteria.CategoryCriteria) (*util.PaginationResponse, error)
+	// Tree returns every category of the merchant nested below its parent, siblings ordered by sort order.
+	Tree(ctx context.Context, merchantId uuid.UUID) ([]response.CategoryTreeResponse, error)
+}
+
+type categoryService struct {
+	categoryRepo repository.CategoryRepository
+	accessGuard  AccessGuard
+}
+
+func NewCategoryService(categoryRepository repository.CategoryRepository, accessGuard AccessGuard) CategoryService {
+	return &categoryService{categoryRepo: categoryRepository, accessGuard: accessGuard}
+}
+
+func (c *categoryService) SaveCategory(ctx context.Context, request *request.CategoryAddRequest) (uuid.UUID, error) {
+	_, err := c.accessGuard.Merchant(ctx, request.MerchantID, model.RoleOwner, model.RoleManager)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	err = c.checkParent(ctx, request.MerchantID, uuid.Nil, request.ParentID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	res, err := c.categoryRepo.Save(
+		ctx, model.Category{
+			MerchantID: request.MerchantID,
+			ParentID:   request.ParentID,
+			Name:       request.Name,
+			SortOrder:  request.SortOrder,
+		},
+	)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return res, nil
+}
+
+func (c *categoryService) UpdateCategory(ctx context.Context, request *request.CategoryUpdateRequest) (
+	uuid.UUID, error,
+) {
+	categoryData, err := c.accessGuard.Category(ctx, request.ID, model.RoleOwner, model.RoleManager)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	err = c.checkParent(ctx, categoryData.MerchantID, categoryData.ID, request.ParentID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	res, err := c.categoryRepo.Save(
+		ctx, model.Category{
+			ID:         categoryData.ID,
+			MerchantID: categoryData.MerchantID,
+			ParentID:   request.ParentID,
+			Name:       request.Name,
+			SortOrder:  request.SortOrder,
+			Audit: model.Audit{
+				CreatedAt: categoryData.CreatedAt,
+			},
+		},
+	)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return res, nil
+}
+
+func (c *categoryService) DeleteCategory(ctx context.Context, spec query.Spec) error {
+	categoryData, err := c.categoryRepo.Get(ctx, spec)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return &custom_error.NotFoundError{Message: "category not found"}
+		}
+
+		return err
+	}
+
+	_, err = c.accessGuard.Category(ctx, categoryData.ID, model.RoleOwner, model.RoleManager)
+	if err != nil {
+		return err
+	}
+
+	// deleting a parent would leave its children hanging in the tree
+	children, err := c.categoryRepo.Count(ctx, query.Where(query.Eq("parent_id", categoryData.ID)))
+	if err != nil {
+		return err
+	}
+
+	if children > 0 {
+		return &custom_error.ConflictError{Message: "move or delete the sub categories first"}
+	}
+
+	err = c.categoryRepo.Delete(ctx, &categoryData)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *categoryService) GetByParam(ctx context.Context, spec query.Spec) (*response.CategoryResponse, error) {
+	categoryData, err := c.categoryRepo.Get(ctx, spec)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, &custom_error.NotFoundError{Message: "category not found"}
+		}
+
+		return nil, err
+	}
+
+	_, err = c.accessGuard.Category(
+		ctx, categoryData.ID, model.RoleOwner, model.RoleManager, model.RoleCashier,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	response := categoryResponse(categoryData)
+
+	return &response, nil
+}
+
+func (c *categoryService) Fetch(ctx context.Context, criteria criteria.CategoryCriteria) (
+	*util.PaginationResponse, error,
+) {
+	merchantIds, all, err := c.accessGuard.MerchantIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := query.Where()
+	if !all {
+		spec = spec.And(query.In("merchant_id", merchantIds))
+	}
+	if criteria.MerchantID != "" {
+		merchantId, err := uuid.Parse(criteria.MerchantID)
+		if err != nil {
+			return nil, &custom_error.BadRequest{Message: "merchant_id must be an uuid", Field: "merchant_id"}
+		}
+
+		spec = spec.And(query.Eq("merchant_id", merchantId))
+	}
+	if criteria.ParentID != "" {
+		parentId, err := uuid.Parse(criteria.ParentID)
+		if err != nil {
+			return nil, &custom_error.BadRequest{Message: "parent_id must be an uuid", Field: "parent_id"}
+		}
+
+		spec = spec.And(query.Eq("parent_id", parentId))
+	}
+	if criteria.Name != "" {
+		spec = spec.And(query.Contains("name", criteria.Name))
+	}
+
+	spec, err = paginate(spec, &criteria.Pagination, "name", "sort_order", "created_at")
+	if err != nil {
+		return nil, err
+	}
+
+	res, rowCount, err := c.categoryRepo.Fetch(ctx, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	var responseData []response.CategoryResponse
+	for _, val := range res {
+		responseData = append(responseData, categoryResponse(val))
+	}
+
+	resPagination := util.BuildPagination(criteria.Pagination, responseData, rowCount)
+
+	return &resPagination, nil
+}
+
+func (c *categoryService) Tree(ctx context.Context, merchantId uuid.UUID) ([]response.CategoryTreeResponse, error) {
+	_, err := c.accessGuard.Merchant(ctx, merchantId, model.RoleOwner, model.RoleManager, model.RoleCashier)
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := c.categoryRepo.List(
+		ctx, query.Where(query.Eq("merchant_id", merchantId)).OrderBy(
+			query.Sort{Field: "sort_order"}, query.Sort{Field: "name"},
+		),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := map[uuid.UUID]bool{}
+	children := map[uuid.UUID][]model.Category{}
+	var roots []model.Category
+	for _, category := range categories {
+		ids[category.ID] = true
+	}
+	for _, category := range categories {
+		if category.ParentID == nil || !ids[*category.ParentID] {
+			roots = append(roots, category)
+			continue
+		}
+
+		children[*category.ParentID] = append(children[*category.ParentID], category)
+	}
+
+	return categoryTree(roots, children), nil
+}
+
+// checkParent makes sure the parent belongs to the same merchant and, when moving an existing category, isn't the
+// category itself or one of its descendants, which would cut that branch off the tree.
+func (c *categoryService) checkParent(
+	ctx context.Context, merchantId uuid.UUID, categoryId uuid.UUID, parentId *uuid.UUID,
+) error {
+	for parentId != nil {
+		if *parentId == categoryId {
+			return &custom_error.BadRequest{
+				Message: "a category can't be moved below itself", Field: "parent_id",
+			}
+		}
+
+		parent, err := c.categoryRepo.Get(
+			ctx, query.Where(query.Eq("id", *parentId), query.Eq("merchant_id", merchantId)),
+		)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return &custom_error.BadRequest{Message: "parent category not found", Field: "parent_id"}
+			}
+
+			return err
+		}
+
+		parentId = parent.ParentID
+	}
+
+	return nil
+}
+
+func categoryTree(
+	categories []model.Category, children map[uuid.UUID][]model.Category,
+) []response.CategoryTreeResponse {
+	tree := []response.CategoryTreeResponse{}
+	for _, category := range categories {
+		tree = append(
+			tree, response.CategoryTreeResponse{
+				ID:        category.ID,
+				Name:      category.Name,
+				SortOrder: category.SortOrder,
+				Children:  categoryTree(children[category.ID], children),
+			},
+		)
+	}
+
+	return tree
+}
+
+func categoryResponse(category model.Category) response.CategoryResponse {
+	return response.CategoryResponse{
+		ID:         category.ID,
+		MerchantID: category.MerchantID,
+		ParentID:   category.ParentID,
+		Name:       category.Name,
+		SortOrder:  category.SortOrder,
+		CreatedAt:  category.CreatedAt.Time,
+	}
+}
+
+func categoryResponses(categories []model.Category) []response.CategoryResponse {
+	responses := []response.CategoryResponse{}
+	for _, category := range categories {
+		responses = append(responses, categoryResponse(category))
+	}
+
+	return responses
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	custom_error "github.com/rehandwi03/test-case-backend-majoo/internal/error"
+	"github.com/rehandwi03/test-case-backend-majoo/internal/helper"
+	"github.com/rehandwi03/test-case-backend-majoo/query"
+	"strconv"
+	"time"
+)
+
+// The criteria filters come straight from the query string, these parse them into values the query builder can
+// compare against. An empty filter is returned as nil, which Between and the spec treat as "not filtered".
+
+func parseIntFilter(name string, value string) (interface{}, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil, &custom_error.BadRequest{Message: fmt.Sprintf("%s must be an integer", name)}
+	}
+
+	return parsed, nil
+}
+
+// parseTimeFilter takes a date or an RFC 3339 time. A date is the start of that day in UTC, or its last
+// microsecond, the resolution of Postgres timestamps, when endOfDay is set so the bound covers the whole day.
+func parseTimeFilter(name string, value string, endOfDay bool) (interface{}, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err == nil {
+		return parsed, nil
+	}
+
+	parsed, err = time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, &custom_error.BadRequest{
+			Message: fmt.Sprintf("%s must be a date like 2006-01-02 or an RFC 3339 time", name), Field: name,
+		}
+	}
+
+	if endOfDay {
+		parsed = parsed.AddDate(0, 0, 1).Add(-time.Microsecond)
+	}
+
+	return parsed, nil
+}
+
+func parseUUIDsFilter(name string, values []string) ([]uuid.UUID, error) {
+	var parsed []uuid.UUID
+	for _, value := range values {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return nil, &custom_error.BadRequest{Message: fmt.Sprintf("%s must be a list of uuid", name)}
+		}
+
+		parsed = append(parsed, id)
+	}
+
+	return parsed, nil
+}
+
+// includeDeleted lets admins list soft deleted rows along with the live ones.
+func includeDeleted(ctx context.Context, spec query.Spec, include bool) (query.Spec, error) {
+	if !include {
+		return spec, nil
+	}
+
+	if !helper.IsAdmin(ctx) {
+		return spec, &custom_error.ForbiddenError{Message: "only admin can list deleted data"}
+	}
+
+	return spec.WithDeleted(), nil
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"github.com/rehandwi03/test-case-backend-majoo/repository"
+	"log"
+	"time"
+)
+
+const (
+	// lowStockLookback is how far back the first scan after a start reads the ledger, so movements posted while the
+	// application was down are still notified of.
+	lowStockLookback = 24 * time.Hour
+	// lowStockOverlap is read again by every scan, a movement is stamped before its transaction commits so it can
+	// show up in the ledger after a scan already passed its time.
+	lowStockOverlap = time.Minute
+)
+
+// LowStockScanner watches the stock ledger in the background and raises a low stock notification for every sale,
+// adjustment or other movement taking the stock of a product, or variant, down to the reorder point of the product.
+// Notifications are stored once per movement, so scans can overlap.
+type LowStockScanner struct {
+	notificationRepo repository.NotificationRepository
+	interval         time.Duration
+}
+
+func NewLowStockScanner(
+	notificationRepository repository.NotificationRepository, interval time.Duration,
+) *LowStockScanner {
+	return &LowStockScanner{notificationRepo: notificationRepository, interval: interval}
+}
+
+// Run scans the ledger every interval until the context is done.
+func (l *LowStockScanner) Run(ctx context.Context) {
+	ticker := time.NewTicker(l.interval)
+	defer ticker.Stop()
+
+	since := time.Now().Add(-lowStockLookback)
+	for {
+		scannedAt := time.Now()
+		err := l.scan(ctx, since)
+		if err != nil {
+			log.Printf("error scanning for low stock: %v", err)
+		} else {
+			since = scannedAt.Add(-lowStockOverlap)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (l *LowStockScanner) scan(ctx context.Context, since time.Time) error {
+	notifications, err := l.notificationRepo.LowStock(ctx, since)
+	if err != nil {
+		return err
+	}
+
+	for i := range notifications {
+		notified, err := l.notificationRepo.Notify(ctx, &notifications[i])
+		if err != nil {
+			return err
+		}
+
+		if notified {
+			log.Printf("low stock in outlet %s: %s", notifications[i].OutletID, notifications[i].Message)
+		}
+	}
+
+	return nil
+}
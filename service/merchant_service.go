@@ -6,6 +6,8 @@ import (
 	"github.com/rehandwi03/test-case-backend-majoo/criteria"
 	custom_error "github.com/rehandwi03/test-case-backend-majoo/internal/error"
 	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"github.com/rehandwi03/test-case-backend-majoo/money"
+	"github.com/rehandwi03/test-case-backend-majoo/query"
 	"github.com/rehandwi03/test-case-backend-majoo/repository"
 	"github.com/rehandwi03/test-case-backend-majoo/request"
 	"github.com/rehandwi03/test-case-backend-majoo/response"
@@ -16,21 +18,30 @@ import (
 type MerchantService interface {
 	SaveMerchant(ctx context.Context, request *request.MerchantAddRequest) (uuid.UUID, error)
 	UpdateMerchant(ctx context.Context, request *request.MerchantUpdateRequest) (uuid.UUID, error)
-	DeleteMerchant(ctx context.Context, params map[string]interface{}) error
-	GetByParam(ctx context.Context, params map[string]interface{}) (*response.MerchantResponse, error)
+	DeleteMerchant(ctx context.Context, spec query.Spec) error
+	RestoreMerchant(ctx context.Context, merchantId uuid.UUID) error
+	GetByParam(ctx context.Context, spec query.Spec) (*response.MerchantResponse, error)
 	Fetch(ctx context.Context, MerchantCriteria criteria.MerchantCriteria) (*util.PaginationResponse, error)
+	SaveMerchantUser(ctx context.Context, request *request.MerchantUserAddRequest) (uuid.UUID, error)
+	DeleteMerchantUser(ctx context.Context, merchantId uuid.UUID, userId uuid.UUID) error
 }
 
 type merchantService struct {
-	merchantRepo repository.MerchantRepository
-	userRepo     repository.UserRepository
-	userID       uuid.UUID
+	merchantRepo     repository.MerchantRepository
+	userRepo         repository.UserRepository
+	merchantUserRepo repository.MerchantUserRepository
+	accessGuard      AccessGuard
+	userID           uuid.UUID
 }
 
 func NewMerchantService(
 	merchantRepository repository.MerchantRepository, userRepository repository.UserRepository,
+	merchantUserRepository repository.MerchantUserRepository, accessGuard AccessGuard,
 ) MerchantService {
-	return &merchantService{merchantRepo: merchantRepository, userRepo: userRepository}
+	return &merchantService{
+		merchantRepo: merchantRepository, userRepo: userRepository, merchantUserRepo: merchantUserRepository,
+		accessGuard: accessGuard,
+	}
 }
 
 func (m *merchantService) SaveMerchant(ctx context.Context, request *request.MerchantAddRequest) (uuid.UUID, error) {
@@ -39,14 +50,8 @@ func (m *merchantService) SaveMerchant(ctx context.Context, request *request.Mer
 		return uuid.Nil, &custom_error.NotFoundError{Message: "user id not found"}
 	}
 
-	params := map[string]interface{}{
-		"where": map[string]interface{}{
-			"default": map[string]interface{}{
-				"id = ?": userId,
-			},
-		},
-	}
-	_, err := m.userRepo.GetByParams(ctx, params)
+	params := query.Where(query.Eq("id", userId))
+	_, err := m.userRepo.List(ctx, params)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return uuid.Nil, &custom_error.NotFoundError{Message: "user not found"}
@@ -55,12 +60,47 @@ func (m *merchantService) SaveMerchant(ctx context.Context, request *request.Mer
 		return uuid.Nil, err
 	}
 
-	res, err := m.merchantRepo.Save(
-		ctx, model.Merchant{
-			UserID:          userId,
-			Name:            request.Name,
-			InstitutionName: request.InstitutionName,
-			PhoneNumber:     request.PhoneNumber,
+	currency := request.Currency
+	if currency == "" {
+		currency = money.DefaultCurrency
+	}
+	if !money.Supported(currency) {
+		return uuid.Nil, &custom_error.BadRequest{Message: "currency isn't supported", Field: "currency"}
+	}
+
+	costingMethod := request.CostingMethod
+	if costingMethod == "" {
+		costingMethod = model.CostingAverage
+	}
+
+	merchant := model.Merchant{
+		UserID:           userId,
+		Name:             request.Name,
+		InstitutionName:  request.InstitutionName,
+		PhoneNumber:      request.PhoneNumber,
+		Currency:         currency,
+		CostingMethod:    costingMethod,
+		CashRoundingMode: model.CashRoundNearest,
+	}
+	for _, method := range model.PaymentMethods {
+		merchant.PaymentMethods = append(merchant.PaymentMethods, model.MerchantPaymentMethod{Method: method})
+	}
+
+	err = applyPaymentSettings(&merchant, request.PaymentSettings)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	res, err := m.merchantRepo.Save(ctx, merchant)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	_, err = m.merchantUserRepo.Save(
+		ctx, model.MerchantUser{
+			MerchantID: res,
+			UserID:     userId,
+			Role:       model.RoleOwner,
 		},
 	)
 	if err != nil {
@@ -77,25 +117,10 @@ func (m *merchantService) UpdateMerchant(ctx context.Context, request *request.M
 	if !ok {
 		return uuid.Nil, &custom_error.NotFoundError{Message: "user id not found"}
 	}
-	merchantParams := map[string]interface{}{
-		"where": map[string]interface{}{
-			"or": map[string]interface{}{},
-			"default": map[string]interface{}{
-				"id = ?":      request.ID,
-				"user_id = ?": userId,
-			},
-		},
-	}
 
-	userParams := map[string]interface{}{
-		"where": map[string]interface{}{
-			"default": map[string]interface{}{
-				"id = ?": userId,
-			},
-		},
-	}
+	userParams := query.Where(query.Eq("id", userId))
 
-	_, err := m.userRepo.GetByParam(ctx, userParams)
+	_, err := m.userRepo.Get(ctx, userParams)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return uuid.Nil, &custom_error.NotFoundError{Message: "user not found"}
@@ -104,27 +129,46 @@ func (m *merchantService) UpdateMerchant(ctx context.Context, request *request.M
 		return uuid.Nil, err
 	}
 
-	merchantData, err := m.merchantRepo.GetByParam(ctx, merchantParams)
+	merchantData, err := m.accessGuard.Merchant(ctx, request.ID, model.RoleOwner)
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return uuid.Nil, &custom_error.NotFoundError{Message: err.Error()}
+		return uuid.Nil, err
+	}
+
+	// every amount of the merchant is kept in its currency, so it can't change afterwards
+	if request.Currency != "" && request.Currency != merchantData.Currency {
+		return uuid.Nil, &custom_error.ConflictError{
+			Message: "the currency of a merchant can't be changed", Field: "currency",
 		}
+	}
 
-		return uuid.Nil, err
+	// the stock is valued both ways all along, so the method can be switched any time and applies to later sales
+	costingMethod := request.CostingMethod
+	if costingMethod == "" {
+		costingMethod = merchantData.CostingMethod
 	}
 
-	res, err := m.merchantRepo.Save(
-		ctx, model.Merchant{
-			ID:              merchantData.ID,
-			UserID:          userId,
-			Name:            request.Name,
-			InstitutionName: request.InstitutionName,
-			PhoneNumber:     request.PhoneNumber,
-			Audit: model.Audit{
-				CreatedAt: merchantData.CreatedAt,
-			},
+	merchant := model.Merchant{
+		ID:               merchantData.ID,
+		UserID:           merchantData.UserID,
+		Name:             request.Name,
+		InstitutionName:  request.InstitutionName,
+		PhoneNumber:      request.PhoneNumber,
+		Currency:         merchantData.Currency,
+		CostingMethod:    costingMethod,
+		CashRounding:     merchantData.CashRounding,
+		CashRoundingMode: merchantData.CashRoundingMode,
+		PaymentMethods:   merchantData.PaymentMethods,
+		Audit: model.Audit{
+			CreatedAt: merchantData.CreatedAt,
 		},
-	)
+	}
+
+	err = applyPaymentSettings(&merchant, request.PaymentSettings)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	res, err := m.merchantRepo.Save(ctx, merchant)
 	if err != nil {
 		return uuid.Nil, err
 	}
@@ -132,8 +176,8 @@ func (m *merchantService) UpdateMerchant(ctx context.Context, request *request.M
 	return res, nil
 }
 
-func (m *merchantService) DeleteMerchant(ctx context.Context, params map[string]interface{}) error {
-	MerchantData, err := m.merchantRepo.GetByParam(ctx, params)
+func (m *merchantService) DeleteMerchant(ctx context.Context, spec query.Spec) error {
+	MerchantData, err := m.merchantRepo.Get(ctx, spec)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return &custom_error.NotFoundError{Message: err.Error()}
@@ -142,6 +186,11 @@ func (m *merchantService) DeleteMerchant(ctx context.Context, params map[string]
 		return err
 	}
 
+	_, err = m.accessGuard.Merchant(ctx, MerchantData.ID, model.RoleOwner)
+	if err != nil {
+		return err
+	}
+
 	err = m.merchantRepo.Delete(ctx, &MerchantData)
 	if err != nil {
 		return err
@@ -150,10 +199,33 @@ func (m *merchantService) DeleteMerchant(ctx context.Context, params map[string]
 	return nil
 }
 
-func (m *merchantService) GetByParam(ctx context.Context, params map[string]interface{}) (
+// RestoreMerchant brings back a deleted merchant together with everything deleted along with it.
+func (m *merchantService) RestoreMerchant(ctx context.Context, merchantId uuid.UUID) error {
+	merchant, err := m.accessGuard.IncludeDeleted().Merchant(ctx, merchantId, model.RoleOwner)
+	if err != nil {
+		return err
+	}
+
+	if !merchant.DeletedAt.Valid {
+		return &custom_error.ConflictError{Message: "merchant isn't deleted"}
+	}
+
+	err = m.merchantRepo.Restore(ctx, merchant.ID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return &custom_error.ConflictError{Message: "merchant isn't deleted"}
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (m *merchantService) GetByParam(ctx context.Context, spec query.Spec) (
 	*response.MerchantResponse, error,
 ) {
-	merchantData, err := m.merchantRepo.GetByParam(ctx, params)
+	merchantData, err := m.merchantRepo.Get(ctx, spec)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, &custom_error.NotFoundError{Message: "merchant not found"}
@@ -162,12 +234,22 @@ func (m *merchantService) GetByParam(ctx context.Context, params map[string]inte
 		return nil, err
 	}
 
+	_, err = m.accessGuard.Merchant(ctx, merchantData.ID, model.RoleOwner, model.RoleManager, model.RoleCashier)
+	if err != nil {
+		return nil, err
+	}
+
 	response := new(response.MerchantResponse)
 	response.ID = merchantData.ID
 	response.UserID = merchantData.UserID
 	response.Name = merchantData.Name
 	response.InstitutionName = merchantData.InstitutionName
 	response.PhoneNumber = merchantData.PhoneNumber
+	response.Currency = merchantData.Currency
+	response.CostingMethod = merchantData.CostingMethod
+	response.PaymentMethods = paymentMethodNames(merchantData)
+	response.CashRounding = merchantData.CashRounding
+	response.CashRoundingMode = merchantData.CashRoundingMode
 	response.CreatedAt = merchantData.CreatedAt.Time
 
 	return response, nil
@@ -176,36 +258,33 @@ func (m *merchantService) GetByParam(ctx context.Context, params map[string]inte
 func (m *merchantService) Fetch(ctx context.Context, criteria criteria.MerchantCriteria) (
 	*util.PaginationResponse, error,
 ) {
-	userId, ok := ctx.Value("user_id").(uuid.UUID)
-	if !ok {
-		return nil, &custom_error.NotFoundError{Message: "user id not found"}
-	}
-
-	params := map[string]interface{}{
-		"where": map[string]interface{}{
-			"default": map[string]interface{}{
-				"user_id = ?": userId,
-			},
-			"pagination": map[string]interface{}{
-				"page":  criteria.Pagination.Page,
-				"sort":  criteria.Pagination.Sort,
-				"limit": criteria.Pagination.Limit,
-			},
-		},
+	merchantIds, all, err := m.accessGuard.MerchantIDs(ctx)
+	if err != nil {
+		return nil, err
 	}
 
+	spec := query.Where()
+	if !all {
+		spec = spec.And(query.In("id", merchantIds))
+	}
 	if criteria.Name != "" {
-		params["where"].(map[string]interface{})["default"] = map[string]interface{}{
-			"name ILIKE ?": "%" + criteria.Name + "%",
-		}
+		spec = spec.And(query.Contains("name", criteria.Name))
 	}
 	if criteria.InstitutionName != "" {
-		params["where"].(map[string]interface{})["default"] = map[string]interface{}{
-			"institution_name ILIKE ?": "%" + criteria.InstitutionName + "%",
-		}
+		spec = spec.And(query.Contains("institution_name", criteria.InstitutionName))
+	}
+
+	spec, err = includeDeleted(ctx, spec, criteria.IncludeDeleted)
+	if err != nil {
+		return nil, err
+	}
+
+	spec, err = paginate(spec, &criteria.Pagination, "name", "institution_name", "created_at")
+	if err != nil {
+		return nil, err
 	}
 
-	res, rowCount, err := m.merchantRepo.Fetch(ctx, params)
+	res, rowCount, err := m.merchantRepo.Fetch(ctx, spec)
 	if err != nil {
 		return nil, err
 	}
@@ -219,7 +298,16 @@ func (m *merchantService) Fetch(ctx context.Context, criteria criteria.MerchantC
 		data.Name = val.Name
 		data.InstitutionName = val.InstitutionName
 		data.PhoneNumber = val.PhoneNumber
+		data.Currency = val.Currency
+		data.CostingMethod = val.CostingMethod
+		data.PaymentMethods = paymentMethodNames(val)
+		data.CashRounding = val.CashRounding
+		data.CashRoundingMode = val.CashRoundingMode
 		data.CreatedAt = val.CreatedAt.Time
+		if val.DeletedAt.Valid {
+			deletedAt := val.DeletedAt.Time
+			data.DeletedAt = &deletedAt
+		}
 
 		responseData = append(responseData, data)
 	}
@@ -228,3 +316,115 @@ func (m *merchantService) Fetch(ctx context.Context, criteria criteria.MerchantC
 
 	return &resPagination, nil
 }
+
+func (m *merchantService) SaveMerchantUser(ctx context.Context, request *request.MerchantUserAddRequest) (
+	uuid.UUID, error,
+) {
+	_, err := m.accessGuard.Merchant(ctx, request.MerchantID, model.RoleOwner)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	userParams := query.Where(query.Eq("id", request.UserID))
+
+	_, err = m.userRepo.Get(ctx, userParams)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return uuid.Nil, &custom_error.NotFoundError{Message: "user not found"}
+		}
+
+		return uuid.Nil, err
+	}
+
+	// a user holds a single role per merchant, assigning again replaces it
+	merchantUser := model.MerchantUser{
+		MerchantID: request.MerchantID,
+		UserID:     request.UserID,
+		Role:       request.Role,
+	}
+
+	membershipParams := query.Where(query.Eq("merchant_id", request.MerchantID), query.Eq("user_id", request.UserID))
+
+	membership, err := m.merchantUserRepo.Get(ctx, membershipParams)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return uuid.Nil, err
+	}
+
+	if err == nil {
+		merchantUser.ID = membership.ID
+		merchantUser.Audit = model.Audit{CreatedAt: membership.CreatedAt}
+	}
+
+	res, err := m.merchantUserRepo.Save(ctx, merchantUser)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return res, nil
+}
+
+func (m *merchantService) DeleteMerchantUser(ctx context.Context, merchantId uuid.UUID, userId uuid.UUID) error {
+	_, err := m.accessGuard.Merchant(ctx, merchantId, model.RoleOwner)
+	if err != nil {
+		return err
+	}
+
+	params := query.Where(query.Eq("merchant_id", merchantId), query.Eq("user_id", userId))
+
+	membership, err := m.merchantUserRepo.Get(ctx, params)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return &custom_error.NotFoundError{Message: "merchant user not found"}
+		}
+
+		return err
+	}
+
+	err = m.merchantUserRepo.Delete(ctx, &membership)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// applyPaymentSettings changes the payment settings of the merchant to the ones sent, a merchant has to take at least
+// one payment method.
+func applyPaymentSettings(merchant *model.Merchant, settings request.PaymentSettings) error {
+	if settings.PaymentMethods != nil {
+		if len(settings.PaymentMethods) == 0 {
+			return &custom_error.BadRequest{
+				Message: "enable at least one payment method", Field: "payment_methods",
+			}
+		}
+
+		enabled := map[string]bool{}
+		merchant.PaymentMethods = nil
+		for _, method := range settings.PaymentMethods {
+			if enabled[method] {
+				continue
+			}
+
+			enabled[method] = true
+			merchant.PaymentMethods = append(merchant.PaymentMethods, model.MerchantPaymentMethod{Method: method})
+		}
+	}
+
+	if settings.CashRounding != nil {
+		merchant.CashRounding = *settings.CashRounding
+	}
+	if settings.CashRoundingMode != "" {
+		merchant.CashRoundingMode = settings.CashRoundingMode
+	}
+
+	return nil
+}
+
+func paymentMethodNames(merchant model.Merchant) []string {
+	methods := []string{}
+	for _, method := range merchant.PaymentMethods {
+		methods = append(methods, method.Method)
+	}
+
+	return methods
+}
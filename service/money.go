@@ -0,0 +1,38 @@
+package service
+
+import (
+	custom_error "github.com/rehandwi03/test-case-backend-majoo/internal/error"
+	"github.com/rehandwi03/test-case-backend-majoo/money"
+)
+
+// inCurrency checks that an amount of a request is in the currency of the merchant, an amount sent without currency
+// is taken to be in it.
+func inCurrency(amount money.Money, currency string, field string) (money.Money, error) {
+	if amount.Currency == "" {
+		amount.Currency = currency
+	}
+
+	if amount.Currency != currency {
+		return amount, &custom_error.BadRequest{Message: field + " has to be in " + currency, Field: field}
+	}
+
+	return amount, nil
+}
+
+// priceIn is inCurrency for prices, which can't be negative.
+func priceIn(amount money.Money, currency string, field string) (money.Money, error) {
+	if amount.Amount < 0 {
+		return amount, &custom_error.BadRequest{Message: field + " can't be negative", Field: field}
+	}
+
+	return inCurrency(amount, currency, field)
+}
+
+// optionalMoney answers an amount that may not be known yet, like a cost price, as null rather than a zero amount.
+func optionalMoney(amount money.Money) *money.Money {
+	if !amount.IsSet() {
+		return nil
+	}
+
+	return &amount
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/rehandwi03/test-case-backend-majoo/criteria"
+	custom_error "github.com/rehandwi03/test-case-backend-majoo/internal/error"
+	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"github.com/rehandwi03/test-case-backend-majoo/query"
+	"github.com/rehandwi03/test-case-backend-majoo/repository"
+	"github.com/rehandwi03/test-case-backend-majoo/response"
+	"github.com/rehandwi03/test-case-backend-majoo/util"
+)
+
+type NotificationService interface {
+	// LowStock reports the products of the outlet, or the variants of them, whose stock is at or below the reorder
+	// point of the product.
+	LowStock(ctx context.Context, outletId uuid.UUID) ([]response.LowStockResponse, error)
+	Fetch(ctx context.Context, criteria criteria.NotificationCriteria) (*util.PaginationResponse, error)
+}
+
+type notificationService struct {
+	notificationRepo repository.NotificationRepository
+	productRepo      repository.ProductRepository
+	accessGuard      AccessGuard
+}
+
+func NewNotificationService(
+	notificationRepository repository.NotificationRepository, productRepository repository.ProductRepository,
+	accessGuard AccessGuard,
+) NotificationService {
+	return &notificationService{
+		notificationRepo: notificationRepository, productRepo: productRepository, accessGuard: accessGuard,
+	}
+}
+
+func (n *notificationService) LowStock(ctx context.Context, outletId uuid.UUID) ([]response.LowStockResponse, error) {
+	_, err := n.accessGuard.Outlet(ctx, outletId, model.RoleOwner, model.RoleManager, model.RoleCashier)
+	if err != nil {
+		return nil, err
+	}
+
+	products, err := n.productRepo.List(
+		ctx, query.Where(query.Eq("outlet_id", outletId), query.NotNull("reorder_point")).
+			OrderBy(query.Sort{Field: "name"}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	responseData := []response.LowStockResponse{}
+	for _, product := range products {
+		low := response.LowStockResponse{
+			ProductID:       product.ID,
+			Name:            product.Name,
+			SKU:             product.SKU,
+			ReorderPoint:    *product.ReorderPoint,
+			ReorderQuantity: product.ReorderQuantity,
+		}
+
+		// the stock of a product with variants is kept by its variants, so each of them is checked instead
+		if len(product.Variants) == 0 && product.Stock <= *product.ReorderPoint {
+			low.Stock = product.Stock
+			responseData = append(responseData, low)
+		}
+
+		for _, variant := range product.Variants {
+			if variant.Stock > *product.ReorderPoint {
+				continue
+			}
+
+			variantId := variant.ID
+			lowVariant := low
+			lowVariant.VariantID = &variantId
+			lowVariant.VariantName = variant.Label()
+			lowVariant.SKU = variant.SKU
+			lowVariant.Stock = variant.Stock
+			responseData = append(responseData, lowVariant)
+		}
+	}
+
+	return responseData, nil
+}
+
+func (n *notificationService) Fetch(ctx context.Context, criteria criteria.NotificationCriteria) (
+	*util.PaginationResponse, error,
+) {
+	outletId, err := uuid.Parse(criteria.OutletID)
+	if err != nil {
+		return nil, &custom_error.BadRequest{Message: "outlet id is invalid"}
+	}
+
+	_, err = n.accessGuard.Outlet(ctx, outletId, model.RoleOwner, model.RoleManager, model.RoleCashier)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := query.Where(query.Eq("outlet_id", outletId))
+	if criteria.Type != "" {
+		spec = spec.And(query.Eq("type", criteria.Type))
+	}
+
+	spec, err = paginate(spec, &criteria.Pagination, "created_at")
+	if err != nil {
+		return nil, err
+	}
+
+	res, rowCount, err := n.notificationRepo.Fetch(ctx, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	var responseData []response.NotificationResponse
+	for _, val := range res {
+		responseData = append(responseData, notificationResponse(val))
+	}
+
+	resPagination := util.BuildPagination(criteria.Pagination, responseData, rowCount)
+
+	return &resPagination, nil
+}
+
+func notificationResponse(notification model.Notification) response.NotificationResponse {
+	return response.NotificationResponse{
+		ID:              notification.ID,
+		OutletID:        notification.OutletID,
+		Type:            notification.Type,
+		ProductID:       notification.ProductID,
+		VariantID:       notification.VariantID,
+		StockMovementID: notification.StockMovementID,
+		Message:         notification.Message,
+		Stock:           notification.Stock,
+		ReorderPoint:    notification.ReorderPoint,
+		ReorderQuantity: notification.ReorderQuantity,
+		CreatedAt:       notification.CreatedAt.Time,
+	}
+}
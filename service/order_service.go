@@ -0,0 +1,490 @@
+package service
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/rehandwi03/test-case-backend-majoo/criteria"
+	custom_error "github.com/rehandwi03/test-case-backend-majoo/internal/error"
+	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"github.com/rehandwi03/test-case-backend-majoo/money"
+	"github.com/rehandwi03/test-case-backend-majoo/query"
+	"github.com/rehandwi03/test-case-backend-majoo/repository"
+	"github.com/rehandwi03/test-case-backend-majoo/request"
+	"github.com/rehandwi03/test-case-backend-majoo/response"
+	"github.com/rehandwi03/test-case-backend-majoo/util"
+	"gorm.io/gorm"
+)
+
+type OrderService interface {
+	// Open opens an order of the outlet with the items ordered so far, it is checked out as a sale later on.
+	Open(ctx context.Context, request *request.OrderOpenRequest) (uuid.UUID, error)
+	Update(ctx context.Context, request *request.OrderUpdateRequest) error
+	// AddItems adds items to an open order, priced as the products are now.
+	AddItems(ctx context.Context, request *request.OrderAddItemsRequest) error
+	UpdateItem(ctx context.Context, request *request.OrderItemUpdateRequest) error
+	RemoveItem(ctx context.Context, outletId uuid.UUID, orderId uuid.UUID, itemId uuid.UUID) error
+	// Park holds an open order as it is, it can't be changed until it is resumed.
+	Park(ctx context.Context, outletId uuid.UUID, orderId uuid.UUID) error
+	Resume(ctx context.Context, outletId uuid.UUID, orderId uuid.UUID) error
+	// Cancel closes an open or parked order without selling it.
+	Cancel(ctx context.Context, outletId uuid.UUID, orderId uuid.UUID) error
+	Transfer(ctx context.Context, request *request.OrderTransferRequest) error
+	Merge(ctx context.Context, request *request.OrderMergeRequest) error
+	// Split moves items off an order into a new one, whose id is returned.
+	Split(ctx context.Context, request *request.OrderSplitRequest) (uuid.UUID, error)
+	GetByParam(ctx context.Context, spec query.Spec) (*response.OrderResponse, error)
+	Fetch(ctx context.Context, criteria criteria.OrderCriteria) (*util.PaginationResponse, error)
+}
+
+type orderService struct {
+	orderRepo   repository.OrderRepository
+	tableRepo   repository.TableRepository
+	cart        cart
+	accessGuard AccessGuard
+}
+
+func NewOrderService(
+	orderRepository repository.OrderRepository, tableRepository repository.TableRepository,
+	productRepository repository.ProductRepository, accessGuard AccessGuard,
+) OrderService {
+	return &orderService{
+		orderRepo: orderRepository, tableRepo: tableRepository, cart: cart{productRepo: productRepository},
+		accessGuard: accessGuard,
+	}
+}
+
+func (o *orderService) Open(ctx context.Context, request *request.OrderOpenRequest) (uuid.UUID, error) {
+	userId, ok := ctx.Value("user_id").(uuid.UUID)
+	if !ok {
+		return uuid.Nil, &custom_error.NotFoundError{Message: "user id not found"}
+	}
+
+	_, err := o.accessGuard.Outlet(ctx, request.OutletID, model.RoleOwner, model.RoleManager, model.RoleCashier)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	err = o.checkTable(ctx, request.OutletID, request.TableID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	items, err := o.priceItems(ctx, request.OutletID, request.Items)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	res, err := o.orderRepo.Open(
+		ctx, model.Order{
+			OutletID: request.OutletID,
+			TableID:  request.TableID,
+			Name:     request.Name,
+			Guests:   request.Guests,
+			Status:   model.OrderOpen,
+			Note:     request.Note,
+			UserID:   userId,
+			Items:    items,
+		},
+	)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return res, nil
+}
+
+func (o *orderService) Update(ctx context.Context, request *request.OrderUpdateRequest) error {
+	order, err := o.getOrder(ctx, request.OutletID, request.ID)
+	if err != nil {
+		return err
+	}
+
+	err = o.orderRepo.Update(
+		ctx, model.Order{ID: order.ID, Name: request.Name, Guests: request.Guests, Note: request.Note},
+	)
+
+	return orderError(err)
+}
+
+func (o *orderService) AddItems(ctx context.Context, request *request.OrderAddItemsRequest) error {
+	order, err := o.getOrder(ctx, request.OutletID, request.ID)
+	if err != nil {
+		return err
+	}
+
+	items, err := o.priceItems(ctx, order.OutletID, request.Items)
+	if err != nil {
+		return err
+	}
+
+	return orderError(o.orderRepo.AddItems(ctx, order.ID, items))
+}
+
+func (o *orderService) UpdateItem(ctx context.Context, request *request.OrderItemUpdateRequest) error {
+	order, err := o.getOrder(ctx, request.OutletID, request.OrderID)
+	if err != nil {
+		return err
+	}
+
+	err = o.orderRepo.UpdateItem(
+		ctx, order.ID, model.OrderItem{
+			ID: request.ID, Quantity: request.Quantity, Seat: request.Seat, Note: request.Note,
+		},
+	)
+
+	return orderError(err)
+}
+
+func (o *orderService) RemoveItem(ctx context.Context, outletId uuid.UUID, orderId uuid.UUID, itemId uuid.UUID) error {
+	order, err := o.getOrder(ctx, outletId, orderId)
+	if err != nil {
+		return err
+	}
+
+	return orderError(o.orderRepo.RemoveItem(ctx, order.ID, itemId))
+}
+
+func (o *orderService) Park(ctx context.Context, outletId uuid.UUID, orderId uuid.UUID) error {
+	return o.setStatus(ctx, outletId, orderId, model.OrderParked, model.OrderOpen)
+}
+
+func (o *orderService) Resume(ctx context.Context, outletId uuid.UUID, orderId uuid.UUID) error {
+	return o.setStatus(ctx, outletId, orderId, model.OrderOpen, model.OrderParked)
+}
+
+func (o *orderService) Cancel(ctx context.Context, outletId uuid.UUID, orderId uuid.UUID) error {
+	return o.setStatus(ctx, outletId, orderId, model.OrderCancelled, model.OrderOpen, model.OrderParked)
+}
+
+func (o *orderService) Transfer(ctx context.Context, request *request.OrderTransferRequest) error {
+	order, err := o.getOrder(ctx, request.OutletID, request.ID)
+	if err != nil {
+		return err
+	}
+
+	err = o.checkTable(ctx, order.OutletID, request.TableID)
+	if err != nil {
+		return err
+	}
+
+	return orderError(o.orderRepo.Transfer(ctx, order.ID, request.TableID))
+}
+
+func (o *orderService) Merge(ctx context.Context, request *request.OrderMergeRequest) error {
+	order, err := o.getOrder(ctx, request.OutletID, request.ID)
+	if err != nil {
+		return err
+	}
+
+	seen := map[uuid.UUID]bool{}
+	var otherIds []uuid.UUID
+	for _, id := range request.OrderIDs {
+		if id == order.ID {
+			return &custom_error.BadRequest{Message: "an order can't be merged into itself", Field: "order_ids"}
+		}
+
+		if !seen[id] {
+			seen[id] = true
+			otherIds = append(otherIds, id)
+		}
+	}
+
+	count, err := o.orderRepo.Count(
+		ctx, query.Where(query.In("id", otherIds), query.Eq("outlet_id", order.OutletID)),
+	)
+	if err != nil {
+		return err
+	}
+
+	if count != int64(len(otherIds)) {
+		return &custom_error.NotFoundError{Message: "order not found"}
+	}
+
+	return orderError(o.orderRepo.Merge(ctx, order.ID, otherIds))
+}
+
+func (o *orderService) Split(ctx context.Context, request *request.OrderSplitRequest) (uuid.UUID, error) {
+	userId, ok := ctx.Value("user_id").(uuid.UUID)
+	if !ok {
+		return uuid.Nil, &custom_error.NotFoundError{Message: "user id not found"}
+	}
+
+	order, err := o.getOrder(ctx, request.OutletID, request.ID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	tableId := order.TableID
+	if request.TableID != nil {
+		tableId = request.TableID
+	}
+
+	err = o.checkTable(ctx, order.OutletID, tableId)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	quantities := map[uuid.UUID]int64{}
+	for _, item := range request.Items {
+		quantities[item.OrderItemID] += item.Quantity
+	}
+
+	// the items of a seat are split off whole, the guest on it pays for all of them
+	seats := map[int64]bool{}
+	for _, seat := range request.Seats {
+		seats[seat] = true
+	}
+	for _, item := range order.Items {
+		if seats[item.Seat] {
+			quantities[item.ID] = item.Quantity
+		}
+	}
+
+	if len(quantities) == 0 {
+		return uuid.Nil, &custom_error.BadRequest{Message: "choose the items or seats to split off"}
+	}
+
+	res, err := o.orderRepo.Split(
+		ctx, order.ID, model.Order{
+			OutletID: order.OutletID,
+			TableID:  tableId,
+			Name:     request.Name,
+			Guests:   request.Guests,
+			UserID:   userId,
+		}, quantities,
+	)
+	if err != nil {
+		return uuid.Nil, orderError(err)
+	}
+
+	return res, nil
+}
+
+func (o *orderService) GetByParam(ctx context.Context, spec query.Spec) (*response.OrderResponse, error) {
+	order, err := o.orderRepo.Get(ctx, spec)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, &custom_error.NotFoundError{Message: "order not found"}
+		}
+
+		return nil, err
+	}
+
+	_, err = o.accessGuard.Outlet(ctx, order.OutletID, model.RoleOwner, model.RoleManager, model.RoleCashier)
+	if err != nil {
+		return nil, err
+	}
+
+	response := orderResponse(order)
+
+	return &response, nil
+}
+
+func (o *orderService) Fetch(ctx context.Context, criteria criteria.OrderCriteria) (*util.PaginationResponse, error) {
+	outletId, err := uuid.Parse(criteria.OutletID)
+	if err != nil {
+		return nil, &custom_error.BadRequest{Message: "outlet id is invalid"}
+	}
+
+	_, err = o.accessGuard.Outlet(ctx, outletId, model.RoleOwner, model.RoleManager, model.RoleCashier)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := query.Where(query.Eq("outlet_id", outletId))
+	if criteria.Status != "" {
+		spec = spec.And(query.Eq("status", criteria.Status))
+	}
+	if criteria.TableID != "" {
+		tableId, err := uuid.Parse(criteria.TableID)
+		if err != nil {
+			return nil, &custom_error.BadRequest{Message: "table_id must be an uuid", Field: "table_id"}
+		}
+
+		spec = spec.And(query.Eq("table_id", tableId))
+	}
+
+	spec, err = paginate(spec, &criteria.Pagination, "status", "created_at")
+	if err != nil {
+		return nil, err
+	}
+
+	res, rowCount, err := o.orderRepo.Fetch(ctx, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	var responseData []response.OrderResponse
+	for _, val := range res {
+		responseData = append(responseData, orderResponse(val))
+	}
+
+	resPagination := util.BuildPagination(criteria.Pagination, responseData, rowCount)
+
+	return &resPagination, nil
+}
+
+// getOrder returns the order of the outlet, which anyone working at the outlet may serve.
+func (o *orderService) getOrder(ctx context.Context, outletId uuid.UUID, orderId uuid.UUID) (model.Order, error) {
+	_, err := o.accessGuard.Outlet(ctx, outletId, model.RoleOwner, model.RoleManager, model.RoleCashier)
+	if err != nil {
+		return model.Order{}, err
+	}
+
+	order, err := o.orderRepo.Get(ctx, query.Where(query.Eq("id", orderId), query.Eq("outlet_id", outletId)))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return order, &custom_error.NotFoundError{Message: "order not found"}
+		}
+
+		return order, err
+	}
+
+	return order, nil
+}
+
+func (o *orderService) setStatus(
+	ctx context.Context, outletId uuid.UUID, orderId uuid.UUID, to string, from ...string,
+) error {
+	order, err := o.getOrder(ctx, outletId, orderId)
+	if err != nil {
+		return err
+	}
+
+	err = o.orderRepo.SetStatus(ctx, order.ID, to, from...)
+	if err == repository.ErrOrderNotOpen {
+		return &custom_error.ConflictError{Message: "the order is " + order.Status}
+	}
+
+	return err
+}
+
+// checkTable makes sure the table of an order, if any, is one of the same outlet.
+func (o *orderService) checkTable(ctx context.Context, outletId uuid.UUID, tableId *uuid.UUID) error {
+	if tableId == nil {
+		return nil
+	}
+
+	count, err := o.tableRepo.Count(ctx, query.Where(query.Eq("id", *tableId), query.Eq("outlet_id", outletId)))
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return &custom_error.BadRequest{Message: "table not found", Field: "table_id"}
+	}
+
+	return nil
+}
+
+// priceItems prices the items ordered like a checkout would, every one on its own since they may be for different
+// seats. The stock isn't checked until the order is checked out.
+func (o *orderService) priceItems(
+	ctx context.Context, outletId uuid.UUID, items []request.OrderItemRequest,
+) ([]model.OrderItem, error) {
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	lines := make([]request.TransactionItemRequest, len(items))
+	for i, item := range items {
+		lines[i] = item.TransactionItemRequest
+	}
+
+	priced, _, err := o.cart.price(ctx, outletId, lines)
+	if err != nil {
+		return nil, err
+	}
+
+	var orderItems []model.OrderItem
+	for i, item := range priced {
+		orderItem := model.OrderItem{
+			ProductID:   item.ProductID,
+			VariantID:   item.VariantID,
+			Name:        item.Name,
+			VariantName: item.VariantName,
+			Price:       item.Price,
+			Quantity:    item.Quantity,
+			Seat:        items[i].Seat,
+			Note:        items[i].Note,
+		}
+		for _, modifier := range item.Modifiers {
+			orderItem.Modifiers = append(orderItem.Modifiers, model.OrderItemModifier{ModifierID: modifier.ModifierID})
+		}
+		for _, optionId := range items[i].OptionIDs {
+			orderItem.Options = append(orderItem.Options, model.OrderItemOption{OptionID: optionId})
+		}
+
+		orderItems = append(orderItems, orderItem)
+	}
+
+	return orderItems, nil
+}
+
+// orderError answers the errors of a change of an order the way the handlers report them.
+func orderError(err error) error {
+	switch err {
+	case repository.ErrOrderNotOpen:
+		return &custom_error.ConflictError{Message: err.Error()}
+	case repository.ErrItemNotFound:
+		return &custom_error.NotFoundError{Message: err.Error()}
+	case repository.ErrSplitTooMuch:
+		return &custom_error.BadRequest{Message: err.Error(), Field: "items"}
+	case gorm.ErrRecordNotFound:
+		return &custom_error.NotFoundError{Message: "order not found"}
+	}
+
+	return err
+}
+
+func orderResponse(order model.Order) response.OrderResponse {
+	data := response.OrderResponse{
+		ID:            order.ID,
+		OutletID:      order.OutletID,
+		TableID:       order.TableID,
+		Name:          order.Name,
+		Guests:        order.Guests,
+		Status:        order.Status,
+		Note:          order.Note,
+		UserID:        order.UserID,
+		TransactionID: order.TransactionID,
+		MergedIntoID:  order.MergedIntoID,
+		Items:         []response.OrderItemResponse{},
+		CreatedAt:     order.CreatedAt.Time,
+		ModifiedAt:    order.ModifiedAt.Time,
+	}
+
+	var total money.Money
+	for _, item := range order.Items {
+		itemResponse := response.OrderItemResponse{
+			ID:          item.ID,
+			ProductID:   item.ProductID,
+			VariantID:   item.VariantID,
+			Name:        item.Name,
+			VariantName: item.VariantName,
+			Price:       item.Price,
+			Quantity:    item.Quantity,
+			Subtotal:    item.Price.Mul(item.Quantity),
+			Seat:        item.Seat,
+			Note:        item.Note,
+			ModifierIDs: []uuid.UUID{},
+		}
+		for _, modifier := range item.Modifiers {
+			itemResponse.ModifierIDs = append(itemResponse.ModifierIDs, modifier.ModifierID)
+		}
+		for _, option := range item.Options {
+			itemResponse.OptionIDs = append(itemResponse.OptionIDs, option.OptionID)
+		}
+
+		// the items are all priced in the currency of the outlet
+		if sum, err := total.Add(itemResponse.Subtotal); err == nil {
+			total = sum
+		}
+
+		data.TotalQuantity += item.Quantity
+		data.Items = append(data.Items, itemResponse)
+	}
+	data.Total = optionalMoney(total)
+
+	return data
+}
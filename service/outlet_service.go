@@ -6,6 +6,7 @@ import (
 	"github.com/rehandwi03/test-case-backend-majoo/criteria"
 	custom_error "github.com/rehandwi03/test-case-backend-majoo/internal/error"
 	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"github.com/rehandwi03/test-case-backend-majoo/query"
 	"github.com/rehandwi03/test-case-backend-majoo/repository"
 	"github.com/rehandwi03/test-case-backend-majoo/request"
 	"github.com/rehandwi03/test-case-backend-majoo/response"
@@ -16,36 +17,28 @@ import (
 type OutletService interface {
 	SaveOutlet(ctx context.Context, request *request.OutletAddRequest) (uuid.UUID, error)
 	UpdateOutlet(ctx context.Context, request *request.OutletUpdateRequest) (uuid.UUID, error)
-	DeleteOutlet(ctx context.Context, params map[string]interface{}) error
-	GetByParam(ctx context.Context, params map[string]interface{}) (*response.OutletResponse, error)
+	DeleteOutlet(ctx context.Context, spec query.Spec) error
+	RestoreOutlet(ctx context.Context, outletId uuid.UUID) error
+	GetByParam(ctx context.Context, spec query.Spec) (*response.OutletResponse, error)
 	Fetch(ctx context.Context, OutletCriteria criteria.OutletCriteria) (*util.PaginationResponse, error)
 }
 
 type outletService struct {
 	outletRepo   repository.OutletRepository
 	merchantRepo repository.MerchantRepository
+	accessGuard  AccessGuard
 }
 
 func NewOutletService(
 	outletRepository repository.OutletRepository, merchantRepository repository.MerchantRepository,
+	accessGuard AccessGuard,
 ) OutletService {
-	return &outletService{outletRepo: outletRepository, merchantRepo: merchantRepository}
+	return &outletService{outletRepo: outletRepository, merchantRepo: merchantRepository, accessGuard: accessGuard}
 }
 
 func (o *outletService) SaveOutlet(ctx context.Context, request *request.OutletAddRequest) (uuid.UUID, error) {
-	params := map[string]interface{}{
-		"where": map[string]interface{}{
-			"default": map[string]interface{}{
-				"id = ?": request.MerchantID,
-			},
-		},
-	}
-	_, err := o.merchantRepo.GetByParams(ctx, params)
+	_, err := o.accessGuard.Merchant(ctx, request.MerchantID, model.RoleOwner, model.RoleManager)
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return uuid.Nil, &custom_error.NotFoundError{Message: "merchant not found"}
-		}
-
 		return uuid.Nil, err
 	}
 
@@ -67,38 +60,14 @@ func (o *outletService) SaveOutlet(ctx context.Context, request *request.OutletA
 func (o *outletService) UpdateOutlet(ctx context.Context, request *request.OutletUpdateRequest) (
 	uuid.UUID, error,
 ) {
-	param := map[string]interface{}{
-		"where": map[string]interface{}{
-			"or": map[string]interface{}{},
-			"default": map[string]interface{}{
-				"id = ?": request.ID,
-			},
-		},
-	}
-
-	merchantParam := map[string]interface{}{
-		"where": map[string]interface{}{
-			"default": map[string]interface{}{
-				"id = ?": request.MerchantID,
-			},
-		},
-	}
-
-	_, err := o.merchantRepo.GetByParam(ctx, merchantParam)
+	// moving an outlet needs the role in both the merchant it belongs to and the one it moves to
+	outletData, err := o.accessGuard.Outlet(ctx, request.ID, model.RoleOwner, model.RoleManager)
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return uuid.Nil, &custom_error.NotFoundError{Message: "merchant not found"}
-		}
-
 		return uuid.Nil, err
 	}
 
-	outletData, err := o.outletRepo.GetByParam(ctx, param)
+	_, err = o.accessGuard.Merchant(ctx, request.MerchantID, model.RoleOwner, model.RoleManager)
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return uuid.Nil, &custom_error.NotFoundError{Message: "outlet not found"}
-		}
-
 		return uuid.Nil, err
 	}
 
@@ -121,8 +90,8 @@ func (o *outletService) UpdateOutlet(ctx context.Context, request *request.Outle
 	return res, nil
 }
 
-func (o *outletService) DeleteOutlet(ctx context.Context, params map[string]interface{}) error {
-	OutletData, err := o.outletRepo.GetByParam(ctx, params)
+func (o *outletService) DeleteOutlet(ctx context.Context, spec query.Spec) error {
+	OutletData, err := o.outletRepo.Get(ctx, spec)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return &custom_error.NotFoundError{Message: "outlet not found"}
@@ -131,6 +100,11 @@ func (o *outletService) DeleteOutlet(ctx context.Context, params map[string]inte
 		return err
 	}
 
+	_, err = o.accessGuard.Outlet(ctx, OutletData.ID, model.RoleOwner, model.RoleManager)
+	if err != nil {
+		return err
+	}
+
 	err = o.outletRepo.Delete(ctx, &OutletData)
 	if err != nil {
 		return err
@@ -139,10 +113,33 @@ func (o *outletService) DeleteOutlet(ctx context.Context, params map[string]inte
 	return nil
 }
 
-func (o *outletService) GetByParam(ctx context.Context, params map[string]interface{}) (
+// RestoreOutlet brings back a deleted outlet together with everything deleted along with it.
+func (o *outletService) RestoreOutlet(ctx context.Context, outletId uuid.UUID) error {
+	outlet, err := o.accessGuard.IncludeDeleted().Outlet(ctx, outletId, model.RoleOwner, model.RoleManager)
+	if err != nil {
+		return err
+	}
+
+	if !outlet.DeletedAt.Valid {
+		return &custom_error.ConflictError{Message: "outlet isn't deleted"}
+	}
+
+	err = o.outletRepo.Restore(ctx, outlet.ID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return &custom_error.ConflictError{Message: "outlet isn't deleted"}
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (o *outletService) GetByParam(ctx context.Context, spec query.Spec) (
 	*response.OutletResponse, error,
 ) {
-	outletData, err := o.outletRepo.GetByParam(ctx, params)
+	outletData, err := o.outletRepo.Get(ctx, spec)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, &custom_error.NotFoundError{Message: err.Error()}
@@ -151,6 +148,11 @@ func (o *outletService) GetByParam(ctx context.Context, params map[string]interf
 		return nil, err
 	}
 
+	_, err = o.accessGuard.Merchant(ctx, outletData.MerchantID, model.RoleOwner, model.RoleManager, model.RoleCashier)
+	if err != nil {
+		return nil, err
+	}
+
 	response := new(response.OutletResponse)
 	response.ID = outletData.ID
 	response.MerchantID = outletData.MerchantID
@@ -165,33 +167,33 @@ func (o *outletService) GetByParam(ctx context.Context, params map[string]interf
 func (o *outletService) Fetch(ctx context.Context, criteria criteria.OutletCriteria) (
 	*util.PaginationResponse, error,
 ) {
-	params := map[string]interface{}{
-		"where": map[string]interface{}{
-			"default": map[string]interface{}{
-			},
-			"pagination": map[string]interface{}{
-				"page":  criteria.Pagination.Page,
-				"sort":  criteria.Pagination.Sort,
-				"limit": criteria.Pagination.Limit,
-			},
-		},
+	merchantIds, all, err := o.accessGuard.MerchantIDs(ctx)
+	if err != nil {
+		return nil, err
 	}
 
+	spec := query.Where()
+	if !all {
+		spec = spec.And(query.In("merchant_id", merchantIds))
+	}
 	if criteria.Name != "" {
-		params["where"].(map[string]interface{})["default"] = map[string]interface{}{
-			"email ILIKE ?": "%" + criteria.
-				Name + "%",
-		}
+		spec = spec.And(query.Contains("name", criteria.Name))
 	}
-
 	if criteria.Location != "" {
-		params["where"].(map[string]interface{})["default"] = map[string]interface{}{
-			"location ILIKE ?": "%" + criteria.
-				Location + "%",
-		}
+		spec = spec.And(query.Contains("location", criteria.Location))
 	}
 
-	res, rowCount, err := o.outletRepo.Fetch(ctx, params)
+	spec, err = includeDeleted(ctx, spec, criteria.IncludeDeleted)
+	if err != nil {
+		return nil, err
+	}
+
+	spec, err = paginate(spec, &criteria.Pagination, "name", "location", "created_at")
+	if err != nil {
+		return nil, err
+	}
+
+	res, rowCount, err := o.outletRepo.Fetch(ctx, spec)
 	if err != nil {
 		return nil, err
 	}
@@ -206,6 +208,10 @@ func (o *outletService) Fetch(ctx context.Context, criteria criteria.OutletCrite
 		data.Location = val.Location
 		data.PhoneNumber = val.PhoneNumber
 		data.CreatedAt = val.CreatedAt.Time
+		if val.DeletedAt.Valid {
+			deletedAt := val.DeletedAt.Time
+			data.DeletedAt = &deletedAt
+		}
 
 		responseData = append(responseData, data)
 	}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	custom_error "github.com/rehandwi03/test-case-backend-majoo/internal/error"
+	"github.com/rehandwi03/test-case-backend-majoo/query"
+	"github.com/rehandwi03/test-case-backend-majoo/util"
+	"strings"
+)
+
+// paginate adds the sort and page requested in pagination to spec. Only the sortable fields are accepted since the
+// sort comes from the query string, and pagination is normalized the same way the page is so that the paging built
+// from it matches the rows returned. A sortable field stored in another column is given as "field:column".
+func paginate(spec query.Spec, pagination *util.Pagination, sortable ...string) (query.Spec, error) {
+	fields := make([]string, len(sortable))
+	columns := map[string]string{}
+	for i, field := range sortable {
+		name, column, _ := strings.Cut(field, ":")
+		fields[i] = name
+		columns[name] = column
+	}
+
+	sorts, err := query.ParseSort(pagination.Sort, fields...)
+	if err != nil {
+		return spec, &custom_error.BadRequest{Message: err.Error()}
+	}
+	for i := range sorts {
+		if column := columns[sorts[i].Field]; column != "" {
+			sorts[i].Field = column
+		}
+	}
+
+	if pagination.Page < 1 {
+		pagination.Page = 1
+	}
+	if pagination.Limit < 1 {
+		pagination.Limit = query.DefaultLimit
+	}
+	if pagination.Limit > query.MaxLimit {
+		pagination.Limit = query.MaxLimit
+	}
+
+	return spec.OrderBy(sorts...).Paginate(pagination.Page, pagination.Limit), nil
+}
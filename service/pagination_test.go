@@ -0,0 +1,120 @@
+package service
+
+import (
+	"github.com/rehandwi03/test-case-backend-majoo/query"
+	"github.com/rehandwi03/test-case-backend-majoo/util"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"strings"
+	"testing"
+)
+
+type sqlRow struct {
+	ID int
+}
+
+// renderSQL answers the statement a repository would run for the spec, without a database.
+func renderSQL(t *testing.T, spec query.Spec) string {
+	t.Helper()
+
+	db, err := gorm.Open(
+		postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DisableAutomaticPing: true},
+	)
+	if err != nil {
+		t.Fatalf("open dry run connection: %v", err)
+	}
+
+	var rows []sqlRow
+	stmt := spec.Apply(db.Session(&gorm.Session{DryRun: true}).Table("rows")).Find(&rows).Statement
+	if stmt.Error != nil {
+		t.Fatalf("render spec: %v", stmt.Error)
+	}
+
+	return stmt.SQL.String()
+}
+
+func TestPaginate(t *testing.T) {
+	sortable := []string{"name", "stock", "price:price_amount", "created_at"}
+
+	tests := []struct {
+		name    string
+		sort    string
+		orderBy string
+		wantErr bool
+	}{
+		{name: "no sort", sort: "", orderBy: ""},
+		{name: "plain field", sort: "name", orderBy: "ORDER BY name ASC"},
+		{name: "field stored in another column", sort: "price desc", orderBy: "ORDER BY price_amount DESC"},
+		{
+			name: "several fields", sort: "price asc, created_at desc",
+			orderBy: "ORDER BY price_amount ASC,created_at DESC",
+		},
+		{name: "column of an alias", sort: "price_amount", wantErr: true},
+		{name: "unknown field", sort: "cost desc", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				pagination := util.Pagination{Sort: tt.sort}
+				spec, err := paginate(query.Where(), &pagination, sortable...)
+				if tt.wantErr {
+					if err == nil {
+						t.Fatalf("paginate(%q) succeeded, want an error", tt.sort)
+					}
+
+					return
+				}
+				if err != nil {
+					t.Fatalf("paginate(%q): %v", tt.sort, err)
+				}
+
+				sql := renderSQL(t, spec)
+				if tt.orderBy == "" {
+					if strings.Contains(sql, "ORDER BY") {
+						t.Errorf("paginate(%q) sorts: %s", tt.sort, sql)
+					}
+
+					return
+				}
+				if !strings.Contains(sql, tt.orderBy) {
+					t.Errorf("paginate(%q) = %s, want %s", tt.sort, sql, tt.orderBy)
+				}
+			},
+		)
+	}
+}
+
+func TestPaginateNormalizesThePage(t *testing.T) {
+	tests := []struct {
+		name      string
+		page      int
+		limit     int
+		wantPage  int
+		wantLimit int
+	}{
+		{name: "defaults", page: 0, limit: 0, wantPage: 1, wantLimit: query.DefaultLimit},
+		{name: "negative", page: -2, limit: -5, wantPage: 1, wantLimit: query.DefaultLimit},
+		{name: "capped limit", page: 3, limit: 500, wantPage: 3, wantLimit: query.MaxLimit},
+		{name: "kept", page: 2, limit: 25, wantPage: 2, wantLimit: 25},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				pagination := util.Pagination{Page: tt.page, Limit: tt.limit}
+				_, err := paginate(query.Where(), &pagination)
+				if err != nil {
+					t.Fatalf("paginate: %v", err)
+				}
+
+				if pagination.Page != tt.wantPage || pagination.Limit != tt.wantLimit {
+					t.Errorf(
+						"page %d limit %d, want page %d limit %d", pagination.Page, pagination.Limit, tt.wantPage,
+						tt.wantLimit,
+					)
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	custom_error "github.com/rehandwi03/test-case-backend-majoo/internal/error"
+	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"github.com/rehandwi03/test-case-backend-majoo/money"
+	"github.com/rehandwi03/test-case-backend-majoo/request"
+)
+
+// settle pays for the transaction with the tenders, by the payment methods the merchant takes, and records them with
+// the cash rounding and the change on it. The tenders other than cash are charged what they pay, so together they
+// can't pay more than the total. Cash pays what they leave, rounded by the cash rounding of the merchant, and what it
+// pays more is given back as change. A total of zero is settled without tenders.
+func settle(
+	transaction *model.Transaction, merchant model.Merchant, tenders []request.TransactionPaymentRequest,
+) error {
+	enabled := map[string]bool{}
+	for _, method := range merchant.PaymentMethods {
+		enabled[method.Method] = true
+	}
+
+	var cash, other int64
+	for _, tender := range tenders {
+		if !enabled[tender.Method] {
+			return &custom_error.BadRequest{
+				Message: "the merchant doesn't take " + tender.Method + " payments", Field: "payments",
+			}
+		}
+
+		amount, err := inCurrency(tender.Amount, merchant.Currency, "payments")
+		if err != nil {
+			return err
+		}
+		if amount.Amount <= 0 {
+			return &custom_error.BadRequest{Message: "a payment has to be positive", Field: "payments"}
+		}
+
+		if tender.Method == model.PaymentCash {
+			if tender.Provider != "" {
+				return &custom_error.BadRequest{
+					Message: "cash is taken at the till, not by a payment provider", Field: "payments",
+				}
+			}
+
+			cash += amount.Amount
+		} else {
+			other += amount.Amount
+		}
+
+		transaction.Payments = append(
+			transaction.Payments, model.TransactionPayment{
+				Method:    tender.Method,
+				Amount:    amount,
+				Reference: tender.Reference,
+				Status:    model.PaymentCaptured,
+				Provider:  tender.Provider,
+			},
+		)
+	}
+
+	total := transaction.Total.Amount
+	if other > total {
+		return &custom_error.BadRequest{
+			Message: "only cash can pay more than the total, " + money.New(other, merchant.Currency).String() +
+				" was paid otherwise for a total of " + transaction.Total.String(),
+			Field: "payments",
+		}
+	}
+
+	var rounding int64
+	left := total - other
+	if cash > 0 {
+		if left == 0 {
+			return &custom_error.BadRequest{Message: "nothing is left to pay in cash", Field: "payments"}
+		}
+
+		rounding = roundCash(left, merchant.CashRounding, merchant.CashRoundingMode) - left
+		left += rounding
+	}
+
+	if cash < left {
+		return &custom_error.BadRequest{
+			Message: "the payments don't cover the total, " + money.New(left-cash, merchant.Currency).String() +
+				" is left to pay",
+			Field: "payments",
+		}
+	}
+
+	transaction.Rounding = money.New(rounding, merchant.Currency)
+	transaction.Paid = money.New(cash+other, merchant.Currency)
+	transaction.Change = money.New(cash-left, merchant.Currency)
+
+	return nil
+}
+
+// roundCash rounds the amount to a multiple of increment by the mode, an increment below 2 leaves it as is. Nearest
+// rounds halves up.
+func roundCash(amount int64, increment int64, mode string) int64 {
+	if increment < 2 {
+		return amount
+	}
+
+	remainder := amount % increment
+	if remainder == 0 {
+		return amount
+	}
+
+	switch mode {
+	case model.CashRoundDown:
+		return amount - remainder
+	case model.CashRoundUp:
+		return amount - remainder + increment
+	default:
+		if remainder*2 >= increment {
+			return amount - remainder + increment
+		}
+
+		return amount - remainder
+	}
+}
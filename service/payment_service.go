@@ -0,0 +1,335 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/rehandwi03/test-case-backend-majoo/gateway"
+	custom_error "github.com/rehandwi03/test-case-backend-majoo/internal/error"
+	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"github.com/rehandwi03/test-case-backend-majoo/money"
+	"github.com/rehandwi03/test-case-backend-majoo/query"
+	"github.com/rehandwi03/test-case-backend-majoo/repository"
+	"github.com/rehandwi03/test-case-backend-majoo/response"
+	"gorm.io/gorm"
+	"log"
+)
+
+type PaymentService interface {
+	// Callback applies the status a payment provider calls back with to the payment of the charge. Callbacks may
+	// come more than once and out of order, a status the payment already has or went past changes nothing.
+	Callback(ctx context.Context, provider string, payload []byte, signature string) error
+	// Refresh asks the payment provider of the payment how its charge stands, for when a callback doesn't come.
+	Refresh(ctx context.Context, outletId uuid.UUID, transactionId uuid.UUID, paymentId uuid.UUID) (
+		*response.TransactionPaymentResponse, error,
+	)
+}
+
+type paymentService struct {
+	transactionRepo repository.TransactionRepository
+	paymentRepo     repository.TransactionPaymentRepository
+	charges         charges
+	accessGuard     AccessGuard
+}
+
+func NewPaymentService(
+	transactionRepository repository.TransactionRepository, paymentRepository repository.TransactionPaymentRepository,
+	providers gateway.Providers, accessGuard AccessGuard,
+) PaymentService {
+	return &paymentService{
+		transactionRepo: transactionRepository, paymentRepo: paymentRepository,
+		charges: charges{providers: providers, paymentRepo: paymentRepository}, accessGuard: accessGuard,
+	}
+}
+
+func (p *paymentService) Callback(
+	ctx context.Context, provider string, payload []byte, signature string,
+) error {
+	paymentProvider, ok := p.charges.providers[provider]
+	if !ok {
+		return &custom_error.NotFoundError{Message: "payment provider " + provider + " not found"}
+	}
+
+	callback, err := paymentProvider.VerifyCallback(payload, signature)
+	if err != nil {
+		switch err {
+		case gateway.ErrInvalidSignature:
+			return &custom_error.UnauthorizedError{Message: err.Error()}
+		case gateway.ErrChargeNotFound:
+			return &custom_error.NotFoundError{Message: err.Error()}
+		}
+
+		return &custom_error.BadRequest{Message: "callback can't be read: " + err.Error()}
+	}
+
+	payment, err := p.paymentRepo.Get(
+		ctx, query.Where(query.Eq("provider", provider), query.Eq("charge_id", callback.ChargeID)),
+	)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return &custom_error.NotFoundError{Message: "payment of charge " + callback.ChargeID + " not found"}
+		}
+
+		return err
+	}
+
+	_, err = p.charges.apply(ctx, payment, callback.Status)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (p *paymentService) Refresh(
+	ctx context.Context, outletId uuid.UUID, transactionId uuid.UUID, paymentId uuid.UUID,
+) (*response.TransactionPaymentResponse, error) {
+	_, err := p.accessGuard.Outlet(ctx, outletId, model.RoleOwner, model.RoleManager, model.RoleCashier)
+	if err != nil {
+		return nil, err
+	}
+
+	transaction, err := p.transactionRepo.Get(
+		ctx, query.Where(query.Eq("id", transactionId), query.Eq("outlet_id", outletId)),
+	)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, &custom_error.NotFoundError{Message: "transaction not found"}
+		}
+
+		return nil, err
+	}
+
+	var payment *model.TransactionPayment
+	for i := range transaction.Payments {
+		if transaction.Payments[i].ID == paymentId {
+			payment = &transaction.Payments[i]
+		}
+	}
+	if payment == nil {
+		return nil, &custom_error.NotFoundError{Message: "payment not found"}
+	}
+
+	if payment.Provider == "" {
+		return nil, &custom_error.BadRequest{Message: "the payment didn't go through a payment provider"}
+	}
+
+	paymentProvider, ok := p.charges.providers[payment.Provider]
+	if !ok {
+		return nil, &custom_error.ConflictError{Message: "payment provider " + payment.Provider + " isn't set up"}
+	}
+
+	charge, err := paymentProvider.Status(ctx, payment.ChargeID)
+	if err != nil {
+		if err == gateway.ErrChargeNotFound {
+			return nil, &custom_error.NotFoundError{Message: "the payment provider has no charge " + payment.ChargeID}
+		}
+
+		return nil, err
+	}
+
+	updated, err := p.charges.apply(ctx, *payment, charge.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	response := transactionPaymentResponse(updated)
+
+	return &response, nil
+}
+
+// charges takes the payments of sales going through a payment provider and keeps their status up with their charge.
+type charges struct {
+	providers   gateway.Providers
+	paymentRepo repository.TransactionPaymentRepository
+}
+
+// create charges the provider of the payment for it, leaving the charge authorized so the sale can still be given
+// up, and records the charge on the payment. The payment is given its id first, which the charge is referenced by
+// so that creating it again answers the same charge. A declined charge fails the sale.
+func (c charges) create(ctx context.Context, payment *model.TransactionPayment) error {
+	paymentProvider, ok := c.providers[payment.Provider]
+	if !ok {
+		return &custom_error.BadRequest{
+			Message: "payment provider " + payment.Provider + " not found", Field: "payments",
+		}
+	}
+
+	if payment.ID == uuid.Nil {
+		payment.ID = uuid.New()
+	}
+
+	charge, err := paymentProvider.CreateCharge(
+		ctx, gateway.ChargeRequest{Reference: payment.ID.String(), Method: payment.Method, Amount: payment.Amount},
+	)
+	if err != nil {
+		return err
+	}
+
+	if charge.Status == gateway.StatusFailed {
+		return &custom_error.BadRequest{
+			Message: "the " + payment.Method + " payment of " + payment.Amount.String() + " was declined",
+			Field:   "payments",
+		}
+	}
+
+	payment.ChargeID = charge.ID
+	payment.Status = string(charge.Status)
+	payment.Action = charge.Action
+
+	return nil
+}
+
+// release cancels the charges created for the payments of a sale which isn't made after all, so the authorizations
+// don't keep holding the money of the customer. It goes on past a charge failing to be cancelled, which is logged.
+func (c charges) release(ctx context.Context, payments []model.TransactionPayment) {
+	for _, payment := range payments {
+		if payment.ChargeID == "" {
+			continue
+		}
+
+		paymentProvider, ok := c.providers[payment.Provider]
+		if !ok {
+			continue
+		}
+
+		_, err := paymentProvider.Cancel(ctx, payment.ChargeID)
+		if err != nil {
+			log.Printf("error cancelling charge %s of payment %s: %v", payment.ChargeID, payment.ID, err)
+		}
+	}
+}
+
+// refund gives back the refund payments still pending by the payment providers of their payments, one after the
+// other, each referenced by its id so asking again doesn't give it back twice. A refund payment the provider won't
+// take fails, any other error stops it, leaving that refund payment and the ones after it pending to be retried.
+// The statuses of the refund payments are updated as they are settled.
+func (c charges) refund(ctx context.Context, refundPayments []model.RefundPayment) error {
+	for i, refundPayment := range refundPayments {
+		if refundPayment.Status != model.RefundPaymentPending {
+			continue
+		}
+
+		payment, err := c.paymentRepo.Get(ctx, query.Where(query.Eq("id", refundPayment.PaymentID)))
+		if err != nil {
+			return err
+		}
+
+		paymentProvider, ok := c.providers[payment.Provider]
+		if !ok {
+			return fmt.Errorf("payment provider %s isn't set up", payment.Provider)
+		}
+
+		status := model.RefundPaymentCompleted
+		_, err = paymentProvider.Refund(ctx, payment.ChargeID, refundPayment.ID.String(), refundPayment.Amount)
+		if err == gateway.ErrInvalidState {
+			log.Printf(
+				"payment provider %s won't give back %s of charge %s, refund payment %s failed", payment.Provider,
+				refundPayment.Amount, payment.ChargeID, refundPayment.ID,
+			)
+			status = model.RefundPaymentFailed
+		} else if err != nil {
+			return fmt.Errorf("refund payment %s: %w", refundPayment.ID, err)
+		}
+
+		_, err = c.paymentRepo.SettleRefund(ctx, refundPayment.ID, status)
+		if err != nil {
+			return err
+		}
+
+		refundPayments[i].Status = status
+	}
+
+	return nil
+}
+
+// apply moves the payment to the status of its charge when it is ahead of the one of the payment, and captures an
+// authorized charge. A status the payment already has, or went past, is left alone so applying it again changes
+// nothing.
+func (c charges) apply(ctx context.Context, payment model.TransactionPayment, status gateway.Status) (
+	model.TransactionPayment, error,
+) {
+	to := string(status)
+	if !paymentStatuses[to] {
+		return payment, &custom_error.BadRequest{Message: "payment status " + to + " is unknown"}
+	}
+
+	if advances(payment.Status, to) {
+		moved, err := c.paymentRepo.SetStatus(ctx, payment.ID, payment.Status, to)
+		if err != nil {
+			return payment, err
+		}
+
+		// a concurrent callback moved the payment first, it is applied from where that one left it
+		if !moved {
+			payment, err = c.paymentRepo.Get(ctx, query.Where(query.Eq("id", payment.ID)))
+			if err != nil {
+				return payment, err
+			}
+
+			return c.apply(ctx, payment, status)
+		}
+
+		payment.Status = to
+	}
+
+	if payment.Status != model.PaymentAuthorized {
+		return payment, nil
+	}
+
+	paymentProvider, ok := c.providers[payment.Provider]
+	if !ok {
+		return payment, &custom_error.ConflictError{Message: "payment provider " + payment.Provider + " isn't set up"}
+	}
+
+	charge, err := paymentProvider.Capture(ctx, payment.ChargeID)
+	if err != nil {
+		if err == gateway.ErrInvalidState {
+			return payment, &custom_error.ConflictError{
+				Message: "the payment provider can't capture charge " + payment.ChargeID + " anymore",
+			}
+		}
+
+		return payment, err
+	}
+	if charge.Status != gateway.StatusCaptured {
+		return payment, nil
+	}
+
+	return c.apply(ctx, payment, charge.Status)
+}
+
+// paymentStatuses are the statuses a payment can have.
+var paymentStatuses = map[string]bool{
+	model.PaymentPending: true, model.PaymentAuthorized: true, model.PaymentCaptured: true, model.PaymentFailed: true,
+	model.PaymentRefunded: true,
+}
+
+// advances reports whether a payment moves forward going from one status to the other: a pending payment is
+// authorized, captured or fails, an authorized one is captured or fails and a captured one is refunded.
+func advances(from string, to string) bool {
+	switch to {
+	case model.PaymentAuthorized:
+		return from == model.PaymentPending
+	case model.PaymentCaptured, model.PaymentFailed:
+		return from == model.PaymentPending || from == model.PaymentAuthorized
+	case model.PaymentRefunded:
+		return from == model.PaymentCaptured
+	}
+
+	return false
+}
+
+func transactionPaymentResponse(payment model.TransactionPayment) response.TransactionPaymentResponse {
+	return response.TransactionPaymentResponse{
+		ID:        payment.ID,
+		Method:    payment.Method,
+		Amount:    payment.Amount,
+		Reference: payment.Reference,
+		Status:    payment.Status,
+		Provider:  payment.Provider,
+		ChargeID:  payment.ChargeID,
+		Action:    payment.Action,
+		Refunded:  money.New(payment.Refunded.Amount, payment.Amount.Currency),
+	}
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"github.com/rehandwi03/test-case-backend-majoo/money"
+	"github.com/rehandwi03/test-case-backend-majoo/request"
+	"testing"
+)
+
+func TestRoundCash(t *testing.T) {
+	tests := []struct {
+		name      string
+		amount    int64
+		increment int64
+		mode      string
+		want      int64
+	}{
+		{name: "no rounding", amount: 1234, increment: 0, mode: model.CashRoundNearest, want: 1234},
+		{name: "increment of one", amount: 1234, increment: 1, mode: model.CashRoundUp, want: 1234},
+		{name: "already a multiple", amount: 1200, increment: 100, mode: model.CashRoundUp, want: 1200},
+		{name: "nearest down", amount: 1249, increment: 100, mode: model.CashRoundNearest, want: 1200},
+		{name: "nearest half up", amount: 1250, increment: 100, mode: model.CashRoundNearest, want: 1300},
+		{name: "nearest by default", amount: 1260, increment: 100, mode: "", want: 1300},
+		{name: "down", amount: 1299, increment: 100, mode: model.CashRoundDown, want: 1200},
+		{name: "up", amount: 1201, increment: 100, mode: model.CashRoundUp, want: 1300},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				got := roundCash(tt.amount, tt.increment, tt.mode)
+				if got != tt.want {
+					t.Errorf("roundCash(%d, %d, %q) = %d, want %d", tt.amount, tt.increment, tt.mode, got, tt.want)
+				}
+			},
+		)
+	}
+}
+
+func TestSettle(t *testing.T) {
+	merchant := model.Merchant{
+		Currency:         "IDR",
+		CashRounding:     10000,
+		CashRoundingMode: model.CashRoundNearest,
+		PaymentMethods: []model.MerchantPaymentMethod{
+			{Method: model.PaymentCash}, {Method: model.PaymentCard}, {Method: model.PaymentQRIS},
+		},
+	}
+	tender := func(method string, amount int64) request.TransactionPaymentRequest {
+		return request.TransactionPaymentRequest{Method: method, Amount: money.Money{Amount: amount}}
+	}
+
+	tests := []struct {
+		name         string
+		total        int64
+		tenders      []request.TransactionPaymentRequest
+		wantRounding int64
+		wantPaid     int64
+		wantChange   int64
+		wantErr      bool
+	}{
+		{
+			name: "exact card", total: 1234500, tenders: []request.TransactionPaymentRequest{
+				tender(model.PaymentCard, 1234500),
+			},
+			wantPaid: 1234500,
+		},
+		{
+			name: "cash rounded down with change", total: 1234500, tenders: []request.TransactionPaymentRequest{
+				tender(model.PaymentCash, 1500000),
+			},
+			wantRounding: -4500, wantPaid: 1500000, wantChange: 270000,
+		},
+		{
+			name: "cash rounded up", total: 1235000, tenders: []request.TransactionPaymentRequest{
+				tender(model.PaymentCash, 1240000),
+			},
+			wantRounding: 5000, wantPaid: 1240000, wantChange: 0,
+		},
+		{
+			name: "split, cash pays the rest", total: 1234500, tenders: []request.TransactionPaymentRequest{
+				tender(model.PaymentCard, 1000000), tender(model.PaymentCash, 300000),
+			},
+			wantRounding: -4500, wantPaid: 1300000, wantChange: 70000,
+		},
+		{
+			name: "nothing to pay", total: 0, tenders: nil,
+		},
+		{
+			name: "no payments for a total", total: 1000000, tenders: nil,
+			wantErr: true,
+		},
+		{
+			name: "card pays for nothing", total: 0, tenders: []request.TransactionPaymentRequest{
+				tender(model.PaymentCard, 10000),
+			},
+			wantErr: true,
+		},
+		{
+			name: "card pays more than the total", total: 1000000, tenders: []request.TransactionPaymentRequest{
+				tender(model.PaymentCard, 1000100),
+			},
+			wantErr: true,
+		},
+		{
+			name: "not covered", total: 1000000, tenders: []request.TransactionPaymentRequest{
+				tender(model.PaymentCash, 900000),
+			},
+			wantErr: true,
+		},
+		{
+			name: "cash with nothing left to pay", total: 1000000, tenders: []request.TransactionPaymentRequest{
+				tender(model.PaymentCard, 1000000), tender(model.PaymentCash, 10000),
+			},
+			wantErr: true,
+		},
+		{
+			name: "method not taken", total: 1000000, tenders: []request.TransactionPaymentRequest{
+				tender(model.PaymentEWallet, 1000000),
+			},
+			wantErr: true,
+		},
+		{
+			name: "another currency", total: 1000000, tenders: []request.TransactionPaymentRequest{
+				{Method: model.PaymentCard, Amount: money.New(1000000, "USD")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "not positive", total: 1000000, tenders: []request.TransactionPaymentRequest{
+				tender(model.PaymentCard, 0), tender(model.PaymentCash, 1000000),
+			},
+			wantErr: true,
+		},
+		{
+			name: "cash through a provider", total: 1000000, tenders: []request.TransactionPaymentRequest{
+				{Method: model.PaymentCash, Amount: money.Money{Amount: 1000000}, Provider: "mock"},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				transaction := model.Transaction{Total: money.New(tt.total, "IDR")}
+				err := settle(&transaction, merchant, tt.tenders)
+				if tt.wantErr {
+					if err == nil {
+						t.Fatal("settle succeeded, want an error")
+					}
+
+					return
+				}
+				if err != nil {
+					t.Fatalf("settle: %v", err)
+				}
+
+				if transaction.Rounding != money.New(tt.wantRounding, "IDR") {
+					t.Errorf("rounding = %v, want %d", transaction.Rounding, tt.wantRounding)
+				}
+				if transaction.Paid != money.New(tt.wantPaid, "IDR") {
+					t.Errorf("paid = %v, want %d", transaction.Paid, tt.wantPaid)
+				}
+				if transaction.Change != money.New(tt.wantChange, "IDR") {
+					t.Errorf("change = %v, want %d", transaction.Change, tt.wantChange)
+				}
+				if len(transaction.Payments) != len(tt.tenders) {
+					t.Errorf("%d payments recorded, want %d", len(transaction.Payments), len(tt.tenders))
+				}
+			},
+		)
+	}
+}
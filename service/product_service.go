@@ -2,51 +2,82 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"github.com/google/uuid"
 	"github.com/rehandwi03/test-case-backend-majoo/criteria"
 	custom_error "github.com/rehandwi03/test-case-backend-majoo/internal/error"
 	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"github.com/rehandwi03/test-case-backend-majoo/query"
 	"github.com/rehandwi03/test-case-backend-majoo/repository"
 	"github.com/rehandwi03/test-case-backend-majoo/request"
 	"github.com/rehandwi03/test-case-backend-majoo/response"
 	"github.com/rehandwi03/test-case-backend-majoo/util"
 	"gorm.io/gorm"
+	"strconv"
+	"strings"
 )
 
 type ProductService interface {
 	SaveProduct(ctx context.Context, request *request.ProductAddRequest) (uuid.UUID, error)
 	UpdateProduct(ctx context.Context, request *request.ProductUpdateRequest) (uuid.UUID, error)
-	DeleteProduct(ctx context.Context, params map[string]interface{}) error
-	GetByParam(ctx context.Context, params map[string]interface{}) (*response.ProductResponse, error)
+	DeleteProduct(ctx context.Context, spec query.Spec) error
+	RestoreProduct(ctx context.Context, productId uuid.UUID) error
+	GetByParam(ctx context.Context, spec query.Spec) (*response.ProductResponse, error)
 	Fetch(ctx context.Context, ProductCriteria criteria.ProductCriteria) (*util.PaginationResponse, error)
-	SaveProductIDImage(ctx context.Context, productId string, fileName string) error
+	// SaveProductIDImage sets the image of the product, store is called to keep the file only once the caller is
+	// allowed to change the product.
+	SaveProductIDImage(ctx context.Context, productId string, fileName string, store func() error) error
+	// SetProductCategories replaces the categories of the product, they have to belong to the merchant of its outlet.
+	SetProductCategories(ctx context.Context, request *request.ProductCategoriesRequest) error
+	// SetProductRecipe replaces the recipe of the product, its ingredients have to be ingredients of the same outlet
+	// whose stock isn't kept by variants.
+	SetProductRecipe(ctx context.Context, request *request.ProductRecipeRequest) error
+	SaveModifierGroup(ctx context.Context, request *request.ModifierGroupAddRequest) (uuid.UUID, error)
+	UpdateModifierGroup(ctx context.Context, request *request.ModifierGroupUpdateRequest) (uuid.UUID, error)
+	DeleteModifierGroup(ctx context.Context, productId uuid.UUID, groupId uuid.UUID) error
+	// SaveBundleSlot adds a slot to a bundle, its options are products of the outlet of the bundle that are sold on
+	// their own, or variants of them.
+	SaveBundleSlot(ctx context.Context, request *request.BundleSlotAddRequest) (uuid.UUID, error)
+	UpdateBundleSlot(ctx context.Context, request *request.BundleSlotUpdateRequest) (uuid.UUID, error)
+	DeleteBundleSlot(ctx context.Context, productId uuid.UUID, slotId uuid.UUID) error
 }
 
 type productService struct {
-	productRepo repository.ProductRepository
-	outletRepo  repository.OutletRepository
+	productRepo       repository.ProductRepository
+	outletRepo        repository.OutletRepository
+	categoryRepo      repository.CategoryRepository
+	modifierGroupRepo repository.ModifierGroupRepository
+	bundleSlotRepo    repository.BundleSlotRepository
+	accessGuard       AccessGuard
 }
 
 func NewProductService(
 	productRepository repository.ProductRepository, outletRepository repository.OutletRepository,
+	categoryRepository repository.CategoryRepository, modifierGroupRepository repository.ModifierGroupRepository,
+	bundleSlotRepository repository.BundleSlotRepository, accessGuard AccessGuard,
 ) ProductService {
-	return &productService{productRepo: productRepository, outletRepo: outletRepository}
+	return &productService{
+		productRepo: productRepository, outletRepo: outletRepository, categoryRepo: categoryRepository,
+		modifierGroupRepo: modifierGroupRepository, bundleSlotRepo: bundleSlotRepository, accessGuard: accessGuard,
+	}
 }
 
-func (p *productService) SaveProductIDImage(ctx context.Context, productId string, fileName string) error {
-	params := map[string]interface{}{
-		"where": map[string]interface{}{
-			"default": map[string]interface{}{
-				"id = ?": productId,
-			},
-		},
+func (p *productService) SaveProductIDImage(
+	ctx context.Context, productId string, fileName string, store func() error,
+) error {
+	id, err := uuid.Parse(productId)
+	if err != nil {
+		return &custom_error.BadRequest{Message: "product id is invalid"}
 	}
 
-	product, err := p.productRepo.GetByParam(ctx, params)
+	product, err := p.accessGuard.Product(ctx, id, model.RoleOwner, model.RoleManager)
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return &custom_error.NotFoundError{Message: "product not found"}
-		}
+		return err
+	}
+
+	err = store()
+	if err != nil {
+		return err
 	}
 
 	product.Image = fileName
@@ -60,29 +91,32 @@ func (p *productService) SaveProductIDImage(ctx context.Context, productId strin
 }
 
 func (p *productService) SaveProduct(ctx context.Context, request *request.ProductAddRequest) (uuid.UUID, error) {
-	params := map[string]interface{}{
-		"where": map[string]interface{}{
-			"default": map[string]interface{}{
-				"id = ?": request.OutletID,
-			},
-		},
-	}
-	_, err := p.outletRepo.GetByParams(ctx, params)
+	merchant, err := p.outletMerchant(ctx, request.OutletID)
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return uuid.Nil, &custom_error.NotFoundError{Message: "user not found"}
-		}
+		return uuid.Nil, err
+	}
 
+	if request.IsBundle && request.IsIngredient {
+		return uuid.Nil, &custom_error.BadRequest{Message: "a bundle can't be an ingredient", Field: "is_bundle"}
+	}
+
+	price, err := priceIn(request.Price, merchant.Currency, "price")
+	if err != nil {
 		return uuid.Nil, err
 	}
 
 	res, err := p.productRepo.Save(
 		ctx, model.Product{
-			OutletID:    request.OutletID,
-			Name:        request.Name,
-			Description: request.Description,
-			Stock:       request.Stock,
-			Price:       request.Price,
+			OutletID:        request.OutletID,
+			Name:            request.Name,
+			Description:     request.Description,
+			SKU:             request.SKU,
+			IsIngredient:    request.IsIngredient,
+			IsBundle:        request.IsBundle,
+			Stock:           request.Stock,
+			ReorderPoint:    request.ReorderPoint,
+			ReorderQuantity: request.ReorderQuantity,
+			Price:           price,
 		},
 	)
 	if err != nil {
@@ -95,49 +129,50 @@ func (p *productService) SaveProduct(ctx context.Context, request *request.Produ
 func (p *productService) UpdateProduct(ctx context.Context, request *request.ProductUpdateRequest) (
 	uuid.UUID, error,
 ) {
-	param := map[string]interface{}{
-		"where": map[string]interface{}{
-			"or": map[string]interface{}{},
-			"default": map[string]interface{}{
-				"id = ?": request.ID,
-			},
-		},
+	ProductData, err := p.accessGuard.Product(ctx, request.ID, model.RoleOwner, model.RoleManager)
+	if err != nil {
+		return uuid.Nil, err
 	}
 
-	outletParam := map[string]interface{}{
-		"where": map[string]interface{}{
-			"default": map[string]interface{}{
-				"id = ?": request.OutletID,
-			},
-		},
+	// the stock, cost and variants of a product are kept in its outlet, goods move between outlets by transfers
+	if request.OutletID != ProductData.OutletID {
+		return uuid.Nil, &custom_error.BadRequest{
+			Message: "a product can't move to another outlet, transfer its stock instead", Field: "outlet_id",
+		}
 	}
 
-	_, err := p.outletRepo.GetByParam(ctx, outletParam)
+	merchant, err := p.outletMerchant(ctx, ProductData.OutletID)
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return uuid.Nil, &custom_error.NotFoundError{Message: "user not found"}
-		}
+		return uuid.Nil, err
+	}
 
+	err = checkProductKind(ProductData, request)
+	if err != nil {
 		return uuid.Nil, err
 	}
 
-	ProductData, err := p.productRepo.GetByParam(ctx, param)
+	err = p.checkProductUses(ctx, ProductData, request)
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return uuid.Nil, &custom_error.NotFoundError{Message: err.Error()}
-		}
+		return uuid.Nil, err
+	}
 
+	price, err := priceIn(request.Price, merchant.Currency, "price")
+	if err != nil {
 		return uuid.Nil, err
 	}
 
 	res, err := p.productRepo.Save(
 		ctx, model.Product{
-			ID:          ProductData.ID,
-			OutletID:    request.OutletID,
-			Name:        request.Name,
-			Description: request.Description,
-			Stock:       request.Stock,
-			Price:       request.Price,
+			ID:              ProductData.ID,
+			OutletID:        ProductData.OutletID,
+			Name:            request.Name,
+			Description:     request.Description,
+			SKU:             request.SKU,
+			IsIngredient:    request.IsIngredient,
+			IsBundle:        request.IsBundle,
+			ReorderPoint:    request.ReorderPoint,
+			ReorderQuantity: request.ReorderQuantity,
+			Price:           price,
 			Audit: model.Audit{
 				CreatedAt: ProductData.CreatedAt,
 			},
@@ -150,8 +185,109 @@ func (p *productService) UpdateProduct(ctx context.Context, request *request.Pro
 	return res, nil
 }
 
-func (p *productService) DeleteProduct(ctx context.Context, params map[string]interface{}) error {
-	ProductData, err := p.productRepo.GetByParam(ctx, params)
+// checkProductKind makes sure an update doesn't turn a product into a kind its recipe or bundle slots don't fit.
+func checkProductKind(product model.Product, request *request.ProductUpdateRequest) error {
+	if request.IsBundle && request.IsIngredient {
+		return &custom_error.BadRequest{Message: "a bundle can't be an ingredient", Field: "is_bundle"}
+	}
+
+	if len(product.Recipe) > 0 {
+		if request.IsIngredient || request.IsBundle {
+			return &custom_error.BadRequest{
+				Message: "a product with a recipe can't be an ingredient or a bundle", Field: "is_ingredient",
+			}
+		}
+	}
+
+	if request.IsBundle && len(product.Variants) > 0 {
+		return &custom_error.BadRequest{Message: "a product with variants can't be a bundle", Field: "is_bundle"}
+	}
+
+	if len(product.BundleSlots) > 0 && !request.IsBundle {
+		return &custom_error.BadRequest{
+			Message: "delete the slots of the bundle before it stops being one", Field: "is_bundle",
+		}
+	}
+
+	return nil
+}
+
+// checkProductUses makes sure an update doesn't turn a product into a kind the products using it don't fit.
+func (p *productService) checkProductUses(
+	ctx context.Context, product model.Product, request *request.ProductUpdateRequest,
+) error {
+	if product.IsIngredient && !request.IsIngredient {
+		recipes, err := p.productRepo.List(
+			ctx, query.Where(
+				query.Raw("id IN (SELECT product_id FROM recipe_lines WHERE ingredient_id = ?)", product.ID),
+			).OrderBy(query.Sort{Field: "name"}),
+		)
+		if err != nil {
+			return err
+		}
+
+		if len(recipes) > 0 {
+			return &custom_error.ConflictError{
+				Message: "the recipes of " + productNames(recipes) + " use " + product.Name +
+					", take it out of them before it stops being an ingredient",
+				Field: "is_ingredient",
+			}
+		}
+	}
+
+	// options fill slots only with products sold on their own
+	if (request.IsBundle && !product.IsBundle) || (request.IsIngredient && !product.IsIngredient) {
+		bundles, err := p.productRepo.List(
+			ctx, query.Where(
+				query.Raw(
+					`id IN (SELECT s.product_id FROM bundle_slots s JOIN bundle_options o ON o.slot_id = s.id
+					WHERE o.product_id = ? AND s.deleted_at IS NULL AND o.deleted_at IS NULL)`, product.ID,
+				),
+			).OrderBy(query.Sort{Field: "name"}),
+		)
+		if err != nil {
+			return err
+		}
+
+		if len(bundles) > 0 {
+			field := "is_bundle"
+			if request.IsIngredient {
+				field = "is_ingredient"
+			}
+
+			return &custom_error.ConflictError{
+				Message: "the slots of " + productNames(bundles) + " are filled with " + product.Name +
+					", take it out of them before it becomes a bundle or an ingredient",
+				Field: field,
+			}
+		}
+	}
+
+	return nil
+}
+
+// productNames lists the names of the products, separated by commas.
+func productNames(products []model.Product) string {
+	var names []string
+	for _, product := range products {
+		names = append(names, product.Name)
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// outletMerchant checks the caller manages the outlet and returns its merchant, whose currency prices are kept in.
+func (p *productService) outletMerchant(ctx context.Context, outletId uuid.UUID) (model.Merchant, error) {
+	outlet, err := p.accessGuard.Outlet(ctx, outletId, model.RoleOwner, model.RoleManager)
+	if err != nil {
+		return model.Merchant{}, err
+	}
+
+	return p.accessGuard.Merchant(ctx, outlet.MerchantID, model.RoleOwner, model.RoleManager)
+}
+
+func (p *productService) DeleteProduct(ctx context.Context, spec query.Spec) error {
+	ProductData, err := p.productRepo.Get(ctx, spec)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return &custom_error.NotFoundError{Message: err.Error()}
@@ -160,6 +296,11 @@ func (p *productService) DeleteProduct(ctx context.Context, params map[string]in
 		return err
 	}
 
+	_, err = p.accessGuard.Outlet(ctx, ProductData.OutletID, model.RoleOwner, model.RoleManager)
+	if err != nil {
+		return err
+	}
+
 	err = p.productRepo.Delete(ctx, &ProductData)
 	if err != nil {
 		return err
@@ -168,10 +309,33 @@ func (p *productService) DeleteProduct(ctx context.Context, params map[string]in
 	return nil
 }
 
-func (p *productService) GetByParam(ctx context.Context, params map[string]interface{}) (
+// RestoreProduct brings back a deleted product together with everything deleted along with it.
+func (p *productService) RestoreProduct(ctx context.Context, productId uuid.UUID) error {
+	product, err := p.accessGuard.IncludeDeleted().Product(ctx, productId, model.RoleOwner, model.RoleManager)
+	if err != nil {
+		return err
+	}
+
+	if !product.DeletedAt.Valid {
+		return &custom_error.ConflictError{Message: "product isn't deleted"}
+	}
+
+	err = p.productRepo.Restore(ctx, product.ID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return &custom_error.ConflictError{Message: "product isn't deleted"}
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (p *productService) GetByParam(ctx context.Context, spec query.Spec) (
 	*response.ProductResponse, error,
 ) {
-	productData, err := p.productRepo.GetByParam(ctx, params)
+	productData, err := p.productRepo.Get(ctx, spec)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, &custom_error.NotFoundError{Message: "product not found"}
@@ -180,50 +344,577 @@ func (p *productService) GetByParam(ctx context.Context, params map[string]inter
 		return nil, err
 	}
 
+	_, err = p.accessGuard.Outlet(ctx, productData.OutletID, model.RoleOwner, model.RoleManager, model.RoleCashier)
+	if err != nil {
+		return nil, err
+	}
+
 	response := new(response.ProductResponse)
 	response.ID = productData.ID
 	response.OutletID = productData.OutletID
 	response.Name = productData.Name
 	response.Description = productData.Description
+	response.SKU = productData.SKU
+	response.IsIngredient = productData.IsIngredient
+	response.IsBundle = productData.IsBundle
 	response.Stock = productData.Stock
+	response.ReorderPoint = productData.ReorderPoint
+	response.ReorderQuantity = productData.ReorderQuantity
 	response.Price = productData.Price
+	response.CostPrice = optionalMoney(productData.CostPrice)
+	response.AverageCost = optionalMoney(productData.AverageCost)
+	response.Categories = categoryResponses(productData.Categories)
+	response.Options = productOptionResponses(productData.Options)
+	response.Variants = productVariantResponses(productData)
+	response.ModifierGroups = modifierGroupResponses(productData.ModifierGroups)
+	response.Recipe = recipeResponses(productData.Recipe)
+	response.BundleSlots = bundleSlotResponses(productData.BundleSlots)
 	response.CreatedAt = productData.CreatedAt.Time
 
 	return response, nil
 }
 
-func (p *productService) Fetch(ctx context.Context, criteria criteria.ProductCriteria) (
-	*util.PaginationResponse, error,
+func (p *productService) SetProductCategories(ctx context.Context, request *request.ProductCategoriesRequest) error {
+	product, err := p.accessGuard.Product(ctx, request.ProductID, model.RoleOwner, model.RoleManager)
+	if err != nil {
+		return err
+	}
+
+	outlet, err := p.accessGuard.Outlet(ctx, product.OutletID, model.RoleOwner, model.RoleManager)
+	if err != nil {
+		return err
+	}
+
+	seen := map[uuid.UUID]bool{}
+	categoryIds := []uuid.UUID{}
+	for _, categoryId := range request.CategoryIDs {
+		if !seen[categoryId] {
+			seen[categoryId] = true
+			categoryIds = append(categoryIds, categoryId)
+		}
+	}
+
+	if len(categoryIds) > 0 {
+		count, err := p.categoryRepo.Count(
+			ctx, query.Where(query.In("id", categoryIds), query.Eq("merchant_id", outlet.MerchantID)),
+		)
+		if err != nil {
+			return err
+		}
+
+		if count != int64(len(categoryIds)) {
+			return &custom_error.BadRequest{Message: "category not found", Field: "category_ids"}
+		}
+	}
+
+	return p.productRepo.SetCategories(ctx, product.ID, categoryIds)
+}
+
+func (p *productService) SetProductRecipe(ctx context.Context, request *request.ProductRecipeRequest) error {
+	product, err := p.accessGuard.Product(ctx, request.ProductID, model.RoleOwner, model.RoleManager)
+	if err != nil {
+		return err
+	}
+
+	if product.IsIngredient && len(request.Lines) > 0 {
+		return &custom_error.BadRequest{Message: "an ingredient can't have a recipe", Field: "lines"}
+	}
+	if product.IsBundle && len(request.Lines) > 0 {
+		return &custom_error.BadRequest{
+			Message: "a bundle is sold as the products filling its slots, it can't have a recipe", Field: "lines",
+		}
+	}
+
+	var ingredientIds []uuid.UUID
+	seen := map[uuid.UUID]bool{}
+	for _, line := range request.Lines {
+		if seen[line.IngredientID] {
+			return &custom_error.BadRequest{
+				Message: "ingredient " + line.IngredientID.String() + " is in the recipe more than once", Field: "lines",
+			}
+		}
+		seen[line.IngredientID] = true
+		ingredientIds = append(ingredientIds, line.IngredientID)
+	}
+
+	ingredientMap := map[uuid.UUID]model.Product{}
+	if len(ingredientIds) > 0 {
+		ingredients, err := p.productRepo.List(
+			ctx, query.Where(query.In("id", ingredientIds), query.Eq("outlet_id", product.OutletID)),
+		)
+		if err != nil {
+			return err
+		}
+
+		for _, ingredient := range ingredients {
+			ingredientMap[ingredient.ID] = ingredient
+		}
+	}
+
+	lines := []model.RecipeLine{}
+	for _, line := range request.Lines {
+		ingredient, ok := ingredientMap[line.IngredientID]
+		if !ok {
+			return &custom_error.BadRequest{
+				Message: "ingredient " + line.IngredientID.String() + " not found", Field: "lines",
+			}
+		}
+		if !ingredient.IsIngredient {
+			return &custom_error.BadRequest{Message: ingredient.Name + " isn't an ingredient", Field: "lines"}
+		}
+		// the stock of a product with variants is kept by its variants, an ingredient has a single stock
+		if len(ingredient.Variants) > 0 {
+			return &custom_error.BadRequest{
+				Message: "ingredient " + ingredient.Name + " has variants", Field: "lines",
+			}
+		}
+
+		lines = append(lines, model.RecipeLine{IngredientID: ingredient.ID, Quantity: line.Quantity})
+	}
+
+	return p.productRepo.SetRecipe(ctx, product.ID, lines)
+}
+
+func (p *productService) SaveModifierGroup(ctx context.Context, request *request.ModifierGroupAddRequest) (
+	uuid.UUID, error,
 ) {
-	params := map[string]interface{}{
-		"where": map[string]interface{}{
-			"default": map[string]interface{}{
-			},
-			"pagination": map[string]interface{}{
-				"page":  criteria.Pagination.Page,
-				"sort":  criteria.Pagination.Sort,
-				"limit": criteria.Pagination.Limit,
-			},
+	product, err := p.accessGuard.Product(ctx, request.ProductID, model.RoleOwner, model.RoleManager)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	group := model.ModifierGroup{
+		ProductID: request.ProductID,
+		Name:      request.Name,
+		MinSelect: request.MinSelect,
+		MaxSelect: request.MaxSelect,
+		SortOrder: request.SortOrder,
+	}
+	for _, modifier := range request.Modifiers {
+		if modifier.ID != uuid.Nil {
+			return uuid.Nil, &custom_error.BadRequest{
+				Message: "a new modifier group can't have existing modifiers", Field: "modifiers",
+			}
+		}
+
+		modifierData, err := modifierModel(modifier, product.Price.Currency)
+		if err != nil {
+			return uuid.Nil, err
+		}
+
+		group.Modifiers = append(group.Modifiers, modifierData)
+	}
+
+	err = checkSelectRange(group)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	res, err := p.modifierGroupRepo.Save(ctx, group)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return res, nil
+}
+
+func (p *productService) UpdateModifierGroup(ctx context.Context, request *request.ModifierGroupUpdateRequest) (
+	uuid.UUID, error,
+) {
+	product, err := p.accessGuard.Product(ctx, request.ProductID, model.RoleOwner, model.RoleManager)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	groupData, err := p.getModifierGroup(ctx, request.ProductID, request.ID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	group := model.ModifierGroup{
+		ID:        groupData.ID,
+		ProductID: groupData.ProductID,
+		Name:      request.Name,
+		MinSelect: request.MinSelect,
+		MaxSelect: request.MaxSelect,
+		SortOrder: request.SortOrder,
+		Audit: model.Audit{
+			CreatedAt: groupData.CreatedAt,
 		},
 	}
+	for _, modifier := range request.Modifiers {
+		modifierData, err := modifierModel(modifier, product.Price.Currency)
+		if err != nil {
+			return uuid.Nil, err
+		}
 
-	if criteria.Name != "" {
-		params["where"].(map[string]interface{})["default"] = map[string]interface{}{
-			"name ILIKE ?": "%" + criteria.Name + "%",
+		group.Modifiers = append(group.Modifiers, modifierData)
+	}
+
+	err = checkSelectRange(group)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	res, err := p.modifierGroupRepo.Save(ctx, group)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return res, nil
+}
+
+func (p *productService) DeleteModifierGroup(ctx context.Context, productId uuid.UUID, groupId uuid.UUID) error {
+	_, err := p.accessGuard.Product(ctx, productId, model.RoleOwner, model.RoleManager)
+	if err != nil {
+		return err
+	}
+
+	group, err := p.getModifierGroup(ctx, productId, groupId)
+	if err != nil {
+		return err
+	}
+
+	err = p.modifierGroupRepo.Delete(ctx, &group)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (p *productService) SaveBundleSlot(ctx context.Context, request *request.BundleSlotAddRequest) (
+	uuid.UUID, error,
+) {
+	product, err := p.bundle(ctx, request.ProductID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	slot := model.BundleSlot{
+		ProductID: request.ProductID,
+		Name:      request.Name,
+		Quantity:  request.Quantity,
+		SortOrder: request.SortOrder,
+	}
+	for _, option := range request.Options {
+		if option.ID != uuid.Nil {
+			return uuid.Nil, &custom_error.BadRequest{
+				Message: "a new bundle slot can't have existing options", Field: "options",
+			}
+		}
+	}
+
+	slot.Options, err = p.bundleOptions(ctx, product, request.Options)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	res, err := p.bundleSlotRepo.Save(ctx, slot)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return res, nil
+}
+
+func (p *productService) UpdateBundleSlot(ctx context.Context, request *request.BundleSlotUpdateRequest) (
+	uuid.UUID, error,
+) {
+	product, err := p.bundle(ctx, request.ProductID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	slotData, err := p.getBundleSlot(ctx, request.ProductID, request.ID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	slot := model.BundleSlot{
+		ID:        slotData.ID,
+		ProductID: slotData.ProductID,
+		Name:      request.Name,
+		Quantity:  request.Quantity,
+		SortOrder: request.SortOrder,
+		Audit: model.Audit{
+			CreatedAt: slotData.CreatedAt,
+		},
+	}
+
+	slot.Options, err = p.bundleOptions(ctx, product, request.Options)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	res, err := p.bundleSlotRepo.Save(ctx, slot)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return res, nil
+}
+
+func (p *productService) DeleteBundleSlot(ctx context.Context, productId uuid.UUID, slotId uuid.UUID) error {
+	_, err := p.accessGuard.Product(ctx, productId, model.RoleOwner, model.RoleManager)
+	if err != nil {
+		return err
+	}
+
+	slot, err := p.getBundleSlot(ctx, productId, slotId)
+	if err != nil {
+		return err
+	}
+
+	err = p.bundleSlotRepo.Delete(ctx, &slot)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// bundle returns the product after checking the caller manages it and it is a bundle.
+func (p *productService) bundle(ctx context.Context, productId uuid.UUID) (model.Product, error) {
+	product, err := p.accessGuard.Product(ctx, productId, model.RoleOwner, model.RoleManager)
+	if err != nil {
+		return product, err
+	}
+
+	if !product.IsBundle {
+		return product, &custom_error.BadRequest{Message: "only a bundle has slots"}
+	}
+
+	return product, nil
+}
+
+func (p *productService) getBundleSlot(ctx context.Context, productId uuid.UUID, slotId uuid.UUID) (
+	model.BundleSlot, error,
+) {
+	slot, err := p.bundleSlotRepo.Get(ctx, query.Where(query.Eq("id", slotId), query.Eq("product_id", productId)))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return slot, &custom_error.NotFoundError{Message: "bundle slot not found"}
+		}
+
+		return slot, err
+	}
+
+	return slot, nil
+}
+
+// bundleOptions checks the options fill a slot of the bundle with products of its outlet that are sold on their own,
+// or variants of them, and prices them in the currency of the bundle.
+func (p *productService) bundleOptions(
+	ctx context.Context, bundle model.Product, options []request.BundleOptionRequest,
+) ([]model.BundleOption, error) {
+	var productIds []uuid.UUID
+	for _, option := range options {
+		productIds = append(productIds, option.ProductID)
+	}
+
+	products, err := p.productRepo.List(
+		ctx, query.Where(query.In("id", productIds), query.Eq("outlet_id", bundle.OutletID)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	productMap := map[uuid.UUID]model.Product{}
+	for _, product := range products {
+		productMap[product.ID] = product
+	}
+
+	var res []model.BundleOption
+	seen := map[stockItem]bool{}
+	for _, option := range options {
+		product, ok := productMap[option.ProductID]
+		if !ok {
+			return nil, &custom_error.BadRequest{
+				Message: "product " + option.ProductID.String() + " not found", Field: "options",
+			}
+		}
+		if product.IsBundle || product.IsIngredient {
+			return nil, &custom_error.BadRequest{
+				Message: product.Name + " isn't sold on its own, it can't fill a slot", Field: "options",
+			}
+		}
+
+		// a product with variants is sold as one of them
+		if option.VariantID != nil || len(product.Variants) > 0 {
+			if option.VariantID == nil {
+				return nil, &custom_error.BadRequest{
+					Message: "choose a variant of product " + product.Name, Field: "options",
+				}
+			}
+
+			if _, ok := findVariant(product, *option.VariantID); !ok {
+				return nil, &custom_error.BadRequest{Message: "variant not found", Field: "options"}
+			}
+		}
+
+		item := stockItemOf(product.ID, option.VariantID)
+		if seen[item] {
+			return nil, &custom_error.BadRequest{
+				Message: product.Name + " is an option of the slot more than once", Field: "options",
+			}
+		}
+		seen[item] = true
+
+		priceDelta, err := inCurrency(option.PriceDelta, bundle.Price.Currency, "price_delta")
+		if err != nil {
+			return nil, err
 		}
+
+		res = append(
+			res, model.BundleOption{
+				ID:         option.ID,
+				ProductID:  product.ID,
+				VariantID:  option.VariantID,
+				PriceDelta: priceDelta,
+				SortOrder:  option.SortOrder,
+			},
+		)
 	}
-	if criteria.Stock != "" {
-		params["where"].(map[string]interface{})["default"] = map[string]interface{}{
-			"stock = ?": criteria.Stock,
+
+	return res, nil
+}
+
+func (p *productService) getModifierGroup(ctx context.Context, productId uuid.UUID, groupId uuid.UUID) (
+	model.ModifierGroup, error,
+) {
+	group, err := p.modifierGroupRepo.Get(
+		ctx, query.Where(query.Eq("id", groupId), query.Eq("product_id", productId)),
+	)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return group, &custom_error.NotFoundError{Message: "modifier group not found"}
 		}
+
+		return group, err
+	}
+
+	return group, nil
+}
+
+func (p *productService) Fetch(ctx context.Context, criteria criteria.ProductCriteria) (
+	*util.PaginationResponse, error,
+) {
+	merchantIds, all, err := p.accessGuard.MerchantIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := query.Where()
+	if !all {
+		spec = spec.And(
+			query.Raw(
+				"outlet_id IN (SELECT id FROM outlets WHERE merchant_id IN ? AND deleted_at IS NULL)", merchantIds,
+			),
+		)
+	}
+
+	outletIds, err := parseUUIDsFilter("outlet_id", criteria.OutletIDs)
+	if err != nil {
+		return nil, err
 	}
-	if criteria.Price != "" {
-		params["where"].(map[string]interface{})["default"] = map[string]interface{}{
-			"price = ?": criteria.Price,
+	if len(outletIds) > 0 {
+		spec = spec.And(query.In("outlet_id", outletIds))
+	}
+
+	// a category matches the products of its sub categories too
+	categoryIds, err := parseUUIDsFilter("category_id", criteria.CategoryIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(categoryIds) > 0 {
+		spec = spec.And(
+			query.Raw(
+				`id IN (SELECT product_id FROM product_categories WHERE category_id IN (
+					WITH RECURSIVE tree AS (
+						SELECT id FROM categories WHERE id IN ? AND deleted_at IS NULL
+						UNION ALL
+						SELECT c.id FROM categories c JOIN tree t ON c.parent_id = t.id WHERE c.deleted_at IS NULL
+					)
+					SELECT id FROM tree
+				))`, categoryIds,
+			),
+		)
+	}
+
+	if criteria.Barcode != "" {
+		spec = spec.And(
+			query.Raw(
+				"id IN (SELECT product_id FROM product_variants WHERE barcode = ? AND deleted_at IS NULL)",
+				criteria.Barcode,
+			),
+		)
+	}
+
+	if criteria.Ingredient != "" {
+		ingredient, err := strconv.ParseBool(criteria.Ingredient)
+		if err != nil {
+			return nil, &custom_error.BadRequest{Message: "ingredient must be true or false", Field: "ingredient"}
 		}
+
+		spec = spec.And(query.Eq("is_ingredient", ingredient))
+	}
+
+	if criteria.Name != "" {
+		spec = spec.And(query.Contains("name", criteria.Name))
+	}
+	if criteria.Keyword != "" {
+		spec = spec.And(
+			query.Or(query.Contains("name", criteria.Keyword), query.Contains("description", criteria.Keyword)),
+		)
+	}
+
+	stock, err := parseIntFilter("stock", criteria.Stock)
+	if err != nil {
+		return nil, err
+	}
+	minStock, err := parseIntFilter("min_stock", criteria.MinStock)
+	if err != nil {
+		return nil, err
+	}
+	maxStock, err := parseIntFilter("max_stock", criteria.MaxStock)
+	if err != nil {
+		return nil, err
+	}
+	if stock != nil {
+		spec = spec.And(query.Eq("stock", stock))
+	}
+	spec = spec.And(query.Between("stock", minStock, maxStock))
+
+	// prices are filtered in minor units, like the amounts of the responses
+	price, err := parseIntFilter("price", criteria.Price)
+	if err != nil {
+		return nil, err
+	}
+	minPrice, err := parseIntFilter("min_price", criteria.MinPrice)
+	if err != nil {
+		return nil, err
+	}
+	maxPrice, err := parseIntFilter("max_price", criteria.MaxPrice)
+	if err != nil {
+		return nil, err
+	}
+	if price != nil {
+		spec = spec.And(query.Eq("price_amount", price))
+	}
+	spec = spec.And(query.Between("price_amount", minPrice, maxPrice))
+
+	spec, err = includeDeleted(ctx, spec, criteria.IncludeDeleted)
+	if err != nil {
+		return nil, err
 	}
 
-	res, rowCount, err := p.productRepo.Fetch(ctx, params)
+	spec, err = paginate(spec, &criteria.Pagination, "name", "stock", "price:price_amount", "created_at")
+	if err != nil {
+		return nil, err
+	}
+
+	res, rowCount, err := p.productRepo.Fetch(ctx, spec)
 	if err != nil {
 		return nil, err
 	}
@@ -236,9 +927,26 @@ func (p *productService) Fetch(ctx context.Context, criteria criteria.ProductCri
 		data.OutletID = val.OutletID
 		data.Name = val.Name
 		data.Description = val.Description
+		data.SKU = val.SKU
+		data.IsIngredient = val.IsIngredient
+		data.IsBundle = val.IsBundle
 		data.Stock = val.Stock
+		data.ReorderPoint = val.ReorderPoint
+		data.ReorderQuantity = val.ReorderQuantity
 		data.Price = val.Price
+		data.CostPrice = optionalMoney(val.CostPrice)
+		data.AverageCost = optionalMoney(val.AverageCost)
+		data.Categories = categoryResponses(val.Categories)
+		data.Options = productOptionResponses(val.Options)
+		data.Variants = productVariantResponses(val)
+		data.ModifierGroups = modifierGroupResponses(val.ModifierGroups)
+		data.Recipe = recipeResponses(val.Recipe)
+		data.BundleSlots = bundleSlotResponses(val.BundleSlots)
 		data.CreatedAt = val.CreatedAt.Time
+		if val.DeletedAt.Valid {
+			deletedAt := val.DeletedAt.Time
+			data.DeletedAt = &deletedAt
+		}
 
 		responseData = append(responseData, data)
 	}
@@ -247,3 +955,156 @@ func (p *productService) Fetch(ctx context.Context, criteria criteria.ProductCri
 
 	return &resPagination, nil
 }
+
+// checkSelectRange makes sure the selection rules of a group can be met by its modifiers.
+func checkSelectRange(group model.ModifierGroup) error {
+	if group.MaxSelect > len(group.Modifiers) {
+		return &custom_error.BadRequest{
+			Message: "max_select can't be more than the number of modifiers", Field: "max_select",
+		}
+	}
+
+	return nil
+}
+
+// selectModifiers resolves the modifiers chosen for a line item of the product and checks them against the
+// selection rules of its modifier groups: every modifier belongs to a group of the product, is chosen once, and every
+// group gets between its min_select and max_select modifiers.
+func selectModifiers(product model.Product, modifierIds []uuid.UUID) ([]model.Modifier, error) {
+	groups := map[uuid.UUID]model.ModifierGroup{}
+	modifiers := map[uuid.UUID]model.Modifier{}
+	for _, group := range product.ModifierGroups {
+		groups[group.ID] = group
+		for _, modifier := range group.Modifiers {
+			modifiers[modifier.ID] = modifier
+		}
+	}
+
+	var selected []model.Modifier
+	counts := map[uuid.UUID]int{}
+	chosen := map[uuid.UUID]bool{}
+	for _, modifierId := range modifierIds {
+		modifier, ok := modifiers[modifierId]
+		if !ok {
+			return nil, &custom_error.BadRequest{
+				Message: "modifier " + modifierId.String() + " isn't available for product " + product.Name,
+				Field:   "modifier_ids",
+			}
+		}
+
+		if chosen[modifierId] {
+			return nil, &custom_error.BadRequest{
+				Message: "modifier " + modifier.Name + " is chosen more than once", Field: "modifier_ids",
+			}
+		}
+		chosen[modifierId] = true
+
+		counts[modifier.GroupID]++
+		selected = append(selected, modifier)
+	}
+
+	for _, group := range product.ModifierGroups {
+		count := counts[group.ID]
+		if count < group.MinSelect || count > group.MaxSelect {
+			return nil, &custom_error.BadRequest{
+				Message: fmt.Sprintf(
+					"choose %d to %d of %s for product %s", group.MinSelect, group.MaxSelect, group.Name, product.Name,
+				),
+				Field: "modifier_ids",
+			}
+		}
+	}
+
+	return selected, nil
+}
+
+func modifierModel(modifier request.ModifierRequest, currency string) (model.Modifier, error) {
+	priceDelta, err := inCurrency(modifier.PriceDelta, currency, "price_delta")
+	if err != nil {
+		return model.Modifier{}, err
+	}
+
+	return model.Modifier{
+		ID:         modifier.ID,
+		Name:       modifier.Name,
+		PriceDelta: priceDelta,
+		SortOrder:  modifier.SortOrder,
+	}, nil
+}
+
+func modifierGroupResponses(groups []model.ModifierGroup) []response.ModifierGroupResponse {
+	responses := []response.ModifierGroupResponse{}
+	for _, group := range groups {
+		data := response.ModifierGroupResponse{
+			ID:        group.ID,
+			Name:      group.Name,
+			MinSelect: group.MinSelect,
+			MaxSelect: group.MaxSelect,
+			SortOrder: group.SortOrder,
+			Modifiers: []response.ModifierResponse{},
+		}
+		for _, modifier := range group.Modifiers {
+			data.Modifiers = append(
+				data.Modifiers, response.ModifierResponse{
+					ID:         modifier.ID,
+					Name:       modifier.Name,
+					PriceDelta: modifier.PriceDelta,
+					SortOrder:  modifier.SortOrder,
+				},
+			)
+		}
+
+		responses = append(responses, data)
+	}
+
+	return responses
+}
+
+func recipeResponses(lines []model.RecipeLine) []response.RecipeLineResponse {
+	responses := []response.RecipeLineResponse{}
+	for _, line := range lines {
+		lineData := response.RecipeLineResponse{IngredientID: line.IngredientID, Quantity: line.Quantity}
+		if line.Ingredient != nil {
+			lineData.Name = line.Ingredient.Name
+			lineData.SKU = line.Ingredient.SKU
+		}
+
+		responses = append(responses, lineData)
+	}
+
+	return responses
+}
+
+func bundleSlotResponses(slots []model.BundleSlot) []response.BundleSlotResponse {
+	responses := []response.BundleSlotResponse{}
+	for _, slot := range slots {
+		data := response.BundleSlotResponse{
+			ID:        slot.ID,
+			Name:      slot.Name,
+			Quantity:  slot.Quantity,
+			SortOrder: slot.SortOrder,
+			Options:   []response.BundleOptionResponse{},
+		}
+		for _, option := range slot.Options {
+			optionData := response.BundleOptionResponse{
+				ID:         option.ID,
+				ProductID:  option.ProductID,
+				VariantID:  option.VariantID,
+				PriceDelta: option.PriceDelta,
+				SortOrder:  option.SortOrder,
+			}
+			if option.Product != nil {
+				optionData.Name = option.Product.Name
+			}
+			if option.Variant != nil {
+				optionData.VariantName = option.Variant.Label()
+			}
+
+			data.Options = append(data.Options, optionData)
+		}
+
+		responses = append(responses, data)
+	}
+
+	return responses
+}
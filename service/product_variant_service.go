@@ -0,0 +1,377 @@
+package service
+
+import (
+	"context"
+	"github.com/google/uuid"
+	custom_error "github.com/rehandwi03/test-case-backend-majoo/internal/error"
+	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"github.com/rehandwi03/test-case-backend-majoo/money"
+	"github.com/rehandwi03/test-case-backend-majoo/query"
+	"github.com/rehandwi03/test-case-backend-majoo/repository"
+	"github.com/rehandwi03/test-case-backend-majoo/request"
+	"github.com/rehandwi03/test-case-backend-majoo/response"
+	"gorm.io/gorm"
+	"sort"
+	"strings"
+)
+
+// ProductVariantService manages the option groups of a product and the variants made of their values. A variant is
+// sold instead of its product, with its own stock and optionally its own price.
+type ProductVariantService interface {
+	SaveOption(ctx context.Context, request *request.ProductOptionAddRequest) (uuid.UUID, error)
+	UpdateOption(ctx context.Context, request *request.ProductOptionUpdateRequest) (uuid.UUID, error)
+	DeleteOption(ctx context.Context, productId uuid.UUID, optionId uuid.UUID) error
+	SaveVariant(ctx context.Context, request *request.ProductVariantAddRequest) (uuid.UUID, error)
+	UpdateVariant(ctx context.Context, request *request.ProductVariantUpdateRequest) (uuid.UUID, error)
+	DeleteVariant(ctx context.Context, productId uuid.UUID, variantId uuid.UUID) error
+}
+
+type productVariantService struct {
+	optionRepo  repository.ProductOptionRepository
+	variantRepo repository.ProductVariantRepository
+	accessGuard AccessGuard
+}
+
+func NewProductVariantService(
+	optionRepository repository.ProductOptionRepository, variantRepository repository.ProductVariantRepository,
+	accessGuard AccessGuard,
+) ProductVariantService {
+	return &productVariantService{
+		optionRepo: optionRepository, variantRepo: variantRepository, accessGuard: accessGuard,
+	}
+}
+
+func (p *productVariantService) SaveOption(ctx context.Context, request *request.ProductOptionAddRequest) (
+	uuid.UUID, error,
+) {
+	_, err := p.accessGuard.Product(ctx, request.ProductID, model.RoleOwner, model.RoleManager)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	option := model.ProductOption{
+		ProductID: request.ProductID,
+		Name:      request.Name,
+		SortOrder: request.SortOrder,
+	}
+	for _, value := range request.Values {
+		if value.ID != uuid.Nil {
+			return uuid.Nil, &custom_error.BadRequest{Message: "a new option can't have existing values", Field: "values"}
+		}
+
+		option.Values = append(option.Values, model.ProductOptionValue{Value: value.Value, SortOrder: value.SortOrder})
+	}
+
+	res, err := p.optionRepo.Save(ctx, option)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return res, nil
+}
+
+func (p *productVariantService) UpdateOption(ctx context.Context, request *request.ProductOptionUpdateRequest) (
+	uuid.UUID, error,
+) {
+	_, err := p.accessGuard.Product(ctx, request.ProductID, model.RoleOwner, model.RoleManager)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	optionData, err := p.getOption(ctx, request.ProductID, request.ID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	option := model.ProductOption{
+		ID:        optionData.ID,
+		ProductID: optionData.ProductID,
+		Name:      request.Name,
+		SortOrder: request.SortOrder,
+		Audit: model.Audit{
+			CreatedAt: optionData.CreatedAt,
+		},
+	}
+	for _, value := range request.Values {
+		option.Values = append(
+			option.Values, model.ProductOptionValue{ID: value.ID, Value: value.Value, SortOrder: value.SortOrder},
+		)
+	}
+
+	res, err := p.optionRepo.Save(ctx, option)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return res, nil
+}
+
+func (p *productVariantService) DeleteOption(ctx context.Context, productId uuid.UUID, optionId uuid.UUID) error {
+	_, err := p.accessGuard.Product(ctx, productId, model.RoleOwner, model.RoleManager)
+	if err != nil {
+		return err
+	}
+
+	option, err := p.getOption(ctx, productId, optionId)
+	if err != nil {
+		return err
+	}
+
+	err = p.optionRepo.Delete(ctx, &option)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (p *productVariantService) SaveVariant(ctx context.Context, request *request.ProductVariantAddRequest) (
+	uuid.UUID, error,
+) {
+	product, err := p.accessGuard.Product(ctx, request.ProductID, model.RoleOwner, model.RoleManager)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	if product.IsBundle {
+		return uuid.Nil, &custom_error.BadRequest{Message: "a bundle can't have variants"}
+	}
+
+	price, err := variantPrice(request.Price, product.Price.Currency)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	values, err := p.optionValues(ctx, request.ProductID, uuid.Nil, request.OptionValueIDs)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	res, err := p.variantRepo.Save(
+		ctx, model.ProductVariant{
+			ProductID:    request.ProductID,
+			SKU:          request.SKU,
+			Barcode:      request.Barcode,
+			Price:        price,
+			Stock:        request.Stock,
+			OptionValues: values,
+		},
+	)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return res, nil
+}
+
+func (p *productVariantService) UpdateVariant(ctx context.Context, request *request.ProductVariantUpdateRequest) (
+	uuid.UUID, error,
+) {
+	product, err := p.accessGuard.Product(ctx, request.ProductID, model.RoleOwner, model.RoleManager)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	variantData, err := p.getVariant(ctx, request.ProductID, request.ID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	price, err := variantPrice(request.Price, product.Price.Currency)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	values, err := p.optionValues(ctx, request.ProductID, variantData.ID, request.OptionValueIDs)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	res, err := p.variantRepo.Save(
+		ctx, model.ProductVariant{
+			ID:           variantData.ID,
+			ProductID:    variantData.ProductID,
+			SKU:          request.SKU,
+			Barcode:      request.Barcode,
+			Price:        price,
+			OptionValues: values,
+			Audit: model.Audit{
+				CreatedAt: variantData.CreatedAt,
+			},
+		},
+	)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return res, nil
+}
+
+func (p *productVariantService) DeleteVariant(ctx context.Context, productId uuid.UUID, variantId uuid.UUID) error {
+	_, err := p.accessGuard.Product(ctx, productId, model.RoleOwner, model.RoleManager)
+	if err != nil {
+		return err
+	}
+
+	variant, err := p.getVariant(ctx, productId, variantId)
+	if err != nil {
+		return err
+	}
+
+	err = p.variantRepo.Delete(ctx, &variant)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (p *productVariantService) getOption(ctx context.Context, productId uuid.UUID, optionId uuid.UUID) (
+	model.ProductOption, error,
+) {
+	option, err := p.optionRepo.Get(ctx, query.Where(query.Eq("id", optionId), query.Eq("product_id", productId)))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return option, &custom_error.NotFoundError{Message: "option not found"}
+		}
+
+		return option, err
+	}
+
+	return option, nil
+}
+
+func (p *productVariantService) getVariant(ctx context.Context, productId uuid.UUID, variantId uuid.UUID) (
+	model.ProductVariant, error,
+) {
+	variant, err := p.variantRepo.Get(ctx, query.Where(query.Eq("id", variantId), query.Eq("product_id", productId)))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return variant, &custom_error.NotFoundError{Message: "variant not found"}
+		}
+
+		return variant, err
+	}
+
+	return variant, nil
+}
+
+// optionValues resolves the option values of a variant. They have to belong to the options of the product, one value
+// per option, and no other live variant of the product may be made of the same values.
+func (p *productVariantService) optionValues(
+	ctx context.Context, productId uuid.UUID, variantId uuid.UUID, valueIds []uuid.UUID,
+) ([]model.ProductOptionValue, error) {
+	options, err := p.optionRepo.List(ctx, query.Where(query.Eq("product_id", productId)))
+	if err != nil {
+		return nil, err
+	}
+
+	valueOptions := map[uuid.UUID]model.ProductOptionValue{}
+	for _, option := range options {
+		for _, value := range option.Values {
+			valueOptions[value.ID] = value
+		}
+	}
+
+	var values []model.ProductOptionValue
+	chosen := map[uuid.UUID]bool{}
+	for _, valueId := range valueIds {
+		value, ok := valueOptions[valueId]
+		if !ok {
+			return nil, &custom_error.BadRequest{Message: "option value not found", Field: "option_value_ids"}
+		}
+
+		if chosen[value.OptionID] {
+			return nil, &custom_error.BadRequest{
+				Message: "a variant takes one value of every option", Field: "option_value_ids",
+			}
+		}
+		chosen[value.OptionID] = true
+
+		values = append(values, value)
+	}
+
+	variants, err := p.variantRepo.List(ctx, query.Where(query.Eq("product_id", productId)))
+	if err != nil {
+		return nil, err
+	}
+
+	key := combination(values)
+	for _, variant := range variants {
+		if variant.ID != variantId && combination(variant.OptionValues) == key {
+			return nil, &custom_error.ConflictError{
+				Message: "the product already has a variant with these options", Field: "option_value_ids",
+			}
+		}
+	}
+
+	return values, nil
+}
+
+// combination identifies a set of option values regardless of their order.
+func combination(values []model.ProductOptionValue) string {
+	var ids []string
+	for _, value := range values {
+		ids = append(ids, value.ID.String())
+	}
+	sort.Strings(ids)
+
+	return strings.Join(ids, ",")
+}
+
+func productOptionResponses(options []model.ProductOption) []response.ProductOptionResponse {
+	responses := []response.ProductOptionResponse{}
+	for _, option := range options {
+		responses = append(
+			responses, response.ProductOptionResponse{
+				ID:        option.ID,
+				Name:      option.Name,
+				SortOrder: option.SortOrder,
+				Values:    productOptionValueResponses(option.Values),
+			},
+		)
+	}
+
+	return responses
+}
+
+func productVariantResponses(product model.Product) []response.ProductVariantResponse {
+	responses := []response.ProductVariantResponse{}
+	for _, variant := range product.Variants {
+		responses = append(
+			responses, response.ProductVariantResponse{
+				ID:           variant.ID,
+				Name:         variant.Label(),
+				SKU:          variant.SKU,
+				Barcode:      variant.Barcode,
+				Price:        variant.EffectivePrice(product),
+				CostPrice:    optionalMoney(variant.CostPrice),
+				AverageCost:  optionalMoney(variant.AverageCost),
+				Stock:        variant.Stock,
+				OptionValues: productOptionValueResponses(variant.OptionValues),
+			},
+		)
+	}
+
+	return responses
+}
+
+func productOptionValueResponses(values []model.ProductOptionValue) []response.ProductOptionValueResponse {
+	responses := []response.ProductOptionValueResponse{}
+	for _, value := range values {
+		responses = append(
+			responses, response.ProductOptionValueResponse{ID: value.ID, Value: value.Value, SortOrder: value.SortOrder},
+		)
+	}
+
+	return responses
+}
+
+// variantPrice is the price overriding the one of the product, the zero Money when the variant doesn't have its own.
+func variantPrice(price *money.Money, currency string) (money.Money, error) {
+	if price == nil {
+		return money.Money{}, nil
+	}
+
+	return priceIn(*price, currency, "price")
+}
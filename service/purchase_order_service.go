@@ -0,0 +1,422 @@
+package service
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/rehandwi03/test-case-backend-majoo/criteria"
+	custom_error "github.com/rehandwi03/test-case-backend-majoo/internal/error"
+	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"github.com/rehandwi03/test-case-backend-majoo/money"
+	"github.com/rehandwi03/test-case-backend-majoo/query"
+	"github.com/rehandwi03/test-case-backend-majoo/repository"
+	"github.com/rehandwi03/test-case-backend-majoo/request"
+	"github.com/rehandwi03/test-case-backend-majoo/response"
+	"github.com/rehandwi03/test-case-backend-majoo/util"
+	"gorm.io/gorm"
+)
+
+type PurchaseOrderService interface {
+	// SavePurchaseOrder drafts an order of products of an outlet from a supplier of its merchant.
+	SavePurchaseOrder(ctx context.Context, request *request.PurchaseOrderAddRequest) (uuid.UUID, error)
+	// Place marks the draft as ordered from the supplier.
+	Place(ctx context.Context, orderId uuid.UUID) error
+	// Receive restocks the outlet with received goods and records what they cost.
+	Receive(ctx context.Context, request *request.PurchaseOrderReceiveRequest) error
+	// Cancel drops an order nothing was received of yet.
+	Cancel(ctx context.Context, orderId uuid.UUID) error
+	GetByParam(ctx context.Context, spec query.Spec) (*response.PurchaseOrderResponse, error)
+	Fetch(ctx context.Context, criteria criteria.PurchaseOrderCriteria) (*util.PaginationResponse, error)
+}
+
+type purchaseOrderService struct {
+	purchaseOrderRepo repository.PurchaseOrderRepository
+	supplierRepo      repository.SupplierRepository
+	productRepo       repository.ProductRepository
+	accessGuard       AccessGuard
+}
+
+func NewPurchaseOrderService(
+	purchaseOrderRepository repository.PurchaseOrderRepository, supplierRepository repository.SupplierRepository,
+	productRepository repository.ProductRepository, accessGuard AccessGuard,
+) PurchaseOrderService {
+	return &purchaseOrderService{
+		purchaseOrderRepo: purchaseOrderRepository, supplierRepo: supplierRepository, productRepo: productRepository,
+		accessGuard: accessGuard,
+	}
+}
+
+func (p *purchaseOrderService) SavePurchaseOrder(ctx context.Context, request *request.PurchaseOrderAddRequest) (
+	uuid.UUID, error,
+) {
+	userId, ok := ctx.Value("user_id").(uuid.UUID)
+	if !ok {
+		return uuid.Nil, &custom_error.NotFoundError{Message: "user id not found"}
+	}
+
+	outlet, err := p.accessGuard.Outlet(ctx, request.OutletID, model.RoleOwner, model.RoleManager)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	merchant, err := p.accessGuard.Merchant(ctx, outlet.MerchantID, model.RoleOwner, model.RoleManager)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	supplier, err := p.supplierRepo.Get(
+		ctx, query.Where(query.Eq("id", request.SupplierID), query.Eq("merchant_id", merchant.ID)),
+	)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return uuid.Nil, &custom_error.BadRequest{Message: "supplier not found", Field: "supplier_id"}
+		}
+
+		return uuid.Nil, err
+	}
+
+	var productIds []uuid.UUID
+	for _, line := range request.Lines {
+		productIds = append(productIds, line.ProductID)
+	}
+
+	products, err := p.productRepo.List(
+		ctx, query.Where(query.In("id", productIds), query.Eq("outlet_id", outlet.ID)),
+	)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	productMap := map[uuid.UUID]model.Product{}
+	for _, product := range products {
+		productMap[product.ID] = product
+	}
+
+	order := model.PurchaseOrder{
+		MerchantID: merchant.ID,
+		SupplierID: supplier.ID,
+		OutletID:   outlet.ID,
+		Status:     model.PurchaseOrderDraft,
+		Note:       request.Note,
+		Total:      money.New(0, merchant.Currency),
+		CreatedBy:  userId,
+	}
+	ordered := map[stockItem]bool{}
+	for _, lineRequest := range request.Lines {
+		product, ok := productMap[lineRequest.ProductID]
+		if !ok {
+			return uuid.Nil, &custom_error.BadRequest{
+				Message: "product " + lineRequest.ProductID.String() + " not found in the outlet", Field: "lines",
+			}
+		}
+
+		unitCost, err := priceIn(lineRequest.UnitCost, merchant.Currency, "unit_cost")
+		if err != nil {
+			return uuid.Nil, err
+		}
+
+		line := model.PurchaseOrderLine{
+			ProductID:    product.ID,
+			SKU:          product.SKU,
+			Name:         product.Name,
+			Quantity:     lineRequest.Quantity,
+			UnitCost:     unitCost,
+			ReceivedCost: money.New(0, merchant.Currency),
+		}
+
+		// the stock of a product with variants is kept by its variants, so one of them is ordered
+		if lineRequest.VariantID != nil || len(product.Variants) > 0 {
+			var variantId uuid.UUID
+			if lineRequest.VariantID != nil {
+				variantId = *lineRequest.VariantID
+			}
+
+			variant, ok := findVariant(product, variantId)
+			if !ok {
+				return uuid.Nil, &custom_error.BadRequest{
+					Message: "choose a variant of product " + product.Name, Field: "lines",
+				}
+			}
+
+			line.VariantID = &variant.ID
+			line.SKU = variant.SKU
+			line.VariantName = variant.Label()
+		}
+
+		item := stockItemOf(line.ProductID, line.VariantID)
+		if ordered[item] {
+			name := product.Name
+			if line.VariantName != "" {
+				name += " (" + line.VariantName + ")"
+			}
+
+			return uuid.Nil, &custom_error.BadRequest{Message: name + " is ordered twice", Field: "lines"}
+		}
+		ordered[item] = true
+
+		order.Total, err = order.Total.Add(unitCost.Mul(line.Quantity))
+		if err != nil {
+			return uuid.Nil, err
+		}
+
+		order.Lines = append(order.Lines, line)
+	}
+
+	res, err := p.purchaseOrderRepo.Create(ctx, order)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return res, nil
+}
+
+func (p *purchaseOrderService) Place(ctx context.Context, orderId uuid.UUID) error {
+	userId, ok := ctx.Value("user_id").(uuid.UUID)
+	if !ok {
+		return &custom_error.NotFoundError{Message: "user id not found"}
+	}
+
+	order, err := p.getOrder(ctx, orderId, model.RoleOwner, model.RoleManager)
+	if err != nil {
+		return err
+	}
+
+	err = p.purchaseOrderRepo.Place(ctx, order.ID, userId)
+	if err != nil {
+		if err == repository.ErrPurchaseOrderStatus {
+			return &custom_error.ConflictError{Message: "only a draft can be ordered"}
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (p *purchaseOrderService) Receive(ctx context.Context, request *request.PurchaseOrderReceiveRequest) error {
+	userId, ok := ctx.Value("user_id").(uuid.UUID)
+	if !ok {
+		return &custom_error.NotFoundError{Message: "user id not found"}
+	}
+
+	order, err := p.getOrder(ctx, request.ID, model.RoleOwner, model.RoleManager)
+	if err != nil {
+		return err
+	}
+
+	lines := map[uuid.UUID]model.PurchaseOrderLine{}
+	for _, line := range order.Lines {
+		lines[line.ID] = line
+	}
+
+	var receipts []repository.GoodsReceipt
+	received := map[uuid.UUID]int64{}
+	for _, receiptRequest := range request.Lines {
+		line, ok := lines[receiptRequest.LineID]
+		if !ok {
+			return &custom_error.BadRequest{
+				Message: "line " + receiptRequest.LineID.String() + " isn't part of the purchase order", Field: "lines",
+			}
+		}
+
+		received[line.ID] += receiptRequest.Quantity
+		if line.Received+received[line.ID] > line.Quantity {
+			return &custom_error.BadRequest{
+				Message: "more of " + line.Name + " would be received than was ordered", Field: "lines",
+			}
+		}
+
+		unitCost := line.UnitCost
+		if receiptRequest.UnitCost != nil {
+			unitCost, err = priceIn(*receiptRequest.UnitCost, order.Total.Currency, "unit_cost")
+			if err != nil {
+				return err
+			}
+		}
+
+		receipts = append(
+			receipts, repository.GoodsReceipt{LineID: line.ID, Quantity: receiptRequest.Quantity, UnitCost: unitCost},
+		)
+	}
+
+	err = p.purchaseOrderRepo.Receive(ctx, order.ID, userId, receipts)
+	if err != nil {
+		switch err {
+		case repository.ErrPurchaseOrderStatus:
+			return &custom_error.ConflictError{Message: "only an ordered purchase order can be received"}
+		case repository.ErrOverOrdered:
+			return &custom_error.BadRequest{Message: err.Error(), Field: "lines"}
+		case repository.ErrInsufficientStock:
+			// receiving only adds stock, so the product left the outlet
+			return &custom_error.ConflictError{Message: "a product of the purchase order isn't in the outlet any more"}
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (p *purchaseOrderService) Cancel(ctx context.Context, orderId uuid.UUID) error {
+	order, err := p.getOrder(ctx, orderId, model.RoleOwner, model.RoleManager)
+	if err != nil {
+		return err
+	}
+
+	err = p.purchaseOrderRepo.Cancel(ctx, order.ID)
+	if err != nil {
+		if err == repository.ErrPurchaseOrderStatus {
+			return &custom_error.ConflictError{Message: "a purchase order can't be cancelled once goods are received"}
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (p *purchaseOrderService) GetByParam(ctx context.Context, spec query.Spec) (
+	*response.PurchaseOrderResponse, error,
+) {
+	order, err := p.purchaseOrderRepo.Get(ctx, spec)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, &custom_error.NotFoundError{Message: "purchase order not found"}
+		}
+
+		return nil, err
+	}
+
+	_, err = p.accessGuard.Merchant(ctx, order.MerchantID, model.RoleOwner, model.RoleManager, model.RoleCashier)
+	if err != nil {
+		return nil, err
+	}
+
+	response := purchaseOrderResponse(order)
+
+	return &response, nil
+}
+
+func (p *purchaseOrderService) Fetch(ctx context.Context, criteria criteria.PurchaseOrderCriteria) (
+	*util.PaginationResponse, error,
+) {
+	merchantIds, all, err := p.accessGuard.MerchantIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := query.Where()
+	if !all {
+		spec = spec.And(query.In("merchant_id", merchantIds))
+	}
+	if criteria.SupplierID != "" {
+		supplierId, err := uuid.Parse(criteria.SupplierID)
+		if err != nil {
+			return nil, &custom_error.BadRequest{Message: "supplier_id must be an uuid", Field: "supplier_id"}
+		}
+
+		spec = spec.And(query.Eq("supplier_id", supplierId))
+	}
+	if criteria.OutletID != "" {
+		outletId, err := uuid.Parse(criteria.OutletID)
+		if err != nil {
+			return nil, &custom_error.BadRequest{Message: "outlet_id must be an uuid", Field: "outlet_id"}
+		}
+
+		spec = spec.And(query.Eq("outlet_id", outletId))
+	}
+	if criteria.Status != "" {
+		spec = spec.And(query.Eq("status", criteria.Status))
+	}
+
+	from, err := parseTimeFilter("from", criteria.From, false)
+	if err != nil {
+		return nil, err
+	}
+	to, err := parseTimeFilter("to", criteria.To, true)
+	if err != nil {
+		return nil, err
+	}
+	spec = spec.And(query.Between("created_at", from, to))
+
+	spec, err = paginate(spec, &criteria.Pagination, "status", "total:total_amount", "created_at")
+	if err != nil {
+		return nil, err
+	}
+
+	res, rowCount, err := p.purchaseOrderRepo.Fetch(ctx, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	var responseData []response.PurchaseOrderResponse
+	for _, val := range res {
+		responseData = append(responseData, purchaseOrderResponse(val))
+	}
+
+	resPagination := util.BuildPagination(criteria.Pagination, responseData, rowCount)
+
+	return &resPagination, nil
+}
+
+// getOrder returns the order after checking the caller holds one of the roles in its outlet.
+func (p *purchaseOrderService) getOrder(ctx context.Context, orderId uuid.UUID, roles ...string) (
+	model.PurchaseOrder, error,
+) {
+	order, err := p.purchaseOrderRepo.Get(ctx, query.Where(query.Eq("id", orderId)))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return order, &custom_error.NotFoundError{Message: "purchase order not found"}
+		}
+
+		return order, err
+	}
+
+	_, err = p.accessGuard.Outlet(ctx, order.OutletID, roles...)
+	if err != nil {
+		return order, err
+	}
+
+	return order, nil
+}
+
+func purchaseOrderResponse(order model.PurchaseOrder) response.PurchaseOrderResponse {
+	data := response.PurchaseOrderResponse{
+		ID:            order.ID,
+		MerchantID:    order.MerchantID,
+		SupplierID:    order.SupplierID,
+		OutletID:      order.OutletID,
+		Status:        order.Status,
+		Note:          order.Note,
+		Total:         order.Total,
+		ReceivedTotal: money.New(0, order.Total.Currency),
+		CreatedBy:     order.CreatedBy,
+		OrderedBy:     order.OrderedBy,
+		Lines:         []response.PurchaseOrderLineResponse{},
+		CreatedAt:     order.CreatedAt.Time,
+	}
+	if order.OrderedAt.Valid {
+		data.OrderedAt = &order.OrderedAt.Time
+	}
+
+	for _, line := range order.Lines {
+		// the lines are all kept in the currency of the order
+		data.ReceivedTotal.Amount += line.ReceivedCost.Amount
+		data.Lines = append(
+			data.Lines, response.PurchaseOrderLineResponse{
+				ID:           line.ID,
+				ProductID:    line.ProductID,
+				VariantID:    line.VariantID,
+				SKU:          line.SKU,
+				Name:         line.Name,
+				VariantName:  line.VariantName,
+				Quantity:     line.Quantity,
+				UnitCost:     line.UnitCost,
+				Received:     line.Received,
+				ReceivedCost: line.ReceivedCost,
+				Outstanding:  line.Quantity - line.Received,
+			},
+		)
+	}
+
+	return data
+}
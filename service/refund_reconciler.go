@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"github.com/rehandwi03/test-case-backend-majoo/gateway"
+	"github.com/rehandwi03/test-case-backend-majoo/repository"
+	"log"
+	"time"
+)
+
+// refundRetryDelay is how old a pending refund payment is before it is retried, so the refund storing it asks its
+// payment provider first.
+const refundRetryDelay = time.Minute
+
+// RefundReconciler gives back in the background the refund payments still pending at their payment provider, like
+// the ones the provider couldn't be reached for when the refund was stored. Providers are asked with the same
+// reference every time, so a refund payment is never given back twice.
+type RefundReconciler struct {
+	charges  charges
+	interval time.Duration
+}
+
+func NewRefundReconciler(
+	paymentRepository repository.TransactionPaymentRepository, providers gateway.Providers, interval time.Duration,
+) *RefundReconciler {
+	return &RefundReconciler{charges: charges{providers: providers, paymentRepo: paymentRepository}, interval: interval}
+}
+
+// Run retries the pending refund payments every interval until the context is done.
+func (r *RefundReconciler) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		err := r.reconcile(ctx)
+		if err != nil {
+			log.Printf("error reconciling refunds: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// reconcile retries every pending refund payment on its own, so one failing again doesn't hold up the others.
+func (r *RefundReconciler) reconcile(ctx context.Context) error {
+	refundPayments, err := r.charges.paymentRepo.PendingRefunds(ctx, time.Now().Add(-refundRetryDelay))
+	if err != nil {
+		return err
+	}
+
+	for i := range refundPayments {
+		err = r.charges.refund(ctx, refundPayments[i:i+1])
+		if err != nil {
+			log.Printf("error giving back refund %s: %v", refundPayments[i].RefundID, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,535 @@
+package service
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/rehandwi03/test-case-backend-majoo/gateway"
+	custom_error "github.com/rehandwi03/test-case-backend-majoo/internal/error"
+	"github.com/rehandwi03/test-case-backend-majoo/internal/helper"
+	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"github.com/rehandwi03/test-case-backend-majoo/money"
+	"github.com/rehandwi03/test-case-backend-majoo/query"
+	"github.com/rehandwi03/test-case-backend-majoo/repository"
+	"github.com/rehandwi03/test-case-backend-majoo/request"
+	"github.com/rehandwi03/test-case-backend-majoo/response"
+	"gorm.io/gorm"
+	"log"
+	"strconv"
+	"time"
+)
+
+const (
+	// failedApprovalWindow is how far back failed approvals count towards the limits.
+	failedApprovalWindow = 15 * time.Minute
+	// failedApprovalsPerUser is how many approvals a cashier may have refused in the window before they have to wait.
+	failedApprovalsPerUser = 5
+	// failedApprovalsPerOutlet is how many approvals may be refused in an outlet in the window, so cashiers can't
+	// take turns guessing.
+	failedApprovalsPerOutlet = 10
+)
+
+type RefundService interface {
+	// Refund returns items of a sale and gives back what they are worth by its payments.
+	Refund(ctx context.Context, request *request.RefundRequest) (*response.RefundResponse, error)
+	// Void cancels a sale nothing was refunded of yet, putting all of its items back into the stock and giving back
+	// all it was paid.
+	Void(ctx context.Context, request *request.VoidRequest) (*response.RefundResponse, error)
+}
+
+type refundService struct {
+	transactionRepo    repository.TransactionRepository
+	userRepo           repository.UserRepository
+	merchantUserRepo   repository.MerchantUserRepository
+	failedApprovalRepo repository.FailedApprovalRepository
+	charges            charges
+	accessGuard        AccessGuard
+}
+
+func NewRefundService(
+	transactionRepository repository.TransactionRepository, userRepository repository.UserRepository,
+	merchantUserRepository repository.MerchantUserRepository,
+	failedApprovalRepository repository.FailedApprovalRepository,
+	paymentRepository repository.TransactionPaymentRepository, providers gateway.Providers, accessGuard AccessGuard,
+) RefundService {
+	return &refundService{
+		transactionRepo: transactionRepository, userRepo: userRepository, merchantUserRepo: merchantUserRepository,
+		failedApprovalRepo: failedApprovalRepository, accessGuard: accessGuard,
+		charges: charges{providers: providers, paymentRepo: paymentRepository},
+	}
+}
+
+func (r *refundService) Refund(ctx context.Context, request *request.RefundRequest) (*response.RefundResponse, error) {
+	transaction, refund, err := r.prepare(
+		ctx, request.OutletID, request.TransactionID, request.Approval, model.RefundKindRefund, request.Reason,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if transaction.Status == model.TransactionPending || transaction.Status == model.TransactionUnpaid {
+		return nil, &custom_error.ConflictError{Message: repository.ErrTransactionUnpaid.Error()}
+	}
+
+	items := map[uuid.UUID]model.TransactionItem{}
+	for _, item := range transaction.Items {
+		items[item.ID] = item
+	}
+
+	left := map[uuid.UUID]int64{}
+	for _, item := range transaction.Items {
+		left[item.ID] = item.Quantity - item.ReturnedQuantity
+	}
+
+	for _, itemRequest := range request.Items {
+		item, ok := items[itemRequest.TransactionItemID]
+		if !ok {
+			return nil, &custom_error.NotFoundError{
+				Message: "item " + itemRequest.TransactionItemID.String() + " isn't of the transaction",
+			}
+		}
+
+		if itemRequest.Quantity > left[item.ID] {
+			return nil, &custom_error.BadRequest{
+				Message: "only " + strconv.FormatInt(left[item.ID], 10) + " of " + item.Name + " are left to return",
+				Field:   "items",
+			}
+		}
+		left[item.ID] -= itemRequest.Quantity
+
+		refund.Lines = append(
+			refund.Lines, model.RefundLine{
+				TransactionItemID: item.ID,
+				Quantity:          itemRequest.Quantity,
+				Restock:           !itemRequest.WriteOff,
+				Amount:            item.Price.Mul(itemRequest.Quantity),
+			},
+		)
+		refund.Amount, err = refund.Amount.Add(item.Price.Mul(itemRequest.Quantity))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	refundable := transaction.Refundable()
+
+	// the refund returning the last units gives back all that is left, the cash rounding too
+	last := true
+	for _, quantity := range left {
+		if quantity > 0 {
+			last = false
+		}
+	}
+	if last {
+		refund.Amount.Amount = 0
+		for _, amount := range refundable {
+			refund.Amount.Amount += amount
+		}
+	}
+
+	if len(request.Payments) == 0 {
+		err = allocateRefund(&refund, transaction, refundable)
+	} else {
+		err = r.refundPayments(&refund, transaction, refundable, request.Payments)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return r.store(ctx, transaction, refund)
+}
+
+func (r *refundService) Void(ctx context.Context, request *request.VoidRequest) (*response.RefundResponse, error) {
+	transaction, refund, err := r.prepare(
+		ctx, request.OutletID, request.TransactionID, request.Approval, model.RefundKindVoid, request.Reason,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// a pending sale is told to refresh its pending payments below
+	if transaction.Status != model.TransactionCompleted && transaction.Status != model.TransactionUnpaid &&
+		transaction.Status != model.TransactionPending {
+		return nil, &custom_error.ConflictError{
+			Message: "items of the transaction were refunded already, refund the rest instead",
+		}
+	}
+
+	for _, payment := range transaction.Payments {
+		if payment.Status == model.PaymentPending || payment.Status == model.PaymentAuthorized {
+			return nil, &custom_error.ConflictError{
+				Message: "the " + payment.Method + " payment of " + payment.Amount.String() + " is still " +
+					payment.Status + ", refresh it first",
+				Field: "payments",
+			}
+		}
+	}
+
+	for _, item := range transaction.Items {
+		refund.Lines = append(
+			refund.Lines, model.RefundLine{
+				TransactionItemID: item.ID,
+				Quantity:          item.Quantity,
+				Restock:           true,
+				Amount:            item.Subtotal,
+			},
+		)
+	}
+
+	refundable := transaction.Refundable()
+	for _, payment := range transaction.Payments {
+		refund.Amount.Amount += refundable[payment.ID]
+	}
+
+	err = allocateRefund(&refund, transaction, refundable)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.store(ctx, transaction, refund)
+}
+
+// prepare loads the transaction of a refund, or void, and starts the refund of it, approved by the caller when they
+// are an owner or manager of the merchant and by the approval otherwise.
+func (r *refundService) prepare(
+	ctx context.Context, outletId uuid.UUID, transactionId uuid.UUID, approval *request.ApprovalRequest, kind string,
+	reason string,
+) (model.Transaction, model.Refund, error) {
+	userId, ok := ctx.Value("user_id").(uuid.UUID)
+	if !ok {
+		return model.Transaction{}, model.Refund{}, &custom_error.NotFoundError{Message: "user id not found"}
+	}
+
+	outlet, err := r.accessGuard.Outlet(ctx, outletId, model.RoleOwner, model.RoleManager, model.RoleCashier)
+	if err != nil {
+		return model.Transaction{}, model.Refund{}, err
+	}
+
+	transaction, err := r.transactionRepo.Get(
+		ctx, query.Where(query.Eq("id", transactionId), query.Eq("outlet_id", outletId)),
+	)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return transaction, model.Refund{}, &custom_error.NotFoundError{Message: "transaction not found"}
+		}
+
+		return transaction, model.Refund{}, err
+	}
+
+	if transaction.Status == model.TransactionVoided {
+		return transaction, model.Refund{}, &custom_error.ConflictError{Message: "the transaction is voided"}
+	}
+
+	approvedBy, err := r.approver(ctx, outlet, userId, approval)
+	if err != nil {
+		return transaction, model.Refund{}, err
+	}
+
+	refund := model.Refund{
+		TransactionID: transaction.ID,
+		OutletID:      outletId,
+		Kind:          kind,
+		Reason:        reason,
+		Amount:        money.New(0, transaction.Total.Currency),
+		UserID:        userId,
+		ApprovedBy:    approvedBy,
+	}
+
+	return transaction, refund, nil
+}
+
+// approver answers the owner or manager approving what the caller does: the caller themselves when they are one,
+// the user the approval has the credentials of otherwise. Approvals refused are recorded, and a cashier or outlet
+// with too many of them recently can't ask for approvals until they are old enough, so passwords can't be guessed.
+func (r *refundService) approver(
+	ctx context.Context, outlet model.Outlet, userId uuid.UUID, approval *request.ApprovalRequest,
+) (uuid.UUID, error) {
+	if helper.IsAdmin(ctx) || r.isManager(ctx, outlet.MerchantID, userId) {
+		return userId, nil
+	}
+
+	if approval == nil {
+		return uuid.Nil, &custom_error.ForbiddenError{
+			Message: "a cashier needs the approval of an owner or manager of the merchant",
+		}
+	}
+
+	err := r.checkFailedApprovals(ctx, outlet.ID, userId)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	approver, err := r.userRepo.Get(ctx, query.Where(query.Eq("email", approval.Email)))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return uuid.Nil, r.failApproval(
+				ctx, outlet.ID, userId, approval,
+				&custom_error.BadRequest{Message: "email or password is incorrect", Field: "approval"},
+			)
+		}
+
+		return uuid.Nil, err
+	}
+
+	ok, err := approver.ComparePassword(approval.Password)
+	if err != nil || !ok {
+		return uuid.Nil, r.failApproval(
+			ctx, outlet.ID, userId, approval,
+			&custom_error.BadRequest{Message: "email or password is incorrect", Field: "approval"},
+		)
+	}
+
+	if !r.isManager(ctx, outlet.MerchantID, approver.ID) {
+		return uuid.Nil, r.failApproval(
+			ctx, outlet.ID, userId, approval,
+			&custom_error.ForbiddenError{Message: "the approval has to come from an owner or manager of the merchant"},
+		)
+	}
+
+	return approver.ID, nil
+}
+
+// checkFailedApprovals refuses to check an approval while the caller, or the outlet, had too many refused recently.
+func (r *refundService) checkFailedApprovals(ctx context.Context, outletId uuid.UUID, userId uuid.UUID) error {
+	since := time.Now().Add(-failedApprovalWindow)
+
+	failed, err := r.failedApprovalRepo.Count(
+		ctx, query.Where(query.Eq("user_id", userId), query.Gte("created_at", since)),
+	)
+	if err != nil {
+		return err
+	}
+	if failed >= failedApprovalsPerUser {
+		return &custom_error.ForbiddenError{Message: "too many approvals were refused, try again later"}
+	}
+
+	failed, err = r.failedApprovalRepo.Count(
+		ctx, query.Where(query.Eq("outlet_id", outletId), query.Gte("created_at", since)),
+	)
+	if err != nil {
+		return err
+	}
+	if failed >= failedApprovalsPerOutlet {
+		return &custom_error.ForbiddenError{
+			Message: "too many approvals were refused in the outlet, try again later",
+		}
+	}
+
+	return nil
+}
+
+// failApproval records the approval as refused and answers why it was.
+func (r *refundService) failApproval(
+	ctx context.Context, outletId uuid.UUID, userId uuid.UUID, approval *request.ApprovalRequest, reason error,
+) error {
+	_, err := r.failedApprovalRepo.Save(
+		ctx, model.FailedApproval{OutletID: outletId, UserID: userId, Email: approval.Email},
+	)
+	if err != nil {
+		return err
+	}
+
+	return reason
+}
+
+func (r *refundService) isManager(ctx context.Context, merchantId uuid.UUID, userId uuid.UUID) bool {
+	membership, err := r.merchantUserRepo.Get(
+		ctx, query.Where(query.Eq("merchant_id", merchantId), query.Eq("user_id", userId)),
+	)
+	if err != nil {
+		return false
+	}
+
+	return membership.Role == model.RoleOwner || membership.Role == model.RoleManager
+}
+
+// refundPayments gives back what the refund is worth by the payments of the request, which have to add up to it.
+func (r *refundService) refundPayments(
+	refund *model.Refund, transaction model.Transaction, refundable map[uuid.UUID]int64,
+	paymentRequests []request.RefundPaymentRequest,
+) error {
+	payments := map[uuid.UUID]model.TransactionPayment{}
+	for _, payment := range transaction.Payments {
+		payments[payment.ID] = payment
+	}
+
+	var total int64
+	seen := map[uuid.UUID]bool{}
+	for _, paymentRequest := range paymentRequests {
+		payment, ok := payments[paymentRequest.PaymentID]
+		if !ok {
+			return &custom_error.NotFoundError{
+				Message: "payment " + paymentRequest.PaymentID.String() + " isn't of the transaction",
+			}
+		}
+
+		if seen[payment.ID] {
+			return &custom_error.BadRequest{Message: "a payment can only be given back once", Field: "payments"}
+		}
+		seen[payment.ID] = true
+
+		amount, err := inCurrency(paymentRequest.Amount, transaction.Total.Currency, "payments")
+		if err != nil {
+			return err
+		}
+
+		if amount.Amount <= 0 {
+			return &custom_error.BadRequest{Message: "payments have to give back more than 0", Field: "payments"}
+		}
+
+		if amount.Amount > refundable[payment.ID] {
+			return &custom_error.BadRequest{
+				Message: "the " + payment.Method + " payment can only give back " +
+					money.New(refundable[payment.ID], amount.Currency).String(),
+				Field: "payments",
+			}
+		}
+
+		refund.Payments = append(
+			refund.Payments, model.RefundPayment{PaymentID: payment.ID, Method: payment.Method, Amount: amount},
+		)
+		total += amount.Amount
+	}
+
+	if total != refund.Amount.Amount {
+		return &custom_error.BadRequest{
+			Message: "payments have to give back " + refund.Amount.String(), Field: "payments",
+		}
+	}
+
+	return nil
+}
+
+// allocateRefund gives back what the refund is worth by the payments of the sale, the last one made first.
+func allocateRefund(refund *model.Refund, transaction model.Transaction, refundable map[uuid.UUID]int64) error {
+	left := refund.Amount.Amount
+	for i := len(transaction.Payments) - 1; i >= 0 && left > 0; i-- {
+		payment := transaction.Payments[i]
+
+		amount := refundable[payment.ID]
+		if amount > left {
+			amount = left
+		}
+		if amount == 0 {
+			continue
+		}
+
+		refund.Payments = append(
+			refund.Payments, model.RefundPayment{
+				PaymentID: payment.ID,
+				Method:    payment.Method,
+				Amount:    money.New(amount, refund.Amount.Currency),
+			},
+		)
+		left -= amount
+	}
+
+	if left > 0 {
+		return &custom_error.ConflictError{
+			Message: "the payments of the transaction can't give back " + refund.Amount.String() +
+				", some of them aren't captured yet",
+			Field: "payments",
+		}
+	}
+
+	return nil
+}
+
+// store stores the refund, and only then gives back what went through payment providers so a refund failing to be
+// stored isn't given back. A provider refund failing is left pending for the RefundReconciler to retry, the refund
+// stands either way.
+func (r *refundService) store(
+	ctx context.Context, transaction model.Transaction, refund model.Refund,
+) (*response.RefundResponse, error) {
+	payments := map[uuid.UUID]model.TransactionPayment{}
+	for _, payment := range transaction.Payments {
+		payments[payment.ID] = payment
+	}
+
+	for i, refundPayment := range refund.Payments {
+		payment := payments[refundPayment.PaymentID]
+		if payment.Provider == "" {
+			refund.Payments[i].Status = model.RefundPaymentCompleted
+			continue
+		}
+
+		if _, ok := r.charges.providers[payment.Provider]; !ok {
+			return nil, &custom_error.ConflictError{Message: "payment provider " + payment.Provider + " isn't set up"}
+		}
+
+		refund.Payments[i].Status = model.RefundPaymentPending
+	}
+
+	refundId, err := r.transactionRepo.Refund(ctx, refund)
+	if err != nil {
+		switch err {
+		case repository.ErrTransactionVoided, repository.ErrAlreadyRefunded, repository.ErrTransactionUnpaid:
+			return nil, &custom_error.ConflictError{Message: err.Error()}
+		case repository.ErrNothingToReturn, repository.ErrSaleUntraced:
+			return nil, &custom_error.ConflictError{Message: err.Error(), Field: "items"}
+		case repository.ErrRefundExceedsPayment:
+			return nil, &custom_error.ConflictError{Message: err.Error(), Field: "payments"}
+		}
+
+		return nil, err
+	}
+
+	refunded, err := r.transactionRepo.Get(ctx, query.Where(query.Eq("id", transaction.ID)))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, stored := range refunded.Refunds {
+		if stored.ID == refundId {
+			err = r.charges.refund(ctx, stored.Payments)
+			if err != nil {
+				log.Printf("error giving back refund %s, it is retried later: %v", stored.ID, err)
+			}
+
+			response := toRefundResponse(stored)
+
+			return &response, nil
+		}
+	}
+
+	return nil, &custom_error.NotFoundError{Message: "refund not found"}
+}
+
+func toRefundResponse(refund model.Refund) response.RefundResponse {
+	data := response.RefundResponse{
+		ID:            refund.ID,
+		TransactionID: refund.TransactionID,
+		Kind:          refund.Kind,
+		Reason:        refund.Reason,
+		Amount:        refund.Amount,
+		UserID:        refund.UserID,
+		ApprovedBy:    refund.ApprovedBy,
+		Lines:         []response.RefundLineResponse{},
+		Payments:      []response.RefundPaymentResponse{},
+		CreatedAt:     refund.CreatedAt.Time,
+	}
+
+	for _, line := range refund.Lines {
+		data.Lines = append(
+			data.Lines, response.RefundLineResponse{
+				TransactionItemID: line.TransactionItemID,
+				Quantity:          line.Quantity,
+				Restock:           line.Restock,
+				Amount:            line.Amount,
+				Cost:              optionalMoney(line.Cost),
+			},
+		)
+	}
+
+	for _, payment := range refund.Payments {
+		data.Payments = append(
+			data.Payments, response.RefundPaymentResponse{
+				PaymentID: payment.PaymentID,
+				Method:    payment.Method,
+				Amount:    payment.Amount,
+				Status:    payment.Status,
+			},
+		)
+	}
+
+	return data
+}
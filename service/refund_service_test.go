@@ -0,0 +1,76 @@
+package service
+
+import (
+	"github.com/google/uuid"
+	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"github.com/rehandwi03/test-case-backend-majoo/money"
+	"reflect"
+	"testing"
+)
+
+func TestAllocateRefund(t *testing.T) {
+	cash := model.TransactionPayment{ID: uuid.New(), Method: model.PaymentCash}
+	card := model.TransactionPayment{ID: uuid.New(), Method: model.PaymentCard}
+	qris := model.TransactionPayment{ID: uuid.New(), Method: model.PaymentQRIS}
+	transaction := model.Transaction{Payments: []model.TransactionPayment{cash, card, qris}}
+
+	tests := []struct {
+		name       string
+		amount     int64
+		refundable map[uuid.UUID]int64
+		want       []model.RefundPayment
+		wantErr    bool
+	}{
+		{
+			name: "by the last payment", amount: 30000,
+			refundable: map[uuid.UUID]int64{cash.ID: 50000, card.ID: 50000, qris.ID: 50000},
+			want: []model.RefundPayment{
+				{PaymentID: qris.ID, Method: model.PaymentQRIS, Amount: money.New(30000, "IDR")},
+			},
+		},
+		{
+			name: "over several payments", amount: 80000,
+			refundable: map[uuid.UUID]int64{cash.ID: 50000, card.ID: 50000, qris.ID: 50000},
+			want: []model.RefundPayment{
+				{PaymentID: qris.ID, Method: model.PaymentQRIS, Amount: money.New(50000, "IDR")},
+				{PaymentID: card.ID, Method: model.PaymentCard, Amount: money.New(30000, "IDR")},
+			},
+		},
+		{
+			name: "skipping what gives nothing back", amount: 40000,
+			refundable: map[uuid.UUID]int64{cash.ID: 50000, card.ID: 0, qris.ID: 10000},
+			want: []model.RefundPayment{
+				{PaymentID: qris.ID, Method: model.PaymentQRIS, Amount: money.New(10000, "IDR")},
+				{PaymentID: cash.ID, Method: model.PaymentCash, Amount: money.New(30000, "IDR")},
+			},
+		},
+		{
+			name: "more than the payments can give back", amount: 70000,
+			refundable: map[uuid.UUID]int64{cash.ID: 20000, card.ID: 20000, qris.ID: 20000},
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				refund := model.Refund{Amount: money.New(tt.amount, "IDR")}
+				err := allocateRefund(&refund, transaction, tt.refundable)
+				if tt.wantErr {
+					if err == nil {
+						t.Fatalf("allocateRefund succeeded with %v, want an error", refund.Payments)
+					}
+
+					return
+				}
+				if err != nil {
+					t.Fatalf("allocateRefund: %v", err)
+				}
+
+				if !reflect.DeepEqual(refund.Payments, tt.want) {
+					t.Errorf("payments = %+v, want %+v", refund.Payments, tt.want)
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/rehandwi03/test-case-backend-majoo/criteria"
+	custom_error "github.com/rehandwi03/test-case-backend-majoo/internal/error"
+	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"github.com/rehandwi03/test-case-backend-majoo/money"
+	"github.com/rehandwi03/test-case-backend-majoo/query"
+	"github.com/rehandwi03/test-case-backend-majoo/repository"
+	"github.com/rehandwi03/test-case-backend-majoo/response"
+	"math"
+)
+
+// ReportService reports on the sales of the outlets. Costs are only shown to owners and managers.
+type ReportService interface {
+	// OutletMargins reports the gross margin of the sales of an outlet by product.
+	OutletMargins(ctx context.Context, criteria criteria.MarginCriteria) (*response.MarginReportResponse, error)
+	// ProductMargins reports the gross margin of the sales of a product by variant, sales of a product without
+	// variants make a single line.
+	ProductMargins(ctx context.Context, criteria criteria.MarginCriteria) (*response.MarginReportResponse, error)
+}
+
+type reportService struct {
+	transactionRepo repository.TransactionRepository
+	accessGuard     AccessGuard
+}
+
+func NewReportService(
+	transactionRepository repository.TransactionRepository, accessGuard AccessGuard,
+) ReportService {
+	return &reportService{transactionRepo: transactionRepository, accessGuard: accessGuard}
+}
+
+func (r *reportService) OutletMargins(ctx context.Context, criteria criteria.MarginCriteria) (
+	*response.MarginReportResponse, error,
+) {
+	outletId, err := uuid.Parse(criteria.OutletID)
+	if err != nil {
+		return nil, &custom_error.BadRequest{Message: "outlet id is invalid"}
+	}
+
+	outlet, err := r.accessGuard.Outlet(ctx, outletId, model.RoleOwner, model.RoleManager)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.margins(
+		ctx, outlet, query.Where(query.Eq("transactions.outlet_id", outlet.ID)), criteria, false,
+		response.MarginReportResponse{OutletID: outlet.ID},
+	)
+}
+
+func (r *reportService) ProductMargins(ctx context.Context, criteria criteria.MarginCriteria) (
+	*response.MarginReportResponse, error,
+) {
+	productId, err := uuid.Parse(criteria.ProductID)
+	if err != nil {
+		return nil, &custom_error.BadRequest{Message: "product id is invalid"}
+	}
+
+	product, err := r.accessGuard.Product(ctx, productId, model.RoleOwner, model.RoleManager)
+	if err != nil {
+		return nil, err
+	}
+
+	outlet, err := r.accessGuard.Outlet(ctx, product.OutletID, model.RoleOwner, model.RoleManager)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.margins(
+		ctx, outlet, query.Where(query.Eq("transaction_items.product_id", product.ID)), criteria, true,
+		response.MarginReportResponse{OutletID: outlet.ID, ProductID: &product.ID},
+	)
+}
+
+// margins fills the report with the margins of the sales matching the spec and the period of the criteria, in the
+// currency of the merchant of the outlet.
+func (r *reportService) margins(
+	ctx context.Context, outlet model.Outlet, spec query.Spec, criteria criteria.MarginCriteria, byVariant bool,
+	report response.MarginReportResponse,
+) (*response.MarginReportResponse, error) {
+	merchant, err := r.accessGuard.Merchant(ctx, outlet.MerchantID, model.RoleOwner, model.RoleManager)
+	if err != nil {
+		return nil, err
+	}
+
+	from, err := parseTimeFilter("from", criteria.From, false)
+	if err != nil {
+		return nil, err
+	}
+	to, err := parseTimeFilter("to", criteria.To, true)
+	if err != nil {
+		return nil, err
+	}
+
+	margins, err := r.transactionRepo.Margins(
+		ctx, spec.And(query.Between("transactions.created_at", from, to)), byVariant,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var revenue, cost int64
+	report.Lines = []response.MarginResponse{}
+	for _, margin := range margins {
+		report.Lines = append(
+			report.Lines, response.MarginResponse{
+				ProductID:   margin.ProductID,
+				VariantID:   margin.VariantID,
+				Name:        margin.Name,
+				VariantName: margin.VariantName,
+				Quantity:    margin.Quantity,
+				Revenue:     money.New(margin.Revenue, merchant.Currency),
+				Cost:        money.New(margin.Cost, merchant.Currency),
+				GrossProfit: money.New(margin.Revenue-margin.Cost, merchant.Currency),
+				Margin:      marginPercentage(margin.Revenue, margin.Cost),
+			},
+		)
+
+		report.Quantity += margin.Quantity
+		revenue += margin.Revenue
+		cost += margin.Cost
+	}
+
+	report.Revenue = money.New(revenue, merchant.Currency)
+	report.Cost = money.New(cost, merchant.Currency)
+	report.GrossProfit = money.New(revenue-cost, merchant.Currency)
+	report.Margin = marginPercentage(revenue, cost)
+
+	return &report, nil
+}
+
+// marginPercentage is the share of the revenue left after the cost, in percent rounded to two decimals.
+func marginPercentage(revenue int64, cost int64) *float64 {
+	if revenue == 0 {
+		return nil
+	}
+
+	margin := math.Round(float64(revenue-cost)/float64(revenue)*10000) / 100
+
+	return &margin
+}
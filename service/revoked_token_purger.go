@@ -0,0 +1,40 @@
+package service
+
+import (
+	"context"
+	"github.com/rehandwi03/test-case-backend-majoo/repository"
+	"log"
+	"time"
+)
+
+// RevokedTokenPurger forgets in the background the revoked access tokens that expired, they are rejected by their
+// exp claim anyway, so the revocation list looked up on every request stays small.
+type RevokedTokenPurger struct {
+	revokedTokenRepo repository.RevokedTokenRepository
+	interval         time.Duration
+}
+
+func NewRevokedTokenPurger(
+	revokedTokenRepository repository.RevokedTokenRepository, interval time.Duration,
+) *RevokedTokenPurger {
+	return &RevokedTokenPurger{revokedTokenRepo: revokedTokenRepository, interval: interval}
+}
+
+// Run purges the expired revoked tokens every interval until the context is done.
+func (r *RevokedTokenPurger) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		err := r.revokedTokenRepo.DeleteExpired(ctx)
+		if err != nil {
+			log.Printf("error deleting expired revoked tokens: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,352 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"github.com/google/uuid"
+	"github.com/rehandwi03/test-case-backend-majoo/criteria"
+	custom_error "github.com/rehandwi03/test-case-backend-majoo/internal/error"
+	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"github.com/rehandwi03/test-case-backend-majoo/query"
+	"github.com/rehandwi03/test-case-backend-majoo/repository"
+	"github.com/rehandwi03/test-case-backend-majoo/request"
+	"github.com/rehandwi03/test-case-backend-majoo/response"
+	"github.com/rehandwi03/test-case-backend-majoo/util"
+	"gorm.io/gorm"
+	"time"
+)
+
+type StockCountService interface {
+	// Start opens a count of the outlet, snapshotting the stock of its products and variants.
+	Start(ctx context.Context, request *request.StockCountStartRequest) (uuid.UUID, error)
+	// Record submits a batch of counted quantities to an open count.
+	Record(ctx context.Context, request *request.StockCountRecordRequest) error
+	// Approve adjusts the stock by the variances of the counted lines and closes the count, lines left uncounted
+	// keep their stock.
+	Approve(ctx context.Context, outletId uuid.UUID, countId uuid.UUID) error
+	Cancel(ctx context.Context, outletId uuid.UUID, countId uuid.UUID) error
+	GetByParam(ctx context.Context, spec query.Spec) (*response.StockCountResponse, error)
+	Fetch(ctx context.Context, criteria criteria.StockCountCriteria) (*util.PaginationResponse, error)
+}
+
+type stockCountService struct {
+	stockCountRepo repository.StockCountRepository
+	productRepo    repository.ProductRepository
+	accessGuard    AccessGuard
+}
+
+func NewStockCountService(
+	stockCountRepository repository.StockCountRepository, productRepository repository.ProductRepository,
+	accessGuard AccessGuard,
+) StockCountService {
+	return &stockCountService{
+		stockCountRepo: stockCountRepository, productRepo: productRepository, accessGuard: accessGuard,
+	}
+}
+
+func (s *stockCountService) Start(ctx context.Context, request *request.StockCountStartRequest) (uuid.UUID, error) {
+	userId, ok := ctx.Value("user_id").(uuid.UUID)
+	if !ok {
+		return uuid.Nil, &custom_error.NotFoundError{Message: "user id not found"}
+	}
+
+	_, err := s.accessGuard.Outlet(ctx, request.OutletID, model.RoleOwner, model.RoleManager)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	products, err := s.productRepo.List(ctx, query.Where(query.Eq("outlet_id", request.OutletID)))
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	count := model.StockCount{
+		OutletID:  request.OutletID,
+		Status:    model.StockCountOpen,
+		Note:      request.Note,
+		CreatedBy: userId,
+	}
+	for _, product := range products {
+		// a product with a recipe, or a bundle, has no stock of its own, its ingredients or components are counted
+		if len(product.Recipe) > 0 || product.IsBundle {
+			continue
+		}
+
+		// the stock of a product with variants is kept by its variants, so they are counted instead
+		if len(product.Variants) == 0 {
+			count.Lines = append(
+				count.Lines, model.StockCountLine{
+					ProductID: product.ID,
+					Name:      product.Name,
+					Expected:  product.Stock,
+				},
+			)
+		}
+
+		for _, variant := range product.Variants {
+			variantId := variant.ID
+			count.Lines = append(
+				count.Lines, model.StockCountLine{
+					ProductID:   product.ID,
+					VariantID:   &variantId,
+					Name:        product.Name,
+					VariantName: variant.Label(),
+					Expected:    variant.Stock,
+				},
+			)
+		}
+	}
+
+	if len(count.Lines) == 0 {
+		return uuid.Nil, &custom_error.BadRequest{Message: "the outlet has no products to count"}
+	}
+
+	res, err := s.stockCountRepo.Start(ctx, count)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return res, nil
+}
+
+func (s *stockCountService) Record(ctx context.Context, request *request.StockCountRecordRequest) error {
+	userId, ok := ctx.Value("user_id").(uuid.UUID)
+	if !ok {
+		return &custom_error.NotFoundError{Message: "user id not found"}
+	}
+
+	count, err := s.openCount(
+		ctx, request.OutletID, request.ID, model.RoleOwner, model.RoleManager, model.RoleCashier,
+	)
+	if err != nil {
+		return err
+	}
+
+	linesByItem := map[stockItem]model.StockCountLine{}
+	for _, line := range count.Lines {
+		linesByItem[stockItemOf(line.ProductID, line.VariantID)] = line
+	}
+
+	countedAt := sql.NullTime{Time: time.Now(), Valid: true}
+	var lines []model.StockCountLine
+	for _, counted := range request.Lines {
+		line, ok := linesByItem[stockItemOf(counted.ProductID, counted.VariantID)]
+		if !ok {
+			return &custom_error.BadRequest{
+				Message: "product " + counted.ProductID.String() + " isn't part of the count", Field: "lines",
+			}
+		}
+
+		quantity := counted.Counted
+		line.Counted = &quantity
+		line.CountedBy = &userId
+		line.CountedAt = countedAt
+		lines = append(lines, line)
+	}
+
+	err = s.stockCountRepo.Record(ctx, count.ID, lines)
+	if err != nil {
+		if err == repository.ErrStockCountClosed {
+			return &custom_error.ConflictError{Message: err.Error()}
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (s *stockCountService) Approve(ctx context.Context, outletId uuid.UUID, countId uuid.UUID) error {
+	userId, ok := ctx.Value("user_id").(uuid.UUID)
+	if !ok {
+		return &custom_error.NotFoundError{Message: "user id not found"}
+	}
+
+	count, err := s.openCount(ctx, outletId, countId, model.RoleOwner, model.RoleManager)
+	if err != nil {
+		return err
+	}
+
+	err = s.stockCountRepo.Approve(ctx, count.ID, userId)
+	if err != nil {
+		switch err {
+		case repository.ErrStockCountClosed:
+			return &custom_error.ConflictError{Message: err.Error()}
+		case repository.ErrInsufficientStock:
+			return &custom_error.ConflictError{
+				Message: "a variance would take the stock below zero as it was sold since it was counted, " +
+					"count it again",
+			}
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (s *stockCountService) Cancel(ctx context.Context, outletId uuid.UUID, countId uuid.UUID) error {
+	count, err := s.openCount(ctx, outletId, countId, model.RoleOwner, model.RoleManager)
+	if err != nil {
+		return err
+	}
+
+	err = s.stockCountRepo.Cancel(ctx, count.ID)
+	if err != nil {
+		if err == repository.ErrStockCountClosed {
+			return &custom_error.ConflictError{Message: err.Error()}
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (s *stockCountService) GetByParam(ctx context.Context, spec query.Spec) (*response.StockCountResponse, error) {
+	count, err := s.stockCountRepo.Get(ctx, spec)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, &custom_error.NotFoundError{Message: "stock count not found"}
+		}
+
+		return nil, err
+	}
+
+	_, err = s.accessGuard.Outlet(ctx, count.OutletID, model.RoleOwner, model.RoleManager, model.RoleCashier)
+	if err != nil {
+		return nil, err
+	}
+
+	response := stockCountResponse(count, true)
+
+	return &response, nil
+}
+
+func (s *stockCountService) Fetch(ctx context.Context, criteria criteria.StockCountCriteria) (
+	*util.PaginationResponse, error,
+) {
+	outletId, err := uuid.Parse(criteria.OutletID)
+	if err != nil {
+		return nil, &custom_error.BadRequest{Message: "outlet id is invalid"}
+	}
+
+	_, err = s.accessGuard.Outlet(ctx, outletId, model.RoleOwner, model.RoleManager, model.RoleCashier)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := query.Where(query.Eq("outlet_id", outletId))
+	if criteria.Status != "" {
+		spec = spec.And(query.Eq("status", criteria.Status))
+	}
+
+	spec, err = paginate(spec, &criteria.Pagination, "status", "created_at")
+	if err != nil {
+		return nil, err
+	}
+
+	res, rowCount, err := s.stockCountRepo.Fetch(ctx, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	var responseData []response.StockCountResponse
+	for _, val := range res {
+		responseData = append(responseData, stockCountResponse(val, false))
+	}
+
+	resPagination := util.BuildPagination(criteria.Pagination, responseData, rowCount)
+
+	return &resPagination, nil
+}
+
+// openCount returns the count of the outlet after checking the caller holds one of the roles in it, a count already
+// approved or cancelled is a conflict.
+func (s *stockCountService) openCount(
+	ctx context.Context, outletId uuid.UUID, countId uuid.UUID, roles ...string,
+) (model.StockCount, error) {
+	_, err := s.accessGuard.Outlet(ctx, outletId, roles...)
+	if err != nil {
+		return model.StockCount{}, err
+	}
+
+	count, err := s.stockCountRepo.Get(ctx, query.Where(query.Eq("id", countId), query.Eq("outlet_id", outletId)))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return count, &custom_error.NotFoundError{Message: "stock count not found"}
+		}
+
+		return count, err
+	}
+
+	if count.Status != model.StockCountOpen {
+		return count, &custom_error.ConflictError{Message: "the stock count is " + count.Status}
+	}
+
+	return count, nil
+}
+
+// stockItem is a product, or a variant of it, whose stock is kept on its own.
+type stockItem struct {
+	productId uuid.UUID
+	variantId uuid.UUID
+}
+
+func stockItemOf(productId uuid.UUID, variantId *uuid.UUID) stockItem {
+	item := stockItem{productId: productId}
+	if variantId != nil {
+		item.variantId = *variantId
+	}
+
+	return item
+}
+
+func stockCountResponse(count model.StockCount, withLines bool) response.StockCountResponse {
+	data := response.StockCountResponse{
+		ID:         count.ID,
+		OutletID:   count.OutletID,
+		Status:     count.Status,
+		Note:       count.Note,
+		CreatedBy:  count.CreatedBy,
+		ApprovedBy: count.ApprovedBy,
+		TotalLines: len(count.Lines),
+		CreatedAt:  count.CreatedAt.Time,
+	}
+	if count.ApprovedAt.Valid {
+		data.ApprovedAt = &count.ApprovedAt.Time
+	}
+
+	for _, line := range count.Lines {
+		variance := line.Variance()
+		if variance != nil {
+			data.CountedLines++
+		}
+		if variance != nil && *variance != 0 {
+			data.LinesWithVariance++
+		}
+
+		if !withLines {
+			continue
+		}
+
+		lineData := response.StockCountLineResponse{
+			ID:          line.ID,
+			ProductID:   line.ProductID,
+			VariantID:   line.VariantID,
+			Name:        line.Name,
+			VariantName: line.VariantName,
+			Expected:    line.Expected,
+			Counted:     line.Counted,
+			Variance:    variance,
+			CountedBy:   line.CountedBy,
+		}
+		if line.CountedAt.Valid {
+			countedAt := line.CountedAt.Time
+			lineData.CountedAt = &countedAt
+		}
+		data.Lines = append(data.Lines, lineData)
+	}
+
+	return data
+}
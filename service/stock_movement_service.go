@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/rehandwi03/test-case-backend-majoo/criteria"
+	custom_error "github.com/rehandwi03/test-case-backend-majoo/internal/error"
+	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"github.com/rehandwi03/test-case-backend-majoo/query"
+	"github.com/rehandwi03/test-case-backend-majoo/repository"
+	"github.com/rehandwi03/test-case-backend-majoo/request"
+	"github.com/rehandwi03/test-case-backend-majoo/response"
+	"github.com/rehandwi03/test-case-backend-majoo/util"
+)
+
+// stockMovementTypes are the movement types the ledger can be filtered by.
+var stockMovementTypes = map[string]bool{
+	model.MovementSale: true, model.MovementRestock: true, model.MovementAdjustment: true, model.MovementWaste: true,
+	model.MovementTransfer: true, model.MovementReturn: true,
+}
+
+type StockMovementService interface {
+	// PostAdjustment records a restock, waste or adjustment of the stock of a product, or of one of its variants.
+	PostAdjustment(ctx context.Context, request *request.StockAdjustmentRequest) (uuid.UUID, error)
+	// Fetch lists the movements of an outlet, or of a product when the criteria names one.
+	Fetch(ctx context.Context, criteria criteria.StockMovementCriteria) (*util.PaginationResponse, error)
+}
+
+type stockMovementService struct {
+	stockMovementRepo repository.StockMovementRepository
+	accessGuard       AccessGuard
+}
+
+func NewStockMovementService(
+	stockMovementRepository repository.StockMovementRepository, accessGuard AccessGuard,
+) StockMovementService {
+	return &stockMovementService{stockMovementRepo: stockMovementRepository, accessGuard: accessGuard}
+}
+
+func (s *stockMovementService) PostAdjustment(ctx context.Context, request *request.StockAdjustmentRequest) (
+	uuid.UUID, error,
+) {
+	userId, ok := ctx.Value("user_id").(uuid.UUID)
+	if !ok {
+		return uuid.Nil, &custom_error.NotFoundError{Message: "user id not found"}
+	}
+
+	product, err := s.accessGuard.Product(ctx, request.ProductID, model.RoleOwner, model.RoleManager)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	movement := model.StockMovement{
+		OutletID:  product.OutletID,
+		ProductID: product.ID,
+		Type:      request.Type,
+		Reason:    request.Reason,
+		Note:      request.Note,
+		Quantity:  request.Quantity,
+		UserID:    &userId,
+	}
+
+	// the stock of a product with variants is kept by its variants
+	if request.VariantID != nil || len(product.Variants) > 0 {
+		if request.VariantID == nil {
+			return uuid.Nil, &custom_error.BadRequest{Message: "choose a variant of the product", Field: "variant_id"}
+		}
+
+		variant, ok := findVariant(product, *request.VariantID)
+		if !ok {
+			return uuid.Nil, &custom_error.BadRequest{Message: "variant not found", Field: "variant_id"}
+		}
+
+		movement.VariantID = &variant.ID
+	}
+
+	if request.Type != model.MovementAdjustment && request.Quantity < 0 {
+		return uuid.Nil, &custom_error.BadRequest{
+			Message: "quantity of a " + request.Type + " must be positive", Field: "quantity",
+		}
+	}
+	if request.Type == model.MovementWaste {
+		movement.Quantity = -request.Quantity
+	}
+
+	res, err := s.stockMovementRepo.Post(ctx, movement)
+	if err != nil {
+		if err == repository.ErrInsufficientStock {
+			return uuid.Nil, &custom_error.BadRequest{Message: "stock can't go below zero", Field: "quantity"}
+		}
+
+		return uuid.Nil, err
+	}
+
+	return res, nil
+}
+
+func (s *stockMovementService) Fetch(ctx context.Context, criteria criteria.StockMovementCriteria) (
+	*util.PaginationResponse, error,
+) {
+	var spec query.Spec
+	if criteria.ProductID != "" {
+		productId, err := uuid.Parse(criteria.ProductID)
+		if err != nil {
+			return nil, &custom_error.BadRequest{Message: "product id is invalid"}
+		}
+
+		_, err = s.accessGuard.Product(ctx, productId, model.RoleOwner, model.RoleManager, model.RoleCashier)
+		if err != nil {
+			return nil, err
+		}
+
+		spec = query.Where(query.Eq("product_id", productId))
+	} else {
+		outletId, err := uuid.Parse(criteria.OutletID)
+		if err != nil {
+			return nil, &custom_error.BadRequest{Message: "outlet id is invalid"}
+		}
+
+		_, err = s.accessGuard.Outlet(ctx, outletId, model.RoleOwner, model.RoleManager, model.RoleCashier)
+		if err != nil {
+			return nil, err
+		}
+
+		spec = query.Where(query.Eq("outlet_id", outletId))
+	}
+
+	if criteria.VariantID != "" {
+		variantId, err := uuid.Parse(criteria.VariantID)
+		if err != nil {
+			return nil, &custom_error.BadRequest{Message: "variant_id must be an uuid", Field: "variant_id"}
+		}
+
+		spec = spec.And(query.Eq("variant_id", variantId))
+	}
+	if criteria.Type != "" {
+		if !stockMovementTypes[criteria.Type] {
+			return nil, &custom_error.BadRequest{Message: "type is not a stock movement type", Field: "type"}
+		}
+
+		spec = spec.And(query.Eq("type", criteria.Type))
+	}
+
+	from, err := parseTimeFilter("from", criteria.From, false)
+	if err != nil {
+		return nil, err
+	}
+	to, err := parseTimeFilter("to", criteria.To, true)
+	if err != nil {
+		return nil, err
+	}
+	spec = spec.And(query.Between("created_at", from, to))
+
+	spec, err = paginate(spec, &criteria.Pagination, "created_at", "quantity")
+	if err != nil {
+		return nil, err
+	}
+
+	res, rowCount, err := s.stockMovementRepo.Fetch(ctx, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	var responseData []response.StockMovementResponse
+	for _, val := range res {
+		responseData = append(responseData, stockMovementResponse(val))
+	}
+
+	resPagination := util.BuildPagination(criteria.Pagination, responseData, rowCount)
+
+	return &resPagination, nil
+}
+
+func stockMovementResponse(movement model.StockMovement) response.StockMovementResponse {
+	return response.StockMovementResponse{
+		ID:                movement.ID,
+		OutletID:          movement.OutletID,
+		ProductID:         movement.ProductID,
+		VariantID:         movement.VariantID,
+		Type:              movement.Type,
+		Reason:            movement.Reason,
+		Note:              movement.Note,
+		Quantity:          movement.Quantity,
+		Balance:           movement.Balance,
+		TransactionID:     movement.TransactionID,
+		TransactionItemID: movement.TransactionItemID,
+		RefundID:          movement.RefundID,
+		PurchaseOrderID:   movement.PurchaseOrderID,
+		UnitCost:          optionalMoney(movement.UnitCost),
+		Cost:              optionalMoney(movement.Cost),
+		UserID:            movement.UserID,
+		CreatedAt:         movement.CreatedAt.Time,
+	}
+}
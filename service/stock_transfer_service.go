@@ -0,0 +1,466 @@
+package service
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/rehandwi03/test-case-backend-majoo/criteria"
+	custom_error "github.com/rehandwi03/test-case-backend-majoo/internal/error"
+	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"github.com/rehandwi03/test-case-backend-majoo/query"
+	"github.com/rehandwi03/test-case-backend-majoo/repository"
+	"github.com/rehandwi03/test-case-backend-majoo/request"
+	"github.com/rehandwi03/test-case-backend-majoo/response"
+	"github.com/rehandwi03/test-case-backend-majoo/util"
+	"gorm.io/gorm"
+)
+
+type StockTransferService interface {
+	// SaveTransfer drafts a transfer between two outlets of a merchant, matching every product of the source outlet
+	// to the one with the same SKU in the destination outlet.
+	SaveTransfer(ctx context.Context, request *request.StockTransferAddRequest) (uuid.UUID, error)
+	// Send takes the stock of the draft out of the source outlet.
+	Send(ctx context.Context, transferId uuid.UUID) error
+	// Receive adds received quantities to the stock of the destination outlet.
+	Receive(ctx context.Context, request *request.StockTransferReceiveRequest) error
+	// Close settles what a sent transfer still has outstanding, when it won't be received any more.
+	Close(ctx context.Context, request *request.StockTransferCloseRequest) error
+	// Cancel drops a draft, a transfer already sent can't be cancelled.
+	Cancel(ctx context.Context, transferId uuid.UUID) error
+	GetByParam(ctx context.Context, spec query.Spec) (*response.StockTransferResponse, error)
+	Fetch(ctx context.Context, criteria criteria.StockTransferCriteria) (*util.PaginationResponse, error)
+}
+
+type stockTransferService struct {
+	stockTransferRepo repository.StockTransferRepository
+	productRepo       repository.ProductRepository
+	accessGuard       AccessGuard
+}
+
+func NewStockTransferService(
+	stockTransferRepository repository.StockTransferRepository, productRepository repository.ProductRepository,
+	accessGuard AccessGuard,
+) StockTransferService {
+	return &stockTransferService{
+		stockTransferRepo: stockTransferRepository, productRepo: productRepository, accessGuard: accessGuard,
+	}
+}
+
+func (s *stockTransferService) SaveTransfer(ctx context.Context, request *request.StockTransferAddRequest) (
+	uuid.UUID, error,
+) {
+	userId, ok := ctx.Value("user_id").(uuid.UUID)
+	if !ok {
+		return uuid.Nil, &custom_error.NotFoundError{Message: "user id not found"}
+	}
+
+	source, err := s.accessGuard.Outlet(ctx, request.SourceOutletID, model.RoleOwner, model.RoleManager)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	destination, err := s.accessGuard.Outlet(ctx, request.DestinationOutletID, model.RoleOwner, model.RoleManager)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	if destination.ID == source.ID || destination.MerchantID != source.MerchantID {
+		return uuid.Nil, &custom_error.BadRequest{
+			Message: "the destination has to be another outlet of the merchant", Field: "destination_outlet_id",
+		}
+	}
+
+	var productIds []uuid.UUID
+	for _, line := range request.Lines {
+		productIds = append(productIds, line.ProductID)
+	}
+
+	sourceProducts, err := s.productRepo.List(
+		ctx, query.Where(query.In("id", productIds), query.Eq("outlet_id", source.ID)),
+	)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	destinationProducts, err := s.productRepo.List(ctx, query.Where(query.Eq("outlet_id", destination.ID)))
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	productMap := map[uuid.UUID]model.Product{}
+	for _, product := range sourceProducts {
+		productMap[product.ID] = product
+	}
+
+	destinationItems := itemsBySKU(destinationProducts)
+
+	transfer := model.StockTransfer{
+		MerchantID:          source.MerchantID,
+		SourceOutletID:      source.ID,
+		DestinationOutletID: destination.ID,
+		Status:              model.StockTransferDraft,
+		Note:                request.Note,
+		CreatedBy:           userId,
+	}
+	lineIndex := map[stockItem]int{}
+	for _, lineRequest := range request.Lines {
+		product, ok := productMap[lineRequest.ProductID]
+		if !ok {
+			return uuid.Nil, &custom_error.BadRequest{
+				Message: "product " + lineRequest.ProductID.String() + " not found in the source outlet",
+				Field:   "lines",
+			}
+		}
+
+		line := model.StockTransferLine{
+			ProductID: product.ID,
+			SKU:       product.SKU,
+			Name:      product.Name,
+			Quantity:  lineRequest.Quantity,
+		}
+
+		// the stock of a product with variants is kept by its variants, so one of them is transferred
+		if lineRequest.VariantID != nil || len(product.Variants) > 0 {
+			var variantId uuid.UUID
+			if lineRequest.VariantID != nil {
+				variantId = *lineRequest.VariantID
+			}
+
+			variant, ok := findVariant(product, variantId)
+			if !ok {
+				return uuid.Nil, &custom_error.BadRequest{
+					Message: "choose a variant of product " + product.Name, Field: "lines",
+				}
+			}
+
+			line.VariantID = &variant.ID
+			line.SKU = variant.SKU
+			line.VariantName = variant.Label()
+		}
+
+		// the same product may be listed twice, its quantities add up
+		item := stockItemOf(line.ProductID, line.VariantID)
+		if i, ok := lineIndex[item]; ok {
+			transfer.Lines[i].Quantity += line.Quantity
+			continue
+		}
+
+		if line.SKU == "" {
+			return uuid.Nil, &custom_error.BadRequest{
+				Message: product.Name + " has no sku to find it in the destination outlet", Field: "lines",
+			}
+		}
+
+		matches := destinationItems[line.SKU]
+		if len(matches) != 1 {
+			message := "no product with sku " + line.SKU + " in the destination outlet"
+			if len(matches) > 1 {
+				message = "more than one product with sku " + line.SKU + " in the destination outlet"
+			}
+
+			return uuid.Nil, &custom_error.BadRequest{Message: message, Field: "lines"}
+		}
+
+		line.DestinationProductID = matches[0].productId
+		if matches[0].variantId != uuid.Nil {
+			destinationVariantId := matches[0].variantId
+			line.DestinationVariantID = &destinationVariantId
+		}
+
+		lineIndex[item] = len(transfer.Lines)
+		transfer.Lines = append(transfer.Lines, line)
+	}
+
+	res, err := s.stockTransferRepo.Create(ctx, transfer)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return res, nil
+}
+
+func (s *stockTransferService) Send(ctx context.Context, transferId uuid.UUID) error {
+	userId, ok := ctx.Value("user_id").(uuid.UUID)
+	if !ok {
+		return &custom_error.NotFoundError{Message: "user id not found"}
+	}
+
+	transfer, err := s.getTransfer(ctx, transferId)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.accessGuard.Outlet(ctx, transfer.SourceOutletID, model.RoleOwner, model.RoleManager)
+	if err != nil {
+		return err
+	}
+
+	err = s.stockTransferRepo.Send(ctx, transfer.ID, userId)
+	if err != nil {
+		switch err {
+		case repository.ErrStockTransferStatus:
+			return &custom_error.ConflictError{Message: "only a draft can be sent"}
+		case repository.ErrInsufficientStock:
+			return &custom_error.BadRequest{Message: "insufficient stock in the source outlet", Field: "lines"}
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (s *stockTransferService) Receive(ctx context.Context, request *request.StockTransferReceiveRequest) error {
+	userId, ok := ctx.Value("user_id").(uuid.UUID)
+	if !ok {
+		return &custom_error.NotFoundError{Message: "user id not found"}
+	}
+
+	transfer, err := s.getTransfer(ctx, request.ID)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.accessGuard.Outlet(ctx, transfer.DestinationOutletID, model.RoleOwner, model.RoleManager)
+	if err != nil {
+		return err
+	}
+
+	lines := map[uuid.UUID]model.StockTransferLine{}
+	for _, line := range transfer.Lines {
+		lines[line.ID] = line
+	}
+
+	received := map[uuid.UUID]int64{}
+	for _, receipt := range request.Lines {
+		line, ok := lines[receipt.LineID]
+		if !ok {
+			return &custom_error.BadRequest{
+				Message: "line " + receipt.LineID.String() + " isn't part of the transfer", Field: "lines",
+			}
+		}
+
+		received[line.ID] += receipt.Quantity
+		if line.Received+received[line.ID] > line.Quantity {
+			return &custom_error.BadRequest{
+				Message: "more of " + line.Name + " would be received than was sent", Field: "lines",
+			}
+		}
+	}
+
+	err = s.stockTransferRepo.Receive(ctx, transfer.ID, userId, received)
+	if err != nil {
+		switch err {
+		case repository.ErrStockTransferStatus:
+			return &custom_error.ConflictError{Message: "only a sent transfer can be received"}
+		case repository.ErrOverReceived:
+			return &custom_error.BadRequest{Message: err.Error(), Field: "lines"}
+		case repository.ErrInsufficientStock:
+			// receiving only adds stock, so the product left the destination outlet
+			return &custom_error.ConflictError{
+				Message: "a product of the transfer isn't in the destination outlet any more",
+			}
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (s *stockTransferService) Close(ctx context.Context, request *request.StockTransferCloseRequest) error {
+	userId, ok := ctx.Value("user_id").(uuid.UUID)
+	if !ok {
+		return &custom_error.NotFoundError{Message: "user id not found"}
+	}
+
+	transfer, err := s.getTransfer(ctx, request.ID)
+	if err != nil {
+		return err
+	}
+
+	// the destination knows what never arrived
+	_, err = s.accessGuard.Outlet(ctx, transfer.DestinationOutletID, model.RoleOwner, model.RoleManager)
+	if err != nil {
+		return err
+	}
+
+	err = s.stockTransferRepo.Close(ctx, transfer.ID, userId, request.Return)
+	if err != nil {
+		switch err {
+		case repository.ErrStockTransferStatus:
+			return &custom_error.ConflictError{
+				Message: "only a sent transfer that isn't received in full can be closed",
+			}
+		case repository.ErrInsufficientStock:
+			// closing takes out no more than it puts back, so the product left the source outlet
+			return &custom_error.ConflictError{Message: "a product of the transfer isn't in the source outlet any more"}
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (s *stockTransferService) Cancel(ctx context.Context, transferId uuid.UUID) error {
+	transfer, err := s.getTransfer(ctx, transferId)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.accessGuard.Outlet(ctx, transfer.SourceOutletID, model.RoleOwner, model.RoleManager)
+	if err != nil {
+		return err
+	}
+
+	err = s.stockTransferRepo.Cancel(ctx, transfer.ID)
+	if err != nil {
+		if err == repository.ErrStockTransferStatus {
+			return &custom_error.ConflictError{Message: "only a draft can be cancelled"}
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (s *stockTransferService) GetByParam(ctx context.Context, spec query.Spec) (
+	*response.StockTransferResponse, error,
+) {
+	transfer, err := s.stockTransferRepo.Get(ctx, spec)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, &custom_error.NotFoundError{Message: "stock transfer not found"}
+		}
+
+		return nil, err
+	}
+
+	_, err = s.accessGuard.Merchant(
+		ctx, transfer.MerchantID, model.RoleOwner, model.RoleManager, model.RoleCashier,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	response := stockTransferResponse(transfer)
+
+	return &response, nil
+}
+
+func (s *stockTransferService) Fetch(ctx context.Context, criteria criteria.StockTransferCriteria) (
+	*util.PaginationResponse, error,
+) {
+	merchantIds, all, err := s.accessGuard.MerchantIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := query.Where()
+	if !all {
+		spec = spec.And(query.In("merchant_id", merchantIds))
+	}
+	if criteria.OutletID != "" {
+		outletId, err := uuid.Parse(criteria.OutletID)
+		if err != nil {
+			return nil, &custom_error.BadRequest{Message: "outlet_id must be an uuid", Field: "outlet_id"}
+		}
+
+		spec = spec.And(query.Or(query.Eq("source_outlet_id", outletId), query.Eq("destination_outlet_id", outletId)))
+	}
+	if criteria.Status != "" {
+		spec = spec.And(query.Eq("status", criteria.Status))
+	}
+
+	spec, err = paginate(spec, &criteria.Pagination, "status", "created_at")
+	if err != nil {
+		return nil, err
+	}
+
+	res, rowCount, err := s.stockTransferRepo.Fetch(ctx, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	var responseData []response.StockTransferResponse
+	for _, val := range res {
+		responseData = append(responseData, stockTransferResponse(val))
+	}
+
+	resPagination := util.BuildPagination(criteria.Pagination, responseData, rowCount)
+
+	return &resPagination, nil
+}
+
+func (s *stockTransferService) getTransfer(ctx context.Context, transferId uuid.UUID) (model.StockTransfer, error) {
+	transfer, err := s.stockTransferRepo.Get(ctx, query.Where(query.Eq("id", transferId)))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return transfer, &custom_error.NotFoundError{Message: "stock transfer not found"}
+		}
+
+		return transfer, err
+	}
+
+	return transfer, nil
+}
+
+// itemsBySKU indexes the products and variants keeping their own stock by their SKU.
+func itemsBySKU(products []model.Product) map[string][]stockItem {
+	items := map[string][]stockItem{}
+	for _, product := range products {
+		if len(product.Variants) == 0 && product.SKU != "" {
+			items[product.SKU] = append(items[product.SKU], stockItem{productId: product.ID})
+		}
+
+		for _, variant := range product.Variants {
+			if variant.SKU != "" {
+				items[variant.SKU] = append(items[variant.SKU], stockItem{productId: product.ID, variantId: variant.ID})
+			}
+		}
+	}
+
+	return items
+}
+
+func stockTransferResponse(transfer model.StockTransfer) response.StockTransferResponse {
+	data := response.StockTransferResponse{
+		ID:                  transfer.ID,
+		MerchantID:          transfer.MerchantID,
+		SourceOutletID:      transfer.SourceOutletID,
+		DestinationOutletID: transfer.DestinationOutletID,
+		Status:              transfer.Status,
+		Note:                transfer.Note,
+		CreatedBy:           transfer.CreatedBy,
+		SentBy:              transfer.SentBy,
+		Lines:               []response.StockTransferLineResponse{},
+		CreatedAt:           transfer.CreatedAt.Time,
+	}
+	if transfer.SentAt.Valid {
+		data.SentAt = &transfer.SentAt.Time
+	}
+
+	for _, line := range transfer.Lines {
+		data.Lines = append(
+			data.Lines, response.StockTransferLineResponse{
+				ID:                   line.ID,
+				ProductID:            line.ProductID,
+				VariantID:            line.VariantID,
+				DestinationProductID: line.DestinationProductID,
+				DestinationVariantID: line.DestinationVariantID,
+				SKU:                  line.SKU,
+				Name:                 line.Name,
+				VariantName:          line.VariantName,
+				Quantity:             line.Quantity,
+				Received:             line.Received,
+				Returned:             line.Returned,
+				Lost:                 line.Lost,
+				Outstanding:          line.Quantity - line.Received - line.Returned - line.Lost,
+				UnitCost:             optionalMoney(line.UnitCost),
+			},
+		)
+	}
+
+	return data
+}
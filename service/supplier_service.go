@@ -0,0 +1,242 @@
+package service
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/rehandwi03/test-case-backend-majoo/criteria"
+	custom_error "github.com/rehandwi03/test-case-backend-majoo/internal/error"
+	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"github.com/rehandwi03/test-case-backend-majoo/query"
+	"github.com/rehandwi03/test-case-backend-majoo/repository"
+	"github.com/rehandwi03/test-case-backend-majoo/request"
+	"github.com/rehandwi03/test-case-backend-majoo/response"
+	"github.com/rehandwi03/test-case-backend-majoo/util"
+	"gorm.io/gorm"
+)
+
+type SupplierService interface {
+	SaveSupplier(ctx context.Context, request *request.SupplierAddRequest) (uuid.UUID, error)
+	UpdateSupplier(ctx context.Context, request *request.SupplierUpdateRequest) (uuid.UUID, error)
+	DeleteSupplier(ctx context.Context, supplierId uuid.UUID) error
+	RestoreSupplier(ctx context.Context, supplierId uuid.UUID) error
+	GetByParam(ctx context.Context, spec query.Spec) (*response.SupplierResponse, error)
+	Fetch(ctx context.Context, criteria criteria.SupplierCriteria) (*util.PaginationResponse, error)
+}
+
+type supplierService struct {
+	supplierRepo repository.SupplierRepository
+	accessGuard  AccessGuard
+}
+
+func NewSupplierService(supplierRepository repository.SupplierRepository, accessGuard AccessGuard) SupplierService {
+	return &supplierService{supplierRepo: supplierRepository, accessGuard: accessGuard}
+}
+
+func (s *supplierService) SaveSupplier(ctx context.Context, request *request.SupplierAddRequest) (uuid.UUID, error) {
+	_, err := s.accessGuard.Merchant(ctx, request.MerchantID, model.RoleOwner, model.RoleManager)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	res, err := s.supplierRepo.Save(
+		ctx, model.Supplier{
+			MerchantID:  request.MerchantID,
+			Name:        request.Name,
+			ContactName: request.ContactName,
+			Email:       request.Email,
+			PhoneNumber: request.PhoneNumber,
+			Address:     request.Address,
+			Note:        request.Note,
+		},
+	)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return res, nil
+}
+
+func (s *supplierService) UpdateSupplier(ctx context.Context, request *request.SupplierUpdateRequest) (
+	uuid.UUID, error,
+) {
+	supplier, err := s.getSupplier(ctx, request.ID, model.RoleOwner, model.RoleManager)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	res, err := s.supplierRepo.Save(
+		ctx, model.Supplier{
+			ID:          supplier.ID,
+			MerchantID:  supplier.MerchantID,
+			Name:        request.Name,
+			ContactName: request.ContactName,
+			Email:       request.Email,
+			PhoneNumber: request.PhoneNumber,
+			Address:     request.Address,
+			Note:        request.Note,
+			Audit: model.Audit{
+				CreatedAt: supplier.CreatedAt,
+			},
+		},
+	)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return res, nil
+}
+
+func (s *supplierService) DeleteSupplier(ctx context.Context, supplierId uuid.UUID) error {
+	supplier, err := s.getSupplier(ctx, supplierId, model.RoleOwner, model.RoleManager)
+	if err != nil {
+		return err
+	}
+
+	err = s.supplierRepo.Delete(ctx, &supplier)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *supplierService) RestoreSupplier(ctx context.Context, supplierId uuid.UUID) error {
+	supplier, err := s.supplierRepo.Get(ctx, query.Where(query.Eq("id", supplierId)).WithDeleted())
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return &custom_error.NotFoundError{Message: "supplier not found"}
+		}
+
+		return err
+	}
+
+	_, err = s.accessGuard.IncludeDeleted().Merchant(ctx, supplier.MerchantID, model.RoleOwner, model.RoleManager)
+	if err != nil {
+		return err
+	}
+
+	if !supplier.DeletedAt.Valid {
+		return &custom_error.ConflictError{Message: "supplier isn't deleted"}
+	}
+
+	err = s.supplierRepo.Restore(ctx, supplier.ID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return &custom_error.ConflictError{Message: "supplier isn't deleted"}
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (s *supplierService) GetByParam(ctx context.Context, spec query.Spec) (*response.SupplierResponse, error) {
+	supplier, err := s.supplierRepo.Get(ctx, spec)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, &custom_error.NotFoundError{Message: "supplier not found"}
+		}
+
+		return nil, err
+	}
+
+	_, err = s.accessGuard.Merchant(ctx, supplier.MerchantID, model.RoleOwner, model.RoleManager, model.RoleCashier)
+	if err != nil {
+		return nil, err
+	}
+
+	response := supplierResponse(supplier)
+
+	return &response, nil
+}
+
+func (s *supplierService) Fetch(ctx context.Context, criteria criteria.SupplierCriteria) (
+	*util.PaginationResponse, error,
+) {
+	merchantIds, all, err := s.accessGuard.MerchantIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := query.Where()
+	if !all {
+		spec = spec.And(query.In("merchant_id", merchantIds))
+	}
+	if criteria.MerchantID != "" {
+		merchantId, err := uuid.Parse(criteria.MerchantID)
+		if err != nil {
+			return nil, &custom_error.BadRequest{Message: "merchant_id must be an uuid", Field: "merchant_id"}
+		}
+
+		spec = spec.And(query.Eq("merchant_id", merchantId))
+	}
+	if criteria.Name != "" {
+		spec = spec.And(query.Contains("name", criteria.Name))
+	}
+
+	spec, err = includeDeleted(ctx, spec, criteria.IncludeDeleted)
+	if err != nil {
+		return nil, err
+	}
+
+	spec, err = paginate(spec, &criteria.Pagination, "name", "created_at")
+	if err != nil {
+		return nil, err
+	}
+
+	res, rowCount, err := s.supplierRepo.Fetch(ctx, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	var responseData []response.SupplierResponse
+	for _, val := range res {
+		responseData = append(responseData, supplierResponse(val))
+	}
+
+	resPagination := util.BuildPagination(criteria.Pagination, responseData, rowCount)
+
+	return &resPagination, nil
+}
+
+// getSupplier returns the supplier after checking the caller holds one of the roles in its merchant.
+func (s *supplierService) getSupplier(ctx context.Context, supplierId uuid.UUID, roles ...string) (
+	model.Supplier, error,
+) {
+	supplier, err := s.supplierRepo.Get(ctx, query.Where(query.Eq("id", supplierId)))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return supplier, &custom_error.NotFoundError{Message: "supplier not found"}
+		}
+
+		return supplier, err
+	}
+
+	_, err = s.accessGuard.Merchant(ctx, supplier.MerchantID, roles...)
+	if err != nil {
+		return supplier, err
+	}
+
+	return supplier, nil
+}
+
+func supplierResponse(supplier model.Supplier) response.SupplierResponse {
+	data := response.SupplierResponse{
+		ID:          supplier.ID,
+		MerchantID:  supplier.MerchantID,
+		Name:        supplier.Name,
+		ContactName: supplier.ContactName,
+		Email:       supplier.Email,
+		PhoneNumber: supplier.PhoneNumber,
+		Address:     supplier.Address,
+		Note:        supplier.Note,
+		CreatedAt:   supplier.CreatedAt.Time,
+	}
+	if supplier.DeletedAt.Valid {
+		deletedAt := supplier.DeletedAt.Time
+		data.DeletedAt = &deletedAt
+	}
+
+	return data
+}
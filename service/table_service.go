@@ -0,0 +1,364 @@
+package service
+
+import (
+	"context"
+	"github.com/google/uuid"
+	custom_error "github.com/rehandwi03/test-case-backend-majoo/internal/error"
+	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"github.com/rehandwi03/test-case-backend-majoo/query"
+	"github.com/rehandwi03/test-case-backend-majoo/repository"
+	"github.com/rehandwi03/test-case-backend-majoo/request"
+	"github.com/rehandwi03/test-case-backend-majoo/response"
+	"gorm.io/gorm"
+)
+
+type TableService interface {
+	SaveArea(ctx context.Context, request *request.AreaAddRequest) (uuid.UUID, error)
+	UpdateArea(ctx context.Context, request *request.AreaUpdateRequest) (uuid.UUID, error)
+	// DeleteArea deletes an area without tables, its tables have to be moved or deleted first.
+	DeleteArea(ctx context.Context, outletId uuid.UUID, areaId uuid.UUID) error
+	// FetchAreas returns the areas of the outlet with their tables, by sort order.
+	FetchAreas(ctx context.Context, outletId uuid.UUID) ([]response.AreaResponse, error)
+	SaveTable(ctx context.Context, request *request.TableAddRequest) (uuid.UUID, error)
+	UpdateTable(ctx context.Context, request *request.TableUpdateRequest) (uuid.UUID, error)
+	// DeleteTable deletes a table no open or parked order is at.
+	DeleteTable(ctx context.Context, outletId uuid.UUID, tableId uuid.UUID) error
+	// FetchTables returns every table of the outlet, by sort order, with the open and parked orders at it.
+	FetchTables(ctx context.Context, outletId uuid.UUID) ([]response.TableResponse, error)
+}
+
+type tableService struct {
+	areaRepo    repository.AreaRepository
+	tableRepo   repository.TableRepository
+	orderRepo   repository.OrderRepository
+	accessGuard AccessGuard
+}
+
+func NewTableService(
+	areaRepository repository.AreaRepository, tableRepository repository.TableRepository,
+	orderRepository repository.OrderRepository, accessGuard AccessGuard,
+) TableService {
+	return &tableService{
+		areaRepo: areaRepository, tableRepo: tableRepository, orderRepo: orderRepository, accessGuard: accessGuard,
+	}
+}
+
+func (t *tableService) SaveArea(ctx context.Context, request *request.AreaAddRequest) (uuid.UUID, error) {
+	_, err := t.accessGuard.Outlet(ctx, request.OutletID, model.RoleOwner, model.RoleManager)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	res, err := t.areaRepo.Save(
+		ctx, model.Area{
+			OutletID:  request.OutletID,
+			Name:      request.Name,
+			SortOrder: request.SortOrder,
+		},
+	)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return res, nil
+}
+
+func (t *tableService) UpdateArea(ctx context.Context, request *request.AreaUpdateRequest) (uuid.UUID, error) {
+	_, err := t.accessGuard.Outlet(ctx, request.OutletID, model.RoleOwner, model.RoleManager)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	area, err := t.getArea(ctx, request.OutletID, request.ID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	res, err := t.areaRepo.Save(
+		ctx, model.Area{
+			ID:        area.ID,
+			OutletID:  area.OutletID,
+			Name:      request.Name,
+			SortOrder: request.SortOrder,
+			Audit: model.Audit{
+				CreatedAt: area.CreatedAt,
+			},
+		},
+	)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return res, nil
+}
+
+func (t *tableService) DeleteArea(ctx context.Context, outletId uuid.UUID, areaId uuid.UUID) error {
+	_, err := t.accessGuard.Outlet(ctx, outletId, model.RoleOwner, model.RoleManager)
+	if err != nil {
+		return err
+	}
+
+	area, err := t.getArea(ctx, outletId, areaId)
+	if err != nil {
+		return err
+	}
+
+	tables, err := t.tableRepo.Count(ctx, query.Where(query.Eq("area_id", area.ID)))
+	if err != nil {
+		return err
+	}
+
+	if tables > 0 {
+		return &custom_error.ConflictError{Message: "move or delete the tables of the area first"}
+	}
+
+	return t.areaRepo.Delete(ctx, &area)
+}
+
+func (t *tableService) FetchAreas(ctx context.Context, outletId uuid.UUID) ([]response.AreaResponse, error) {
+	_, err := t.accessGuard.Outlet(ctx, outletId, model.RoleOwner, model.RoleManager, model.RoleCashier)
+	if err != nil {
+		return nil, err
+	}
+
+	areas, err := t.areaRepo.List(
+		ctx, query.Where(query.Eq("outlet_id", outletId)).OrderBy(
+			query.Sort{Field: "sort_order"}, query.Sort{Field: "name"},
+		),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	orders, err := t.tableOrders(ctx, outletId)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := []response.AreaResponse{}
+	for _, area := range areas {
+		data := response.AreaResponse{
+			ID:        area.ID,
+			OutletID:  area.OutletID,
+			Name:      area.Name,
+			SortOrder: area.SortOrder,
+			Tables:    []response.TableResponse{},
+			CreatedAt: area.CreatedAt.Time,
+		}
+		for _, table := range area.Tables {
+			data.Tables = append(data.Tables, tableResponse(table, orders[table.ID]))
+		}
+
+		responses = append(responses, data)
+	}
+
+	return responses, nil
+}
+
+func (t *tableService) SaveTable(ctx context.Context, request *request.TableAddRequest) (uuid.UUID, error) {
+	_, err := t.accessGuard.Outlet(ctx, request.OutletID, model.RoleOwner, model.RoleManager)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	err = t.checkArea(ctx, request.OutletID, request.AreaID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	res, err := t.tableRepo.Save(
+		ctx, model.Table{
+			OutletID:  request.OutletID,
+			AreaID:    request.AreaID,
+			Name:      request.Name,
+			Seats:     request.Seats,
+			SortOrder: request.SortOrder,
+		},
+	)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return res, nil
+}
+
+func (t *tableService) UpdateTable(ctx context.Context, request *request.TableUpdateRequest) (uuid.UUID, error) {
+	_, err := t.accessGuard.Outlet(ctx, request.OutletID, model.RoleOwner, model.RoleManager)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	table, err := t.getTable(ctx, request.OutletID, request.ID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	err = t.checkArea(ctx, request.OutletID, request.AreaID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	res, err := t.tableRepo.Save(
+		ctx, model.Table{
+			ID:        table.ID,
+			OutletID:  table.OutletID,
+			AreaID:    request.AreaID,
+			Name:      request.Name,
+			Seats:     request.Seats,
+			SortOrder: request.SortOrder,
+			Audit: model.Audit{
+				CreatedAt: table.CreatedAt,
+			},
+		},
+	)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return res, nil
+}
+
+func (t *tableService) DeleteTable(ctx context.Context, outletId uuid.UUID, tableId uuid.UUID) error {
+	_, err := t.accessGuard.Outlet(ctx, outletId, model.RoleOwner, model.RoleManager)
+	if err != nil {
+		return err
+	}
+
+	table, err := t.getTable(ctx, outletId, tableId)
+	if err != nil {
+		return err
+	}
+
+	orders, err := t.orderRepo.Count(
+		ctx, query.Where(
+			query.Eq("table_id", table.ID), query.In("status", []string{model.OrderOpen, model.OrderParked}),
+		),
+	)
+	if err != nil {
+		return err
+	}
+
+	if orders > 0 {
+		return &custom_error.ConflictError{Message: "close or move the orders at the table first"}
+	}
+
+	return t.tableRepo.Delete(ctx, &table)
+}
+
+func (t *tableService) FetchTables(ctx context.Context, outletId uuid.UUID) ([]response.TableResponse, error) {
+	_, err := t.accessGuard.Outlet(ctx, outletId, model.RoleOwner, model.RoleManager, model.RoleCashier)
+	if err != nil {
+		return nil, err
+	}
+
+	tables, err := t.tableRepo.List(
+		ctx, query.Where(query.Eq("outlet_id", outletId)).OrderBy(
+			query.Sort{Field: "sort_order"}, query.Sort{Field: "name"},
+		),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	orders, err := t.tableOrders(ctx, outletId)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := []response.TableResponse{}
+	for _, table := range tables {
+		responses = append(responses, tableResponse(table, orders[table.ID]))
+	}
+
+	return responses, nil
+}
+
+func (t *tableService) getArea(ctx context.Context, outletId uuid.UUID, areaId uuid.UUID) (model.Area, error) {
+	area, err := t.areaRepo.Get(ctx, query.Where(query.Eq("id", areaId), query.Eq("outlet_id", outletId)))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return area, &custom_error.NotFoundError{Message: "area not found"}
+		}
+
+		return area, err
+	}
+
+	return area, nil
+}
+
+func (t *tableService) getTable(ctx context.Context, outletId uuid.UUID, tableId uuid.UUID) (model.Table, error) {
+	table, err := t.tableRepo.Get(ctx, query.Where(query.Eq("id", tableId), query.Eq("outlet_id", outletId)))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return table, &custom_error.NotFoundError{Message: "table not found"}
+		}
+
+		return table, err
+	}
+
+	return table, nil
+}
+
+// checkArea makes sure the area of a table, if any, is one of the same outlet.
+func (t *tableService) checkArea(ctx context.Context, outletId uuid.UUID, areaId *uuid.UUID) error {
+	if areaId == nil {
+		return nil
+	}
+
+	count, err := t.areaRepo.Count(ctx, query.Where(query.Eq("id", *areaId), query.Eq("outlet_id", outletId)))
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return &custom_error.BadRequest{Message: "area not found", Field: "area_id"}
+	}
+
+	return nil
+}
+
+// tableOrders returns the open and parked orders of the outlet by the table they are at.
+func (t *tableService) tableOrders(
+	ctx context.Context, outletId uuid.UUID,
+) (map[uuid.UUID][]response.TableOrderResponse, error) {
+	orders, err := t.orderRepo.List(
+		ctx, query.Where(
+			query.Eq("outlet_id", outletId), query.NotNull("table_id"),
+			query.In("status", []string{model.OrderOpen, model.OrderParked}),
+		).OrderBy(query.Sort{Field: "created_at"}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	tableOrders := map[uuid.UUID][]response.TableOrderResponse{}
+	for _, order := range orders {
+		tableOrders[*order.TableID] = append(
+			tableOrders[*order.TableID], response.TableOrderResponse{
+				ID:     order.ID,
+				Name:   order.Name,
+				Guests: order.Guests,
+				Status: order.Status,
+			},
+		)
+	}
+
+	return tableOrders, nil
+}
+
+func tableResponse(table model.Table, orders []response.TableOrderResponse) response.TableResponse {
+	if orders == nil {
+		orders = []response.TableOrderResponse{}
+	}
+
+	return response.TableResponse{
+		ID:        table.ID,
+		OutletID:  table.OutletID,
+		AreaID:    table.AreaID,
+		Name:      table.Name,
+		Seats:     table.Seats,
+		SortOrder: table.SortOrder,
+		Orders:    orders,
+		CreatedAt: table.CreatedAt.Time,
+	}
+}
@@ -0,0 +1,699 @@
+package service
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/rehandwi03/test-case-backend-majoo/criteria"
+	"github.com/rehandwi03/test-case-backend-majoo/gateway"
+	custom_error "github.com/rehandwi03/test-case-backend-majoo/internal/error"
+	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"github.com/rehandwi03/test-case-backend-majoo/query"
+	"github.com/rehandwi03/test-case-backend-majoo/repository"
+	"github.com/rehandwi03/test-case-backend-majoo/request"
+	"github.com/rehandwi03/test-case-backend-majoo/response"
+	"github.com/rehandwi03/test-case-backend-majoo/util"
+	"gorm.io/gorm"
+	"log"
+	"sort"
+	"strings"
+)
+
+type TransactionService interface {
+	// Checkout sells the items of the cart, or of the order it checks out, paid for by its payments, and answers the
+	// change to give back. The order is closed by the sale.
+	Checkout(ctx context.Context, request *request.TransactionCheckoutRequest) (*response.CheckoutResponse, error)
+	GetByParam(ctx context.Context, spec query.Spec) (*response.TransactionResponse, error)
+	Fetch(ctx context.Context, transactionCriteria criteria.TransactionCriteria) (*util.PaginationResponse, error)
+}
+
+type transactionService struct {
+	transactionRepo repository.TransactionRepository
+	outletRepo      repository.OutletRepository
+	productRepo     repository.ProductRepository
+	orderRepo       repository.OrderRepository
+	charges         charges
+	accessGuard     AccessGuard
+}
+
+func NewTransactionService(
+	transactionRepository repository.TransactionRepository, outletRepository repository.OutletRepository,
+	productRepository repository.ProductRepository, orderRepository repository.OrderRepository,
+	paymentRepository repository.TransactionPaymentRepository, providers gateway.Providers, accessGuard AccessGuard,
+) TransactionService {
+	return &transactionService{
+		transactionRepo: transactionRepository, outletRepo: outletRepository, productRepo: productRepository,
+		orderRepo: orderRepository, charges: charges{providers: providers, paymentRepo: paymentRepository},
+		accessGuard: accessGuard,
+	}
+}
+
+func (t *transactionService) Checkout(ctx context.Context, request *request.TransactionCheckoutRequest) (
+	*response.CheckoutResponse, error,
+) {
+	userId, ok := ctx.Value("user_id").(uuid.UUID)
+	if !ok {
+		return nil, &custom_error.NotFoundError{Message: "user id not found"}
+	}
+
+	roles := []string{model.RoleOwner, model.RoleManager, model.RoleCashier}
+
+	outlet, err := t.accessGuard.Outlet(ctx, request.OutletID, roles...)
+	if err != nil {
+		return nil, err
+	}
+
+	merchant, err := t.accessGuard.Merchant(ctx, outlet.MerchantID, roles...)
+	if err != nil {
+		return nil, err
+	}
+
+	var order *model.Order
+	if request.OrderID != nil {
+		if len(request.Items) > 0 {
+			return nil, &custom_error.BadRequest{Message: "an order is checked out with its items", Field: "items"}
+		}
+
+		stored, err := t.orderRepo.Get(
+			ctx, query.Where(query.Eq("id", *request.OrderID), query.Eq("outlet_id", request.OutletID)),
+		)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil, &custom_error.NotFoundError{Message: "order not found"}
+			}
+
+			return nil, err
+		}
+
+		if !stored.IsActive() {
+			return nil, &custom_error.ConflictError{Message: "the order is " + stored.Status}
+		}
+
+		if len(stored.Items) == 0 {
+			return nil, &custom_error.BadRequest{Message: "the order has no items"}
+		}
+
+		order = &stored
+		request.Items = orderItemRequests(stored.Items)
+	}
+
+	// the same product may be scanned more than once, so merge the quantities while keeping the cart order
+	items := mergeItems(request.Items)
+
+	transaction := model.Transaction{
+		OutletID: request.OutletID,
+		UserID:   userId,
+		OrderID:  request.OrderID,
+		Status:   model.TransactionCompleted,
+	}
+
+	var needs *stockNeeds
+	transaction.Items, needs, err = cart{productRepo: t.productRepo}.price(ctx, request.OutletID, items)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range transaction.Items {
+		transaction.TotalQuantity += item.Quantity
+		transaction.Total, err = transaction.Total.Add(item.Subtotal)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = needs.check()
+	if err != nil {
+		return nil, err
+	}
+
+	err = settle(&transaction, merchant, request.Payments)
+	if err != nil {
+		return nil, err
+	}
+
+	// the charges are only authorized until the sale is stored, the ones created are cancelled again when a later
+	// one is declined or the sale fails to be stored
+	for i, payment := range transaction.Payments {
+		if payment.Provider == "" {
+			continue
+		}
+
+		err = t.charges.create(ctx, &transaction.Payments[i])
+		if err != nil {
+			t.charges.release(ctx, transaction.Payments[:i])
+			return nil, err
+		}
+	}
+
+	// a payment still pending at its provider hasn't paid yet, the sale is pending until it does
+	for _, payment := range transaction.Payments {
+		if payment.Status == model.PaymentPending {
+			transaction.Status = model.TransactionPending
+			transaction.Paid.Amount -= payment.Amount.Amount
+		}
+	}
+
+	res, err := t.transactionRepo.Checkout(ctx, transaction, order)
+	if err != nil {
+		t.charges.release(ctx, transaction.Payments)
+
+		switch err {
+		case repository.ErrInsufficientStock:
+			return nil, &custom_error.BadRequest{Message: err.Error()}
+		case repository.ErrOrderChanged:
+			return nil, &custom_error.ConflictError{Message: err.Error()}
+		}
+
+		return nil, err
+	}
+
+	stored, err := t.transactionRepo.Get(ctx, query.Where(query.Eq("id", res)))
+	if err != nil {
+		return nil, err
+	}
+
+	checkout := &response.CheckoutResponse{
+		TransactionID: res,
+		Status:        stored.Status,
+		Total:         stored.Total,
+		Rounding:      stored.Rounding,
+		Paid:          stored.Paid,
+		Change:        stored.Change,
+		Payments:      []response.TransactionPaymentResponse{},
+	}
+	for _, payment := range stored.Payments {
+		// the sale is made, a capture failing leaves the payment authorized for a refresh to capture it again
+		if payment.Status == model.PaymentAuthorized {
+			captured, err := t.charges.apply(ctx, payment, gateway.Status(payment.Status))
+			if err != nil {
+				log.Printf("error capturing payment %s: %v", payment.ID, err)
+			} else {
+				payment = captured
+			}
+		}
+
+		checkout.Payments = append(checkout.Payments, transactionPaymentResponse(payment))
+	}
+
+	return checkout, nil
+}
+
+func (t *transactionService) GetByParam(ctx context.Context, spec query.Spec) (
+	*response.TransactionResponse, error,
+) {
+	transactionData, err := t.transactionRepo.Get(ctx, spec)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, &custom_error.NotFoundError{Message: "transaction not found"}
+		}
+
+		return nil, err
+	}
+
+	_, err = t.accessGuard.Outlet(
+		ctx, transactionData.OutletID, model.RoleOwner, model.RoleManager, model.RoleCashier,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	response := toTransactionResponse(transactionData)
+
+	return &response, nil
+}
+
+func (t *transactionService) Fetch(ctx context.Context, criteria criteria.TransactionCriteria) (
+	*util.PaginationResponse, error,
+) {
+	outletId, err := uuid.Parse(criteria.OutletID)
+	if err != nil {
+		return nil, &custom_error.BadRequest{Message: "outlet id is invalid"}
+	}
+
+	_, err = t.accessGuard.Outlet(ctx, outletId, model.RoleOwner, model.RoleManager, model.RoleCashier)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := query.Where(query.Eq("outlet_id", outletId))
+	if criteria.Status != "" {
+		spec = spec.And(query.Eq("status", criteria.Status))
+	}
+
+	spec, err = paginate(spec, &criteria.Pagination, "status", "total_quantity", "total_amount", "created_at")
+	if err != nil {
+		return nil, err
+	}
+
+	res, rowCount, err := t.transactionRepo.Fetch(ctx, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	var responseData []response.TransactionResponse
+	for _, val := range res {
+		responseData = append(responseData, toTransactionResponse(val))
+	}
+
+	resPagination := util.BuildPagination(criteria.Pagination, responseData, rowCount)
+
+	return &resPagination, nil
+}
+
+// mergeItems merges the items of the same product, or variant, with the same modifiers and bundle options into the
+// first one of them.
+func mergeItems(items []request.TransactionItemRequest) []request.TransactionItemRequest {
+	var merged []request.TransactionItemRequest
+	lines := map[cartLine]int{}
+	for _, item := range items {
+		line := cartLine{
+			productId: item.ProductID, modifiers: choicesKey(item.ModifierIDs), options: choicesKey(item.OptionIDs),
+		}
+		if item.VariantID != nil {
+			line.variantId = *item.VariantID
+		}
+
+		if i, ok := lines[line]; ok {
+			merged[i].Quantity += item.Quantity
+			continue
+		}
+
+		lines[line] = len(merged)
+		merged = append(merged, item)
+	}
+
+	return merged
+}
+
+// orderItemRequests answers the items of an order as the items of a checkout selling them.
+func orderItemRequests(items []model.OrderItem) []request.TransactionItemRequest {
+	var requests []request.TransactionItemRequest
+	for _, item := range items {
+		itemRequest := request.TransactionItemRequest{
+			ProductID: item.ProductID, VariantID: item.VariantID, Quantity: item.Quantity,
+		}
+		for _, modifier := range item.Modifiers {
+			itemRequest.ModifierIDs = append(itemRequest.ModifierIDs, modifier.ModifierID)
+		}
+		for _, option := range item.Options {
+			itemRequest.OptionIDs = append(itemRequest.OptionIDs, option.OptionID)
+		}
+
+		requests = append(requests, itemRequest)
+	}
+
+	return requests
+}
+
+// cart prices items of a sale, or of an order, by the products of an outlet as they are now.
+type cart struct {
+	productRepo repository.ProductRepository
+}
+
+// price answers an item for every one of the items, at the price of its product, or variant, its modifiers and its
+// bundle options add to, and the stock they need.
+func (c cart) price(ctx context.Context, outletId uuid.UUID, items []request.TransactionItemRequest) (
+	[]model.TransactionItem, *stockNeeds, error,
+) {
+	var productIds []uuid.UUID
+	for _, item := range items {
+		productIds = append(productIds, item.ProductID)
+	}
+
+	productParams := query.Where(query.In("id", productIds), query.Eq("outlet_id", outletId))
+
+	products, err := c.productRepo.List(ctx, productParams)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	productMap := map[uuid.UUID]model.Product{}
+	var componentIds []uuid.UUID
+	for _, product := range products {
+		productMap[product.ID] = product
+
+		for _, slot := range product.BundleSlots {
+			for _, option := range slot.Options {
+				componentIds = append(componentIds, option.ProductID)
+			}
+		}
+	}
+
+	// the products filling the slots of the bundles are sold, and their stock checked, in place of the bundles
+	componentMap := map[uuid.UUID]model.Product{}
+	if len(componentIds) > 0 {
+		components, err := c.productRepo.List(
+			ctx, query.Where(query.In("id", componentIds), query.Eq("outlet_id", outletId)),
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, component := range components {
+			componentMap[component.ID] = component
+		}
+	}
+
+	needs := newStockNeeds()
+	var priced []model.TransactionItem
+	for _, line := range items {
+		product, ok := productMap[line.ProductID]
+		if !ok {
+			return nil, nil, &custom_error.NotFoundError{Message: "product " + line.ProductID.String() + " not found"}
+		}
+
+		if product.IsIngredient {
+			return nil, nil, &custom_error.BadRequest{
+				Message: "product " + product.Name + " is an ingredient and isn't sold on its own",
+			}
+		}
+
+		item := model.TransactionItem{
+			ProductID: product.ID,
+			Name:      product.Name,
+			Price:     product.Price,
+			Quantity:  line.Quantity,
+		}
+		var variant *model.ProductVariant
+
+		// a product with variants is sold as one of them, which has its own stock and may have its own price
+		if line.VariantID != nil || len(product.Variants) > 0 {
+			var variantId uuid.UUID
+			if line.VariantID != nil {
+				variantId = *line.VariantID
+			}
+
+			chosen, ok := findVariant(product, variantId)
+			if !ok {
+				return nil, nil, &custom_error.BadRequest{
+					Message: "choose a variant of product " + product.Name, Field: "variant_id",
+				}
+			}
+
+			variant = &chosen
+			item.VariantID = &chosen.ID
+			item.VariantName = chosen.Label()
+			item.Price = chosen.EffectivePrice(product)
+		}
+
+		if product.IsBundle {
+			item.Components, err = chooseComponents(product, line.OptionIDs, componentMap)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			for _, component := range item.Components {
+				item.Price, err = item.Price.Add(component.PriceDelta)
+				if err != nil {
+					return nil, nil, err
+				}
+
+				componentProduct := componentMap[component.ProductID]
+				var componentVariant *model.ProductVariant
+				if component.VariantID != nil {
+					chosen, _ := findVariant(componentProduct, *component.VariantID)
+					componentVariant = &chosen
+				}
+
+				err = needs.add(componentProduct, componentVariant, component.Quantity*item.Quantity)
+				if err != nil {
+					return nil, nil, err
+				}
+			}
+		} else {
+			err = needs.add(product, variant, item.Quantity)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+
+		modifiers, err := selectModifiers(product, line.ModifierIDs)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, modifier := range modifiers {
+			item.Price, err = item.Price.Add(modifier.PriceDelta)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			item.Modifiers = append(
+				item.Modifiers, model.TransactionItemModifier{
+					ModifierID: modifier.ID,
+					Name:       modifier.Name,
+					PriceDelta: modifier.PriceDelta,
+				},
+			)
+		}
+
+		item.Subtotal = item.Price.Mul(item.Quantity)
+		priced = append(priced, item)
+	}
+
+	return priced, needs, nil
+}
+
+// cartLine is a product, or a variant of it, with the same modifiers and bundle options in the cart.
+type cartLine struct {
+	productId uuid.UUID
+	variantId uuid.UUID
+	modifiers string
+	options   string
+}
+
+// choicesKey identifies a choice of modifiers, or of bundle options, regardless of their order.
+func choicesKey(ids []uuid.UUID) string {
+	var keys []string
+	for _, id := range ids {
+		keys = append(keys, id.String())
+	}
+	sort.Strings(keys)
+
+	return strings.Join(keys, ",")
+}
+
+// chooseComponents fills every slot of the bundle with the option chosen for it, or the only option it has. The
+// products of the options are looked up in products, an option whose product or variant is gone can't be sold.
+func chooseComponents(
+	bundle model.Product, optionIds []uuid.UUID, products map[uuid.UUID]model.Product,
+) ([]model.TransactionItemComponent, error) {
+	if len(bundle.BundleSlots) == 0 {
+		return nil, &custom_error.BadRequest{Message: "bundle " + bundle.Name + " has no slots"}
+	}
+
+	chosen := map[uuid.UUID]bool{}
+	for _, optionId := range optionIds {
+		chosen[optionId] = true
+	}
+
+	var components []model.TransactionItemComponent
+	for _, slot := range bundle.BundleSlots {
+		var picked *model.BundleOption
+		for i, option := range slot.Options {
+			if !chosen[option.ID] {
+				continue
+			}
+			if picked != nil {
+				return nil, &custom_error.BadRequest{
+					Message: "choose a single option of " + slot.Name + " of bundle " + bundle.Name,
+					Field:   "option_ids",
+				}
+			}
+
+			picked = &slot.Options[i]
+			delete(chosen, option.ID)
+		}
+
+		if picked == nil {
+			if len(slot.Options) != 1 {
+				return nil, &custom_error.BadRequest{
+					Message: "choose an option of " + slot.Name + " of bundle " + bundle.Name, Field: "option_ids",
+				}
+			}
+
+			picked = &slot.Options[0]
+		}
+
+		unavailable := &custom_error.BadRequest{
+			Message: "an option of " + slot.Name + " of bundle " + bundle.Name + " isn't sold anymore",
+			Field:   "option_ids",
+		}
+		product, ok := products[picked.ProductID]
+		if !ok {
+			return nil, unavailable
+		}
+
+		component := model.TransactionItemComponent{
+			OptionID:   picked.ID,
+			SlotName:   slot.Name,
+			ProductID:  product.ID,
+			VariantID:  picked.VariantID,
+			Name:       product.Name,
+			Quantity:   slot.Quantity,
+			PriceDelta: picked.PriceDelta,
+		}
+		if picked.VariantID != nil {
+			variant, ok := findVariant(product, *picked.VariantID)
+			if !ok {
+				return nil, unavailable
+			}
+
+			component.VariantName = variant.Label()
+		}
+
+		components = append(components, component)
+	}
+
+	for optionId := range chosen {
+		return nil, &custom_error.BadRequest{
+			Message: "option " + optionId.String() + " isn't an option of bundle " + bundle.Name, Field: "option_ids",
+		}
+	}
+
+	return components, nil
+}
+
+// stockNeeds adds up what a cart takes out of every stock it sells from, of a product, a variant or an ingredient,
+// so the lines sharing one are checked together.
+type stockNeeds struct {
+	items      []stockItem
+	names      map[stockItem]string
+	stock      map[stockItem]int64
+	quantities map[stockItem]int64
+}
+
+func newStockNeeds() *stockNeeds {
+	return &stockNeeds{
+		names: map[stockItem]string{}, stock: map[stockItem]int64{}, quantities: map[stockItem]int64{},
+	}
+}
+
+// add takes quantity units of the product, or of its variant, out of its stock, or out of the stock of its
+// ingredients when it has a recipe.
+func (s *stockNeeds) add(product model.Product, variant *model.ProductVariant, quantity int64) error {
+	for _, line := range product.Recipe {
+		if line.Ingredient == nil {
+			return &custom_error.BadRequest{Message: "an ingredient of product " + product.Name + " is deleted"}
+		}
+
+		s.take(
+			stockItemOf(line.IngredientID, nil), "ingredient "+line.Ingredient.Name, line.Ingredient.Stock,
+			line.Quantity*quantity,
+		)
+	}
+	if len(product.Recipe) > 0 {
+		return nil
+	}
+
+	if variant != nil {
+		s.take(stockItemOf(product.ID, &variant.ID), "product "+product.Name, variant.Stock, quantity)
+		return nil
+	}
+
+	s.take(stockItemOf(product.ID, nil), "product "+product.Name, product.Stock, quantity)
+
+	return nil
+}
+
+func (s *stockNeeds) take(item stockItem, name string, stock int64, quantity int64) {
+	if _, ok := s.quantities[item]; !ok {
+		s.items = append(s.items, item)
+		s.names[item] = name
+		s.stock[item] = stock
+	}
+
+	s.quantities[item] += quantity
+}
+
+// check fails on the first stock the cart takes more out of than is left.
+func (s *stockNeeds) check() error {
+	for _, item := range s.items {
+		if s.quantities[item] > s.stock[item] {
+			return &custom_error.BadRequest{Message: "insufficient stock for " + s.names[item]}
+		}
+	}
+
+	return nil
+}
+
+func findVariant(product model.Product, variantId uuid.UUID) (model.ProductVariant, bool) {
+	for _, variant := range product.Variants {
+		if variant.ID == variantId {
+			return variant, true
+		}
+	}
+
+	return model.ProductVariant{}, false
+}
+
+func toTransactionResponse(transaction model.Transaction) response.TransactionResponse {
+	var data response.TransactionResponse
+
+	data.ID = transaction.ID
+	data.OutletID = transaction.OutletID
+	data.UserID = transaction.UserID
+	data.OrderID = transaction.OrderID
+	data.Status = transaction.Status
+	data.TotalQuantity = transaction.TotalQuantity
+	data.Total = transaction.Total
+	data.Rounding = transaction.Rounding
+	data.Paid = transaction.Paid
+	data.Change = transaction.Change
+	data.CreatedAt = transaction.CreatedAt.Time
+	for _, item := range transaction.Items {
+		modifiers := []response.TransactionItemModifierResponse{}
+		for _, modifier := range item.Modifiers {
+			modifiers = append(
+				modifiers, response.TransactionItemModifierResponse{
+					ModifierID: modifier.ModifierID,
+					Name:       modifier.Name,
+					PriceDelta: modifier.PriceDelta,
+				},
+			)
+		}
+
+		var components []response.TransactionItemComponentResponse
+		for _, component := range item.Components {
+			components = append(
+				components, response.TransactionItemComponentResponse{
+					OptionID:    component.OptionID,
+					SlotName:    component.SlotName,
+					ProductID:   component.ProductID,
+					VariantID:   component.VariantID,
+					Name:        component.Name,
+					VariantName: component.VariantName,
+					Quantity:    component.Quantity,
+					PriceDelta:  component.PriceDelta,
+				},
+			)
+		}
+
+		data.Items = append(
+			data.Items, response.TransactionItemResponse{
+				ID:               item.ID,
+				ProductID:        item.ProductID,
+				VariantID:        item.VariantID,
+				Name:             item.Name,
+				VariantName:      item.VariantName,
+				Price:            item.Price,
+				Quantity:         item.Quantity,
+				Subtotal:         item.Subtotal,
+				Cost:             optionalMoney(item.Cost),
+				ReturnedQuantity: item.ReturnedQuantity,
+				Modifiers:        modifiers,
+				Components:       components,
+			},
+		)
+	}
+
+	data.Payments = []response.TransactionPaymentResponse{}
+	for _, payment := range transaction.Payments {
+		data.Payments = append(data.Payments, transactionPaymentResponse(payment))
+	}
+
+	data.Refunds = []response.RefundResponse{}
+	for _, refund := range transaction.Refunds {
+		data.Refunds = append(data.Refunds, toRefundResponse(refund))
+	}
+
+	return data
+}
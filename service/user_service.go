@@ -5,7 +5,9 @@ import (
 	"github.com/google/uuid"
 	"github.com/rehandwi03/test-case-backend-majoo/criteria"
 	custom_error "github.com/rehandwi03/test-case-backend-majoo/internal/error"
+	"github.com/rehandwi03/test-case-backend-majoo/internal/helper"
 	"github.com/rehandwi03/test-case-backend-majoo/model"
+	"github.com/rehandwi03/test-case-backend-majoo/query"
 	"github.com/rehandwi03/test-case-backend-majoo/repository"
 	"github.com/rehandwi03/test-case-backend-majoo/request"
 	"github.com/rehandwi03/test-case-backend-majoo/response"
@@ -16,32 +18,25 @@ import (
 type UserService interface {
 	SaveUser(ctx context.Context, request *request.UserAddRequest) (uuid.UUID, error)
 	UpdateUser(ctx context.Context, request *request.UserUpdateRequest) (uuid.UUID, error)
-	DeleteUser(ctx context.Context, params map[string]interface{}) error
-	GetByParam(ctx context.Context, params map[string]interface{}) (*response.UserResponse, error)
+	DeleteUser(ctx context.Context, spec query.Spec) error
+	GetByParam(ctx context.Context, spec query.Spec) (*response.UserResponse, error)
 	Fetch(ctx context.Context, userCriteria criteria.UserCriteria) (*util.PaginationResponse, error)
 }
 
 type userService struct {
-	userRepo repository.UserRepository
+	userRepo         repository.UserRepository
+	refreshTokenRepo repository.RefreshTokenRepository
 }
 
-func NewUserService(userRepository repository.UserRepository) UserService {
-	return &userService{userRepo: userRepository}
+func NewUserService(
+	userRepository repository.UserRepository, refreshTokenRepository repository.RefreshTokenRepository,
+) UserService {
+	return &userService{userRepo: userRepository, refreshTokenRepo: refreshTokenRepository}
 }
 
 func (u *userService) SaveUser(ctx context.Context, request *request.UserAddRequest) (uuid.UUID, error) {
-	param := map[string]interface{}{
-		"where": map[string]interface{}{
-			"or": map[string]interface{}{},
-			"default": map[string]interface{}{
-				"email = ?": request.Email,
-			},
-		},
-	}
-
-	_, err := u.userRepo.GetByParam(ctx, param)
-	if err == nil {
-		return uuid.Nil, &custom_error.BadRequest{Message: "email already exist"}
+	if request.Role != "" && !helper.IsAdmin(ctx) {
+		return uuid.Nil, &custom_error.ForbiddenError{Message: "only admin can assign a platform role"}
 	}
 
 	userModel := model.User{
@@ -50,11 +45,14 @@ func (u *userService) SaveUser(ctx context.Context, request *request.UserAddRequ
 		Email:       request.Email,
 		PhoneNumber: request.PhoneNumber,
 		Password:    request.Password,
+		Role:        request.Role,
 	}
 
 	if err := userModel.EncryptPassword(); err != nil {
 		return uuid.Nil, err
 	}
+
+	// the email is unique in the database, taking one already used fails the save with a conflict
 	res, err := u.userRepo.Save(
 		ctx, userModel,
 	)
@@ -66,25 +64,13 @@ func (u *userService) SaveUser(ctx context.Context, request *request.UserAddRequ
 }
 
 func (u *userService) UpdateUser(ctx context.Context, request *request.UserUpdateRequest) (uuid.UUID, error) {
-	param := map[string]interface{}{
-		"where": map[string]interface{}{
-			"or": map[string]interface{}{},
-			"default": map[string]interface{}{
-				"id = ?": request.ID,
-			},
-		},
-	}
+	param := query.Where(query.Eq("id", request.ID))
 
-	emailParam := map[string]interface{}{
-		"where": map[string]interface{}{
-			"or": map[string]interface{}{},
-			"default": map[string]interface{}{
-				"id = ?": request.ID,
-			},
-		},
+	if !canAccessUser(ctx, request.ID) {
+		return uuid.Nil, &custom_error.ForbiddenError{Message: "you can only update your own account"}
 	}
 
-	userData, err := u.userRepo.GetByParam(ctx, param)
+	userData, err := u.userRepo.Get(ctx, param)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return uuid.Nil, &custom_error.NotFoundError{Message: err.Error()}
@@ -93,11 +79,10 @@ func (u *userService) UpdateUser(ctx context.Context, request *request.UserUpdat
 		return uuid.Nil, err
 	}
 
-	if request.Email != userData.Email {
-		_, err = u.userRepo.GetByParam(ctx, emailParam)
-		if err == nil {
-			return uuid.Nil, &custom_error.BadRequest{Message: "email already exist"}
-		}
+	// the platform role can only be changed by an admin
+	role := userData.Role
+	if helper.IsAdmin(ctx) {
+		role = request.Role
 	}
 
 	userModel := model.User{
@@ -107,6 +92,10 @@ func (u *userService) UpdateUser(ctx context.Context, request *request.UserUpdat
 		Email:       request.Email,
 		Password:    request.Password,
 		PhoneNumber: request.PhoneNumber,
+		Role:        role,
+		Audit: model.Audit{
+			CreatedAt: userData.CreatedAt,
+		},
 	}
 	err = userModel.EncryptPassword()
 	if err != nil {
@@ -123,8 +112,8 @@ func (u *userService) UpdateUser(ctx context.Context, request *request.UserUpdat
 	return res, nil
 }
 
-func (u *userService) DeleteUser(ctx context.Context, params map[string]interface{}) error {
-	userData, err := u.userRepo.GetByParam(ctx, params)
+func (u *userService) DeleteUser(ctx context.Context, spec query.Spec) error {
+	userData, err := u.userRepo.Get(ctx, spec)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return &custom_error.NotFoundError{Message: err.Error()}
@@ -138,11 +127,17 @@ func (u *userService) DeleteUser(ctx context.Context, params map[string]interfac
 		return err
 	}
 
+	// a deleted user must not keep working with the tokens handed out before
+	err = RevokeUserSessions(ctx, u.refreshTokenRepo, userData.ID)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
-func (u *userService) GetByParam(ctx context.Context, params map[string]interface{}) (*response.UserResponse, error) {
-	userData, err := u.userRepo.GetByParam(ctx, params)
+func (u *userService) GetByParam(ctx context.Context, spec query.Spec) (*response.UserResponse, error) {
+	userData, err := u.userRepo.Get(ctx, spec)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, &custom_error.NotFoundError{Message: err.Error()}
@@ -151,46 +146,42 @@ func (u *userService) GetByParam(ctx context.Context, params map[string]interfac
 		return nil, err
 	}
 
+	if !canAccessUser(ctx, userData.ID) {
+		return nil, &custom_error.ForbiddenError{Message: "you can only access your own account"}
+	}
+
 	response := new(response.UserResponse)
 	response.ID = userData.ID
 	response.FirstName = userData.FirstName
 	response.LastName = userData.LastName
 	response.Email = userData.Email
 	response.PhoneNumber = userData.PhoneNumber
+	response.Role = userData.Role
 	response.CreatedAt = userData.CreatedAt.Time
 
 	return response, nil
 }
 
 func (u *userService) Fetch(ctx context.Context, criteria criteria.UserCriteria) (*util.PaginationResponse, error) {
-	params := map[string]interface{}{
-		"where": map[string]interface{}{
-			"default": map[string]interface{}{
-			},
-			"pagination": map[string]interface{}{
-				"page":  criteria.Pagination.Page,
-				"sort":  criteria.Pagination.Sort,
-				"limit": criteria.Pagination.Limit,
-			},
-		},
+	spec := query.Where()
+	if criteria.Email != "" {
+		spec = spec.And(query.Contains("email", criteria.Email))
+	}
+	if criteria.PhoneNumber != "" {
+		spec = spec.And(query.Contains("phone_number", criteria.PhoneNumber))
 	}
 
-	if criteria.Email != "" {
-		params["where"].(map[string]interface{})["default"] = map[string]interface{}{
-			"email ILIKE ?": "%" + criteria.
-				Email + "%",
-		}
+	spec, err := includeDeleted(ctx, spec, criteria.IncludeDeleted)
+	if err != nil {
+		return nil, err
 	}
 
-	if criteria.PhoneNumber != "" {
-		params["where"].(map[string]interface{})["default"] = map[string]interface{}{
-			"phone_number LIKE" +
-				" ?": "%" + criteria.
-				PhoneNumber + "%",
-		}
+	spec, err = paginate(spec, &criteria.Pagination, "first_name", "last_name", "email", "created_at")
+	if err != nil {
+		return nil, err
 	}
 
-	res, rowCount, err := u.userRepo.Fetch(ctx, params)
+	res, rowCount, err := u.userRepo.Fetch(ctx, spec)
 	if err != nil {
 		return nil, err
 	}
@@ -204,7 +195,12 @@ func (u *userService) Fetch(ctx context.Context, criteria criteria.UserCriteria)
 		data.LastName = val.LastName
 		data.Email = val.Email
 		data.PhoneNumber = val.PhoneNumber
+		data.Role = val.Role
 		data.CreatedAt = val.CreatedAt.Time
+		if val.DeletedAt.Valid {
+			deletedAt := val.DeletedAt.Time
+			data.DeletedAt = &deletedAt
+		}
 
 		responseData = append(responseData, data)
 	}
@@ -213,3 +209,11 @@ func (u *userService) Fetch(ctx context.Context, criteria criteria.UserCriteria)
 
 	return &resPagination, nil
 }
+
+// canAccessUser reports whether the caller may read or change the given account, which is only their own unless
+// they are a platform admin.
+func canAccessUser(ctx context.Context, userId uuid.UUID) bool {
+	callerId, _ := ctx.Value("user_id").(uuid.UUID)
+
+	return callerId == userId || helper.IsAdmin(ctx)
+}
@@ -60,8 +60,13 @@ func BuildPagination(
 	}
 
 	response.Paging.TotalPage = totalPage
-	response.Paging.OrderBy = splitOrderAndSort[0]
-	response.Paging.SortBy = splitOrderAndSort[1]
+	if len(splitOrderAndSort) > 0 {
+		response.Paging.OrderBy = strings.TrimSuffix(splitOrderAndSort[0], ",")
+		response.Paging.SortBy = "asc"
+	}
+	if len(splitOrderAndSort) > 1 {
+		response.Paging.SortBy = strings.TrimSuffix(splitOrderAndSort[1], ",")
+	}
 
 	return response
 }